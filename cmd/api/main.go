@@ -1,13 +1,33 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"time"
 
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/blob"
+	"github.com/codeforgood-org/golang-book-api/internal/catalog"
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
 	"github.com/codeforgood-org/golang-book-api/internal/config"
+	"github.com/codeforgood-org/golang-book-api/internal/covers"
+	"github.com/codeforgood-org/golang-book-api/internal/dedupe"
+	"github.com/codeforgood-org/golang-book-api/internal/enrich"
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/fuzzy"
+	"github.com/codeforgood-org/golang-book-api/internal/gql"
 	"github.com/codeforgood-org/golang-book-api/internal/handlers"
+	"github.com/codeforgood-org/golang-book-api/internal/idempotency"
+	"github.com/codeforgood-org/golang-book-api/internal/ingest"
 	"github.com/codeforgood-org/golang-book-api/internal/middleware"
+	"github.com/codeforgood-org/golang-book-api/internal/opds"
+	"github.com/codeforgood-org/golang-book-api/internal/reviews"
+	"github.com/codeforgood-org/golang-book-api/internal/rpc"
 	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/internal/typeahead"
+	"github.com/codeforgood-org/golang-book-api/internal/webhook"
 	"github.com/codeforgood-org/golang-book-api/pkg/logger"
 )
 
@@ -18,24 +38,255 @@ func main() {
 	// Initialize storage
 	bookStorage := storage.NewMemoryStorage()
 
+	// Publish storage changes for the event stream
+	eventBus := events.NewBus(cfg.EventLogSize)
+	bookStorage.SetEventBus(eventBus)
+
+	// Record every write for the audit log and revision history
+	auditLog := audit.NewLog(cfg.AuditLogSize)
+	bookStorage.SetAuditLog(auditLog)
+
+	// Purge expired books from the trash in the background, once the covers
+	// and ingest services below can clean up after them
+	if cfg.TrashPurgeIntervalMinutes < 0 {
+		logger.Error.Fatalf("Invalid TRASH_PURGE_INTERVAL_MINUTES %d: must not be negative", cfg.TrashPurgeIntervalMinutes)
+	}
+	purger := storage.NewPurger(bookStorage,
+		time.Duration(cfg.TrashRetentionHours)*time.Hour,
+		time.Duration(cfg.TrashPurgeIntervalMinutes)*time.Minute,
+	)
+
+	// Deliver webhooks in the background
+	if cfg.WebhookWorkers < 1 {
+		logger.Error.Fatalf("Invalid WEBHOOK_WORKERS %d: must be positive", cfg.WebhookWorkers)
+	}
+	webhookStore, err := webhook.OpenStore(cfg.WebhookStateFile)
+	if err != nil {
+		logger.Error.Fatalf("Failed to open webhook store: %v", err)
+	}
+	dispatcher := webhook.NewDispatcher(webhookStore, webhook.Options{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		Timeout:     time.Duration(cfg.WebhookTimeoutSeconds) * time.Second,
+		Workers:     cfg.WebhookWorkers,
+	})
+	go dispatcher.Run(context.Background())
+
+	// Lend books to patrons under the configured loan policies
+	policies, err := circulation.LoadPolicies(cfg.LoanPolicyFile)
+	if err != nil {
+		logger.Error.Fatalf("Failed to load loan policies: %v", err)
+	}
+	patronStorage := storage.NewMemoryPatronStorage()
+	circulationService := circulation.NewService(bookStorage, storage.NewMemoryCopyStorage(), patronStorage,
+		storage.NewMemoryLoanStorage(), storage.NewMemoryHoldStorage(), storage.NewMemoryFineStorage(), policies)
+	circulationService.SetHoldPickupWindow(time.Duration(cfg.HoldPickupDays) * 24 * time.Hour)
+	circulationService.SetEventBus(eventBus)
+	circulationService.SetNotifier(dispatcher)
+	if cfg.CirculationScanIntervalMinutes <= 0 {
+		logger.Error.Fatalf("Invalid CIRCULATION_SCAN_INTERVAL_MINUTES %d: must be positive", cfg.CirculationScanIntervalMinutes)
+	}
+	go circulationService.Run(context.Background(), time.Duration(cfg.CirculationScanIntervalMinutes)*time.Minute)
+
+	// Collect patron reviews, keeping each book's rating on the book
+	reviewService := reviews.NewService(bookStorage, patronStorage, storage.NewMemoryReviewStorage())
+
+	// Keep cover images, their thumbnails and ebook files in the blob store
+	blobs, err := blob.Open(cfg.BlobStore, cfg.BlobDir)
+	if err != nil {
+		logger.Error.Fatalf("Failed to open blob store: %v", err)
+	}
+	coverService := covers.NewService(bookStorage, blobs)
+	coverService.SetMaxBytes(cfg.CoverMaxBytes)
+
+	// Create or match books from uploaded EPUB and PDF files
+	assetStorage := storage.NewMemoryAssetStorage()
+	ingestService := ingest.NewService(bookStorage, assetStorage, blobs)
+	ingestService.SetCovers(coverService)
+	ingestService.SetMaxBytes(cfg.IngestMaxBytes)
+
+	// Remove the cover images and ebook files of books purged from the trash
+	purger.AddForgetter(coverService)
+	purger.AddForgetter(ingestService)
+	go purger.Run(context.Background())
+
+	// Publish the catalogue and its ebook files as OPDS feeds
+	opdsCatalog := opds.NewCatalog(bookStorage, assetStorage)
+	opdsCatalog.SetTitle(cfg.OPDSTitle)
+
+	// Fill in books from their ISBN with the configured metadata providers
+	enrichClient := &http.Client{Timeout: time.Duration(cfg.EnrichTimeoutSeconds) * time.Second}
+	var providers []enrich.Provider
+	for _, name := range cfg.EnrichProviders {
+		provider, err := enrich.NewProvider(name, cfg.OpenLibraryURL, enrichClient)
+		if err != nil {
+			logger.Error.Fatalf("Failed to configure metadata providers: %v", err)
+		}
+		providers = append(providers, provider)
+	}
+	enrichService := enrich.NewService(providers...)
+	enrichService.SetClient(enrichClient)
+	enrichService.SetCacheTTL(time.Duration(cfg.EnrichCacheTTLHours) * time.Hour)
+	enrichService.SetCovers(coverService)
+
+	// Group books into series and link the editions of each work
+	catalogService := catalog.NewService(bookStorage, storage.NewMemorySeriesStorage(), storage.NewMemoryWorkStorage())
+
+	// Find books entered more than once and merge them, moving what belongs
+	// to each merged book to the one it is merged into
+	dedupeService := dedupe.NewService(bookStorage)
+	if err := dedupeService.SetThreshold(cfg.DuplicateThreshold); err != nil {
+		logger.Error.Fatalf("Invalid DUPLICATE_THRESHOLD: %v", err)
+	}
+	dedupeService.AddMover(circulationService)
+	dedupeService.AddMover(reviewService)
+	dedupeService.AddMover(coverService)
+	dedupeService.AddMover(ingestService)
+	dedupeService.AddMover(catalogService)
+
+	// Complete titles and authors from an index that follows book changes
+	typeaheadIndex := typeahead.NewIndex()
+	go typeaheadIndex.Run(context.Background(), eventBus, bookStorage, time.Duration(cfg.TypeaheadRefreshMinutes)*time.Minute)
+
 	// Initialize handlers
 	bookHandler := handlers.NewBookHandler(bookStorage)
+	bookHandler.SetNotifier(dispatcher)
+	if len(providers) > 0 {
+		bookHandler.SetEnricher(enrichService, cfg.EnrichOnCreate)
+	}
+	bookHandler.SetDuplicates(dedupeService)
+	if cfg.SearchFuzziness < 0 || cfg.SearchFuzziness > fuzzy.MaxFuzziness {
+		logger.Error.Fatalf("Invalid SEARCH_FUZZINESS %d: must be from 0 to %d", cfg.SearchFuzziness, fuzzy.MaxFuzziness)
+	}
+	bookHandler.SetFuzziness(cfg.SearchFuzziness)
+	bookHandler.SetTypeahead(typeaheadIndex)
+
+	webhookHandler := handlers.NewWebhookHandler(webhookStore, dispatcher)
+	auditHandler := handlers.NewAuditHandler(bookStorage, auditLog)
+	patronHandler := handlers.NewPatronHandler(patronStorage, circulationService)
+	circulationHandler := handlers.NewCirculationHandler(circulationService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	catalogHandler := handlers.NewCatalogHandler(catalogService)
+	coverHandler := handlers.NewCoverHandler(coverService)
+	ingestHandler := handlers.NewIngestHandler(ingestService)
+	ingestHandler.SetNotifier(dispatcher)
+	opdsHandler := handlers.NewOPDSHandler(opdsCatalog)
+	eventsHandler := handlers.NewEventsHandler(eventBus, time.Duration(cfg.SSEHeartbeatSeconds)*time.Second)
+
+	schema, err := gql.NewSchema(bookStorage, gql.Services{Reviews: reviewService, Circulation: circulationService})
+	if err != nil {
+		logger.Error.Fatalf("Failed to build GraphQL schema: %v", err)
+	}
+	graphqlHandler := gql.NewHandler(schema, gql.Limits{
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+	})
 
 	// Setup routes
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", handlers.HealthCheck)
 	mux.HandleFunc("/books", bookHandler.HandleBooks)
 	mux.HandleFunc("/books/", bookHandler.HandleBookByID)
+	mux.HandleFunc("/books/import", bookHandler.HandleImport)
+	mux.HandleFunc("/books/lookup", bookHandler.HandleLookup)
+	mux.HandleFunc("/books/duplicates", bookHandler.HandleDuplicates)
+	mux.HandleFunc("/books/merge", bookHandler.HandleMerge)
+	mux.HandleFunc("/books/suggest", bookHandler.HandleSuggest)
+	mux.HandleFunc("/books/ingest", ingestHandler.HandleIngest)
+	mux.HandleFunc("/books/export", bookHandler.HandleExport)
+	mux.HandleFunc("/books/cite", bookHandler.HandleCiteList)
+	mux.Handle("/books/events", eventsHandler)
+	mux.HandleFunc("/books/{id}/restore", bookHandler.HandleRestore)
+	mux.HandleFunc("/trash", bookHandler.HandleTrash)
+	mux.HandleFunc("/genres", bookHandler.HandleGenres)
+	mux.HandleFunc("/classification/{scheme}", bookHandler.HandleClassTree)
+	mux.HandleFunc("/classification/{scheme}/books", bookHandler.HandleShelf)
+	mux.HandleFunc("/series", catalogHandler.HandleSeries)
+	mux.HandleFunc("/series/{id}", catalogHandler.HandleSeriesByID)
+	mux.HandleFunc("/series/{id}/books", catalogHandler.HandleSeriesBooks)
+	mux.HandleFunc("/series/{id}/books/{book}", catalogHandler.HandleSeriesBook)
+	mux.HandleFunc("/works", catalogHandler.HandleWorks)
+	mux.HandleFunc("/works/{id}", catalogHandler.HandleWorkByID)
+	mux.HandleFunc("/works/{id}/editions", catalogHandler.HandleEditions)
+	mux.HandleFunc("/works/{id}/editions/{book}", catalogHandler.HandleEdition)
+	mux.HandleFunc("/books/{id}/history", auditHandler.HandleHistory)
+	mux.HandleFunc("/books/{id}/history/{revision}", auditHandler.HandleRevision)
+	mux.HandleFunc("/books/{id}/history/{revision}/restore", auditHandler.HandleRestore)
+	mux.HandleFunc("/audit", auditHandler.HandleAudit)
+	mux.HandleFunc("/books/{id}/cite", bookHandler.HandleCite)
+	mux.HandleFunc("/books/{id}/cover", coverHandler.HandleCover)
+	mux.HandleFunc("/books/{id}/assets", ingestHandler.HandleAssets)
+	mux.HandleFunc("/books/{id}/assets/{asset}", ingestHandler.HandleAsset)
+	mux.HandleFunc("/books/{id}/copies", circulationHandler.HandleCopies)
+	mux.HandleFunc("/books/{id}/copies/{copy}", circulationHandler.HandleCopy)
+	mux.HandleFunc("/books/{id}/checkout", circulationHandler.HandleCheckout)
+	mux.HandleFunc("/books/{id}/return", circulationHandler.HandleReturn)
+	mux.HandleFunc("/books/{id}/availability", circulationHandler.HandleAvailability)
+	mux.HandleFunc("/books/{id}/holds", circulationHandler.HandleBookHolds)
+	mux.HandleFunc("/books/{id}/reviews", reviewHandler.HandleReviews)
+	mux.HandleFunc("/books/{id}/reviews/{review}", reviewHandler.HandleReview)
+	mux.HandleFunc("/admin/reviews", reviewHandler.HandleModerationQueue)
+	mux.HandleFunc("/admin/reviews/{id}/moderate", reviewHandler.HandleModerate)
+	mux.HandleFunc("/patrons", patronHandler.HandlePatrons)
+	mux.HandleFunc("/patrons/{id}", patronHandler.HandlePatronByID)
+	mux.HandleFunc("/patrons/{id}/loans", circulationHandler.HandlePatronLoans)
+	mux.HandleFunc("/patrons/{id}/holds", circulationHandler.HandlePatronHolds)
+	mux.HandleFunc("/patrons/{id}/fines", circulationHandler.HandlePatronFines)
+	mux.HandleFunc("/loans/{id}", circulationHandler.HandleLoan)
+	mux.HandleFunc("/loans/{id}/renew", circulationHandler.HandleRenew)
+	mux.HandleFunc("/holds/{id}", circulationHandler.HandleHold)
+	mux.HandleFunc("/fines/{id}", circulationHandler.HandleFine)
+	mux.HandleFunc("/fines/{id}/payments", circulationHandler.HandleFinePayment)
+	mux.HandleFunc("/fines/{id}/waive", circulationHandler.HandleFineWaiver)
+	mux.HandleFunc("/loan-policies", circulationHandler.HandlePolicies)
+	mux.HandleFunc("/webhooks", webhookHandler.HandleWebhooks)
+	mux.HandleFunc("/webhooks/{id}", webhookHandler.HandleWebhookByID)
+	mux.HandleFunc("/webhooks/{id}/deliveries", webhookHandler.HandleDeliveries)
+	mux.HandleFunc("/webhooks/{id}/deliveries/{delivery}", webhookHandler.HandleDelivery)
+	mux.HandleFunc("/webhooks/{id}/deliveries/{delivery}/retry", webhookHandler.HandleRedeliver)
+	mux.HandleFunc("/webhooks/dead-letters", webhookHandler.HandleDeadLetters)
+	mux.HandleFunc("/opds", opdsHandler.HandleStart)
+	mux.HandleFunc("/opds/new", opdsHandler.HandleNew)
+	mux.HandleFunc("/opds/books", opdsHandler.HandleAll)
+	mux.HandleFunc("/opds/authors", opdsHandler.HandleAuthors)
+	mux.HandleFunc("/opds/authors/{author}", opdsHandler.HandleAuthor)
+	mux.HandleFunc("/opds/genres", opdsHandler.HandleGenres)
+	mux.HandleFunc("/opds/genres/{genre}", opdsHandler.HandleGenre)
+	mux.HandleFunc("/opds/search", opdsHandler.HandleSearch)
+	mux.HandleFunc("/opds/opensearch.xml", opdsHandler.HandleOpenSearch)
+	mux.Handle("/graphql", graphqlHandler)
+
+	// Replay responses to retried POSTs carrying an Idempotency-Key, buffering
+	// bodies up to the largest upload accepted
+	idempotencyStore := idempotency.NewStore(time.Duration(cfg.IdempotencyTTLHours) * time.Hour)
+	idempotentBodyBytes := max(cfg.IngestMaxBytes, cfg.CoverMaxBytes) + handlers.MultipartOverhead
 
 	// Apply middleware
 	handler := middleware.Recovery(
 		middleware.RequestID(
-			middleware.Logger(
-				middleware.CORS(mux),
+			middleware.Audit(
+				middleware.Logger(
+					middleware.CORS(
+						middleware.Idempotency(idempotencyStore, idempotentBodyBytes)(mux),
+					),
+				),
 			),
 		),
 	)
 
+	// Start gRPC server
+	grpcAddr := fmt.Sprintf(":%s", cfg.GRPCPort)
+	lis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		logger.Error.Fatalf("Failed to listen on %s: %v", grpcAddr, err)
+	}
+	grpcServer := rpc.NewServer(bookStorage)
+	go func() {
+		logger.Info.Printf("Starting gRPC server on %s", grpcAddr)
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Error.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.ServerPort)
 	logger.Info.Printf("Starting server on %s", addr)
@@ -0,0 +1,164 @@
+// Command bookctl is a command-line client for the Book API.
+//
+// Usage:
+//
+//	bookctl import [-format marc|marcxml] FILE
+//	bookctl export [-format marc|marcxml] [-o FILE] [-title T] [-author A] [-search S]
+//
+// The API location is read from the API_URL environment variable and
+// defaults to http://localhost:8080.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	baseURL := os.Getenv("API_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(baseURL, os.Args[2:])
+	case "export":
+		err = runExport(baseURL, os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "bookctl: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bookctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage:
+  bookctl import [-format marc|marcxml] FILE
+  bookctl export [-format marc|marcxml] [-o FILE] [-title T] [-author A] [-search S]`)
+}
+
+// runImport uploads a MARC file to POST /books/import
+func runImport(baseURL string, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "", "input format: marc or marcxml (default: from file extension)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("import requires exactly one file")
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		*format = "marc"
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".xml" {
+			*format = "marcxml"
+		}
+	}
+
+	contentType := "application/marc"
+	if *format == "marcxml" {
+		contentType = "application/marcxml+xml"
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	resp, err := http.Post(baseURL+"/books/import", contentType, f)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Imported int `json:"imported"`
+		Failed   int `json:"failed"`
+		Errors   []struct {
+			Record int    `json:"record"`
+			Error  string `json:"error"`
+		} `json:"errors"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	if result.Error != "" {
+		return fmt.Errorf("import failed: %s", result.Error)
+	}
+
+	for _, e := range result.Errors {
+		fmt.Printf("✗ Record %d: %s\n", e.Record, e.Error)
+	}
+	fmt.Printf("Imported %d books, %d failed\n", result.Imported, result.Failed)
+	return nil
+}
+
+// runExport downloads GET /books/export to a file or stdout
+func runExport(baseURL string, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "marcxml", "output format: marc or marcxml")
+	output := fs.String("o", "", "output file (default: stdout)")
+	title := fs.String("title", "", "filter by title")
+	author := fs.String("author", "", "filter by author")
+	search := fs.String("search", "", "search in title and author")
+	fs.Parse(args)
+
+	query := url.Values{}
+	query.Set("format", *format)
+	for key, value := range map[string]string{"title": *title, "author": *author, "search": *search} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	resp, err := http.Get(baseURL + "/books/export?" + query.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("export failed: status %d: %s", resp.StatusCode, errResp.Error)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
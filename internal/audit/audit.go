@@ -0,0 +1,274 @@
+// Package audit records who changed which book, when and how, giving every
+// book a numbered revision history.
+package audit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// ErrRevisionNotFound is returned when a book has no revision with the requested number
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Action is the kind of write an entry records
+type Action string
+
+// Recorded actions
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRevert  Action = "revert"
+	ActionRestore Action = "restore"
+	ActionPurge   Action = "purge"
+	ActionMerge   Action = "merge"
+)
+
+// Actors recorded when a write carries no actor, and for writes the service
+// makes on its own
+const (
+	Anonymous = "anonymous"
+	System    = "system"
+)
+
+// Entry records a single write to a book
+type Entry struct {
+	ID        int64        `json:"id"`
+	Time      time.Time    `json:"time"`
+	Actor     string       `json:"actor"`
+	RequestID string       `json:"request_id,omitempty"`
+	Action    Action       `json:"action"`
+	BookID    int          `json:"book_id"`
+	Revision  int          `json:"revision"`
+	Before    *models.Book `json:"before,omitempty"`
+	After     *models.Book `json:"after,omitempty"`
+	Changes   []Change     `json:"changes"`
+	// RevertedTo is the revision a revert restored
+	RevertedTo int `json:"reverted_to,omitempty"`
+	// MergedInto is the book a merged book was merged into
+	MergedInto int `json:"merged_into,omitempty"`
+}
+
+// State returns the book as it was after this entry was applied, or nil if
+// the entry deleted it
+func (e *Entry) State() *models.Book {
+	return e.After
+}
+
+// Change describes one field that differs between two versions of a book
+type Change struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Source identifies who made a write
+type Source struct {
+	Actor     string
+	RequestID string
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the source of the writes made with it
+func NewContext(ctx context.Context, src Source) context.Context {
+	return context.WithValue(ctx, contextKey{}, src)
+}
+
+// FromContext returns the source carried by ctx, with the actor defaulting
+// to Anonymous
+func FromContext(ctx context.Context) Source {
+	src, _ := ctx.Value(contextKey{}).(Source)
+	if src.Actor == "" {
+		src.Actor = Anonymous
+	}
+	return src
+}
+
+// Filter narrows a query of the log; zero fields match everything
+type Filter struct {
+	BookID    int
+	Actor     string
+	Action    Action
+	RequestID string
+	Since     time.Time
+	Until     time.Time
+}
+
+// Match reports whether an entry satisfies the filter
+func (f Filter) Match(e Entry) bool {
+	if f.BookID != 0 && e.BookID != f.BookID {
+		return false
+	}
+	if f.Actor != "" && !strings.EqualFold(e.Actor, f.Actor) {
+		return false
+	}
+	if f.Action != "" && e.Action != f.Action {
+		return false
+	}
+	if f.RequestID != "" && e.RequestID != f.RequestID {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// DefaultLogSize is the number of entries retained when no size is given
+const DefaultLogSize = 10000
+
+// Log is an append-only, in-memory audit log keeping a bounded number of
+// the most recent entries
+type Log struct {
+	mu sync.RWMutex
+	// entries is a ring of up to size entries, the oldest at start
+	entries   []Entry
+	start     int
+	size      int
+	nextID    int64
+	revisions map[int]int
+	now       func() time.Time
+}
+
+// NewLog creates an empty audit log retaining up to size entries. Once it
+// is full the oldest entries are dropped, but revision numbers carry on
+func NewLog(size int) *Log {
+	if size < 1 {
+		size = DefaultLogSize
+	}
+	return &Log{
+		entries:   make([]Entry, 0, min(size, 1024)),
+		size:      size,
+		nextID:    1,
+		revisions: make(map[int]int),
+		now:       time.Now,
+	}
+}
+
+// Record appends an entry for a write to a book, attributing it to the
+// source carried by ctx. before is nil for creates and after is nil for
+// deletes
+func (l *Log) Record(ctx context.Context, action Action, bookID int, before, after *models.Book) Entry {
+	return l.record(ctx, Entry{Action: action, BookID: bookID, Before: before, After: after})
+}
+
+// RecordRevert appends an entry for a book being put back to the state of
+// an earlier revision. before is nil if the book had been deleted
+func (l *Log) RecordRevert(ctx context.Context, bookID int, before, after *models.Book, revision int) Entry {
+	return l.record(ctx, Entry{Action: ActionRevert, BookID: bookID, Before: before, After: after, RevertedTo: revision})
+}
+
+// RecordMerge appends an entry for a book being merged into another and
+// removed
+func (l *Log) RecordMerge(ctx context.Context, bookID int, before *models.Book, into int) Entry {
+	return l.record(ctx, Entry{Action: ActionMerge, BookID: bookID, Before: before, MergedInto: into})
+}
+
+// record appends entry, filling in its ID, time, source, revision and
+// changes
+func (l *Log) record(ctx context.Context, entry Entry) Entry {
+	src := FromContext(ctx)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.revisions[entry.BookID]++
+	entry.ID = l.nextID
+	l.nextID++
+	entry.Time = l.now().UTC()
+	entry.Actor = src.Actor
+	entry.RequestID = src.RequestID
+	entry.Revision = l.revisions[entry.BookID]
+	entry.Changes = Diff(entry.Before, entry.After)
+	entry.Before = copyBook(entry.Before)
+	entry.After = copyBook(entry.After)
+	if len(l.entries) < l.size {
+		l.entries = append(l.entries, entry)
+	} else {
+		l.entries[l.start] = entry
+		l.start = (l.start + 1) % l.size
+	}
+	return entry
+}
+
+// at returns the i-th oldest retained entry; l.mu must be held
+func (l *Log) at(i int) Entry {
+	return l.entries[(l.start+i)%len(l.entries)]
+}
+
+// Query returns the entries matching the filter, newest first
+func (l *Log) Query(f Filter) []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]Entry, 0)
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if e := l.at(i); f.Match(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// History returns the revisions of a book, newest first
+func (l *Log) History(bookID int) []Entry {
+	return l.Query(Filter{BookID: bookID})
+}
+
+// Revision returns a single revision of a book
+func (l *Log) Revision(bookID, revision int) (*Entry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for i := range l.entries {
+		if e := l.at(i); e.BookID == bookID && e.Revision == revision {
+			return &e, nil
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
+
+// Diff lists the fields that differ between two versions of a book, using
+// the JSON field names. A nil version is treated as a book with no fields set
+func Diff(before, after *models.Book) []Change {
+	var from, to models.Book
+	if before != nil {
+		from = *before
+	}
+	if after != nil {
+		to = *after
+	}
+
+	changes := make([]Change, 0)
+	fv, tv := reflect.ValueOf(from), reflect.ValueOf(to)
+	for i := 0; i < fv.NumField(); i++ {
+		field := fv.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		a, b := fv.Field(i).Interface(), tv.Field(i).Interface()
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, Change{Field: name, From: a, To: b})
+		}
+	}
+	return changes
+}
+
+func copyBook(book *models.Book) *models.Book {
+	if book == nil {
+		return nil
+	}
+	bookCopy := *book
+	return &bookCopy
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestDiff(t *testing.T) {
+	before := &models.Book{ID: 1, Title: "Old", Author: "Author", PublishedYear: 2000}
+	after := &models.Book{ID: 1, Title: "New", Author: "Author", PublishedYear: 2001}
+
+	changes := Diff(before, after)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Field != "title" || changes[0].From != "Old" || changes[0].To != "New" {
+		t.Errorf("unexpected title change %+v", changes[0])
+	}
+	if changes[1].Field != "published_year" || changes[1].From != 2000 || changes[1].To != 2001 {
+		t.Errorf("unexpected year change %+v", changes[1])
+	}
+
+	if changes := Diff(nil, after); len(changes) != 4 {
+		t.Errorf("expected every set field to change on create, got %+v", changes)
+	}
+	if changes := Diff(before, before); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestLog_Record(t *testing.T) {
+	log := NewLog(0)
+	ctx := NewContext(context.Background(), Source{Actor: "alice", RequestID: "req-1"})
+
+	book := &models.Book{ID: 7, Title: "Title", Author: "Author"}
+	first := log.Record(ctx, ActionCreate, 7, nil, book)
+	book.Title = "Changed"
+	log.Record(context.Background(), ActionUpdate, 7, &models.Book{ID: 7, Title: "Title", Author: "Author"}, book)
+	log.Record(ctx, ActionCreate, 8, nil, &models.Book{ID: 8})
+
+	if first.Actor != "alice" || first.RequestID != "req-1" || first.Revision != 1 {
+		t.Errorf("unexpected first entry %+v", first)
+	}
+	if first.After.Title != "Title" {
+		t.Error("expected entry to keep a copy of the book")
+	}
+
+	history := log.History(7)
+	if len(history) != 2 || history[0].Revision != 2 || history[0].Actor != Anonymous {
+		t.Fatalf("expected two revisions newest first by anonymous, got %+v", history)
+	}
+
+	if _, err := log.Revision(7, 3); err != ErrRevisionNotFound {
+		t.Errorf("expected ErrRevisionNotFound, got %v", err)
+	}
+	if rev, _ := log.Revision(8, 1); rev == nil || rev.BookID != 8 {
+		t.Errorf("expected revision 1 of book 8, got %+v", rev)
+	}
+}
+
+func TestLog_DropsOldestEntries(t *testing.T) {
+	log := NewLog(3)
+	for i := 0; i < 5; i++ {
+		log.Record(context.Background(), ActionUpdate, 7, nil, &models.Book{ID: 7})
+	}
+
+	history := log.History(7)
+	if len(history) != 3 || history[0].Revision != 5 || history[2].Revision != 3 {
+		t.Fatalf("expected revisions 5 to 3, got %+v", history)
+	}
+	if history[0].ID != 5 {
+		t.Errorf("expected entry ID 5, got %d", history[0].ID)
+	}
+	if _, err := log.Revision(7, 2); err != ErrRevisionNotFound {
+		t.Errorf("expected ErrRevisionNotFound for a dropped revision, got %v", err)
+	}
+	if rev, _ := log.Revision(7, 4); rev == nil || rev.Revision != 4 {
+		t.Errorf("expected revision 4, got %+v", rev)
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entry := Entry{Time: at, Actor: "Alice", RequestID: "r1", Action: ActionDelete, BookID: 3}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty", Filter{}, true},
+		{"book", Filter{BookID: 3}, true},
+		{"other book", Filter{BookID: 4}, false},
+		{"actor case-insensitive", Filter{Actor: "alice"}, true},
+		{"action", Filter{Action: ActionUpdate}, false},
+		{"request", Filter{RequestID: "r1"}, true},
+		{"since", Filter{Since: at.Add(-time.Hour)}, true},
+		{"after since", Filter{Since: at.Add(time.Hour)}, false},
+		{"until", Filter{Until: at.Add(-time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(entry); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
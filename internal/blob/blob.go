@@ -0,0 +1,65 @@
+// Package blob keeps binary objects, such as cover images, in a pluggable
+// store. Objects are addressed by slash-separated keys like
+// covers/42/original.
+package blob
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when no object is stored under a key
+	ErrNotFound = errors.New("blob not found")
+
+	// ErrInvalidKey is returned for keys that are empty, absolute or not
+	// clean, such as keys containing .. segments
+	ErrInvalidKey = errors.New("blob key must be a clean relative slash-separated path")
+)
+
+// Store keeps objects under keys. Implementations must be safe for
+// concurrent use
+type Store interface {
+	// Put stores data under key, replacing anything already there
+	Put(key string, data []byte) error
+
+	// Get returns the data stored under key
+	Get(key string) ([]byte, error)
+
+	// Delete removes the object stored under key
+	Delete(key string) error
+}
+
+// Drivers lists the store drivers Open accepts
+var Drivers = []string{"local", "memory"}
+
+// Open returns a store using the named driver: local keeps objects as files
+// under dir, and memory keeps them in memory, ignoring dir
+func Open(driver, dir string) (Store, error) {
+	switch driver {
+	case "local":
+		return NewLocalStore(dir)
+	case "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown blob store driver %q, must be one of %s", driver, strings.Join(Drivers, ", "))
+	}
+}
+
+// ValidKey reports whether key can address an object
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return false
+	}
+	if path.Clean(key) != key {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." || segment == "." {
+			return false
+		}
+	}
+	return true
+}
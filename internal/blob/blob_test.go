@@ -0,0 +1,79 @@
+package blob
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStores(t *testing.T) {
+	local, err := NewLocalStore(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	stores := []struct {
+		name  string
+		store Store
+	}{
+		{"local", local},
+		{"memory", NewMemoryStore()},
+	}
+
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.store
+
+			if _, err := s.Get("covers/1/original"); err != ErrNotFound {
+				t.Errorf("expected ErrNotFound, got %v", err)
+			}
+			if err := s.Put("covers/1/original", []byte("first")); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := s.Put("covers/1/original", []byte("second")); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if data, err := s.Get("covers/1/original"); err != nil || !bytes.Equal(data, []byte("second")) {
+				t.Errorf("expected the replaced data, got %q and %v", data, err)
+			}
+
+			if err := s.Delete("covers/1/original"); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := s.Delete("covers/1/original"); err != ErrNotFound {
+				t.Errorf("expected ErrNotFound, got %v", err)
+			}
+
+			for _, key := range []string{"", "/etc/passwd", "../outside", "covers/../../outside", "covers//1", `covers\1`} {
+				if err := s.Put(key, []byte("x")); err != ErrInvalidKey {
+					t.Errorf("expected ErrInvalidKey for %q, got %v", key, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalStore_LeavesNoTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := NewLocalStore(dir)
+
+	s.Put("covers/1/original", []byte("cover"))
+
+	entries, _ := os.ReadDir(filepath.Join(dir, "covers", "1"))
+	if len(entries) != 1 || entries[0].Name() != "original" {
+		t.Errorf("expected only the stored file, got %v", entries)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if s, err := Open("memory", ""); err != nil || s == nil {
+		t.Errorf("expected a memory store, got %v and %v", s, err)
+	}
+	if s, err := Open("local", t.TempDir()); err != nil || s == nil {
+		t.Errorf("expected a local store, got %v and %v", s, err)
+	}
+	if _, err := Open("s3", ""); err == nil {
+		t.Error("expected an error for an unknown driver")
+	}
+}
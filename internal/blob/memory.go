@@ -0,0 +1,62 @@
+package blob
+
+import (
+	"slices"
+	"sync"
+)
+
+// MemoryStore keeps objects in memory. It is meant for tests and for
+// deployments where covers need not survive a restart
+type MemoryStore struct {
+	objects map[string][]byte
+	mu      sync.RWMutex
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		objects: make(map[string][]byte),
+	}
+}
+
+// Put stores a copy of data under key
+func (s *MemoryStore) Put(key string, data []byte) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = slices.Clone(data)
+	return nil
+}
+
+// Get returns a copy of the data stored under key
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	if !ValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return slices.Clone(data), nil
+}
+
+// Delete removes the object stored under key
+func (s *MemoryStore) Delete(key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.objects[key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.objects, key)
+	return nil
+}
@@ -0,0 +1,99 @@
+// Package catalog groups books into series, in reading order, and links the
+// editions and translations of a book to the work they are editions of.
+package catalog
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+var (
+	// ErrAlreadyInSeries is returned when a book is added to a series twice
+	ErrAlreadyInSeries = errors.New("book is already in this series")
+
+	// ErrNotInSeries is returned when a book is not in the series it is
+	// removed from
+	ErrNotInSeries = errors.New("book is not in this series")
+
+	// ErrInvalidPosition is returned when a book is added to a series past
+	// its end
+	ErrInvalidPosition = errors.New("position must be from 1 to one past the last book in the series")
+
+	// ErrInvalidOrder is returned when a series is reordered with a list that
+	// is not the books already in it
+	ErrInvalidOrder = errors.New("book_ids must list every book in the series exactly once")
+
+	// ErrNotAnEdition is returned when a book is unlinked from a work it is
+	// not an edition of
+	ErrNotAnEdition = errors.New("book is not an edition of this work")
+)
+
+// Service manages series and works against the book, series and work
+// storages
+type Service struct {
+	books  storage.Storage
+	series storage.SeriesStorage
+	works  storage.WorkStorage
+	// mu serializes writes so that concurrent changes to the same series or
+	// work are not lost
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewService creates a catalog service. The book storage must implement
+// storage.Editions for books to be linked to works
+func NewService(books storage.Storage, series storage.SeriesStorage, works storage.WorkStorage) *Service {
+	return &Service{
+		books:  books,
+		series: series,
+		works:  works,
+		now:    time.Now,
+	}
+}
+
+// MoveBook puts one book in place of another in every series when the
+// second is merged into it, or drops the second where the first is already
+// in the series, and links the first to the second's work if it has none
+func (s *Service) MoveBook(from, to int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.series.GetAllSeries()
+	if err != nil {
+		return err
+	}
+	for _, series := range all {
+		position := series.Position(from)
+		if position == 0 {
+			continue
+		}
+		if series.Position(to) > 0 {
+			series.BookIDs = slices.Delete(series.BookIDs, position-1, position)
+		} else {
+			series.BookIDs[position-1] = to
+		}
+		series.UpdatedAt = s.now().UTC()
+		if _, err := s.series.UpdateSeries(series); err != nil {
+			return err
+		}
+	}
+
+	source, err := s.books.GetByID(from)
+	if err != nil {
+		return err
+	}
+	target, err := s.books.GetByID(to)
+	if err != nil {
+		return err
+	}
+	if source.WorkID != 0 && target.WorkID == 0 {
+		if _, err := s.setWork(to, source.WorkID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
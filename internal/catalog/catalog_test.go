@@ -0,0 +1,57 @@
+package catalog
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newTestService returns a service with the three books of a trilogy
+func newTestService(t *testing.T) (*Service, []*models.Book) {
+	t.Helper()
+
+	books := storage.NewMemoryStorage()
+	s := NewService(books, storage.NewMemorySeriesStorage(), storage.NewMemoryWorkStorage())
+	s.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	var trilogy []*models.Book
+	for _, title := range []string{"A Wizard of Earthsea", "The Tombs of Atuan", "The Farthest Shore"} {
+		book, _ := books.Create(models.Book{Title: title, Author: "Ursula K. Le Guin"})
+		trilogy = append(trilogy, book)
+	}
+	return s, trilogy
+}
+
+func TestService_MoveBook(t *testing.T) {
+	s, trilogy := newTestService(t)
+	wizard, tombs, shore := trilogy[0].ID, trilogy[1].ID, trilogy[2].ID
+	duplicate, _ := s.books.Create(models.Book{Title: "Wizard of Earthsea", Author: "Ursula Le Guin"})
+
+	earthsea, _ := s.CreateSeries(models.Series{Title: "Earthsea"})
+	for _, id := range []int{duplicate.ID, tombs, shore} {
+		s.AddToSeries(earthsea.ID, id, 0)
+	}
+	// The kept book is already in this one, so the duplicate drops out
+	omnibus, _ := s.CreateSeries(models.Series{Title: "Omnibus"})
+	for _, id := range []int{duplicate.ID, wizard} {
+		s.AddToSeries(omnibus.ID, id, 0)
+	}
+	work, _ := s.CreateWork(models.Work{Title: "A Wizard of Earthsea", Author: "Ursula K. Le Guin"})
+	s.AddEdition(work.ID, duplicate.ID)
+
+	if err := s.MoveBook(duplicate.ID, wizard); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if series, _ := s.Series(earthsea.ID); !slices.Equal(series.BookIDs, []int{wizard, tombs, shore}) {
+		t.Errorf("expected the kept book in the duplicate's place, got %v", series.BookIDs)
+	}
+	if series, _ := s.Series(omnibus.ID); !slices.Equal(series.BookIDs, []int{wizard}) {
+		t.Errorf("expected the duplicate dropped, got %v", series.BookIDs)
+	}
+	if book, _ := s.books.GetByID(wizard); book.WorkID != work.ID {
+		t.Errorf("expected the kept book linked to work %d, got %d", work.ID, book.WorkID)
+	}
+}
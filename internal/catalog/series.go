@@ -0,0 +1,152 @@
+package catalog
+
+import (
+	"slices"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// AllSeries returns every series, oldest first
+func (s *Service) AllSeries() ([]models.Series, error) {
+	return s.series.GetAllSeries()
+}
+
+// Series returns a series by its ID
+func (s *Service) Series(id int) (*models.Series, error) {
+	return s.series.GetSeries(id)
+}
+
+// CreateSeries adds an empty series; books are added to it afterwards
+func (s *Service) CreateSeries(series models.Series) (*models.Series, error) {
+	if err := series.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	return s.series.CreateSeries(models.Series{
+		Title:       series.Title,
+		Description: series.Description,
+		BookIDs:     []int{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+}
+
+// UpdateSeries replaces the title and description of a series, leaving its
+// books unchanged
+func (s *Service) UpdateSeries(id int, series models.Series) (*models.Series, error) {
+	if err := series.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.series.GetSeries(id)
+	if err != nil {
+		return nil, err
+	}
+	existing.Title = series.Title
+	existing.Description = series.Description
+	existing.UpdatedAt = s.now().UTC()
+	return s.series.UpdateSeries(*existing)
+}
+
+// DeleteSeries removes a series. Its books are not affected
+func (s *Service) DeleteSeries(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.series.DeleteSeries(id)
+}
+
+// SeriesBooks returns the books of a series in order. Books that have been
+// deleted are left out, but the others keep their positions
+func (s *Service) SeriesBooks(id int) ([]models.SeriesEntry, error) {
+	series, err := s.series.GetSeries(id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.SeriesEntry, 0, len(series.BookIDs))
+	for i, bookID := range series.BookIDs {
+		book, err := s.books.GetByID(bookID)
+		if err == models.ErrBookNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, models.SeriesEntry{Position: i + 1, Book: *book})
+	}
+	return entries, nil
+}
+
+// AddToSeries puts a book into a series at a 1-based position, moving the
+// books from that position on back by one. A position of 0 adds the book at
+// the end
+func (s *Service) AddToSeries(id, bookID, position int) (*models.Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, err := s.series.GetSeries(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	if series.Position(bookID) > 0 {
+		return nil, ErrAlreadyInSeries
+	}
+	if position == 0 {
+		position = len(series.BookIDs) + 1
+	}
+	if position < 1 || position > len(series.BookIDs)+1 {
+		return nil, ErrInvalidPosition
+	}
+
+	series.BookIDs = slices.Insert(series.BookIDs, position-1, bookID)
+	series.UpdatedAt = s.now().UTC()
+	return s.series.UpdateSeries(*series)
+}
+
+// RemoveFromSeries takes a book out of a series, moving the books after it
+// forward by one
+func (s *Service) RemoveFromSeries(id, bookID int) (*models.Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, err := s.series.GetSeries(id)
+	if err != nil {
+		return nil, err
+	}
+	position := series.Position(bookID)
+	if position == 0 {
+		return nil, ErrNotInSeries
+	}
+
+	series.BookIDs = slices.Delete(series.BookIDs, position-1, position)
+	series.UpdatedAt = s.now().UTC()
+	return s.series.UpdateSeries(*series)
+}
+
+// ReorderSeries puts the books of a series in a new order. bookIDs must list
+// each book already in the series exactly once
+func (s *Service) ReorderSeries(id int, bookIDs []int) (*models.Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, err := s.series.GetSeries(id)
+	if err != nil {
+		return nil, err
+	}
+	current, proposed := slices.Sorted(slices.Values(series.BookIDs)), slices.Sorted(slices.Values(bookIDs))
+	if !slices.Equal(current, proposed) {
+		return nil, ErrInvalidOrder
+	}
+
+	series.BookIDs = slices.Clone(bookIDs)
+	series.UpdatedAt = s.now().UTC()
+	return s.series.UpdateSeries(*series)
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestService_SeriesMembership(t *testing.T) {
+	s, books := newTestService(t)
+	a, b, c := books[0].ID, books[1].ID, books[2].ID
+
+	series, err := s.CreateSeries(models.Series{Title: " Earthsea ", BookIDs: []int{a}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if series.Title != "Earthsea" || len(series.BookIDs) != 0 {
+		t.Errorf("expected an empty series titled Earthsea, got %+v", series)
+	}
+
+	steps := []struct {
+		name     string
+		bookID   int
+		position int
+		wantErr  error
+		expected []int
+	}{
+		{"append", c, 0, nil, []int{c}},
+		{"insert at the front", a, 1, nil, []int{a, c}},
+		{"insert in the middle", b, 2, nil, []int{a, b, c}},
+		{"add twice", b, 0, ErrAlreadyInSeries, []int{a, b, c}},
+		{"unknown book", 999, 0, models.ErrBookNotFound, []int{a, b, c}},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if _, err := s.AddToSeries(series.ID, step.bookID, step.position); err != step.wantErr {
+				t.Errorf("expected error %v, got %v", step.wantErr, err)
+			}
+			if got, _ := s.Series(series.ID); !slices.Equal(got.BookIDs, step.expected) {
+				t.Errorf("expected books %v, got %v", step.expected, got.BookIDs)
+			}
+		})
+	}
+
+	if _, err := s.RemoveFromSeries(series.ID, a); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := s.RemoveFromSeries(series.ID, a); err != ErrNotInSeries {
+		t.Errorf("expected ErrNotInSeries, got %v", err)
+	}
+	if _, err := s.AddToSeries(series.ID, a, 4); err != ErrInvalidPosition {
+		t.Errorf("expected ErrInvalidPosition, got %v", err)
+	}
+	if _, err := s.AddToSeries(999, a, 0); err != models.ErrSeriesNotFound {
+		t.Errorf("expected ErrSeriesNotFound, got %v", err)
+	}
+}
+
+func TestService_ReorderSeries(t *testing.T) {
+	s, books := newTestService(t)
+	a, b, c := books[0].ID, books[1].ID, books[2].ID
+
+	series, _ := s.CreateSeries(models.Series{Title: "Earthsea"})
+	for _, id := range []int{a, b, c} {
+		s.AddToSeries(series.ID, id, 0)
+	}
+
+	tests := []struct {
+		name    string
+		order   []int
+		wantErr error
+	}{
+		{"missing a book", []int{c, a}, ErrInvalidOrder},
+		{"book listed twice", []int{c, a, a}, ErrInvalidOrder},
+		{"book not in the series", []int{c, a, 999}, ErrInvalidOrder},
+		{"every book once", []int{c, a, b}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.ReorderSeries(series.ID, tt.order); err != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Deleted books are left out of the listing but the rest keep their places
+	s.books.Delete(a)
+	entries, err := s.SeriesBooks(series.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != c || entries[0].Position != 1 || entries[1].ID != b || entries[1].Position != 3 {
+		t.Errorf("expected books %d at 1 and %d at 3, got %+v", c, b, entries)
+	}
+}
+
+func TestService_UpdateSeries(t *testing.T) {
+	s, books := newTestService(t)
+
+	series, _ := s.CreateSeries(models.Series{Title: "Earthsea"})
+	s.AddToSeries(series.ID, books[0].ID, 0)
+
+	updated, err := s.UpdateSeries(series.ID, models.Series{Title: "The Earthsea Cycle", Description: "Six books"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Title != "The Earthsea Cycle" || updated.Description != "Six books" || len(updated.BookIDs) != 1 {
+		t.Errorf("expected a renamed series that keeps its book, got %+v", updated)
+	}
+
+	if _, err := s.UpdateSeries(series.ID, models.Series{}); err != models.ErrInvalidSeriesTitle {
+		t.Errorf("expected ErrInvalidSeriesTitle, got %v", err)
+	}
+	if err := s.DeleteSeries(series.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := s.UpdateSeries(series.ID, models.Series{Title: "Earthsea"}); err != models.ErrSeriesNotFound {
+		t.Errorf("expected ErrSeriesNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package catalog
+
+import (
+	"sort"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// Works returns every work, oldest first
+func (s *Service) Works() ([]models.Work, error) {
+	return s.works.GetWorks()
+}
+
+// Work returns a work by its ID
+func (s *Service) Work(id int) (*models.Work, error) {
+	return s.works.GetWork(id)
+}
+
+// CreateWork adds a work with no editions; editions are linked to it
+// afterwards
+func (s *Service) CreateWork(work models.Work) (*models.Work, error) {
+	if err := work.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	return s.works.CreateWork(models.Work{
+		Title:     work.Title,
+		Author:    work.Author,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+// UpdateWork replaces the title and author of a work
+func (s *Service) UpdateWork(id int, work models.Work) (*models.Work, error) {
+	if err := work.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.works.GetWork(id)
+	if err != nil {
+		return nil, err
+	}
+	existing.Title = work.Title
+	existing.Author = work.Author
+	existing.UpdatedAt = s.now().UTC()
+	return s.works.UpdateWork(*existing)
+}
+
+// DeleteWork removes a work, unlinking its editions first, including any
+// in the trash
+func (s *Service) DeleteWork(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.works.GetWork(id); err != nil {
+		return err
+	}
+	if editions, ok := s.books.(storage.Editions); ok {
+		books, err := s.books.GetAll()
+		if err != nil {
+			return err
+		}
+		if trash, ok := s.books.(storage.Trash); ok {
+			trashed, err := trash.Trashed()
+			if err != nil {
+				return err
+			}
+			books = append(books, trashed...)
+		}
+		for _, book := range books {
+			if book.WorkID != id {
+				continue
+			}
+			if err := editions.SetWork(book.ID, 0); err != nil && err != models.ErrBookNotFound {
+				return err
+			}
+		}
+	}
+	return s.works.DeleteWork(id)
+}
+
+// Editions returns the editions of a work, oldest publication first; those
+// with no publication year come last
+func (s *Service) Editions(id int) ([]models.Book, error) {
+	if _, err := s.works.GetWork(id); err != nil {
+		return nil, err
+	}
+	books, err := s.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	editions := make([]models.Book, 0)
+	for _, book := range books {
+		if book.WorkID == id {
+			editions = append(editions, book)
+		}
+	}
+	sort.SliceStable(editions, func(i, j int) bool {
+		a, b := editions[i].PublishedYear, editions[j].PublishedYear
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
+	return editions, nil
+}
+
+// AddEdition links a book to a work as one of its editions, moving it from
+// any work it was linked to before
+func (s *Service) AddEdition(id, bookID int) (*models.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.works.GetWork(id); err != nil {
+		return nil, err
+	}
+	return s.setWork(bookID, id)
+}
+
+// RemoveEdition unlinks a book from a work
+func (s *Service) RemoveEdition(id, bookID int) (*models.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.works.GetWork(id); err != nil {
+		return nil, err
+	}
+	book, err := s.books.GetByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if book.WorkID != id {
+		return nil, ErrNotAnEdition
+	}
+	return s.setWork(bookID, 0)
+}
+
+// setWork links a book to a work, or unlinks it if workID is 0, and returns
+// the book as it now is; s.mu must be held
+func (s *Service) setWork(bookID, workID int) (*models.Book, error) {
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	if editions, ok := s.books.(storage.Editions); ok {
+		if err := editions.SetWork(bookID, workID); err != nil {
+			return nil, err
+		}
+	}
+	return s.books.GetByID(bookID)
+}
@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestService_Editions(t *testing.T) {
+	s, _ := newTestService(t)
+
+	first, _ := s.books.Create(models.Book{Title: "Don Quixote", Author: "Cervantes", PublishedYear: 2003})
+	second, _ := s.books.Create(models.Book{Title: "Don Quijote", Author: "Cervantes"})
+	third, _ := s.books.Create(models.Book{Title: "Don Quichotte", Author: "Cervantes", PublishedYear: 1998})
+
+	work, err := s.CreateWork(models.Work{Title: "Don Quixote", Author: "Miguel de Cervantes"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	other, _ := s.CreateWork(models.Work{Title: "Exemplary Novels"})
+
+	for _, book := range []*models.Book{first, second, third} {
+		linked, err := s.AddEdition(work.ID, book.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if linked.WorkID != work.ID {
+			t.Errorf("expected book %d to be an edition of work %d, got %d", book.ID, work.ID, linked.WorkID)
+		}
+	}
+
+	// Oldest publication first, unknown years last
+	editions, _ := s.Editions(work.ID)
+	if len(editions) != 3 || editions[0].ID != third.ID || editions[1].ID != first.ID || editions[2].ID != second.ID {
+		t.Errorf("expected editions %d, %d, %d, got %+v", third.ID, first.ID, second.ID, editions)
+	}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{"link unknown book", func() error { _, err := s.AddEdition(work.ID, 999); return err }, models.ErrBookNotFound},
+		{"link to unknown work", func() error { _, err := s.AddEdition(999, first.ID); return err }, models.ErrWorkNotFound},
+		{"unlink from another work", func() error { _, err := s.RemoveEdition(other.ID, first.ID); return err }, ErrNotAnEdition},
+		{"unlink", func() error { _, err := s.RemoveEdition(work.ID, first.ID); return err }, nil},
+		{"unlink again", func() error { _, err := s.RemoveEdition(work.ID, first.ID); return err }, ErrNotAnEdition},
+		{"move to another work", func() error { _, err := s.AddEdition(other.ID, second.ID); return err }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); err != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if editions, _ := s.Editions(work.ID); len(editions) != 1 || editions[0].ID != third.ID {
+		t.Errorf("expected only book %d left, got %+v", third.ID, editions)
+	}
+}
+
+func TestService_DeleteWork(t *testing.T) {
+	s, books := newTestService(t)
+
+	work, _ := s.CreateWork(models.Work{Title: "Earthsea"})
+	s.AddEdition(work.ID, books[0].ID)
+	s.AddEdition(work.ID, books[1].ID)
+	s.books.Delete(books[1].ID)
+
+	if err := s.DeleteWork(work.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := s.Work(work.ID); err != models.ErrWorkNotFound {
+		t.Errorf("expected ErrWorkNotFound, got %v", err)
+	}
+
+	// Editions are unlinked, including those in the trash
+	if book, _ := s.books.GetByID(books[0].ID); book.WorkID != 0 {
+		t.Errorf("expected book %d to be unlinked, got work %d", book.ID, book.WorkID)
+	}
+	restored, _ := s.books.(storage.Trash).Restore(books[1].ID)
+	if restored.WorkID != 0 {
+		t.Errorf("expected the trashed book to be unlinked, got work %d", restored.WorkID)
+	}
+
+	if err := s.DeleteWork(work.ID); err != models.ErrWorkNotFound {
+		t.Errorf("expected ErrWorkNotFound, got %v", err)
+	}
+	if _, err := s.UpdateWork(work.ID, models.Work{Title: "Earthsea"}); err != models.ErrWorkNotFound {
+		t.Errorf("expected ErrWorkNotFound, got %v", err)
+	}
+}
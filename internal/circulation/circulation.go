@@ -0,0 +1,489 @@
+// Package circulation lends the physical copies of books to patrons:
+// checkouts, returns and renewals under per-patron-type loan policies, holds
+// queued for books that are out, fines for overdue loans, and the inventory
+// of copies those operate on.
+package circulation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+var (
+	// ErrBookUnavailable is returned when checking out a book with no copy
+	// available
+	ErrBookUnavailable = errors.New("no copy of the book is available")
+	// ErrCopyUnavailable is returned when checking out a particular copy that
+	// is not available
+	ErrCopyUnavailable = errors.New("copy is not available")
+	// ErrNotOnLoan is returned when returning a book or copy that is not checked out
+	ErrNotOnLoan = errors.New("book is not checked out")
+	// ErrCopyRequired is returned when returning a book with several copies
+	// out without saying which one
+	ErrCopyRequired = errors.New("several copies of the book are checked out; give the copy to return")
+	// ErrCopyOnLoan is returned when deleting a copy, or changing its status,
+	// while it is checked out
+	ErrCopyOnLoan = errors.New("copy is checked out")
+	// ErrCopyOnHold is returned when deleting a copy, or changing its status,
+	// while it is set aside for a hold
+	ErrCopyOnHold = errors.New("copy is set aside for a hold")
+	// ErrLoanReturned is returned when renewing a loan that has ended
+	ErrLoanReturned = errors.New("loan has already been returned")
+	// ErrLoanLimitReached is returned when a patron already has as many books
+	// out as their policy allows
+	ErrLoanLimitReached = errors.New("patron has reached their loan limit")
+	// ErrRenewalLimitReached is returned when a loan has been renewed as many
+	// times as the patron's policy allows
+	ErrRenewalLimitReached = errors.New("loan has reached its renewal limit")
+	// ErrPatronHasLoans is returned when deleting a patron with books out
+	ErrPatronHasLoans = errors.New("patron has books checked out")
+	// ErrPatronHasFines is returned when deleting a patron with unpaid fines
+	ErrPatronHasFines = errors.New("patron has unpaid fines")
+)
+
+// Availability statuses
+const (
+	// StatusAvailable means at least one copy can be borrowed
+	StatusAvailable = "available"
+	// StatusOnLoan means no copy can be borrowed but some will be returned
+	StatusOnLoan = "on_loan"
+	// StatusOnHold means every copy in circulation is set aside for a hold
+	StatusOnHold = "on_hold"
+	// StatusUnavailable means the book has no copies in circulation
+	StatusUnavailable = "unavailable"
+)
+
+// Availability describes whether a book can be borrowed
+type Availability struct {
+	BookID          int        `json:"book_id"`
+	Status          string     `json:"status"`
+	TotalCopies     int        `json:"total_copies"`
+	AvailableCopies int        `json:"available_copies"`
+	NextDueAt       *time.Time `json:"next_due_at,omitempty"`
+	// HoldsWaiting is the number of holds queued for a copy
+	HoldsWaiting int                `json:"holds_waiting"`
+	Copies       []CopyAvailability `json:"copies"`
+}
+
+// CopyAvailability describes the status of one copy of a book
+type CopyAvailability struct {
+	CopyID  int               `json:"copy_id"`
+	Barcode string            `json:"barcode"`
+	Branch  string            `json:"branch"`
+	Status  models.CopyStatus `json:"status"`
+	DueAt   *time.Time        `json:"due_at,omitempty"`
+}
+
+// DefaultHoldPickupWindow is how long a copy stays set aside for a hold when
+// no window is configured
+const DefaultHoldPickupWindow = 7 * 24 * time.Hour
+
+// Notifier is told about circulation events, for example to trigger
+// outgoing webhooks
+type Notifier interface {
+	NotifyHold(typ events.Type, hold models.Hold)
+}
+
+// Service carries out circulation against the book, copy, patron, loan, hold
+// and fine storages
+type Service struct {
+	books    storage.Storage
+	copies   storage.CopyStorage
+	patrons  storage.PatronStorage
+	loans    storage.LoanStorage
+	holds    storage.HoldStorage
+	fines    storage.FineStorage
+	policies Policies
+	// pickupWindow is how long a copy is set aside for a ready hold
+	pickupWindow time.Duration
+	bus          *events.Bus
+	notifier     Notifier
+	// mu serializes writes so a copy cannot be lent twice
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewService creates a circulation service applying policies
+func NewService(books storage.Storage, copies storage.CopyStorage, patrons storage.PatronStorage, loans storage.LoanStorage, holds storage.HoldStorage, fines storage.FineStorage, policies Policies) *Service {
+	return &Service{
+		books:        books,
+		copies:       copies,
+		patrons:      patrons,
+		loans:        loans,
+		holds:        holds,
+		fines:        fines,
+		policies:     policies,
+		pickupWindow: DefaultHoldPickupWindow,
+		now:          time.Now,
+	}
+}
+
+// SetClock replaces the source of the current time, so that due dates, fines
+// and hold expiry can be driven by a simulated clock
+func (s *Service) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// SetEventBus registers a bus to publish circulation events to
+func (s *Service) SetEventBus(bus *events.Bus) {
+	s.bus = bus
+}
+
+// SetNotifier registers n to be told about circulation events
+func (s *Service) SetNotifier(n Notifier) {
+	s.notifier = n
+}
+
+// SetHoldPickupWindow sets how long a copy stays set aside for a ready hold
+// before the hold expires
+func (s *Service) SetHoldPickupWindow(d time.Duration) {
+	if d > 0 {
+		s.pickupWindow = d
+	}
+}
+
+// Run carries out the scheduled circulation work once per interval until
+// ctx is cancelled: marking overdue loans and accruing their fines, and
+// expiring holds that were not picked up. It does nothing for an interval
+// of zero or less
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.ScanOverdue(); err != nil {
+			logger.Error.Printf("Failed to scan for overdue loans: %v", err)
+		} else if n > 0 {
+			logger.Info.Printf("Marked %d loans overdue", n)
+		}
+		if n, err := s.ExpireHolds(); err != nil {
+			logger.Error.Printf("Failed to expire holds: %v", err)
+		} else if n > 0 {
+			logger.Info.Printf("Expired %d holds", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Policies returns the loan policies in force
+func (s *Service) Policies() Policies {
+	return s.policies
+}
+
+// Loans returns the loans matching the filter, most recent first
+func (s *Service) Loans(filter models.LoanFilter) ([]models.Loan, error) {
+	return s.loans.GetLoans(filter)
+}
+
+// PatronLoans returns a patron's loans, most recent first, leaving out
+// returned loans if activeOnly is set
+func (s *Service) PatronLoans(patronID int, activeOnly bool) ([]models.Loan, error) {
+	if _, err := s.patrons.GetPatron(patronID); err != nil {
+		return nil, err
+	}
+	return s.loans.GetLoans(models.LoanFilter{PatronID: patronID, ActiveOnly: activeOnly})
+}
+
+// Loan returns a loan by its ID
+func (s *Service) Loan(id int) (*models.Loan, error) {
+	return s.loans.GetLoan(id)
+}
+
+// Checkout lends a copy of a book to a patron, due after their policy's loan
+// period. A copyID of 0 lends the copy set aside for the patron's ready hold,
+// if there is one, and otherwise the first available copy. Any hold the
+// patron has on the book is fulfilled
+func (s *Service) Checkout(bookID, copyID, patronID int) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	patron, err := s.patrons.GetPatron(patronID)
+	if err != nil {
+		return nil, err
+	}
+	hold, err := s.patronHold(bookID, patronID)
+	if err != nil {
+		return nil, err
+	}
+	var item *models.Copy
+	if hold != nil && hold.Status == models.HoldReady && (copyID == 0 || copyID == hold.CopyID) {
+		item, err = s.copies.GetCopy(hold.CopyID)
+	} else {
+		item, err = s.lendableCopy(bookID, copyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	policy := s.policies.For(patron.Type)
+	borrowed, err := s.loans.GetLoans(models.LoanFilter{PatronID: patronID, ActiveOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(borrowed) >= policy.MaxLoans {
+		return nil, ErrLoanLimitReached
+	}
+
+	now := s.now().UTC()
+	loan, err := s.loans.CreateLoan(models.Loan{
+		BookID:       bookID,
+		CopyID:       item.ID,
+		PatronID:     patronID,
+		CheckedOutAt: now,
+		DueAt:        now.AddDate(0, 0, policy.LoanDays),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setCopyStatus(*item, models.CopyOnLoan); err != nil {
+		return nil, err
+	}
+	if hold != nil {
+		if err := s.closeHold(*hold, models.HoldFulfilled); err != nil {
+			return nil, err
+		}
+		// A different copy was lent, so the one set aside can go to the next
+		// patron in the queue
+		if hold.CopyID != item.ID {
+			if err := s.releaseHeldCopy(*hold); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return loan, nil
+}
+
+// lendableCopy picks the copy to lend; s.mu must be held
+func (s *Service) lendableCopy(bookID, copyID int) (*models.Copy, error) {
+	if copyID != 0 {
+		item, err := s.copies.GetCopy(copyID)
+		if err != nil {
+			return nil, err
+		}
+		if item.BookID != bookID {
+			return nil, models.ErrCopyNotFound
+		}
+		if item.Status != models.CopyAvailable {
+			return nil, ErrCopyUnavailable
+		}
+		return item, nil
+	}
+
+	copies, err := s.copies.GetCopies(bookID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range copies {
+		if item.Status == models.CopyAvailable {
+			return &item, nil
+		}
+	}
+	return nil, ErrBookUnavailable
+}
+
+// Return ends the active loan of a copy of a book, setting the copy aside for
+// the next hold in the queue if there is one. A late return is fined for
+// every day it was overdue. A copyID of 0 returns the book's only copy on loan
+func (s *Service) Return(bookID, copyID int) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out, err := s.loans.GetLoans(models.LoanFilter{BookID: bookID, CopyID: copyID, ActiveOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(out) == 0:
+		return nil, ErrNotOnLoan
+	case len(out) > 1:
+		return nil, ErrCopyRequired
+	}
+
+	loan := out[0]
+	now := s.now().UTC()
+	loan.ReturnedAt = &now
+	if now.After(loan.DueAt) {
+		loan.Overdue = true
+	}
+	returned, err := s.loans.UpdateLoan(loan)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assess(*returned, now); err != nil {
+		return nil, err
+	}
+	if item, err := s.copies.GetCopy(loan.CopyID); err == nil && item.Status == models.CopyOnLoan {
+		if err := s.release(*item); err != nil {
+			return nil, err
+		}
+	}
+	return returned, nil
+}
+
+// setCopyStatus saves a copy with a new status; s.mu must be held
+func (s *Service) setCopyStatus(item models.Copy, status models.CopyStatus) error {
+	item.Status = status
+	item.UpdatedAt = s.now().UTC()
+	_, err := s.copies.UpdateCopy(item)
+	return err
+}
+
+// Renew extends an active loan by another loan period from now, unless the
+// patron's policy renewal limit has been reached
+func (s *Service) Renew(loanID int) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loan, err := s.loans.GetLoan(loanID)
+	if err != nil {
+		return nil, err
+	}
+	if !loan.Active() {
+		return nil, ErrLoanReturned
+	}
+
+	policy := s.policies.For(models.PatronAdult)
+	if patron, err := s.patrons.GetPatron(loan.PatronID); err == nil {
+		policy = s.policies.For(patron.Type)
+	}
+	if loan.Renewals >= policy.MaxRenewals {
+		return nil, ErrRenewalLimitReached
+	}
+
+	// Renewing early never shortens the loan; fines already accrued stay,
+	// and the days overdue so far keep counting towards the fine
+	now := s.now().UTC()
+	if now.After(loan.DueAt) {
+		if err := s.assess(*loan, now); err != nil {
+			return nil, err
+		}
+		loan.OverdueDays += int(now.Sub(loan.DueAt) / (24 * time.Hour))
+	}
+	due := now.AddDate(0, 0, policy.LoanDays)
+	if due.After(loan.DueAt) {
+		loan.DueAt = due
+	}
+	if loan.DueAt.After(now) {
+		loan.Overdue = false
+	}
+	loan.Renewals++
+	return s.loans.UpdateLoan(*loan)
+}
+
+// Availability reports how many copies of a book can be borrowed, when those
+// on loan are due back and how many holds are waiting for them
+func (s *Service) Availability(bookID int) (*Availability, error) {
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+
+	copies, err := s.copies.GetCopies(bookID)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.loans.GetLoans(models.LoanFilter{BookID: bookID, ActiveOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	waiting, err := s.holds.GetHolds(models.HoldFilter{BookID: bookID, Statuses: []models.HoldStatus{models.HoldWaiting}})
+	if err != nil {
+		return nil, err
+	}
+	dueAt := make(map[int]time.Time, len(out))
+	for _, loan := range out {
+		dueAt[loan.CopyID] = loan.DueAt
+	}
+
+	availability := &Availability{
+		BookID:       bookID,
+		Status:       StatusUnavailable,
+		TotalCopies:  len(copies),
+		HoldsWaiting: len(waiting),
+		Copies:       make([]CopyAvailability, 0, len(copies)),
+	}
+	held := 0
+	for _, item := range copies {
+		entry := CopyAvailability{
+			CopyID:  item.ID,
+			Barcode: item.Barcode,
+			Branch:  item.Branch,
+			Status:  item.Status,
+		}
+		if due, ok := dueAt[item.ID]; ok {
+			entry.DueAt = &due
+			if availability.NextDueAt == nil || due.Before(*availability.NextDueAt) {
+				availability.NextDueAt = &due
+			}
+		}
+		switch item.Status {
+		case models.CopyAvailable:
+			availability.AvailableCopies++
+		case models.CopyOnHold:
+			held++
+		}
+		availability.Copies = append(availability.Copies, entry)
+	}
+
+	switch {
+	case availability.AvailableCopies > 0:
+		availability.Status = StatusAvailable
+	case availability.NextDueAt != nil:
+		availability.Status = StatusOnLoan
+	case held > 0:
+		availability.Status = StatusOnHold
+	}
+	return availability, nil
+}
+
+// DeletePatron removes a patron who has no books checked out and no unpaid
+// fines, cancelling their holds
+func (s *Service) DeletePatron(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out, err := s.loans.GetLoans(models.LoanFilter{PatronID: id, ActiveOnly: true})
+	if err != nil {
+		return err
+	}
+	if len(out) > 0 {
+		return ErrPatronHasLoans
+	}
+	unpaid, err := s.fines.GetFines(models.FineFilter{PatronID: id, OpenOnly: true})
+	if err != nil {
+		return err
+	}
+	if len(unpaid) > 0 {
+		return ErrPatronHasFines
+	}
+	if err := s.patrons.DeletePatron(id); err != nil {
+		return err
+	}
+	holds, err := s.holds.GetHolds(models.HoldFilter{PatronID: id, Statuses: activeHoldStatuses})
+	if err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		if err := s.cancel(hold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
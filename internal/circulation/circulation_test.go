@@ -0,0 +1,127 @@
+package circulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newTestService returns a service with one book with a single copy, an
+// adult and a child, and a clock the test can move
+func newTestService(t *testing.T) (*Service, *models.Book, *models.Patron, *models.Patron, *time.Time) {
+	t.Helper()
+	books := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	adult, _ := patrons.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	child, _ := patrons.CreatePatron(models.Patron{Name: "Kit", Type: models.PatronChild})
+
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	s := NewService(books, storage.NewMemoryCopyStorage(), patrons, storage.NewMemoryLoanStorage(), storage.NewMemoryHoldStorage(), storage.NewMemoryFineStorage(), DefaultPolicies())
+	s.now = func() time.Time { return now }
+	if _, err := s.AddCopy(book.ID, models.Copy{Barcode: "CC-1", Branch: "Main"}); err != nil {
+		t.Fatalf("failed to add copy: %v", err)
+	}
+	return s, book, adult, child, &now
+}
+
+func TestService_CheckoutAndReturn(t *testing.T) {
+	s, book, adult, child, now := newTestService(t)
+
+	loan, err := s.Checkout(book.ID, 0, child.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := now.AddDate(0, 0, 14); !loan.DueAt.Equal(want) {
+		t.Errorf("expected child loan due %v, got %v", want, loan.DueAt)
+	}
+
+	if _, err := s.Checkout(book.ID, 0, adult.ID); err != ErrBookUnavailable {
+		t.Errorf("expected ErrBookUnavailable, got %v", err)
+	}
+	availability, _ := s.Availability(book.ID)
+	if availability.Status != StatusOnLoan || availability.NextDueAt == nil || availability.AvailableCopies != 0 {
+		t.Errorf("expected book on loan with a due date, got %+v", availability)
+	}
+
+	returned, err := s.Return(book.ID, 0)
+	if err != nil || returned.Active() {
+		t.Fatalf("expected loan returned, got %+v (%v)", returned, err)
+	}
+	if _, err := s.Return(book.ID, 0); err != ErrNotOnLoan {
+		t.Errorf("expected ErrNotOnLoan, got %v", err)
+	}
+	availability, _ = s.Availability(book.ID)
+	if availability.Status != StatusAvailable {
+		t.Errorf("expected book available, got %+v", availability)
+	}
+
+	if _, err := s.Checkout(999, 0, adult.ID); err != models.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+	if _, err := s.Checkout(book.ID, 0, 999); err != models.ErrPatronNotFound {
+		t.Errorf("expected ErrPatronNotFound, got %v", err)
+	}
+}
+
+func TestService_LoanLimit(t *testing.T) {
+	s, _, _, child, _ := newTestService(t)
+	s.policies[models.PatronChild] = Policy{LoanDays: 14, MaxRenewals: 1, MaxLoans: 1}
+
+	first, _ := s.books.Create(models.Book{Title: "One", Author: "Author"})
+	second, _ := s.books.Create(models.Book{Title: "Two", Author: "Author"})
+	s.AddCopy(first.ID, models.Copy{Barcode: "ONE-1", Branch: "Main"})
+	s.AddCopy(second.ID, models.Copy{Barcode: "TWO-1", Branch: "Main"})
+
+	if _, err := s.Checkout(first.ID, 0, child.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := s.Checkout(second.ID, 0, child.ID); err != ErrLoanLimitReached {
+		t.Errorf("expected ErrLoanLimitReached, got %v", err)
+	}
+	if err := s.DeletePatron(child.ID); err != ErrPatronHasLoans {
+		t.Errorf("expected ErrPatronHasLoans, got %v", err)
+	}
+}
+
+func TestService_Renew(t *testing.T) {
+	s, book, _, child, now := newTestService(t)
+
+	loan, _ := s.Checkout(book.ID, 0, child.ID)
+
+	*now = now.AddDate(0, 0, 10)
+	renewed, err := s.Renew(loan.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := now.AddDate(0, 0, 14); !renewed.DueAt.Equal(want) || renewed.Renewals != 1 {
+		t.Errorf("expected first renewal due %v, got %+v", want, renewed)
+	}
+
+	if _, err := s.Renew(loan.ID); err != ErrRenewalLimitReached {
+		t.Errorf("expected ErrRenewalLimitReached, got %v", err)
+	}
+
+	s.Return(book.ID, 0)
+	if _, err := s.Renew(loan.ID); err != ErrLoanReturned {
+		t.Errorf("expected ErrLoanReturned, got %v", err)
+	}
+}
+
+func TestService_RunWithoutInterval(t *testing.T) {
+	s, _, _, _, _ := newTestService(t)
+	done := make(chan struct{})
+	go func() {
+		s.Run(context.Background(), 0)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return without an interval")
+	}
+}
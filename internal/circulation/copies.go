@@ -0,0 +1,128 @@
+package circulation
+
+import (
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// Copies returns the copies of a book, oldest first
+func (s *Service) Copies(bookID int) ([]models.Copy, error) {
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	return s.copies.GetCopies(bookID)
+}
+
+// Copy returns a copy of a book by its ID
+func (s *Service) Copy(bookID, copyID int) (*models.Copy, error) {
+	item, err := s.copies.GetCopy(copyID)
+	if err != nil {
+		return nil, err
+	}
+	if item.BookID != bookID {
+		return nil, models.ErrCopyNotFound
+	}
+	return item, nil
+}
+
+// CopyByBarcode returns a copy by its barcode
+func (s *Service) CopyByBarcode(barcode string) (*models.Copy, error) {
+	return s.copies.GetCopyByBarcode(barcode)
+}
+
+// AddCopy adds a copy of a book to the inventory. Copies cannot be added
+// already on loan or on hold, and an available copy goes to the first hold
+// waiting for the book
+func (s *Service) AddCopy(bookID int, item models.Copy) (*models.Copy, error) {
+	if err := item.Validate(); err != nil {
+		return nil, err
+	}
+	if item.Status == models.CopyOnLoan || item.Status == models.CopyOnHold {
+		return nil, models.ErrInvalidCopyStatus
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	item.BookID = bookID
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	created, err := s.copies.CreateCopy(item)
+	if err != nil {
+		return nil, err
+	}
+	if created.Status != models.CopyAvailable {
+		return created, nil
+	}
+	if err := s.release(*created); err != nil {
+		return nil, err
+	}
+	return s.copies.GetCopy(created.ID)
+}
+
+// UpdateCopy replaces a copy's barcode, branch, condition and status, keeping
+// the status if none is given. Only circulation moves a copy onto or off loan
+// or hold, so the status of a copy on loan or on hold cannot be changed and no
+// copy can be put on loan or on hold here. A copy made available goes to the
+// first hold waiting for the book
+func (s *Service) UpdateCopy(bookID, copyID int, item models.Copy) (*models.Copy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.Copy(bookID, copyID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Status == "" {
+		item.Status = existing.Status
+	}
+	if err := item.Validate(); err != nil {
+		return nil, err
+	}
+	if existing.Status == models.CopyOnLoan && item.Status != models.CopyOnLoan {
+		return nil, ErrCopyOnLoan
+	}
+	if existing.Status == models.CopyOnHold && item.Status != models.CopyOnHold {
+		return nil, ErrCopyOnHold
+	}
+	if existing.Status != item.Status && (item.Status == models.CopyOnLoan || item.Status == models.CopyOnHold) {
+		return nil, models.ErrInvalidCopyStatus
+	}
+
+	item.ID = existing.ID
+	item.BookID = existing.BookID
+	item.CreatedAt = existing.CreatedAt
+	item.UpdatedAt = s.now().UTC()
+	updated, err := s.copies.UpdateCopy(item)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == models.CopyAvailable || updated.Status != models.CopyAvailable {
+		return updated, nil
+	}
+	if err := s.release(*updated); err != nil {
+		return nil, err
+	}
+	return s.copies.GetCopy(updated.ID)
+}
+
+// DeleteCopy removes a copy that is not on loan or on hold from the inventory
+func (s *Service) DeleteCopy(bookID, copyID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.Copy(bookID, copyID)
+	if err != nil {
+		return err
+	}
+	switch item.Status {
+	case models.CopyOnLoan:
+		return ErrCopyOnLoan
+	case models.CopyOnHold:
+		return ErrCopyOnHold
+	}
+	return s.copies.DeleteCopy(copyID)
+}
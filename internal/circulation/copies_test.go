@@ -0,0 +1,75 @@
+package circulation
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestService_Copies(t *testing.T) {
+	s, book, adult, child, _ := newTestService(t)
+
+	second, err := s.AddCopy(book.ID, models.Copy{Barcode: "CC-2", Branch: "East", Condition: models.ConditionFair})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := s.AddCopy(book.ID, models.Copy{Barcode: "CC-2", Branch: "West"}); err != models.ErrDuplicateBarcode {
+		t.Errorf("expected ErrDuplicateBarcode, got %v", err)
+	}
+	if _, err := s.AddCopy(book.ID, models.Copy{Barcode: "CC-3", Branch: "West", Status: models.CopyOnLoan}); err != models.ErrInvalidCopyStatus {
+		t.Errorf("expected ErrInvalidCopyStatus, got %v", err)
+	}
+	if _, err := s.AddCopy(999, models.Copy{Barcode: "CC-3", Branch: "West"}); err != models.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+
+	// Both copies can be lent at once, choosing one by ID
+	if _, err := s.Checkout(book.ID, second.ID, adult.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := s.Checkout(book.ID, second.ID, child.ID); err != ErrCopyUnavailable {
+		t.Errorf("expected ErrCopyUnavailable, got %v", err)
+	}
+	if _, err := s.Checkout(book.ID, 0, child.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := s.Checkout(book.ID, 0, child.ID); err != ErrBookUnavailable {
+		t.Errorf("expected ErrBookUnavailable, got %v", err)
+	}
+
+	availability, _ := s.Availability(book.ID)
+	if availability.TotalCopies != 2 || availability.AvailableCopies != 0 || availability.Status != StatusOnLoan {
+		t.Errorf("expected both copies on loan, got %+v", availability)
+	}
+
+	// With two copies out, the copy to return must be named
+	if _, err := s.Return(book.ID, 0); err != ErrCopyRequired {
+		t.Errorf("expected ErrCopyRequired, got %v", err)
+	}
+	if err := s.DeleteCopy(book.ID, second.ID); err != ErrCopyOnLoan {
+		t.Errorf("expected ErrCopyOnLoan, got %v", err)
+	}
+	if _, err := s.UpdateCopy(book.ID, second.ID, models.Copy{Barcode: "CC-2", Branch: "East", Status: models.CopyLost}); err != ErrCopyOnLoan {
+		t.Errorf("expected ErrCopyOnLoan, got %v", err)
+	}
+	if _, err := s.Return(book.ID, second.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Status is kept when omitted, and copies out of circulation are not lent
+	updated, err := s.UpdateCopy(book.ID, second.ID, models.Copy{Barcode: "CC-2", Branch: "West"})
+	if err != nil || updated.Status != models.CopyAvailable || updated.Branch != "West" {
+		t.Errorf("expected available copy moved to West, got %+v (%v)", updated, err)
+	}
+	s.UpdateCopy(book.ID, second.ID, models.Copy{Barcode: "CC-2", Branch: "West", Status: models.CopyInRepair})
+	if _, err := s.Checkout(book.ID, 0, child.ID); err != ErrBookUnavailable {
+		t.Errorf("expected ErrBookUnavailable, got %v", err)
+	}
+
+	if err := s.DeleteCopy(book.ID, second.ID); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if _, err := s.Copy(book.ID, second.ID); err != models.ErrCopyNotFound {
+		t.Errorf("expected ErrCopyNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package circulation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+var (
+	// ErrInvalidAmount is returned when a payment or waiver is not a positive
+	// number of cents
+	ErrInvalidAmount = errors.New("amount_cents must be a positive number of cents")
+	// ErrAmountExceedsBalance is returned when a payment or waiver is more
+	// than the fine's balance
+	ErrAmountExceedsBalance = errors.New("amount exceeds the fine's balance")
+)
+
+// FineSummary lists a patron's fines with the total left to pay
+type FineSummary struct {
+	PatronID     int           `json:"patron_id"`
+	BalanceCents int           `json:"balance_cents"`
+	Fines        []models.Fine `json:"fines"`
+}
+
+// ScanOverdue marks the active loans past their due date as overdue and
+// brings their fines up to date. It returns the number of loans newly found
+// overdue
+func (s *Service) ScanOverdue() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out, err := s.loans.GetLoans(models.LoanFilter{ActiveOnly: true})
+	if err != nil {
+		return 0, err
+	}
+	now := s.now().UTC()
+	marked := 0
+	for _, loan := range out {
+		if !now.After(loan.DueAt) {
+			continue
+		}
+		if !loan.Overdue {
+			loan.Overdue = true
+			if _, err := s.loans.UpdateLoan(loan); err != nil {
+				return marked, err
+			}
+			marked++
+		}
+		if err := s.assess(loan, now); err != nil {
+			return marked, err
+		}
+	}
+	return marked, nil
+}
+
+// PatronFines returns a patron's fines, oldest first, leaving out settled
+// fines if openOnly is set
+func (s *Service) PatronFines(patronID int, openOnly bool) (*FineSummary, error) {
+	if _, err := s.patrons.GetPatron(patronID); err != nil {
+		return nil, err
+	}
+	fines, err := s.fines.GetFines(models.FineFilter{PatronID: patronID, OpenOnly: openOnly})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &FineSummary{PatronID: patronID, Fines: fines}
+	for _, fine := range fines {
+		summary.BalanceCents += fine.BalanceCents
+	}
+	return summary, nil
+}
+
+// Fine returns a fine by its ID
+func (s *Service) Fine(id int) (*models.Fine, error) {
+	return s.fines.GetFine(id)
+}
+
+// PayFine records a payment of amount cents towards a fine
+func (s *Service) PayFine(id, amount int, note string) (*models.Fine, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	return s.settle(id, models.FinePayment, amount, note)
+}
+
+// WaiveFine forgives amount cents of a fine, or its whole balance if amount
+// is 0
+func (s *Service) WaiveFine(id, amount int, note string) (*models.Fine, error) {
+	if amount < 0 {
+		return nil, ErrInvalidAmount
+	}
+	return s.settle(id, models.FineWaiver, amount, note)
+}
+
+// settle applies a payment or waiver to a fine; an amount of 0 covers the
+// whole balance
+func (s *Service) settle(id int, typ models.FineTransactionType, amount int, note string) (*models.Fine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fine, err := s.fines.GetFine(id)
+	if err != nil {
+		return nil, err
+	}
+	if amount == 0 {
+		amount = fine.BalanceCents
+	}
+	if amount <= 0 || amount > fine.BalanceCents {
+		return nil, ErrAmountExceedsBalance
+	}
+
+	now := s.now().UTC()
+	if typ == models.FinePayment {
+		fine.PaidCents += amount
+	} else {
+		fine.WaivedCents += amount
+	}
+	fine.Transactions = append(fine.Transactions, models.FineTransaction{
+		Type:        typ,
+		AmountCents: amount,
+		Note:        note,
+		Time:        now,
+	})
+	fine.UpdatedAt = now
+	fine.Reconcile()
+	return s.fines.UpdateFine(*fine)
+}
+
+// assess brings the fine for a loan up to date as of end, creating it once
+// the loan is fined at all. The days overdue before renewals are added to
+// those since the current due date, so a renewed loan keeps accruing from
+// where it left off; s.mu must be held
+func (s *Service) assess(loan models.Loan, end time.Time) error {
+	overdue := time.Duration(loan.OverdueDays) * 24 * time.Hour
+	if end.After(loan.DueAt) {
+		overdue += end.Sub(loan.DueAt)
+	}
+	if overdue == 0 {
+		return nil
+	}
+	policy := s.policies.For(models.PatronAdult)
+	if patron, err := s.patrons.GetPatron(loan.PatronID); err == nil {
+		policy = s.policies.For(patron.Type)
+	}
+	amount := policy.Fine(overdue)
+
+	existing, err := s.fines.GetFines(models.FineFilter{LoanID: loan.ID})
+	if err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	if len(existing) == 0 {
+		if amount == 0 {
+			return nil
+		}
+		fine := models.Fine{
+			PatronID:    loan.PatronID,
+			LoanID:      loan.ID,
+			BookID:      loan.BookID,
+			AmountCents: amount,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		fine.Reconcile()
+		_, err := s.fines.CreateFine(fine)
+		return err
+	}
+
+	fine := existing[0]
+	if amount <= fine.AmountCents {
+		return nil
+	}
+	fine.AmountCents = amount
+	fine.UpdatedAt = now
+	fine.Reconcile()
+	_, err = s.fines.UpdateFine(fine)
+	return err
+}
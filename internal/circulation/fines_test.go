@@ -0,0 +1,183 @@
+package circulation
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestService_ScanOverdue(t *testing.T) {
+	s, book, adult, _, now := newTestService(t)
+
+	loan, err := s.Checkout(book.ID, 0, adult.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Adults have 21 days, a day's grace and then 25 cents a day up to $10
+	*now = now.AddDate(0, 0, 21)
+	if n, _ := s.ScanOverdue(); n != 0 {
+		t.Errorf("expected no loans overdue on the due date, got %d", n)
+	}
+
+	*now = now.AddDate(0, 0, 1)
+	if n, _ := s.ScanOverdue(); n != 1 {
+		t.Errorf("expected one loan overdue, got %d", n)
+	}
+	if overdue, _ := s.Loan(loan.ID); !overdue.Overdue {
+		t.Errorf("expected loan marked overdue")
+	}
+	if summary, _ := s.PatronFines(adult.ID, false); len(summary.Fines) != 0 {
+		t.Errorf("expected no fine within the grace period, got %+v", summary.Fines)
+	}
+
+	*now = now.AddDate(0, 0, 3)
+	if n, _ := s.ScanOverdue(); n != 0 {
+		t.Errorf("expected the loan to be counted only once, got %d", n)
+	}
+	summary, _ := s.PatronFines(adult.ID, false)
+	if len(summary.Fines) != 1 || summary.Fines[0].AmountCents != 75 || summary.BalanceCents != 75 {
+		t.Fatalf("expected a 75 cent fine, got %+v", summary)
+	}
+
+	*now = now.AddDate(0, 0, 100)
+	s.ScanOverdue()
+	if fine, _ := s.Fine(summary.Fines[0].ID); fine.AmountCents != 1000 || fine.LoanID != loan.ID {
+		t.Errorf("expected the fine capped at 1000 cents, got %+v", fine)
+	}
+
+	if _, err := s.Return(book.ID, 0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	*now = now.AddDate(0, 0, 10)
+	s.ScanOverdue()
+	if summary, _ := s.PatronFines(adult.ID, true); summary.BalanceCents != 1000 {
+		t.Errorf("expected the fine to stop growing after the return, got %d", summary.BalanceCents)
+	}
+}
+
+func TestService_LateReturnIsFined(t *testing.T) {
+	s, book, _, child, now := newTestService(t)
+
+	s.Checkout(book.ID, 0, child.ID)
+	// Children have 14 days, three days' grace and then 10 cents a day
+	*now = now.AddDate(0, 0, 14+5)
+	returned, err := s.Return(book.ID, 0)
+	if err != nil || !returned.Overdue {
+		t.Fatalf("expected an overdue return, got %+v (%v)", returned, err)
+	}
+	summary, _ := s.PatronFines(child.ID, false)
+	if summary.BalanceCents != 20 {
+		t.Errorf("expected a 20 cent fine without a scan, got %+v", summary)
+	}
+}
+
+func TestService_RenewClearsOverdue(t *testing.T) {
+	s, book, adult, _, now := newTestService(t)
+
+	loan, _ := s.Checkout(book.ID, 0, adult.ID)
+	*now = now.AddDate(0, 0, 25)
+	s.ScanOverdue()
+
+	renewed, err := s.Renew(loan.ID)
+	if err != nil || renewed.Overdue {
+		t.Fatalf("expected renewal to clear overdue, got %+v (%v)", renewed, err)
+	}
+	if summary, _ := s.PatronFines(adult.ID, false); summary.BalanceCents != 75 {
+		t.Errorf("expected accrued fine kept after renewal, got %d", summary.BalanceCents)
+	}
+}
+
+func TestService_RenewAfterOverdueKeepsAccruing(t *testing.T) {
+	s, book, adult, _, now := newTestService(t)
+
+	loan, _ := s.Checkout(book.ID, 0, adult.ID)
+	// Five days overdue, renewed without a scan in between
+	*now = now.AddDate(0, 0, 21+5)
+	renewed, err := s.Renew(loan.ID)
+	if err != nil || renewed.OverdueDays != 5 {
+		t.Fatalf("expected 5 days overdue recorded, got %+v (%v)", renewed, err)
+	}
+	summary, _ := s.PatronFines(adult.ID, false)
+	if summary.BalanceCents != 100 {
+		t.Fatalf("expected a 100 cent fine at renewal, got %+v", summary)
+	}
+
+	// Within the new loan period the fine stays as it was
+	*now = now.AddDate(0, 0, 21)
+	s.ScanOverdue()
+	if summary, _ := s.PatronFines(adult.ID, false); summary.BalanceCents != 100 {
+		t.Errorf("expected the fine unchanged until the new due date, got %d", summary.BalanceCents)
+	}
+
+	// Three more days overdue count on top of the first five
+	*now = now.AddDate(0, 0, 3)
+	s.ScanOverdue()
+	if summary, _ := s.PatronFines(adult.ID, false); summary.BalanceCents != 175 {
+		t.Errorf("expected 8 days overdue fined 175 cents, got %d", summary.BalanceCents)
+	}
+	s.Return(book.ID, 0)
+	if summary, _ := s.PatronFines(adult.ID, false); len(summary.Fines) != 1 || summary.BalanceCents != 175 {
+		t.Errorf("expected one 175 cent fine after the return, got %+v", summary)
+	}
+}
+
+func TestService_PayAndWaiveFine(t *testing.T) {
+	s, book, adult, _, now := newTestService(t)
+
+	s.Checkout(book.ID, 0, adult.ID)
+	*now = now.AddDate(0, 0, 25)
+	s.Return(book.ID, 0)
+	summary, _ := s.PatronFines(adult.ID, false)
+	id := summary.Fines[0].ID
+
+	if err := s.DeletePatron(adult.ID); err != ErrPatronHasFines {
+		t.Errorf("expected ErrPatronHasFines, got %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		settle          func() (*models.Fine, error)
+		wantErr         error
+		expectedBalance int
+	}{
+		{"zero payment", func() (*models.Fine, error) { return s.PayFine(id, 0, "") }, ErrInvalidAmount, 75},
+		{"overpayment", func() (*models.Fine, error) { return s.PayFine(id, 100, "") }, ErrAmountExceedsBalance, 75},
+		{"part payment", func() (*models.Fine, error) { return s.PayFine(id, 50, "cash") }, nil, 25},
+		{"waive the rest", func() (*models.Fine, error) { return s.WaiveFine(id, 0, "first offence") }, nil, 0},
+		{"waive a settled fine", func() (*models.Fine, error) { return s.WaiveFine(id, 0, "") }, ErrAmountExceedsBalance, 0},
+		{"unknown fine", func() (*models.Fine, error) { return s.PayFine(999, 10, "") }, models.ErrFineNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fine, err := tt.settle()
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && fine.BalanceCents != tt.expectedBalance {
+				t.Errorf("expected balance %d, got %d", tt.expectedBalance, fine.BalanceCents)
+			}
+		})
+	}
+
+	fine, _ := s.Fine(id)
+	if fine.Status != models.FinePaid || len(fine.Transactions) != 2 || fine.Transactions[1].Note != "first offence" {
+		t.Errorf("expected a paid fine with a payment and a waiver, got %+v", fine)
+	}
+	if err := s.DeletePatron(adult.ID); err != nil {
+		t.Errorf("expected patron deleted once the fine is settled, got %v", err)
+	}
+}
+
+func TestService_StaffAreNotFined(t *testing.T) {
+	s, book, _, _, now := newTestService(t)
+	staff, _ := s.patrons.CreatePatron(models.Patron{Name: "Sam", Type: models.PatronStaff})
+
+	s.Checkout(book.ID, 0, staff.ID)
+	*now = now.AddDate(0, 0, 100)
+	s.ScanOverdue()
+	if summary, _ := s.PatronFines(staff.ID, false); len(summary.Fines) != 0 {
+		t.Errorf("expected no fines for staff, got %+v", summary.Fines)
+	}
+}
@@ -0,0 +1,292 @@
+package circulation
+
+import (
+	"errors"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+var (
+	// ErrDuplicateHold is returned when a patron places a second hold on a book
+	ErrDuplicateHold = errors.New("patron already has a hold on the book")
+	// ErrAlreadyBorrowed is returned when a patron places a hold on a book
+	// they have checked out
+	ErrAlreadyBorrowed = errors.New("patron already has the book checked out")
+	// ErrHoldClosed is returned when cancelling a hold that is no longer active
+	ErrHoldClosed = errors.New("hold is no longer active")
+)
+
+// activeHoldStatuses are the statuses of holds still in a book's queue
+var activeHoldStatuses = []models.HoldStatus{models.HoldWaiting, models.HoldReady}
+
+// PlaceHold queues a patron for the next copy of a book. If a copy is
+// available it is set aside for the patron straight away
+func (s *Service) PlaceHold(bookID, patronID int) (*models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	if _, err := s.patrons.GetPatron(patronID); err != nil {
+		return nil, err
+	}
+	existing, err := s.patronHold(bookID, patronID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrDuplicateHold
+	}
+	out, err := s.loans.GetLoans(models.LoanFilter{BookID: bookID, PatronID: patronID, ActiveOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > 0 {
+		return nil, ErrAlreadyBorrowed
+	}
+
+	now := s.now().UTC()
+	hold, err := s.holds.CreateHold(models.Hold{
+		BookID:    bookID,
+		PatronID:  patronID,
+		Status:    models.HoldWaiting,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.lendableCopy(bookID, 0)
+	switch err {
+	case nil:
+		if err := s.release(*item); err != nil {
+			return nil, err
+		}
+	case ErrBookUnavailable:
+	default:
+		return nil, err
+	}
+	return s.hold(hold.ID)
+}
+
+// Hold returns a hold by its ID
+func (s *Service) Hold(id int) (*models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.hold(id)
+}
+
+// BookHolds returns the queue of active holds on a book, oldest first
+func (s *Service) BookHolds(bookID int) ([]models.Hold, error) {
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	holds, err := s.holds.GetHolds(models.HoldFilter{BookID: bookID, Statuses: activeHoldStatuses})
+	if err != nil {
+		return nil, err
+	}
+	position := 0
+	for i := range holds {
+		if holds[i].Status == models.HoldWaiting {
+			position++
+			holds[i].Position = position
+		}
+	}
+	return holds, nil
+}
+
+// PatronHolds returns a patron's holds, oldest first, leaving out holds that
+// have ended if activeOnly is set
+func (s *Service) PatronHolds(patronID int, activeOnly bool) ([]models.Hold, error) {
+	if _, err := s.patrons.GetPatron(patronID); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filter := models.HoldFilter{PatronID: patronID}
+	if activeOnly {
+		filter.Statuses = activeHoldStatuses
+	}
+	holds, err := s.holds.GetHolds(filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holds {
+		if err := s.setPosition(&holds[i]); err != nil {
+			return nil, err
+		}
+	}
+	return holds, nil
+}
+
+// CancelHold withdraws an active hold. A copy set aside for it passes to the
+// next hold in the queue
+func (s *Service) CancelHold(id int) (*models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hold, err := s.holds.GetHold(id)
+	if err != nil {
+		return nil, err
+	}
+	if !hold.Active() {
+		return nil, ErrHoldClosed
+	}
+	if err := s.cancel(*hold); err != nil {
+		return nil, err
+	}
+	return s.hold(id)
+}
+
+// ExpireHolds ends the ready holds whose pickup window has passed, passing
+// their copies to the next holds in the queue. It returns the number of
+// holds expired
+func (s *Service) ExpireHolds() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ready, err := s.holds.GetHolds(models.HoldFilter{Statuses: []models.HoldStatus{models.HoldReady}})
+	if err != nil {
+		return 0, err
+	}
+	now := s.now()
+	expired := 0
+	for _, hold := range ready {
+		if hold.ExpiresAt == nil || now.Before(*hold.ExpiresAt) {
+			continue
+		}
+		if err := s.closeHold(hold, models.HoldExpired); err != nil {
+			return expired, err
+		}
+		if err := s.releaseHeldCopy(hold); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// hold returns a hold with its queue position; s.mu must be held
+func (s *Service) hold(id int) (*models.Hold, error) {
+	hold, err := s.holds.GetHold(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setPosition(hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// setPosition fills in the queue position of a waiting hold; s.mu must be held
+func (s *Service) setPosition(hold *models.Hold) error {
+	if hold.Status != models.HoldWaiting {
+		return nil
+	}
+	waiting, err := s.holds.GetHolds(models.HoldFilter{BookID: hold.BookID, Statuses: []models.HoldStatus{models.HoldWaiting}})
+	if err != nil {
+		return err
+	}
+	for i, h := range waiting {
+		if h.ID == hold.ID {
+			hold.Position = i + 1
+			break
+		}
+	}
+	return nil
+}
+
+// patronHold returns a patron's active hold on a book, or nil if they have
+// none; s.mu must be held
+func (s *Service) patronHold(bookID, patronID int) (*models.Hold, error) {
+	holds, err := s.holds.GetHolds(models.HoldFilter{BookID: bookID, PatronID: patronID, Statuses: activeHoldStatuses})
+	if err != nil || len(holds) == 0 {
+		return nil, err
+	}
+	return &holds[0], nil
+}
+
+// release makes a copy available again, or sets it aside for the oldest
+// waiting hold on its book if there is one; s.mu must be held
+func (s *Service) release(item models.Copy) error {
+	waiting, err := s.holds.GetHolds(models.HoldFilter{BookID: item.BookID, Statuses: []models.HoldStatus{models.HoldWaiting}})
+	if err != nil {
+		return err
+	}
+	if len(waiting) == 0 {
+		return s.setCopyStatus(item, models.CopyAvailable)
+	}
+
+	if err := s.setCopyStatus(item, models.CopyOnHold); err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	expiresAt := now.Add(s.pickupWindow)
+	hold := waiting[0]
+	hold.Status = models.HoldReady
+	hold.CopyID = item.ID
+	hold.ReadyAt = &now
+	hold.ExpiresAt = &expiresAt
+	hold.UpdatedAt = now
+	ready, err := s.holds.UpdateHold(hold)
+	if err != nil {
+		return err
+	}
+
+	if s.bus != nil {
+		s.bus.PublishHold(events.HoldReady, *ready)
+	}
+	if s.notifier != nil {
+		s.notifier.NotifyHold(events.HoldReady, *ready)
+	}
+	return nil
+}
+
+// releaseHeldCopy releases the copy that was set aside for a hold, if it is
+// still on hold; s.mu must be held
+func (s *Service) releaseHeldCopy(hold models.Hold) error {
+	if hold.CopyID == 0 {
+		return nil
+	}
+	item, err := s.copies.GetCopy(hold.CopyID)
+	if err == models.ErrCopyNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if item.Status != models.CopyOnHold {
+		return nil
+	}
+	return s.release(*item)
+}
+
+// cancel withdraws an active hold, releasing its copy if one was set aside;
+// s.mu must be held
+func (s *Service) cancel(hold models.Hold) error {
+	if err := s.closeHold(hold, models.HoldCancelled); err != nil {
+		return err
+	}
+	if hold.Status == models.HoldReady {
+		return s.releaseHeldCopy(hold)
+	}
+	return nil
+}
+
+// closeHold saves a hold with a final status; s.mu must be held
+func (s *Service) closeHold(hold models.Hold, status models.HoldStatus) error {
+	hold.Status = status
+	hold.UpdatedAt = s.now().UTC()
+	_, err := s.holds.UpdateHold(hold)
+	return err
+}
@@ -0,0 +1,204 @@
+package circulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// recordingNotifier remembers the holds it is told about
+type recordingNotifier struct {
+	holds []models.Hold
+}
+
+func (n *recordingNotifier) NotifyHold(typ events.Type, hold models.Hold) {
+	n.holds = append(n.holds, hold)
+}
+
+func TestService_HoldQueue(t *testing.T) {
+	s, book, adult, child, _ := newTestService(t)
+	staff, _ := s.patrons.CreatePatron(models.Patron{Name: "Sam", Type: models.PatronStaff})
+	bus := events.NewBus(10)
+	sub, _, _ := bus.Subscribe(0)
+	defer sub.Close()
+	notifier := &recordingNotifier{}
+	s.SetEventBus(bus)
+	s.SetNotifier(notifier)
+
+	if _, err := s.Checkout(book.ID, 0, child.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := s.PlaceHold(book.ID, child.ID); err != ErrAlreadyBorrowed {
+		t.Errorf("expected ErrAlreadyBorrowed, got %v", err)
+	}
+
+	first, err := s.PlaceHold(book.ID, adult.ID)
+	if err != nil || first.Status != models.HoldWaiting || first.Position != 1 {
+		t.Fatalf("expected first waiting hold, got %+v (%v)", first, err)
+	}
+	second, _ := s.PlaceHold(book.ID, staff.ID)
+	if second.Position != 2 {
+		t.Errorf("expected second hold at position 2, got %d", second.Position)
+	}
+	if _, err := s.PlaceHold(book.ID, adult.ID); err != ErrDuplicateHold {
+		t.Errorf("expected ErrDuplicateHold, got %v", err)
+	}
+
+	if _, err := s.Return(book.ID, 0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ready, _ := s.Hold(first.ID)
+	if ready.Status != models.HoldReady || ready.CopyID == 0 || ready.ExpiresAt == nil || ready.Position != 0 {
+		t.Fatalf("expected first hold ready with a copy set aside, got %+v", ready)
+	}
+	if want := ready.ReadyAt.Add(DefaultHoldPickupWindow); !ready.ExpiresAt.Equal(want) {
+		t.Errorf("expected hold to expire at %v, got %v", want, *ready.ExpiresAt)
+	}
+	if item, _ := s.copies.GetCopy(ready.CopyID); item.Status != models.CopyOnHold {
+		t.Errorf("expected copy on hold, got %s", item.Status)
+	}
+	if waiting, _ := s.Hold(second.ID); waiting.Position != 1 {
+		t.Errorf("expected second hold to move up to position 1, got %d", waiting.Position)
+	}
+	if availability, _ := s.Availability(book.ID); availability.Status != StatusOnHold || availability.HoldsWaiting != 1 {
+		t.Errorf("expected book on hold with one hold waiting, got %+v", availability)
+	}
+
+	select {
+	case event := <-sub.Events():
+		if event.Type != events.HoldReady || event.Hold == nil || event.Hold.ID != first.ID {
+			t.Errorf("expected a hold.ready event for the first hold, got %+v", event)
+		}
+	default:
+		t.Error("expected a hold.ready event to be published")
+	}
+	if len(notifier.holds) != 1 || notifier.holds[0].ID != first.ID {
+		t.Errorf("expected notifier told about the first hold, got %+v", notifier.holds)
+	}
+
+	if _, err := s.Checkout(book.ID, 0, staff.ID); err != ErrBookUnavailable {
+		t.Errorf("expected ErrBookUnavailable for a patron further back in the queue, got %v", err)
+	}
+	loan, err := s.Checkout(book.ID, 0, adult.ID)
+	if err != nil || loan.CopyID != ready.CopyID {
+		t.Fatalf("expected the held copy lent, got %+v (%v)", loan, err)
+	}
+	if fulfilled, _ := s.Hold(first.ID); fulfilled.Status != models.HoldFulfilled {
+		t.Errorf("expected hold fulfilled, got %s", fulfilled.Status)
+	}
+
+	holds, _ := s.BookHolds(book.ID)
+	if len(holds) != 1 || holds[0].ID != second.ID || holds[0].Position != 1 {
+		t.Errorf("expected only the second hold queued, got %+v", holds)
+	}
+}
+
+func TestService_PlaceHoldOnAvailableBook(t *testing.T) {
+	s, book, adult, child, _ := newTestService(t)
+
+	hold, err := s.PlaceHold(book.ID, adult.ID)
+	if err != nil || hold.Status != models.HoldReady {
+		t.Fatalf("expected hold ready straight away, got %+v (%v)", hold, err)
+	}
+	if _, err := s.Checkout(book.ID, 0, child.ID); err != ErrBookUnavailable {
+		t.Errorf("expected ErrBookUnavailable, got %v", err)
+	}
+	if _, err := s.PlaceHold(999, adult.ID); err != models.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+	if _, err := s.PlaceHold(book.ID, 999); err != models.ErrPatronNotFound {
+		t.Errorf("expected ErrPatronNotFound, got %v", err)
+	}
+}
+
+func TestService_ExpireHolds(t *testing.T) {
+	s, book, adult, child, now := newTestService(t)
+	s.SetHoldPickupWindow(48 * time.Hour)
+
+	first, _ := s.PlaceHold(book.ID, adult.ID)
+	second, _ := s.PlaceHold(book.ID, child.ID)
+
+	*now = now.Add(47 * time.Hour)
+	if n, err := s.ExpireHolds(); err != nil || n != 0 {
+		t.Fatalf("expected no holds expired, got %d (%v)", n, err)
+	}
+
+	*now = now.Add(time.Hour)
+	if n, err := s.ExpireHolds(); err != nil || n != 1 {
+		t.Fatalf("expected one hold expired, got %d (%v)", n, err)
+	}
+	if expired, _ := s.Hold(first.ID); expired.Status != models.HoldExpired {
+		t.Errorf("expected first hold expired, got %s", expired.Status)
+	}
+	next, _ := s.Hold(second.ID)
+	if next.Status != models.HoldReady || next.CopyID != first.CopyID {
+		t.Errorf("expected the copy passed to the second hold, got %+v", next)
+	}
+
+	*now = now.Add(48 * time.Hour)
+	s.ExpireHolds()
+	if item, _ := s.copies.GetCopy(first.CopyID); item.Status != models.CopyAvailable {
+		t.Errorf("expected copy available once the queue is empty, got %s", item.Status)
+	}
+}
+
+func TestService_CancelHold(t *testing.T) {
+	s, book, adult, child, _ := newTestService(t)
+
+	first, _ := s.PlaceHold(book.ID, adult.ID)
+	second, _ := s.PlaceHold(book.ID, child.ID)
+
+	cancelled, err := s.CancelHold(first.ID)
+	if err != nil || cancelled.Status != models.HoldCancelled {
+		t.Fatalf("expected hold cancelled, got %+v (%v)", cancelled, err)
+	}
+	next, _ := s.Hold(second.ID)
+	if next.Status != models.HoldReady || next.CopyID != first.CopyID {
+		t.Errorf("expected the copy passed to the second hold, got %+v", next)
+	}
+	if _, err := s.CancelHold(first.ID); err != ErrHoldClosed {
+		t.Errorf("expected ErrHoldClosed, got %v", err)
+	}
+	if _, err := s.CancelHold(999); err != models.ErrHoldNotFound {
+		t.Errorf("expected ErrHoldNotFound, got %v", err)
+	}
+
+	if err := s.DeletePatron(child.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if deleted, _ := s.Hold(second.ID); deleted.Status != models.HoldCancelled {
+		t.Errorf("expected deleting the patron to cancel their hold, got %s", deleted.Status)
+	}
+	if item, _ := s.copies.GetCopy(next.CopyID); item.Status != models.CopyAvailable {
+		t.Errorf("expected copy available again, got %s", item.Status)
+	}
+}
+
+func TestService_CopyOnHold(t *testing.T) {
+	s, book, adult, child, _ := newTestService(t)
+
+	if _, err := s.Checkout(book.ID, 0, child.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	hold, _ := s.PlaceHold(book.ID, adult.ID)
+
+	added, err := s.AddCopy(book.ID, models.Copy{Barcode: "CC-2", Branch: "Main"})
+	if err != nil || added.Status != models.CopyOnHold {
+		t.Fatalf("expected new copy set aside for the hold, got %+v (%v)", added, err)
+	}
+	if ready, _ := s.Hold(hold.ID); ready.CopyID != added.ID {
+		t.Errorf("expected hold ready with the new copy, got %+v", ready)
+	}
+
+	if _, err := s.UpdateCopy(book.ID, added.ID, models.Copy{Barcode: "CC-2", Branch: "Main", Status: models.CopyLost}); err != ErrCopyOnHold {
+		t.Errorf("expected ErrCopyOnHold, got %v", err)
+	}
+	if err := s.DeleteCopy(book.ID, added.ID); err != ErrCopyOnHold {
+		t.Errorf("expected ErrCopyOnHold, got %v", err)
+	}
+	if _, err := s.AddCopy(book.ID, models.Copy{Barcode: "CC-3", Branch: "Main", Status: models.CopyOnHold}); err != models.ErrInvalidCopyStatus {
+		t.Errorf("expected ErrInvalidCopyStatus, got %v", err)
+	}
+}
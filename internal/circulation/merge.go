@@ -0,0 +1,85 @@
+package circulation
+
+import "github.com/codeforgood-org/golang-book-api/internal/models"
+
+// MoveBook moves the copies, loans, holds and fines of one book to another
+// when the first is merged into it. A patron with active holds on both
+// books keeps the one on the book merged into. Available copies that come
+// over are set aside for its waiting holds, as returned copies would be
+func (s *Service) MoveBook(from, to int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copies, err := s.copies.GetCopies(from)
+	if err != nil {
+		return err
+	}
+	for _, item := range copies {
+		item.BookID = to
+		if _, err := s.copies.UpdateCopy(item); err != nil {
+			return err
+		}
+	}
+
+	loans, err := s.loans.GetLoans(models.LoanFilter{BookID: from})
+	if err != nil {
+		return err
+	}
+	for _, loan := range loans {
+		loan.BookID = to
+		if _, err := s.loans.UpdateLoan(loan); err != nil {
+			return err
+		}
+	}
+
+	// Fines cannot be filtered by book
+	fines, err := s.fines.GetFines(models.FineFilter{})
+	if err != nil {
+		return err
+	}
+	for _, fine := range fines {
+		if fine.BookID != from {
+			continue
+		}
+		fine.BookID = to
+		if _, err := s.fines.UpdateFine(fine); err != nil {
+			return err
+		}
+	}
+
+	holds, err := s.holds.GetHolds(models.HoldFilter{BookID: from})
+	if err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		if hold.Active() {
+			existing, err := s.patronHold(to, hold.PatronID)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				if err := s.cancel(hold); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		hold.BookID = to
+		if _, err := s.holds.UpdateHold(hold); err != nil {
+			return err
+		}
+	}
+
+	copies, err = s.copies.GetCopies(to)
+	if err != nil {
+		return err
+	}
+	for _, item := range copies {
+		if item.Status == models.CopyAvailable {
+			if err := s.release(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package circulation
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestService_MoveBook(t *testing.T) {
+	s, from, adult, child, _ := newTestService(t)
+	staff, _ := s.patrons.CreatePatron(models.Patron{Name: "Sam", Type: models.PatronStaff})
+	s.AddCopy(from.ID, models.Copy{Barcode: "CC-2", Branch: "Main"})
+	to, _ := s.books.Create(models.Book{Title: "Clean Code", Author: "Robert Martin"})
+
+	loan, _ := s.Checkout(from.ID, 0, child.ID)
+	fine, _ := s.fines.CreateFine(models.Fine{BookID: from.ID, LoanID: loan.ID, PatronID: child.ID, AmountCents: 50})
+	// The adult's hold on the old record has a copy set aside; their hold on
+	// the one it is merged into is older and waiting
+	waiting, _ := s.PlaceHold(to.ID, adult.ID)
+	ready, _ := s.PlaceHold(from.ID, adult.ID)
+	queued, _ := s.PlaceHold(to.ID, staff.ID)
+	if ready.Status != models.HoldReady || waiting.Status != models.HoldWaiting {
+		t.Fatalf("unexpected holds %+v and %+v", ready, waiting)
+	}
+
+	if err := s.MoveBook(from.ID, to.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if copies, _ := s.Copies(to.ID); len(copies) != 2 {
+		t.Errorf("expected both copies moved, got %+v", copies)
+	}
+	if moved, _ := s.Loan(loan.ID); moved.BookID != to.ID {
+		t.Errorf("expected the loan moved, got book %d", moved.BookID)
+	}
+	if moved, _ := s.Fine(fine.ID); moved.BookID != to.ID {
+		t.Errorf("expected the fine moved, got book %d", moved.BookID)
+	}
+
+	if cancelled, _ := s.Hold(ready.ID); cancelled.Status != models.HoldCancelled {
+		t.Errorf("expected the duplicate hold cancelled, got %s", cancelled.Status)
+	}
+	kept, _ := s.Hold(waiting.ID)
+	if kept.Status != models.HoldReady || kept.CopyID != ready.CopyID {
+		t.Errorf("expected the kept hold to get the set-aside copy, got %+v", kept)
+	}
+	if next, _ := s.Hold(queued.ID); next.Status != models.HoldWaiting || next.Position != 1 {
+		t.Errorf("expected the next hold first in the queue, got %+v", next)
+	}
+}
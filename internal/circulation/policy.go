@@ -0,0 +1,110 @@
+package circulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// Policy sets the lending rules for a type of patron
+type Policy struct {
+	// LoanDays is the loan period, and the extension given by each renewal
+	LoanDays int `json:"loan_days"`
+	// MaxRenewals is how many times a loan can be renewed
+	MaxRenewals int `json:"max_renewals"`
+	// MaxLoans is how many books a patron can have out at once
+	MaxLoans int `json:"max_loans"`
+	// FineDailyCents is the fine for each full day a loan is overdue
+	FineDailyCents int `json:"fine_daily_cents"`
+	// FineGraceDays is how many days a loan can be overdue before it is fined
+	FineGraceDays int `json:"fine_grace_days"`
+	// FineCapCents is the most a single loan can be fined; 0 means no cap
+	FineCapCents int `json:"fine_cap_cents"`
+}
+
+// Validate checks that the policy allows lending at all
+func (p Policy) Validate() error {
+	if p.LoanDays < 1 {
+		return fmt.Errorf("loan_days must be at least 1")
+	}
+	if p.MaxRenewals < 0 {
+		return fmt.Errorf("max_renewals cannot be negative")
+	}
+	if p.MaxLoans < 1 {
+		return fmt.Errorf("max_loans must be at least 1")
+	}
+	if p.FineDailyCents < 0 || p.FineGraceDays < 0 || p.FineCapCents < 0 {
+		return fmt.Errorf("fine_daily_cents, fine_grace_days and fine_cap_cents cannot be negative")
+	}
+	return nil
+}
+
+// Fine returns the fine in cents for a loan returned, or still out, overdue
+// by the given time. Only full days count, and none are charged until the
+// grace period has passed; after that every day past the grace period is
+// charged, up to the cap
+func (p Policy) Fine(overdue time.Duration) int {
+	days := int(overdue / (24 * time.Hour))
+	if days <= p.FineGraceDays {
+		return 0
+	}
+	fine := (days - p.FineGraceDays) * p.FineDailyCents
+	if p.FineCapCents > 0 && fine > p.FineCapCents {
+		fine = p.FineCapCents
+	}
+	return fine
+}
+
+// Policies maps each patron type to its policy
+type Policies map[models.PatronType]Policy
+
+// DefaultPolicies returns the policies used when none are configured
+func DefaultPolicies() Policies {
+	return Policies{
+		models.PatronAdult: {LoanDays: 21, MaxRenewals: 2, MaxLoans: 10, FineDailyCents: 25, FineGraceDays: 1, FineCapCents: 1000},
+		models.PatronChild: {LoanDays: 14, MaxRenewals: 1, MaxLoans: 5, FineDailyCents: 10, FineGraceDays: 3, FineCapCents: 300},
+		models.PatronStaff: {LoanDays: 42, MaxRenewals: 5, MaxLoans: 25},
+	}
+}
+
+// For returns the policy for a patron type, falling back to the adult policy
+func (p Policies) For(t models.PatronType) Policy {
+	if policy, ok := p[t]; ok {
+		return policy
+	}
+	return p[models.PatronAdult]
+}
+
+// LoadPolicies reads policies from a JSON file keyed by patron type, such as
+// {"child": {"loan_days": 14, "max_renewals": 1, "max_loans": 5}}. Patron
+// types missing from the file keep their default policy, while those in it
+// are replaced whole, so a policy without fine settings charges no fines. An
+// empty path returns the defaults
+func LoadPolicies(path string) (Policies, error) {
+	policies := DefaultPolicies()
+	if path == "" {
+		return policies, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var configured Policies
+	if err := json.Unmarshal(data, &configured); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for t, policy := range configured {
+		if _, ok := policies[t]; !ok {
+			return nil, fmt.Errorf("%s: unknown patron type %q", path, t)
+		}
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %s policy: %w", path, t, err)
+		}
+		policies[t] = policy
+	}
+	return policies, nil
+}
@@ -0,0 +1,80 @@
+package circulation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestLoadPolicies(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+		child   Policy
+	}{
+		{"override child", `{"child": {"loan_days": 7, "max_renewals": 0, "max_loans": 3}}`, false, Policy{LoanDays: 7, MaxRenewals: 0, MaxLoans: 3}},
+		{"unknown patron type", `{"robot": {"loan_days": 7, "max_loans": 3}}`, true, Policy{}},
+		{"invalid policy", `{"child": {"loan_days": 0, "max_loans": 3}}`, true, Policy{}},
+		{"negative fine", `{"child": {"loan_days": 7, "max_loans": 3, "fine_daily_cents": -5}}`, true, Policy{}},
+		{"malformed JSON", `{`, true, Policy{}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".json")
+			os.WriteFile(path, []byte(tt.content), 0o644)
+
+			policies, err := LoadPolicies(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := policies.For(models.PatronChild); got != tt.child {
+				t.Errorf("expected child policy %+v, got %+v", tt.child, got)
+			}
+			if got := policies.For(models.PatronAdult); got != DefaultPolicies()[models.PatronAdult] {
+				t.Errorf("expected default adult policy, got %+v", got)
+			}
+		})
+	}
+
+	if policies, err := LoadPolicies(""); err != nil || len(policies) != len(models.PatronTypes) {
+		t.Errorf("expected default policies, got %v (%v)", policies, err)
+	}
+}
+
+func TestPolicy_Fine(t *testing.T) {
+	policy := Policy{FineDailyCents: 25, FineGraceDays: 2, FineCapCents: 200}
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		overdue  time.Duration
+		expected int
+	}{
+		{"part of a day", policy, 23 * time.Hour, 0},
+		{"within grace period", policy, 2 * day, 0},
+		{"first day past grace", policy, 3*day + time.Hour, 25},
+		{"several days", policy, 6 * day, 100},
+		{"capped", policy, 60 * day, 200},
+		{"uncapped", Policy{FineDailyCents: 10}, 60 * day, 600},
+		{"no fines", Policy{}, 60 * day, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Fine(tt.overdue); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package citation
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// bibtexEscaper escapes characters with special meaning in BibTeX values
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// escapeBibTeX escapes a field value and collapses line breaks
+func escapeBibTeX(s string) string {
+	return bibtexEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
+
+func writeBibTeX(w io.Writer, books []models.Book, keys []string) error {
+	for i, book := range books {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, bibtexEntry(book, keys[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bibtexEntry(book models.Book, key string) string {
+	var fields [][2]string
+
+	if authors := names(book); len(authors) > 0 {
+		parts := make([]string, len(authors))
+		for i, name := range authors {
+			parts[i] = escapeBibTeX(name.String())
+		}
+		fields = append(fields, [2]string{"author", strings.Join(parts, " and ")})
+	}
+	// Double braces keep BibTeX styles from changing the title's case
+	fields = append(fields, [2]string{"title", "{" + escapeBibTeX(book.Title) + "}"})
+	if book.Publisher != "" {
+		fields = append(fields, [2]string{"publisher", escapeBibTeX(book.Publisher)})
+	}
+	if book.PublishedYear > 0 {
+		fields = append(fields, [2]string{"year", strconv.Itoa(book.PublishedYear)})
+	}
+	if book.ISBN != "" {
+		fields = append(fields, [2]string{"isbn", escapeBibTeX(book.ISBN)})
+	}
+	if book.Language != "" {
+		fields = append(fields, [2]string{"language", escapeBibTeX(book.Language)})
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "@book{%s,\n", key)
+	for i, field := range fields {
+		fmt.Fprintf(&sb, "  %s = {%s}", field[0], field[1])
+		if i < len(fields)-1 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
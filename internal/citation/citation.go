@@ -0,0 +1,204 @@
+// Package citation renders books as bibliographic citations in BibTeX, RIS
+// and CSL-JSON.
+package citation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// Format identifies a citation export format
+type Format string
+
+const (
+	// FormatBibTeX is the BibTeX database format
+	FormatBibTeX Format = "bibtex"
+	// FormatRIS is the Research Information Systems tagged format
+	FormatRIS Format = "ris"
+	// FormatCSLJSON is the Citation Style Language JSON format
+	FormatCSLJSON Format = "csl-json"
+)
+
+// ErrUnsupportedFormat is returned for an unknown citation format
+var ErrUnsupportedFormat = errors.New("unsupported citation format")
+
+// ParseFormat parses a format name, defaulting to BibTeX when empty
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return FormatBibTeX, nil
+	case FormatBibTeX, FormatRIS, FormatCSLJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, s)
+	}
+}
+
+// ContentType returns the media type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatRIS:
+		return "application/x-research-info-systems"
+	case FormatCSLJSON:
+		return "application/vnd.citationstyles.csl+json"
+	default:
+		return "application/x-bibtex"
+	}
+}
+
+// Write renders books as citations in the given format. Citation keys are
+// unique within the batch.
+func Write(w io.Writer, f Format, books []models.Book) error {
+	keys := Keys(books)
+	switch f {
+	case FormatBibTeX:
+		return writeBibTeX(w, books, keys)
+	case FormatRIS:
+		return writeRIS(w, books, keys)
+	case FormatCSLJSON:
+		return writeCSLJSON(w, books, keys)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, f)
+	}
+}
+
+// Name is a personal name split into family and given parts
+type Name struct {
+	Family string
+	Given  string
+}
+
+// String returns the name in inverted "Family, Given" form
+func (n Name) String() string {
+	if n.Given == "" {
+		return n.Family
+	}
+	return n.Family + ", " + n.Given
+}
+
+// ParseName splits an author name into family and given names. Inverted
+// names ("Martin, Robert C.") are split at the comma; otherwise the last word
+// is the family name, together with any lowercase particles ("van", "de")
+// that precede it.
+func ParseName(s string) Name {
+	s = strings.TrimSpace(s)
+	if family, given, ok := strings.Cut(s, ","); ok {
+		return Name{Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)}
+	}
+
+	words := strings.Fields(s)
+	if len(words) <= 1 {
+		return Name{Family: s}
+	}
+
+	i := len(words) - 1
+	for i > 1 && isParticle(words[i-1]) {
+		i--
+	}
+	return Name{
+		Family: strings.Join(words[i:], " "),
+		Given:  strings.Join(words[:i], " "),
+	}
+}
+
+// isParticle reports whether a word is a lowercase name particle
+func isParticle(word string) bool {
+	r := []rune(word)
+	return len(r) > 0 && unicode.IsLower(r[0])
+}
+
+// names returns the parsed author names of a book
+func names(book models.Book) []Name {
+	authors := book.Authors()
+	parsed := make([]Name, 0, len(authors))
+	for _, author := range authors {
+		parsed = append(parsed, ParseName(author))
+	}
+	return parsed
+}
+
+// stopWords are skipped when picking the title word of a citation key
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "of": true, "on": true, "in": true,
+	"and": true, "to": true, "for": true, "with": true,
+}
+
+// Key generates a citation key from the first author's family name, the
+// publication year and the first significant title word, e.g.
+// "martin2008clean"
+func Key(book models.Book) string {
+	var sb strings.Builder
+	if authors := names(book); len(authors) > 0 {
+		sb.WriteString(keyPart(lastWord(authors[0].Family)))
+	}
+	if book.PublishedYear > 0 {
+		sb.WriteString(strconv.Itoa(book.PublishedYear))
+	}
+	for _, word := range strings.Fields(book.Title) {
+		word = keyPart(word)
+		if word != "" && !stopWords[word] {
+			sb.WriteString(word)
+			break
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "book" + strconv.Itoa(book.ID)
+	}
+	return sb.String()
+}
+
+// Keys generates citation keys for a batch, appending "a", "b", ... to keys
+// that would otherwise collide
+func Keys(books []models.Book) []string {
+	keys := make([]string, len(books))
+	count := make(map[string]int)
+	for i, book := range books {
+		keys[i] = Key(book)
+		count[keys[i]]++
+	}
+
+	seen := make(map[string]int)
+	for i, key := range keys {
+		if count[key] > 1 {
+			keys[i] = key + suffix(seen[key])
+			seen[key]++
+		}
+	}
+	return keys
+}
+
+// suffix returns the disambiguation suffix for the n-th duplicate key:
+// a, b, ..., z, aa, ab, ...
+func suffix(n int) string {
+	s := string(rune('a' + n%26))
+	for n = n/26 - 1; n >= 0; n = n/26 - 1 {
+		s = string(rune('a'+n%26)) + s
+	}
+	return s
+}
+
+// keyPart lowercases a word and keeps only ASCII letters and digits
+func keyPart(word string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(word) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func lastWord(s string) string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return ""
+	}
+	return words[len(words)-1]
+}
@@ -0,0 +1,157 @@
+package citation
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+var cleanCode = models.Book{
+	ID:            1,
+	Title:         "Clean Code",
+	Author:        "Robert C. Martin",
+	ISBN:          "9780132350884",
+	Publisher:     "Prentice Hall",
+	PublishedYear: 2008,
+}
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Name
+	}{
+		{input: "Robert C. Martin", want: Name{Family: "Martin", Given: "Robert C."}},
+		{input: "Martin, Robert C.", want: Name{Family: "Martin", Given: "Robert C."}},
+		{input: "Ludwig van Beethoven", want: Name{Family: "van Beethoven", Given: "Ludwig"}},
+		{input: "Homer", want: Name{Family: "Homer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ParseName(tt.input); got != tt.want {
+				t.Errorf("ParseName(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name string
+		book models.Book
+		want string
+	}{
+		{name: "author year title", book: cleanCode, want: "martin2008clean"},
+		{name: "skips stop words", book: models.Book{Title: "The Go Programming Language", Author: "Alan A. A. Donovan", PublishedYear: 2015}, want: "donovan2015go"},
+		{name: "no year", book: models.Book{Title: "Refactoring", Author: "Martin Fowler"}, want: "fowlerrefactoring"},
+		{name: "ascii letters only", book: models.Book{Title: "Don't Panic", Author: "Zoë O'Brien"}, want: "obriendont"},
+		{name: "fallback to id", book: models.Book{ID: 7, Title: "!!!"}, want: "book7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.book); got != tt.want {
+				t.Errorf("Key() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeys_Disambiguates(t *testing.T) {
+	books := []models.Book{cleanCode, {Title: "Refactoring", Author: "Martin Fowler"}, cleanCode}
+
+	got := Keys(books)
+	want := []string{"martin2008cleana", "fowlerrefactoring", "martin2008cleanb"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Keys() = %q, want %q", got, want)
+			break
+		}
+	}
+}
+
+func TestWrite_BibTeX(t *testing.T) {
+	books := []models.Book{{
+		Title:         "Profit & Loss: 100% {Guaranteed}",
+		Author:        "Andrew Hunt and David Thomas",
+		PublishedYear: 1999,
+	}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatBibTeX, books); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := "@book{hunt1999profit,\n" +
+		"  author = {Hunt, Andrew and Thomas, David},\n" +
+		"  title = {{Profit \\& Loss: 100\\% \\{Guaranteed\\}}},\n" +
+		"  year = {1999}\n" +
+		"}\n"
+	if buf.String() != want {
+		t.Errorf("unexpected BibTeX:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWrite_RIS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatRIS, []models.Book{cleanCode}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := strings.Join([]string{
+		"TY  - BOOK",
+		"ID  - martin2008clean",
+		"AU  - Martin, Robert C.",
+		"TI  - Clean Code",
+		"PB  - Prentice Hall",
+		"PY  - 2008",
+		"SN  - 9780132350884",
+		"ER  - ",
+		"",
+	}, "\r\n")
+	if buf.String() != want {
+		t.Errorf("unexpected RIS:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
+
+func TestWrite_CSLJSON(t *testing.T) {
+	books := []models.Book{cleanCode, {Title: "Design Patterns", Author: "Erich Gamma, Richard Helm"}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSLJSON, books); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var items []CSLItem
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatalf("failed to decode CSL-JSON: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	if items[0].ID != "martin2008clean" || items[0].Type != "book" {
+		t.Errorf("unexpected item: %+v", items[0])
+	}
+	if items[0].Issued == nil || items[0].Issued.DateParts[0][0] != 2008 {
+		t.Errorf("expected issued year 2008, got %+v", items[0].Issued)
+	}
+	if len(items[1].Author) != 2 || items[1].Author[1] != (CSLName{Family: "Helm", Given: "Richard"}) {
+		t.Errorf("unexpected authors: %+v", items[1].Author)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(""); err != nil || f != FormatBibTeX {
+		t.Errorf("ParseFormat(\"\") = %q, %v; want bibtex", f, err)
+	}
+	if f, err := ParseFormat("CSL-JSON"); err != nil || f != FormatCSLJSON {
+		t.Errorf("ParseFormat(\"CSL-JSON\") = %q, %v; want csl-json", f, err)
+	}
+	if _, err := ParseFormat("endnote"); err == nil {
+		t.Error("expected an error for unsupported format")
+	}
+}
@@ -0,0 +1,61 @@
+package citation
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// CSLItem is a CSL-JSON bibliographic item
+type CSLItem struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Author    []CSLName `json:"author,omitempty"`
+	Publisher string    `json:"publisher,omitempty"`
+	Issued    *CSLDate  `json:"issued,omitempty"`
+	ISBN      string    `json:"ISBN,omitempty"`
+	Language  string    `json:"language,omitempty"`
+}
+
+// CSLName is a CSL-JSON name variable
+type CSLName struct {
+	Family string `json:"family"`
+	Given  string `json:"given,omitempty"`
+}
+
+// CSLDate is a CSL-JSON date variable
+type CSLDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// NewCSLItem converts a book into a CSL-JSON item
+func NewCSLItem(book models.Book, key string) CSLItem {
+	item := CSLItem{
+		ID:        key,
+		Type:      "book",
+		Title:     book.Title,
+		Publisher: book.Publisher,
+		ISBN:      book.ISBN,
+		Language:  book.Language,
+	}
+	for _, name := range names(book) {
+		item.Author = append(item.Author, CSLName{Family: name.Family, Given: name.Given})
+	}
+	if book.PublishedYear > 0 {
+		item.Issued = &CSLDate{DateParts: [][]int{{book.PublishedYear}}}
+	}
+	return item
+}
+
+func writeCSLJSON(w io.Writer, books []models.Book, keys []string) error {
+	items := make([]CSLItem, len(books))
+	for i, book := range books {
+		items[i] = NewCSLItem(book, keys[i])
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(items)
+}
@@ -0,0 +1,48 @@
+package citation
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// risValue flattens a value onto a single line, since RIS is line-oriented
+func risValue(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func writeRIS(w io.Writer, books []models.Book, keys []string) error {
+	for i, book := range books {
+		if _, err := io.WriteString(w, risEntry(book, keys[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func risEntry(book models.Book, key string) string {
+	var sb strings.Builder
+	tag := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&sb, "%s  - %s\r\n", name, risValue(value))
+		}
+	}
+
+	tag("TY", "BOOK")
+	tag("ID", key)
+	for _, name := range names(book) {
+		tag("AU", name.String())
+	}
+	tag("TI", book.Title)
+	tag("PB", book.Publisher)
+	if book.PublishedYear > 0 {
+		tag("PY", strconv.Itoa(book.PublishedYear))
+	}
+	tag("SN", book.ISBN)
+	tag("LA", book.Language)
+	sb.WriteString("ER  - \r\n")
+	return sb.String()
+}
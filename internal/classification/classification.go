@@ -0,0 +1,312 @@
+// Package classification parses Dewey Decimal and Library of Congress call
+// numbers, puts them in shelf order and groups them into class trees.
+package classification
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Scheme identifies a classification scheme
+type Scheme string
+
+const (
+	// Dewey is the Dewey Decimal Classification
+	Dewey Scheme = "dewey"
+	// LCC is the Library of Congress Classification
+	LCC Scheme = "lcc"
+)
+
+var (
+	// ErrUnsupportedScheme is returned for an unknown classification scheme
+	ErrUnsupportedScheme = errors.New("unsupported classification scheme")
+
+	// ErrInvalidCallNumber is returned when a call number does not follow
+	// the format of its scheme
+	ErrInvalidCallNumber = errors.New("invalid call number")
+)
+
+// ParseScheme parses a scheme name
+func ParseScheme(s string) (Scheme, error) {
+	switch scheme := Scheme(strings.ToLower(strings.TrimSpace(s))); scheme {
+	case Dewey, LCC:
+		return scheme, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedScheme, s)
+	}
+}
+
+// CallNumber is a parsed call number. Call numbers are shelved class by
+// class, comparing class numbers as numbers and their decimal parts and
+// Cutter numbers digit by digit, so 5.2 files after 5.13
+type CallNumber struct {
+	scheme Scheme
+	text   string
+	// class holds the LCC class letters
+	class string
+	// number and decimal are the whole and fractional parts of the class
+	// number; an LCC class on its own, such as QA, has no number
+	number    int
+	hasNumber bool
+	decimal   string
+	// parts are the Cutter numbers, years and volumes that follow the class
+	parts []part
+}
+
+type partKind int
+
+const (
+	cutterPart partKind = iota
+	yearPart
+	volumePart
+)
+
+// part is a Cutter number such as M37a, a year such as 2008, or a volume or
+// copy such as v.2
+type part struct {
+	kind partKind
+	// text is the letters of a Cutter number or the prefix of a volume
+	text string
+	// digits are the digits of a Cutter number, read as a decimal fraction
+	digits string
+	// number is a year or volume number
+	number int
+	// suffix holds the work letters after a Cutter number or year
+	suffix string
+}
+
+// Normalize trims a call number and collapses runs of spaces
+func Normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Parse parses a call number in the given scheme, normalizing its spacing
+func Parse(scheme Scheme, s string) (CallNumber, error) {
+	return parse(scheme, s, false)
+}
+
+// ParseBound parses the start or end of a range of call numbers, which may
+// also be an LCC class on its own, such as QA
+func ParseBound(scheme Scheme, s string) (CallNumber, error) {
+	return parse(scheme, s, true)
+}
+
+func parse(scheme Scheme, s string, bound bool) (CallNumber, error) {
+	s = Normalize(s)
+	var (
+		c   CallNumber
+		err error
+	)
+	switch scheme {
+	case Dewey:
+		c, err = parseDewey(s)
+	case LCC:
+		c, err = parseLCC(s, bound)
+	default:
+		return CallNumber{}, fmt.Errorf("%w: %q", ErrUnsupportedScheme, scheme)
+	}
+	if err != nil {
+		return CallNumber{}, fmt.Errorf("%w: %q is not a valid %s call number", ErrInvalidCallNumber, s, scheme.Name())
+	}
+	c.scheme, c.text = scheme, s
+	return c, nil
+}
+
+// Name returns the display name of the scheme
+func (s Scheme) Name() string {
+	switch s {
+	case Dewey:
+		return "Dewey Decimal"
+	case LCC:
+		return "Library of Congress"
+	default:
+		return string(s)
+	}
+}
+
+// String returns the normalized call number
+func (c CallNumber) String() string {
+	return c.text
+}
+
+// Compare returns -1, 0 or 1 as c shelves before, with or after o
+func (c CallNumber) Compare(o CallNumber) int {
+	if n := cmp.Or(
+		strings.Compare(c.class, o.class),
+		cmp.Compare(c.number, o.number),
+		strings.Compare(c.decimal, o.decimal),
+	); n != 0 {
+		return n
+	}
+	for i := 0; i < len(c.parts) && i < len(o.parts); i++ {
+		if n := c.parts[i].compare(o.parts[i]); n != 0 {
+			return n
+		}
+	}
+	return cmp.Compare(len(c.parts), len(o.parts))
+}
+
+// Range is a run of shelves from one call number up to and including
+// another. The end covers every call number it is the start of, so 599
+// covers 599.93 M37 and Q covers QA76.73. A nil end leaves the range open
+type Range struct {
+	From *CallNumber
+	To   *CallNumber
+}
+
+// ParseRange parses the ends of a range, either of which may be empty
+func ParseRange(scheme Scheme, from, to string) (Range, error) {
+	var (
+		r   Range
+		err error
+	)
+	if r.From, err = parseEnd(scheme, from); err != nil {
+		return Range{}, err
+	}
+	if r.To, err = parseEnd(scheme, to); err != nil {
+		return Range{}, err
+	}
+	return r, nil
+}
+
+// parseEnd parses one end of a range, returning nil if it is empty
+func parseEnd(scheme Scheme, s string) (*CallNumber, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	c, err := ParseBound(scheme, s)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Contains reports whether c is shelved within the range
+func (r Range) Contains(c CallNumber) bool {
+	if r.From != nil && c.Compare(*r.From) < 0 {
+		return false
+	}
+	return r.To == nil || c.truncate(*r.To).Compare(*r.To) <= 0
+}
+
+// truncate cuts c down to the precision of to
+func (c CallNumber) truncate(to CallNumber) CallNumber {
+	if !to.hasNumber {
+		class := c.class
+		if len(class) > len(to.class) {
+			class = class[:len(to.class)]
+		}
+		return CallNumber{scheme: c.scheme, class: class}
+	}
+	if len(c.decimal) > len(to.decimal) {
+		c.decimal = c.decimal[:len(to.decimal)]
+	}
+	if len(c.parts) > len(to.parts) {
+		c.parts = c.parts[:len(to.parts)]
+	}
+	return c
+}
+
+func (p part) compare(o part) int {
+	return cmp.Or(
+		cmp.Compare(p.kind, o.kind),
+		strings.Compare(p.text, o.text),
+		strings.Compare(p.digits, o.digits),
+		cmp.Compare(p.number, o.number),
+		strings.Compare(p.suffix, o.suffix),
+	)
+}
+
+// Compare compares two call numbers in shelf order. Call numbers that do not
+// parse shelve after those that do, in string order
+func Compare(scheme Scheme, a, b string) int {
+	ca, errA := Parse(scheme, a)
+	cb, errB := Parse(scheme, b)
+	switch {
+	case errA == nil && errB == nil:
+		return ca.Compare(cb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// parseTail parses the years and volumes that end a call number, given as
+// space-separated words, plus Cutter numbers if cutters is set
+func parseTail(s string, cutters bool) ([]part, error) {
+	var parts []part
+	for _, word := range strings.Fields(s) {
+		p, ok := parseYear(word)
+		if !ok {
+			p, ok = parseVolume(word)
+		}
+		if !ok && cutters {
+			p, ok = parseCutter(word, 3)
+		}
+		if !ok {
+			return nil, ErrInvalidCallNumber
+		}
+		parts = append(parts, p)
+	}
+	return parts, nil
+}
+
+// parseCutter parses a Cutter number of up to maxLetters capital letters,
+// digits and trailing lower-case work letters, such as M37a
+func parseCutter(s string, maxLetters int) (part, bool) {
+	letters := leading(s, isUpper)
+	if letters == "" || len(letters) > maxLetters {
+		return part{}, false
+	}
+	digits := leading(s[len(letters):], isDigit)
+	suffix := s[len(letters)+len(digits):]
+	if leading(suffix, isLower) != suffix || len(suffix) > 2 {
+		return part{}, false
+	}
+	return part{kind: cutterPart, text: letters, digits: digits, suffix: suffix}, true
+}
+
+// parseYear parses a four-digit year, optionally followed by a letter
+func parseYear(s string) (part, bool) {
+	digits := leading(s, isDigit)
+	suffix := s[len(digits):]
+	if len(digits) != 4 || len(suffix) > 1 || leading(suffix, isLower) != suffix {
+		return part{}, false
+	}
+	year, _ := strconv.Atoi(digits)
+	return part{kind: yearPart, number: year, suffix: suffix}, true
+}
+
+// parseVolume parses a volume, copy, part or number such as v.2 or c.10
+func parseVolume(s string) (part, bool) {
+	prefix, rest, ok := strings.Cut(s, ".")
+	switch prefix {
+	case "v", "c", "pt", "no":
+	default:
+		return part{}, false
+	}
+	if !ok || rest == "" || leading(rest, isDigit) != rest || len(rest) > 6 {
+		return part{}, false
+	}
+	number, _ := strconv.Atoi(rest)
+	return part{kind: volumePart, text: prefix, number: number}, true
+}
+
+// leading returns the longest prefix of s whose bytes satisfy f
+func leading(s string, f func(byte) bool) string {
+	i := 0
+	for i < len(s) && f(s[i]) {
+		i++
+	}
+	return s[:i]
+}
+
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
+func isUpper(b byte) bool { return b >= 'A' && b <= 'Z' }
+func isLower(b byte) bool { return b >= 'a' && b <= 'z' }
@@ -0,0 +1,181 @@
+package classification
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		scheme Scheme
+		input  string
+		valid  bool
+	}{
+		{Dewey, "005.133", true},
+		{Dewey, "813.54 M37 2008", true},
+		{Dewey, " 005.133  D66 ", true},
+		{Dewey, "641.5 SMI v.2", true},
+		{Dewey, "599.93 M37a", true},
+		{Dewey, "005", true},
+		{Dewey, "5.1", false},
+		{Dewey, "005.", false},
+		{Dewey, "005.133D66", false},
+		{Dewey, "FIC KIN", false},
+		{Dewey, "813.54 m37", false},
+		{LCC, "QA76.73.G63 D66 2016", true},
+		{LCC, "QA 76.73 .G63 D66", true},
+		{LCC, "PS3566.I5 G8 1985", true},
+		{LCC, "E184.A1 S65", true},
+		{LCC, "KF4550.Z9 K45 v.2", true},
+		{LCC, "QA76", true},
+		{LCC, "QA", false},
+		{LCC, "QAB76", true},
+		{LCC, "QABC76", false},
+		{LCC, "I76", false},
+		{LCC, "QA76.G", false},
+		{LCC, "QA76.73 G63 foo", false},
+		{LCC, "005.133", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.scheme)+" "+tt.input, func(t *testing.T) {
+			_, err := Parse(tt.scheme, tt.input)
+			if (err == nil) != tt.valid {
+				t.Errorf("expected valid %v, got error %v", tt.valid, err)
+			}
+		})
+	}
+
+	if c, _ := Parse(Dewey, " 005.133  D66 "); c.String() != "005.133 D66" {
+		t.Errorf("expected normalized call number, got %q", c.String())
+	}
+	if _, err := Parse("udc", "004"); err == nil {
+		t.Error("expected an error for an unknown scheme")
+	}
+}
+
+func TestCompare_ShelfOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme Scheme
+		// shelved lists call numbers in shelf order
+		shelved []string
+	}{
+		{
+			name:    "dewey decimals are read digit by digit",
+			scheme:  Dewey,
+			shelved: []string{"005", "005.1", "005.13", "005.133", "005.2", "050"},
+		},
+		{
+			name:    "dewey cutters, years and volumes",
+			scheme:  Dewey,
+			shelved: []string{"813.54", "813.54 K5", "813.54 K54", "813.54 K6", "813.54 M37", "813.54 M37 2001", "813.54 M37 2008", "813.54 M37 v.2", "813.54 M37 v.10"},
+		},
+		{
+			name:    "lcc class numbers are whole numbers",
+			scheme:  LCC,
+			shelved: []string{"Q1", "QA9", "QA76", "QA76.A1", "QA76.5", "QA100", "QB1"},
+		},
+		{
+			name:    "lcc cutters are decimals",
+			scheme:  LCC,
+			shelved: []string{"QA76.73.J38", "QA76.73.J38 S65", "QA76.73.J38 S65 2015", "QA76.73.J4", "QA76.73.P98"},
+		},
+		{
+			name:    "invalid call numbers go last",
+			scheme:  Dewey,
+			shelved: []string{"005", "900", "FIC KIN"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shuffled := slices.Clone(tt.shelved)
+			slices.Reverse(shuffled)
+			slices.SortFunc(shuffled, func(a, b string) int { return Compare(tt.scheme, a, b) })
+			if !slices.Equal(shuffled, tt.shelved) {
+				t.Errorf("expected %q, got %q", tt.shelved, shuffled)
+			}
+		})
+	}
+}
+
+func TestRange_Contains(t *testing.T) {
+	tests := []struct {
+		scheme     Scheme
+		from, to   string
+		callNumber string
+		expected   bool
+	}{
+		{Dewey, "500", "599", "599.93 M37", true},
+		{Dewey, "500", "599", "500", true},
+		{Dewey, "500", "599", "600", false},
+		{Dewey, "500", "599", "499.9", false},
+		{Dewey, "", "599.9", "599.93", true},
+		{Dewey, "", "599.9", "599.1", true},
+		{Dewey, "599.5", "", "599.1", false},
+		{LCC, "QA", "QA", "QA76.73.J38", true},
+		{LCC, "Q", "Q", "QB1", true},
+		{LCC, "Q", "Q", "R5", false},
+		{LCC, "QA76", "QA76.9", "QA76.73.J38", true},
+		{LCC, "QA76", "QA76.9", "QA100", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"-"+tt.to+" "+tt.callNumber, func(t *testing.T) {
+			r, err := ParseRange(tt.scheme, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			c, _ := Parse(tt.scheme, tt.callNumber)
+			if got := r.Contains(c); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := ParseRange(Dewey, "5", ""); err == nil {
+		t.Error("expected an error for a malformed bound")
+	}
+}
+
+func TestTree(t *testing.T) {
+	callNumbers := []string{"005.133 D66", "005.1 M37", "025.04", "599.93 M37", "not a call number"}
+
+	classes, err := Tree(Dewey, callNumbers, MaxDepth)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(classes) != 2 || classes[0].Code != "000" || classes[0].Count != 3 || classes[1].Code != "500" {
+		t.Fatalf("expected classes 000 (3) and 500, got %+v", classes)
+	}
+	if classes[0].Name == "" {
+		t.Error("expected the main class to be named")
+	}
+	divisions := classes[0].Children
+	if len(divisions) != 2 || divisions[0].Code != "000" || divisions[0].Count != 2 || divisions[1].Code != "020" {
+		t.Fatalf("expected divisions 000 (2) and 020, got %+v", divisions)
+	}
+	if sections := divisions[0].Children; len(sections) != 1 || sections[0].Code != "005" || sections[0].Count != 2 || sections[0].Name != "" {
+		t.Errorf("expected section 005 (2), got %+v", sections)
+	}
+
+	classes, _ = Tree(LCC, []string{"QA100", "QA76.73.J38", "QA76", "E184.A1", "Q1"}, MaxDepth)
+	var codes []string
+	var walk func(nodes []*Node)
+	walk = func(nodes []*Node) {
+		for _, n := range nodes {
+			codes = append(codes, n.Code)
+			walk(n.Children)
+		}
+	}
+	walk(classes)
+	expected := []string{"E", "E184", "Q", "Q1", "QA", "QA76", "QA100"}
+	if !slices.Equal(codes, expected) {
+		t.Errorf("expected %q, got %q", expected, codes)
+	}
+
+	if classes, _ := Tree(Dewey, callNumbers, 1); len(classes[0].Children) != 0 {
+		t.Errorf("expected no children at depth 1, got %+v", classes[0].Children)
+	}
+}
@@ -0,0 +1,50 @@
+package classification
+
+import "strconv"
+
+// deweyClasses names the ten main classes of the Dewey Decimal Classification
+var deweyClasses = map[string]string{
+	"000": "Computer science, information and general works",
+	"100": "Philosophy and psychology",
+	"200": "Religion",
+	"300": "Social sciences",
+	"400": "Language",
+	"500": "Science",
+	"600": "Technology",
+	"700": "Arts and recreation",
+	"800": "Literature",
+	"900": "History and geography",
+}
+
+// parseDewey parses a Dewey call number: a three-digit class number with an
+// optional decimal part, such as 005.133, followed by Cutter numbers, a year
+// and volumes, as in 813.54 M37 2008 v.2
+func parseDewey(s string) (CallNumber, error) {
+	var c CallNumber
+
+	digits := leading(s, isDigit)
+	if len(digits) != 3 {
+		return c, ErrInvalidCallNumber
+	}
+	c.number, _ = strconv.Atoi(digits)
+	c.hasNumber = true
+	rest := s[len(digits):]
+
+	if len(rest) > 0 && rest[0] == '.' {
+		c.decimal = leading(rest[1:], isDigit)
+		if c.decimal == "" {
+			return c, ErrInvalidCallNumber
+		}
+		rest = rest[1+len(c.decimal):]
+	}
+	if rest != "" && rest[0] != ' ' {
+		return c, ErrInvalidCallNumber
+	}
+
+	parts, err := parseTail(rest, true)
+	if err != nil {
+		return c, err
+	}
+	c.parts = parts
+	return c, nil
+}
@@ -0,0 +1,90 @@
+package classification
+
+import (
+	"strconv"
+	"strings"
+)
+
+// lccClasses names the main classes of the Library of Congress Classification
+var lccClasses = map[string]string{
+	"A": "General works",
+	"B": "Philosophy, psychology and religion",
+	"C": "Auxiliary sciences of history",
+	"D": "World history",
+	"E": "History of the Americas",
+	"F": "History of the Americas",
+	"G": "Geography, anthropology and recreation",
+	"H": "Social sciences",
+	"J": "Political science",
+	"K": "Law",
+	"L": "Education",
+	"M": "Music",
+	"N": "Fine arts",
+	"P": "Language and literature",
+	"Q": "Science",
+	"R": "Medicine",
+	"S": "Agriculture",
+	"T": "Technology",
+	"U": "Military science",
+	"V": "Naval science",
+	"Z": "Bibliography and library science",
+}
+
+// parseLCC parses a Library of Congress call number: one to three class
+// letters and a class number with an optional decimal part, followed by up
+// to three Cutter numbers, a year and volumes, as in QA76.73.J38 S65 2015.
+// A bound may also be class letters alone
+func parseLCC(s string, bound bool) (CallNumber, error) {
+	var c CallNumber
+
+	c.class = leading(s, isUpper)
+	if c.class == "" || len(c.class) > 3 {
+		return c, ErrInvalidCallNumber
+	}
+	if _, ok := lccClasses[c.class[:1]]; !ok {
+		return c, ErrInvalidCallNumber
+	}
+	rest := strings.TrimPrefix(s[len(c.class):], " ")
+	if rest == "" && bound {
+		return c, nil
+	}
+
+	digits := leading(rest, isDigit)
+	if digits == "" || len(digits) > 4 {
+		return c, ErrInvalidCallNumber
+	}
+	c.number, _ = strconv.Atoi(digits)
+	c.hasNumber = true
+	rest = rest[len(digits):]
+
+	if len(rest) > 1 && rest[0] == '.' && isDigit(rest[1]) {
+		c.decimal = leading(rest[1:], isDigit)
+		rest = rest[1+len(c.decimal):]
+	}
+
+	// Cutter numbers are a letter and digits, the first usually introduced
+	// by a period: .J38 S65 or .J38S65
+	for len(c.parts) < 3 {
+		next := strings.TrimPrefix(strings.TrimPrefix(rest, " "), ".")
+		if next == "" || !isUpper(next[0]) {
+			break
+		}
+		digits := leading(next[1:], isDigit)
+		if digits == "" {
+			return c, ErrInvalidCallNumber
+		}
+		suffix := leading(next[1+len(digits):], isLower)
+		c.parts = append(c.parts, part{kind: cutterPart, text: next[:1], digits: digits, suffix: suffix})
+		rest = next[1+len(digits)+len(suffix):]
+	}
+	if rest != "" && rest[0] != ' ' {
+		return c, ErrInvalidCallNumber
+	}
+
+	tail, err := parseTail(rest, false)
+	if err != nil {
+		return c, err
+	}
+	c.parts = append(c.parts, tail...)
+	return c, nil
+}
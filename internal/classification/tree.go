@@ -0,0 +1,92 @@
+package classification
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// MaxDepth is the deepest level of a class tree: Dewey main classes,
+// divisions and sections, or LCC main classes, subclasses and class numbers
+const MaxDepth = 3
+
+// Node is a class in a class tree with the number of call numbers in it and
+// its subclasses
+type Node struct {
+	Code     string  `json:"code"`
+	Name     string  `json:"name,omitempty"`
+	Count    int     `json:"count"`
+	Children []*Node `json:"children,omitempty"`
+
+	key CallNumber
+}
+
+// Tree groups call numbers into a tree of classes, depth levels deep, with
+// the classes at each level in shelf order. Call numbers that do not parse
+// are left out, and classes without call numbers are not included.
+func Tree(scheme Scheme, callNumbers []string, depth int) ([]*Node, error) {
+	if _, err := ParseScheme(string(scheme)); err != nil {
+		return nil, err
+	}
+	depth = min(max(depth, 1), MaxDepth)
+
+	root := &Node{}
+	for _, s := range callNumbers {
+		c, err := Parse(scheme, s)
+		if err != nil {
+			continue
+		}
+		codes := path(c)
+		node := root
+		for i, code := range codes[:min(depth, len(codes))] {
+			node = node.child(scheme, code, i == 0)
+			node.Count++
+		}
+	}
+	root.sort()
+	return root.Children, nil
+}
+
+// path returns the codes of the classes containing c, broadest first. An LCC
+// main class with no subclasses, such as E, has no subclass level
+func path(c CallNumber) []string {
+	if c.scheme == Dewey {
+		code := fmt.Sprintf("%03d", c.number)
+		return []string{code[:1] + "00", code[:2] + "0", code}
+	}
+	number := c.class + strconv.Itoa(c.number)
+	if len(c.class) == 1 {
+		return []string{c.class, number}
+	}
+	return []string{c.class[:1], c.class, number}
+}
+
+// child returns the child of n with the given code, adding it if needed.
+// Only main classes are named
+func (n *Node) child(scheme Scheme, code string, main bool) *Node {
+	for _, child := range n.Children {
+		if child.Code == code {
+			return child
+		}
+	}
+	child := &Node{Code: code}
+	child.key, _ = ParseBound(scheme, code)
+	if main {
+		switch scheme {
+		case Dewey:
+			child.Name = deweyClasses[code]
+		case LCC:
+			child.Name = lccClasses[code]
+		}
+	}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// sort puts the children of n and their descendants in shelf order
+func (n *Node) sort() {
+	slices.SortFunc(n.Children, func(a, b *Node) int { return a.key.Compare(b.key) })
+	for _, child := range n.Children {
+		child.sort()
+	}
+}
@@ -0,0 +1,70 @@
+package codec
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MediaRange is one entry of an Accept header
+type MediaRange struct {
+	Type    string
+	Subtype string
+	Q       float64
+}
+
+// ParseAccept parses an Accept header into media ranges sorted by
+// descending quality. An empty header is equivalent to "*/*".
+func ParseAccept(header string) []MediaRange {
+	if strings.TrimSpace(header) == "" {
+		return []MediaRange{{Type: "*", Subtype: "*", Q: 1}}
+	}
+
+	var ranges []MediaRange
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(params[0])), "/")
+		if !ok || typ == "" || subtype == "" {
+			continue
+		}
+
+		mr := MediaRange{Type: typ, Subtype: subtype, Q: 1}
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil && q >= 0 && q <= 1 {
+					mr.Q = q
+				}
+			}
+		}
+		ranges = append(ranges, mr)
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Q > ranges[j].Q
+	})
+	return ranges
+}
+
+// quality returns the q-value the most specific matching range assigns to
+// mediaType, or 0 if no range matches
+func quality(ranges []MediaRange, mediaType string) float64 {
+	typ, subtype, _ := strings.Cut(strings.ToLower(mediaType), "/")
+
+	bestSpecificity, q := -1, 0.0
+	for _, mr := range ranges {
+		specificity := -1
+		switch {
+		case mr.Type == typ && mr.Subtype == subtype:
+			specificity = 2
+		case mr.Type == typ && mr.Subtype == "*":
+			specificity = 1
+		case mr.Type == "*" && mr.Subtype == "*":
+			specificity = 0
+		}
+		if specificity > bestSpecificity {
+			bestSpecificity, q = specificity, mr.Q
+		}
+	}
+	return q
+}
@@ -0,0 +1,143 @@
+// Package codec serializes API payloads in the format a client asks for.
+// Response encoders are chosen from the Accept header (honoring q-values)
+// and request body decoders from the Content-Type header.
+package codec
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"strings"
+)
+
+var (
+	// ErrNotAcceptable is returned when no encoder satisfies the Accept header
+	ErrNotAcceptable = errors.New("none of the requested media types can be produced")
+
+	// ErrUnsupportedMediaType is returned when no decoder handles the Content-Type
+	ErrUnsupportedMediaType = errors.New("unsupported request media type")
+)
+
+// Encoder writes a payload in a particular media type
+type Encoder interface {
+	// MediaTypes returns the media types the encoder produces; the first
+	// one is used as the response Content-Type
+	MediaTypes() []string
+
+	// Encode writes v to w
+	Encode(w io.Writer, v interface{}) error
+}
+
+// Decoder reads a request body in a particular media type
+type Decoder interface {
+	// MediaTypes returns the media types the decoder accepts
+	MediaTypes() []string
+
+	// Decode reads r into v, which must be a pointer
+	Decode(r io.Reader, v interface{}) error
+}
+
+// Selective is implemented by encoders that can only represent some
+// payloads, such as CSV which needs a list of records
+type Selective interface {
+	CanEncode(v interface{}) bool
+}
+
+// Registry holds the available encoders and decoders in order of preference
+type Registry struct {
+	encoders []Encoder
+	decoders []Decoder
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default creates a registry with the JSON, XML, YAML, CSV and MessagePack
+// codecs. JSON is preferred when the client expresses no preference.
+func Default() *Registry {
+	reg := NewRegistry()
+	reg.RegisterEncoder(JSON{})
+	reg.RegisterEncoder(XML{})
+	reg.RegisterEncoder(YAML{})
+	reg.RegisterEncoder(CSV{})
+	reg.RegisterEncoder(MessagePack{})
+
+	reg.RegisterDecoder(JSON{})
+	reg.RegisterDecoder(XML{})
+	reg.RegisterDecoder(YAML{})
+	reg.RegisterDecoder(MessagePack{})
+	return reg
+}
+
+// RegisterEncoder adds an encoder with lower preference than those already
+// registered
+func (reg *Registry) RegisterEncoder(e Encoder) {
+	reg.encoders = append(reg.encoders, e)
+}
+
+// RegisterDecoder adds a decoder
+func (reg *Registry) RegisterDecoder(d Decoder) {
+	reg.decoders = append(reg.decoders, d)
+}
+
+// Negotiate picks the encoder that best satisfies the Accept header for the
+// payload v. An empty Accept header selects the most preferred encoder.
+func (reg *Registry) Negotiate(accept string, v interface{}) (Encoder, error) {
+	ranges := ParseAccept(accept)
+
+	var best Encoder
+	bestQ := 0.0
+	for _, enc := range reg.encoders {
+		encQ := 0.0
+		for _, mediaType := range enc.MediaTypes() {
+			encQ = max(encQ, quality(ranges, mediaType))
+		}
+		// Checking whether the payload can be encoded may be as costly as
+		// encoding it, so only encoders that would be chosen are asked
+		if encQ <= bestQ {
+			continue
+		}
+		if s, ok := enc.(Selective); ok && !s.CanEncode(v) {
+			continue
+		}
+		best, bestQ = enc, encQ
+	}
+
+	if best == nil {
+		return nil, ErrNotAcceptable
+	}
+	return best, nil
+}
+
+// Decoder returns the decoder for a Content-Type header. A missing
+// Content-Type is treated as JSON.
+func (reg *Registry) Decoder(contentType string) (Decoder, error) {
+	mediaType := "application/json"
+	if contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, ErrUnsupportedMediaType
+		}
+		mediaType = parsed
+	}
+
+	for _, dec := range reg.decoders {
+		for _, t := range dec.MediaTypes() {
+			if strings.EqualFold(t, mediaType) {
+				return dec, nil
+			}
+		}
+	}
+	return nil, ErrUnsupportedMediaType
+}
+
+// Decode reads a request body into v using the decoder for contentType
+func (reg *Registry) Decode(contentType string, r io.Reader, v interface{}) error {
+	dec, err := reg.Decoder(contentType)
+	if err != nil {
+		return err
+	}
+	return dec.Decode(r, v)
+}
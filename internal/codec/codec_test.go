@@ -0,0 +1,224 @@
+package codec
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+var testBook = models.Book{ID: 1, Title: "Clean Code", Author: "Robert C. Martin", PublishedYear: 2008}
+
+func TestRegistry_Negotiate(t *testing.T) {
+	reg := Default()
+	list := models.NewPaginatedResponse([]models.Book{testBook}, 1, 10, 1)
+
+	tests := []struct {
+		name    string
+		accept  string
+		payload interface{}
+		want    string
+		wantErr error
+	}{
+		{name: "empty accept", accept: "", payload: testBook, want: "application/json"},
+		{name: "wildcard", accept: "*/*", payload: testBook, want: "application/json"},
+		{name: "exact", accept: "application/xml", payload: testBook, want: "application/xml"},
+		{name: "alias", accept: "application/x-yaml", payload: testBook, want: "application/yaml"},
+		{name: "q-values", accept: "application/json;q=0.5, application/yaml;q=0.9", payload: testBook, want: "application/yaml"},
+		{name: "specific beats wildcard", accept: "*/*;q=0.1, application/msgpack", payload: testBook, want: "application/msgpack"},
+		{name: "q zero excludes", accept: "application/json;q=0, */*", payload: testBook, want: "application/xml"},
+		{name: "type wildcard", accept: "text/*", payload: list, want: "text/xml"},
+		{name: "csv for list", accept: "text/csv", payload: list, want: "text/csv"},
+		{name: "csv not for single book", accept: "text/csv", payload: testBook, wantErr: ErrNotAcceptable},
+		{name: "unsupported", accept: "image/png", payload: testBook, wantErr: ErrNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := reg.Negotiate(tt.accept, tt.payload)
+			if err != tt.wantErr {
+				t.Fatalf("Negotiate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := enc.MediaTypes()[0]; got != tt.want && !contains(enc.MediaTypes(), tt.want) {
+				t.Errorf("Negotiate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingEncoder is a Selective encoder counting how often it is asked
+// whether it can encode a payload
+type countingEncoder struct {
+	CSV
+	checks *int
+}
+
+func (e countingEncoder) CanEncode(v interface{}) bool {
+	*e.checks++
+	return e.CSV.CanEncode(v)
+}
+
+func TestRegistry_Negotiate_ChecksOnlyAcceptedEncoders(t *testing.T) {
+	checks := 0
+	reg := NewRegistry()
+	reg.RegisterEncoder(JSON{})
+	reg.RegisterEncoder(countingEncoder{checks: &checks})
+	list := models.NewPaginatedResponse([]models.Book{testBook}, 1, 10, 1)
+
+	if _, err := reg.Negotiate("application/json", list); err != nil {
+		t.Fatalf("Negotiate() error = %v", err)
+	}
+	if _, err := reg.Negotiate("", list); err != nil {
+		t.Fatalf("Negotiate() error = %v", err)
+	}
+	if checks != 0 {
+		t.Errorf("expected CanEncode not to be called, got %d calls", checks)
+	}
+
+	enc, err := reg.Negotiate("application/json;q=0.5, text/csv", list)
+	if err != nil {
+		t.Fatalf("Negotiate() error = %v", err)
+	}
+	if checks != 1 || enc.MediaTypes()[0] != "text/csv" {
+		t.Errorf("expected CSV after 1 check, got %s after %d", enc.MediaTypes()[0], checks)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRegistry_Decoder(t *testing.T) {
+	reg := Default()
+
+	tests := []struct {
+		contentType string
+		wantErr     error
+	}{
+		{contentType: "", wantErr: nil},
+		{contentType: "application/json; charset=utf-8", wantErr: nil},
+		{contentType: "application/xml", wantErr: nil},
+		{contentType: "application/yaml", wantErr: nil},
+		{contentType: "application/msgpack", wantErr: nil},
+		{contentType: "text/csv", wantErr: ErrUnsupportedMediaType},
+		{contentType: "text/plain", wantErr: ErrUnsupportedMediaType},
+		{contentType: ";;;", wantErr: ErrUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			if _, err := reg.Decoder(tt.contentType); err != tt.wantErr {
+				t.Errorf("Decoder(%q) error = %v, wantErr %v", tt.contentType, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	codecs := []interface {
+		Encoder
+		Decoder
+	}{JSON{}, XML{}, YAML{}, MessagePack{}}
+	book := testBook
+	book.Tags = []string{"classic", "craft"}
+
+	for _, c := range codecs {
+		t.Run(c.MediaTypes()[0], func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := c.Encode(&buf, book); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			var got models.Book
+			if err := c.Decode(&buf, &got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, book) {
+				t.Errorf("round trip = %+v, want %+v", got, book)
+			}
+		})
+	}
+}
+
+func TestXML_Encode(t *testing.T) {
+	var buf bytes.Buffer
+	payload := map[string]interface{}{"data": []models.Book{testBook}, "page": 1}
+	if err := (XML{}).Encode(&buf, payload); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	want := "<response><data><item><id>1</id><title>Clean Code</title>" +
+		"<author>Robert C. Martin</author><published_year>2008</published_year>" +
+		"</item></data><page>1</page></response>"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("unexpected XML:\n%s", buf.String())
+	}
+}
+
+func TestYAML_Encode(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (YAML{}).Encode(&buf, models.Book{ID: 2, Title: "123", Author: "true"}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	// Strings that look like other YAML types must stay quoted
+	want := "id: 2\ntitle: \"123\"\nauthor: \"true\"\n"
+	if buf.String() != want {
+		t.Errorf("unexpected YAML:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
+
+func TestCSV_Encode(t *testing.T) {
+	books := []models.Book{
+		testBook,
+		{ID: 2, Title: "Design Patterns", Author: "Gamma, Erich", ISBN: "0201633612"},
+	}
+
+	var buf bytes.Buffer
+	if err := (CSV{}).Encode(&buf, models.NewPaginatedResponse(books, 1, 10, 2)); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	want := "id,title,author,published_year,isbn\n" +
+		"1,Clean Code,Robert C. Martin,2008,\n" +
+		"2,Design Patterns,\"Gamma, Erich\",,0201633612\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestMessagePack_Encode(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (MessagePack{}).Encode(&buf, testBook); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := msgpack.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode MessagePack: %v", err)
+	}
+	if got["title"] != "Clean Code" {
+		t.Errorf("expected title Clean Code, got %v", got["title"])
+	}
+}
+
+func TestXML_DecodeInvalidNumber(t *testing.T) {
+	body := "<book><title>T</title><author>A</author><published_year>soon</published_year></book>"
+
+	var book models.Book
+	if err := (XML{}).Decode(strings.NewReader(body), &book); err == nil {
+		t.Error("expected an error for a non-numeric year")
+	}
+}
@@ -0,0 +1,132 @@
+package codec
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// CSV encodes lists of records as text/csv. It accepts a slice of objects
+// or an object whose "data" member is one, such as a paginated response;
+// any other payload is rejected during negotiation.
+type CSV struct{}
+
+// MediaTypes returns the CSV media types
+func (CSV) MediaTypes() []string {
+	return []string{"text/csv"}
+}
+
+// CanEncode reports whether v is a list of records
+func (CSV) CanEncode(v interface{}) bool {
+	tree, err := toTree(v)
+	if err != nil {
+		return false
+	}
+	_, ok := csvRows(tree)
+	return ok
+}
+
+// Encode writes the records in v as CSV with a header row. Columns appear
+// in the order their keys are first seen; nested values are written as JSON.
+func (CSV) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+	rows, ok := csvRows(tree)
+	if !ok {
+		return ErrNotAcceptable
+	}
+
+	var columns []string
+	index := map[string]int{}
+	for _, row := range rows {
+		for _, m := range row {
+			if _, seen := index[m.Key]; !seen {
+				index[m.Key] = len(columns)
+				columns = append(columns, m.Key)
+			}
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for _, m := range row {
+			cell, err := csvCell(m.Value)
+			if err != nil {
+				return err
+			}
+			record[index[m.Key]] = cell
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvRows extracts the records from a list payload
+func csvRows(tree interface{}) ([]object, bool) {
+	if obj, ok := tree.(object); ok {
+		for _, m := range obj {
+			if m.Key == "data" {
+				tree = m.Value
+				break
+			}
+		}
+	}
+
+	items, ok := tree.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	rows := make([]object, 0, len(items))
+	for _, item := range items {
+		row, ok := item.(object)
+		if !ok {
+			return nil, false
+		}
+		rows = append(rows, row)
+	}
+	return rows, true
+}
+
+func csvCell(value interface{}) (string, error) {
+	switch val := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case object, []interface{}:
+		data, err := json.Marshal(plain(val))
+		return string(data), err
+	default:
+		return fmt.Sprint(val), nil
+	}
+}
+
+// plain converts a tree back into values encoding/json can marshal
+func plain(value interface{}) interface{} {
+	switch val := value.(type) {
+	case object:
+		m := make(map[string]interface{}, len(val))
+		for _, member := range val {
+			m[member.Key] = plain(member.Value)
+		}
+		return m
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = plain(item)
+		}
+		return out
+	default:
+		return val
+	}
+}
@@ -0,0 +1,24 @@
+package codec
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSON encodes and decodes application/json
+type JSON struct{}
+
+// MediaTypes returns the JSON media types
+func (JSON) MediaTypes() []string {
+	return []string{"application/json"}
+}
+
+// Encode writes v as JSON
+func (JSON) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// Decode reads JSON into v
+func (JSON) Decode(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
@@ -0,0 +1,77 @@
+package codec
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MessagePack encodes and decodes application/msgpack
+type MessagePack struct{}
+
+// MediaTypes returns the MessagePack media types
+func (MessagePack) MediaTypes() []string {
+	return []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}
+}
+
+// Encode writes v as MessagePack, keeping JSON field names and order
+func (MessagePack) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+	return writeMsgpack(msgpack.NewEncoder(w), tree)
+}
+
+func writeMsgpack(enc *msgpack.Encoder, value interface{}) error {
+	switch val := value.(type) {
+	case object:
+		if err := enc.EncodeMapLen(len(val)); err != nil {
+			return err
+		}
+		for _, m := range val {
+			if err := enc.EncodeString(m.Key); err != nil {
+				return err
+			}
+			if err := writeMsgpack(enc, m.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		if err := enc.EncodeArrayLen(len(val)); err != nil {
+			return err
+		}
+		for _, item := range val {
+			if err := writeMsgpack(enc, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return enc.EncodeInt(n)
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return err
+		}
+		return enc.EncodeFloat64(f)
+	case string:
+		return enc.EncodeString(val)
+	case bool:
+		return enc.EncodeBool(val)
+	default:
+		return enc.EncodeNil()
+	}
+}
+
+// Decode reads a MessagePack value into v
+func (MessagePack) Decode(r io.Reader, v interface{}) error {
+	generic, err := msgpack.NewDecoder(r).DecodeInterface()
+	if err != nil {
+		return err
+	}
+	return assign(generic, v)
+}
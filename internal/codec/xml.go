@@ -0,0 +1,165 @@
+package codec
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// XML encodes and decodes application/xml. Objects become elements named
+// after their keys, array entries become <item> elements and the document
+// root is <response>.
+type XML struct{}
+
+const (
+	xmlRoot = "response"
+	xmlItem = "item"
+)
+
+// MediaTypes returns the XML media types
+func (XML) MediaTypes() []string {
+	return []string{"application/xml", "text/xml"}
+}
+
+// Encode writes v as an XML document
+func (XML) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	if err := writeXMLElement(enc, xmlRoot, tree); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func writeXMLElement(enc *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch val := value.(type) {
+	case object:
+		for _, m := range val {
+			if err := writeXMLElement(enc, m.Key, m.Value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range val {
+			if err := writeXMLElement(enc, xmlItem, item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(val))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlName turns a JSON key into a valid XML element name
+func xmlName(key string) string {
+	var sb strings.Builder
+	for i, r := range key {
+		valid := unicode.IsLetter(r) || r == '_' ||
+			(i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'))
+		if !valid {
+			r = '_'
+		}
+		sb.WriteRune(r)
+	}
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
+
+// Decode reads an XML document into v. Element text is converted to the
+// field types of v, so <published_year>2008</published_year> fills an int.
+func (XML) Decode(r io.Reader, v interface{}) error {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			generic, err := readXMLElement(dec)
+			if err != nil {
+				return err
+			}
+			generic, err = coerce(generic, reflect.TypeOf(v))
+			if err != nil {
+				return fmt.Errorf("element %s: %w", start.Name.Local, err)
+			}
+			return assign(generic, v)
+		}
+	}
+}
+
+// readXMLElement reads the content of an element whose start tag has been
+// consumed. Elements with children become maps (or slices when every child
+// is an <item>); leaf elements become strings.
+func readXMLElement(dec *xml.Decoder) (interface{}, error) {
+	var text strings.Builder
+	children := map[string]interface{}{}
+	var items []interface{}
+	hasChildren := false
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			hasChildren = true
+			child, err := readXMLElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			if name == xmlItem {
+				items = append(items, child)
+				continue
+			}
+			switch existing := children[name].(type) {
+			case nil:
+				children[name] = child
+			case []interface{}:
+				children[name] = append(existing, child)
+			default:
+				children[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			switch {
+			case !hasChildren:
+				return strings.TrimSpace(text.String()), nil
+			case len(children) == 0:
+				return items, nil
+			default:
+				return children, nil
+			}
+		}
+	}
+}
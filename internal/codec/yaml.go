@@ -0,0 +1,75 @@
+package codec
+
+import (
+	"encoding/json"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML encodes and decodes application/yaml
+type YAML struct{}
+
+// MediaTypes returns the YAML media types
+func (YAML) MediaTypes() []string {
+	return []string{"application/yaml", "application/x-yaml", "text/yaml"}
+}
+
+// Encode writes v as a YAML document
+func (YAML) Encode(w io.Writer, v interface{}) error {
+	tree, err := toTree(v)
+	if err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(tree)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func yamlNode(value interface{}) *yaml.Node {
+	switch val := value.(type) {
+	case object:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, m := range val {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: m.Key},
+				yamlNode(m.Value),
+			)
+		}
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range val {
+			node.Content = append(node.Content, yamlNode(item))
+		}
+		return node
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: val.String()}
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: val.String()}
+	case bool:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}
+		if val {
+			node.Value = "true"
+		}
+		return node
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: val}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+// Decode reads a YAML document into v
+func (YAML) Decode(r io.Reader, v interface{}) error {
+	var generic interface{}
+	if err := yaml.NewDecoder(r).Decode(&generic); err != nil {
+		return err
+	}
+	return assign(generic, v)
+}
@@ -3,19 +3,135 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 // Config holds the application configuration
 type Config struct {
 	ServerPort string
+	GRPCPort   string
 	LogLevel   string
+
+	// GraphQLMaxDepth limits how deeply GraphQL selections may nest
+	GraphQLMaxDepth int
+	// GraphQLMaxComplexity limits the estimated fields a GraphQL query resolves
+	GraphQLMaxComplexity int
+
+	// EventLogSize is the number of change events kept for Last-Event-ID replay
+	EventLogSize int
+	// AuditLogSize is the number of audit entries kept, oldest dropped first
+	AuditLogSize int
+	// SSEHeartbeatSeconds is the idle interval between event stream heartbeats
+	SSEHeartbeatSeconds int
+
+	// WebhookStateFile persists webhook subscriptions and pending deliveries;
+	// empty keeps them in memory only
+	WebhookStateFile string
+	// WebhookMaxAttempts is the number of delivery attempts before dead-lettering
+	WebhookMaxAttempts int
+	// WebhookTimeoutSeconds bounds each delivery request
+	WebhookTimeoutSeconds int
+	// WebhookWorkers is the most webhook deliveries sent at once
+	WebhookWorkers int
+
+	// TrashRetentionHours is how long deleted books stay restorable
+	TrashRetentionHours int
+	// TrashPurgeIntervalMinutes is how often expired books are purged; 0
+	// turns purging off
+	TrashPurgeIntervalMinutes int
+
+	// IdempotencyTTLHours is how long responses to Idempotency-Key requests
+	// are kept for replay
+	IdempotencyTTLHours int
+
+	// LoanPolicyFile is a JSON file of loan policies by patron type; empty
+	// uses the built-in defaults
+	LoanPolicyFile string
+	// HoldPickupDays is how long a copy is set aside for a ready hold
+	HoldPickupDays int
+	// CirculationScanIntervalMinutes is how often loans are checked for being
+	// overdue and ready holds for expiry
+	CirculationScanIntervalMinutes int
+
+	// BlobStore is the driver cover images and ebook files are kept with:
+	// local or memory
+	BlobStore string
+	// BlobDir is the directory the local blob store keeps files in
+	BlobDir string
+	// CoverMaxBytes is the largest cover image upload accepted
+	CoverMaxBytes int
+	// IngestMaxBytes is the largest EPUB or PDF upload accepted
+	IngestMaxBytes int
+	// OPDSTitle is the name e-reader apps show for the OPDS catalog
+	OPDSTitle string
+
+	// EnrichProviders lists the metadata providers ISBNs are looked up with,
+	// in order; none disables lookups
+	EnrichProviders []string
+	// OpenLibraryURL is the server the openlibrary provider asks
+	OpenLibraryURL string
+	// EnrichTimeoutSeconds bounds each provider request and cover download
+	EnrichTimeoutSeconds int
+	// EnrichCacheTTLHours is how long lookup results are cached
+	EnrichCacheTTLHours int
+	// EnrichOnCreate fills in books created with an ISBN by default
+	EnrichOnCreate bool
+
+	// DuplicateThreshold is the score, from 0 to 1, from which books are
+	// reported as likely duplicates
+	DuplicateThreshold float64
+	// SearchFuzziness is the number of typos, from 0 to 2, forgiven in each
+	// word of a book search by default
+	SearchFuzziness int
+	// TypeaheadRefreshMinutes is how often the typeahead index is rebuilt
+	// to rank books by their latest ratings; 0 turns rebuilding off
+	TypeaheadRefreshMinutes int
 }
 
 // Load loads configuration from environment variables with defaults
 func Load() *Config {
 	return &Config{
 		ServerPort: getEnv("SERVER_PORT", "8080"),
+		GRPCPort:   getEnv("GRPC_PORT", "9090"),
 		LogLevel:   getEnv("LOG_LEVEL", "info"),
+
+		GraphQLMaxDepth:      getEnvAsInt("GRAPHQL_MAX_DEPTH", 10),
+		GraphQLMaxComplexity: getEnvAsInt("GRAPHQL_MAX_COMPLEXITY", 1000),
+
+		EventLogSize:        getEnvAsInt("EVENT_LOG_SIZE", 1000),
+		AuditLogSize:        getEnvAsInt("AUDIT_LOG_SIZE", 10000),
+		SSEHeartbeatSeconds: getEnvAsInt("SSE_HEARTBEAT_SECONDS", 15),
+
+		WebhookStateFile:      getEnv("WEBHOOK_STATE_FILE", "data/webhooks.json"),
+		WebhookMaxAttempts:    getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookTimeoutSeconds: getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+		WebhookWorkers:        getEnvAsInt("WEBHOOK_WORKERS", 8),
+
+		TrashRetentionHours:       getEnvAsInt("TRASH_RETENTION_HOURS", 720),
+		TrashPurgeIntervalMinutes: getEnvAsInt("TRASH_PURGE_INTERVAL_MINUTES", 60),
+
+		IdempotencyTTLHours: getEnvAsInt("IDEMPOTENCY_TTL_HOURS", 24),
+
+		LoanPolicyFile:                 getEnv("LOAN_POLICY_FILE", ""),
+		HoldPickupDays:                 getEnvAsInt("HOLD_PICKUP_DAYS", 7),
+		CirculationScanIntervalMinutes: getEnvAsInt("CIRCULATION_SCAN_INTERVAL_MINUTES", 15),
+
+		BlobStore:      getEnv("BLOB_STORE", "local"),
+		BlobDir:        getEnv("BLOB_DIR", "data/blobs"),
+		CoverMaxBytes:  getEnvAsInt("COVER_MAX_BYTES", 5242880),
+		IngestMaxBytes: getEnvAsInt("INGEST_MAX_BYTES", 52428800),
+		OPDSTitle:      getEnv("OPDS_TITLE", "Library Catalog"),
+
+		EnrichProviders:      getEnvAsList("ENRICH_PROVIDERS", "openlibrary"),
+		OpenLibraryURL:       getEnv("OPENLIBRARY_URL", "https://openlibrary.org"),
+		EnrichTimeoutSeconds: getEnvAsInt("ENRICH_TIMEOUT_SECONDS", 5),
+		EnrichCacheTTLHours:  getEnvAsInt("ENRICH_CACHE_TTL_HOURS", 24),
+		EnrichOnCreate:       getEnvAsBool("ENRICH_ON_CREATE", false),
+
+		DuplicateThreshold: getEnvAsFloat("DUPLICATE_THRESHOLD", 0.85),
+		SearchFuzziness:    getEnvAsInt("SEARCH_FUZZINESS", 2),
+
+		TypeaheadRefreshMinutes: getEnvAsInt("TYPEAHEAD_REFRESH_MINUTES", 15),
 	}
 }
 
@@ -35,3 +151,33 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsFloat gets an environment variable as a float with a default value
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsBool gets an environment variable as a boolean with a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsList gets an environment variable as a comma-separated list with
+// a default value; the value none gives an empty list
+func getEnvAsList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" && item != "none" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,339 @@
+// Package covers stores the cover images of books in a blob store, makes
+// thumbnails of them and keeps a description of each book's cover on the
+// book.
+package covers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // registers the PNG decoder
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+
+	"github.com/codeforgood-org/golang-book-api/internal/blob"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+const (
+	// DefaultMaxBytes is the default limit on the size of an upload
+	DefaultMaxBytes = 5 << 20
+
+	// MaxDimension is the most pixels a cover can have on either side
+	MaxDimension = 8000
+
+	// thumbnailQuality is the JPEG quality thumbnails are encoded at
+	thumbnailQuality = 85
+)
+
+var (
+	// ErrNoCover is returned when a book has no cover
+	ErrNoCover = errors.New("book has no cover")
+
+	// ErrUnsupportedType is returned when an upload is not a JPEG, PNG or
+	// WebP image
+	ErrUnsupportedType = errors.New("cover must be a JPEG, PNG or WebP image")
+
+	// ErrInvalidImage is returned when an upload cannot be decoded
+	ErrInvalidImage = errors.New("cover image is corrupt or truncated")
+
+	// ErrTooLarge is returned when an upload has more bytes than allowed
+	ErrTooLarge = errors.New("cover image is too large")
+
+	// ErrTooManyPixels is returned when an upload is wider or taller than
+	// MaxDimension
+	ErrTooManyPixels = errors.New("cover image must be at most 8000 pixels on each side")
+
+	// ErrUnknownSize is returned when a thumbnail size is not one of Sizes
+	ErrUnknownSize = errors.New("size must be small, medium or large")
+)
+
+// Size is a thumbnail size, named for use in URLs
+type Size struct {
+	Name  string
+	Width int
+}
+
+// Sizes lists the thumbnails made of every cover, smallest first. Covers are
+// never scaled up, so a thumbnail can be narrower than its size
+var Sizes = []Size{
+	{Name: "small", Width: 150},
+	{Name: "medium", Width: 300},
+	{Name: "large", Width: 600},
+}
+
+// contentTypes are the accepted upload types, as sniffed from their content
+var contentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// Image is a stored cover or thumbnail, ready to be served
+type Image struct {
+	Data        []byte
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+}
+
+// Service manages covers against the book storage and a blob store
+type Service struct {
+	books    storage.Storage
+	blobs    blob.Store
+	maxBytes int
+	// mu serializes writes so that a book's cover matches its blobs
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewService creates a covers service. The book storage must implement
+// storage.Covers for covers to appear on books
+func NewService(books storage.Storage, blobs blob.Store) *Service {
+	return &Service{
+		books:    books,
+		blobs:    blobs,
+		maxBytes: DefaultMaxBytes,
+		now:      time.Now,
+	}
+}
+
+// SetMaxBytes sets the largest upload accepted, in bytes
+func (s *Service) SetMaxBytes(n int) {
+	s.maxBytes = n
+}
+
+// MaxBytes returns the largest upload accepted, in bytes
+func (s *Service) MaxBytes() int {
+	return s.maxBytes
+}
+
+// Upload validates an image, stores it with its thumbnails as the cover of a
+// book, replacing any earlier cover, and returns the new cover
+func (s *Service) Upload(bookID int, data []byte) (*models.Cover, error) {
+	if len(data) > s.maxBytes {
+		return nil, ErrTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if !contentTypes[contentType] {
+		return nil, ErrUnsupportedType
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	// A JPEG can claim to be zero pixels wide and still decode
+	if config.Width < 1 || config.Height < 1 {
+		return nil, ErrInvalidImage
+	}
+	if config.Width > MaxDimension || config.Height > MaxDimension {
+		return nil, ErrTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+
+	base := fmt.Sprintf("/books/%d/cover", bookID)
+	cover := &models.Cover{
+		URL:         base,
+		ContentType: contentType,
+		Width:       config.Width,
+		Height:      config.Height,
+		Bytes:       len(data),
+		ETag:        etag(data),
+		Thumbnails:  make([]models.Thumbnail, 0, len(Sizes)),
+		UpdatedAt:   s.now().UTC(),
+	}
+	if err := s.blobs.Put(key(bookID, "original"), data); err != nil {
+		return nil, err
+	}
+	for _, size := range Sizes {
+		thumb, bounds, err := thumbnail(img, size.Width)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.blobs.Put(key(bookID, size.Name), thumb); err != nil {
+			return nil, err
+		}
+		cover.Thumbnails = append(cover.Thumbnails, models.Thumbnail{
+			Size:        size.Name,
+			URL:         base + "?size=" + size.Name,
+			ContentType: "image/jpeg",
+			Width:       bounds.Dx(),
+			Height:      bounds.Dy(),
+			Bytes:       len(thumb),
+			ETag:        etag(thumb),
+		})
+	}
+
+	if covers, ok := s.books.(storage.Covers); ok {
+		if err := covers.SetCover(bookID, cover); err != nil {
+			return nil, err
+		}
+	}
+	return cover, nil
+}
+
+// Image returns the cover of a book, or its thumbnail of the named size if
+// size is not empty
+func (s *Service) Image(bookID int, size string) (*Image, error) {
+	book, err := s.books.GetByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if size != "" && !validSize(size) {
+		return nil, ErrUnknownSize
+	}
+	if book.Cover == nil {
+		return nil, ErrNoCover
+	}
+
+	img := &Image{ContentType: book.Cover.ContentType, ETag: book.Cover.ETag, ModTime: book.Cover.UpdatedAt}
+	name := "original"
+	if size != "" {
+		thumb := book.Cover.Thumbnail(size)
+		if thumb == nil {
+			return nil, ErrNoCover
+		}
+		img.ContentType, img.ETag, name = thumb.ContentType, thumb.ETag, size
+	}
+	img.Data, err = s.blobs.Get(key(bookID, name))
+	if err == blob.ErrNotFound {
+		return nil, ErrNoCover
+	}
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// Delete removes the cover of a book and its thumbnails
+func (s *Service) Delete(bookID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	book, err := s.books.GetByID(bookID)
+	if err != nil {
+		return err
+	}
+	if book.Cover == nil {
+		return ErrNoCover
+	}
+
+	if covers, ok := s.books.(storage.Covers); ok {
+		if err := covers.SetCover(bookID, nil); err != nil {
+			return err
+		}
+	}
+	return s.deleteImages(bookID, book.Cover)
+}
+
+// ForgetBook removes the cover images of a book purged from the book
+// storage, which still carries the cover it had
+func (s *Service) ForgetBook(book models.Book) error {
+	if book.Cover == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deleteImages(book.ID, book.Cover)
+}
+
+// deleteImages removes the blobs of a book's cover and its thumbnails
+func (s *Service) deleteImages(bookID int, cover *models.Cover) error {
+	names := []string{"original"}
+	for _, size := range cover.Thumbnails {
+		names = append(names, size.Size)
+	}
+	for _, name := range names {
+		if err := s.blobs.Delete(key(bookID, name)); err != nil && err != blob.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// MoveBook makes the cover of one book the cover of another that has none
+// when the first is merged into it, and removes the first book's images
+func (s *Service) MoveBook(from, to int) error {
+	source, err := s.books.GetByID(from)
+	if err != nil {
+		return err
+	}
+	if source.Cover == nil {
+		return nil
+	}
+	target, err := s.books.GetByID(to)
+	if err != nil {
+		return err
+	}
+	if target.Cover == nil {
+		img, err := s.Image(from, "")
+		if err != nil {
+			return err
+		}
+		if _, err := s.Upload(to, img.Data); err != nil {
+			return err
+		}
+	}
+	return s.Delete(from)
+}
+
+// thumbnail scales img down to width, keeping its aspect ratio, and encodes
+// it as a JPEG. Transparent areas become white
+func thumbnail(img image.Image, width int) ([]byte, image.Rectangle, error) {
+	src := img.Bounds()
+	if src.Dx() < width {
+		width = src.Dx()
+	}
+	height := max(1, (src.Dy()*width+src.Dx()/2)/src.Dx())
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, image.Rectangle{}, err
+	}
+	return buf.Bytes(), dst.Bounds(), nil
+}
+
+// validSize reports whether name is one of Sizes
+func validSize(name string) bool {
+	for _, size := range Sizes {
+		if size.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns the blob key of a book's cover or one of its thumbnails
+func key(bookID int, name string) string {
+	return fmt.Sprintf("covers/%d/%s", bookID, name)
+}
+
+// etag returns a strong entity tag for data
+func etag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
@@ -0,0 +1,250 @@
+package covers
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/blob"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newTestService returns a service with one book and its blob store
+func newTestService(t *testing.T) (*Service, *models.Book, *blob.MemoryStore) {
+	t.Helper()
+
+	books := storage.NewMemoryStorage()
+	blobs := blob.NewMemoryStore()
+	s := NewService(books, blobs)
+	s.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	return s, book, blobs
+}
+
+// pngImage returns a PNG of the given size
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// zeroWidthJPEG returns a JPEG whose frame header claims a width of zero
+func zeroWidthJPEG(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatalf("failed to encode JPEG: %v", err)
+	}
+	data := buf.Bytes()
+	// The start of frame segment holds its length, the sample precision, the
+	// height and then the width
+	sof := bytes.Index(data, []byte{0xFF, 0xC0})
+	if sof < 0 {
+		t.Fatal("expected a start of frame segment")
+	}
+	data[sof+7], data[sof+8] = 0, 0
+	return data
+}
+
+func TestService_Upload(t *testing.T) {
+	s, book, _ := newTestService(t)
+
+	cover, err := s.Upload(book.ID, pngImage(t, 800, 1200))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cover.ContentType != "image/png" || cover.Width != 800 || cover.Height != 1200 || cover.ETag == "" {
+		t.Errorf("expected an 800x1200 PNG with an ETag, got %+v", cover)
+	}
+
+	expected := []struct {
+		size          string
+		width, height int
+	}{
+		{"small", 150, 225},
+		{"medium", 300, 450},
+		{"large", 600, 900},
+	}
+	if len(cover.Thumbnails) != len(expected) {
+		t.Fatalf("expected %d thumbnails, got %d", len(expected), len(cover.Thumbnails))
+	}
+	for i, want := range expected {
+		thumb := cover.Thumbnails[i]
+		if thumb.Size != want.size || thumb.Width != want.width || thumb.Height != want.height || thumb.ContentType != "image/jpeg" {
+			t.Errorf("expected a %dx%d %s JPEG, got %+v", want.width, want.height, want.size, thumb)
+		}
+	}
+
+	// The cover is kept on the book
+	stored, _ := s.books.GetByID(book.ID)
+	if stored.Cover == nil || stored.Cover.ETag != cover.ETag || stored.Cover.URL == "" {
+		t.Errorf("expected the cover on the book, got %+v", stored.Cover)
+	}
+}
+
+func TestService_UploadDoesNotScaleUp(t *testing.T) {
+	s, book, _ := newTestService(t)
+
+	cover, err := s.Upload(book.ID, pngImage(t, 200, 300))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if large := cover.Thumbnail("large"); large == nil || large.Width != 200 || large.Height != 300 {
+		t.Errorf("expected the large thumbnail to stay 200x300, got %+v", large)
+	}
+}
+
+func TestService_UploadValidation(t *testing.T) {
+	s, book, _ := newTestService(t)
+	s.SetMaxBytes(1 << 20)
+
+	var gifImage bytes.Buffer
+	gif.Encode(&gifImage, image.NewPaletted(image.Rect(0, 0, 10, 10), color.Palette{color.White}), nil)
+	valid := pngImage(t, 10, 10)
+
+	tests := []struct {
+		name    string
+		bookID  int
+		data    []byte
+		wantErr error
+	}{
+		{"unsupported type", book.ID, gifImage.Bytes(), ErrUnsupportedType},
+		{"not an image", book.ID, []byte("hello, world"), ErrUnsupportedType},
+		{"truncated", book.ID, valid[:len(valid)/2], ErrInvalidImage},
+		{"too many bytes", book.ID, make([]byte, 1<<20+1), ErrTooLarge},
+		{"too wide", book.ID, pngImage(t, MaxDimension+1, 1), ErrTooManyPixels},
+		{"zero width", book.ID, zeroWidthJPEG(t), ErrInvalidImage},
+		{"unknown book", 999, valid, models.ErrBookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Upload(tt.bookID, tt.data); err != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestService_Image(t *testing.T) {
+	s, book, _ := newTestService(t)
+
+	if _, err := s.Image(book.ID, ""); err != ErrNoCover {
+		t.Errorf("expected ErrNoCover, got %v", err)
+	}
+
+	data := pngImage(t, 400, 600)
+	cover, _ := s.Upload(book.ID, data)
+
+	original, err := s.Image(book.ID, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !bytes.Equal(original.Data, data) || original.ContentType != "image/png" || original.ETag != cover.ETag {
+		t.Errorf("expected the original PNG, got %s with ETag %s", original.ContentType, original.ETag)
+	}
+
+	small, err := s.Image(book.ID, "small")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if small.ContentType != "image/jpeg" || small.ETag != cover.Thumbnail("small").ETag {
+		t.Errorf("expected the small JPEG thumbnail, got %s with ETag %s", small.ContentType, small.ETag)
+	}
+
+	if _, err := s.Image(book.ID, "huge"); err != ErrUnknownSize {
+		t.Errorf("expected ErrUnknownSize, got %v", err)
+	}
+
+	// A new upload replaces the cover and its ETag
+	replaced, _ := s.Upload(book.ID, pngImage(t, 300, 300))
+	if replaced.ETag == cover.ETag {
+		t.Error("expected a new ETag for a new cover")
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	s, book, blobs := newTestService(t)
+
+	s.Upload(book.ID, pngImage(t, 400, 600))
+	if err := s.Delete(book.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if stored, _ := s.books.GetByID(book.ID); stored.Cover != nil {
+		t.Errorf("expected no cover on the book, got %+v", stored.Cover)
+	}
+	for _, name := range []string{"original", "small", "medium", "large"} {
+		if _, err := blobs.Get(key(book.ID, name)); err != blob.ErrNotFound {
+			t.Errorf("expected %s to be deleted, got %v", name, err)
+		}
+	}
+	if err := s.Delete(book.ID); err != ErrNoCover {
+		t.Errorf("expected ErrNoCover, got %v", err)
+	}
+}
+
+func TestService_ForgetBook(t *testing.T) {
+	s, book, blobs := newTestService(t)
+
+	s.Upload(book.ID, pngImage(t, 400, 600))
+	s.books.Delete(book.ID)
+	// A negative retention purges everything in the trash
+	purger := storage.NewPurger(s.books, -time.Minute, 0)
+	purger.AddForgetter(s)
+	if purged, err := purger.PurgeOnce(context.Background()); err != nil || purged != 1 {
+		t.Fatalf("expected 1 book purged, got %d (%v)", purged, err)
+	}
+
+	for _, name := range []string{"original", "small", "medium", "large"} {
+		if _, err := blobs.Get(key(book.ID, name)); err != blob.ErrNotFound {
+			t.Errorf("expected %s to be deleted, got %v", name, err)
+		}
+	}
+	if err := s.ForgetBook(models.Book{ID: book.ID}); err != nil {
+		t.Errorf("expected no error for a book without a cover, got %v", err)
+	}
+}
+
+func TestService_MoveBook(t *testing.T) {
+	s, book, blobs := newTestService(t)
+	first, _ := s.books.Create(models.Book{Title: "Clean Code", Author: "Robert Martin"})
+	second, _ := s.books.Create(models.Book{Title: "Clean Code", Author: "R. C. Martin"})
+
+	s.Upload(first.ID, pngImage(t, 400, 600))
+	if err := s.MoveBook(first.ID, book.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stored, _ := s.books.GetByID(book.ID); stored.Cover == nil || stored.Cover.Width != 400 {
+		t.Errorf("expected the cover moved, got %+v", stored.Cover)
+	}
+	if _, err := blobs.Get(key(first.ID, "original")); err != blob.ErrNotFound {
+		t.Errorf("expected the old book's images removed, got %v", err)
+	}
+
+	// A book keeps its own cover
+	s.Upload(second.ID, pngImage(t, 100, 150))
+	if err := s.MoveBook(second.ID, book.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stored, _ := s.books.GetByID(book.ID); stored.Cover.Width != 400 {
+		t.Errorf("expected the existing cover kept, got %+v", stored.Cover)
+	}
+}
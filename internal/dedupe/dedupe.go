@@ -0,0 +1,342 @@
+// Package dedupe finds books entered more than once, by ISBN or by the
+// similarity of their titles and authors, and merges duplicate records into
+// one, redirecting the IDs of the books merged away.
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// DefaultThreshold is the score from which two books are taken to be
+// duplicates
+const DefaultThreshold = 0.85
+
+var (
+	// ErrInvalidThreshold is returned for a threshold outside (0, 1]
+	ErrInvalidThreshold = errors.New("threshold must be greater than 0 and at most 1")
+
+	// ErrNoBooks is returned when merging without naming a book to merge
+	ErrNoBooks = errors.New("ids must list at least one book to merge")
+
+	// ErrMergeIntoSelf is returned when a book is merged into itself
+	ErrMergeIntoSelf = errors.New("a book cannot be merged into itself")
+
+	// ErrNotSupported is returned when merging books in a storage that does
+	// not implement storage.Redirects
+	ErrNotSupported = errors.New("book storage cannot merge books")
+)
+
+// Pair is two books scored as alike
+type Pair struct {
+	BookIDs [2]int   `json:"book_ids"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Cluster is a group of books that are likely to be the same record, each
+// linked to another by a pair scoring at least the threshold
+type Cluster struct {
+	// Score is that of the most alike pair
+	Score float64 `json:"score"`
+	// Books are listed oldest first, the usual choice of the book to keep
+	Books []models.Book `json:"books"`
+	Pairs []Pair        `json:"pairs"`
+}
+
+// Match is a book scored as alike to another
+type Match struct {
+	Book    models.Book `json:"book"`
+	Score   float64     `json:"score"`
+	Reasons []string    `json:"reasons,omitempty"`
+}
+
+// Mover moves what belongs to one book, such as its copies or reviews, to
+// another when the first is merged into it
+type Mover interface {
+	MoveBook(from, to int) error
+}
+
+// Service finds and merges duplicate books in the book storage
+type Service struct {
+	books     storage.Storage
+	movers    []Mover
+	threshold float64
+	// mu serializes merges so that a book cannot be merged twice at once
+	mu sync.Mutex
+}
+
+// NewService creates a dedupe service. The book storage must implement
+// storage.Redirects for books to be merged
+func NewService(books storage.Storage) *Service {
+	return &Service{
+		books:     books,
+		threshold: DefaultThreshold,
+	}
+}
+
+// SetThreshold sets the score from which books are taken to be duplicates
+func (s *Service) SetThreshold(threshold float64) error {
+	if threshold <= 0 || threshold > 1 {
+		return ErrInvalidThreshold
+	}
+	s.threshold = threshold
+	return nil
+}
+
+// Threshold returns the score from which books are taken to be duplicates
+func (s *Service) Threshold() float64 {
+	return s.threshold
+}
+
+// AddMover makes merges move what m keeps for each merged book to the book
+// it is merged into
+func (s *Service) AddMover(m Mover) {
+	s.movers = append(s.movers, m)
+}
+
+// Duplicates returns the clusters of books scoring at least threshold, or
+// the service's threshold if it is 0, most alike first
+func (s *Service) Duplicates(threshold float64) ([]Cluster, error) {
+	if threshold == 0 {
+		threshold = s.threshold
+	}
+	if threshold < 0 || threshold > 1 {
+		return nil, ErrInvalidThreshold
+	}
+	books, err := s.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return clusters(books, threshold), nil
+}
+
+// Similar returns the books other than book itself that score at least the
+// service's threshold against it, most alike first
+func (s *Service) Similar(book models.Book) ([]Match, error) {
+	books, err := s.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	keys := blockKeys(book)
+
+	matches := make([]Match, 0)
+	for _, other := range books {
+		if other.ID == book.ID || !shareKey(keys, blockKeys(other)) {
+			continue
+		}
+		if score, reasons := Score(book, other); score >= s.threshold {
+			matches = append(matches, Match{Book: other, Score: score, Reasons: reasons})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches, nil
+}
+
+// Merge merges the books with the given IDs into the book into: its empty
+// fields are filled in from them, their genres and tags are added to its
+// own, everything the movers keep for them is moved to it, and they are
+// removed with their IDs redirected to it. It returns the merged book
+func (s *Service) Merge(ctx context.Context, into int, ids []int) (*models.Book, error) {
+	if len(ids) == 0 {
+		return nil, ErrNoBooks
+	}
+	if slices.Contains(ids, into) {
+		return nil, ErrMergeIntoSelf
+	}
+	store := storage.WithContext(s.books, ctx)
+	redirects, ok := store.(storage.Redirects)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target, err := s.books.GetByID(into)
+	if err != nil {
+		return nil, err
+	}
+	var sources []models.Book
+	for _, id := range ids {
+		if slices.ContainsFunc(sources, func(b models.Book) bool { return b.ID == id }) {
+			continue
+		}
+		book, err := s.books.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, *book)
+	}
+
+	merged := mergeBooks(*target, sources)
+	if err := merged.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := store.Update(into, merged); err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		for _, m := range s.movers {
+			if err := m.MoveBook(source.ID, into); err != nil {
+				return nil, fmt.Errorf("moving book %d to %d: %w", source.ID, into, err)
+			}
+		}
+		if err := redirects.Merge(source.ID, into); err != nil {
+			return nil, err
+		}
+	}
+	return s.books.GetByID(into)
+}
+
+// mergeBooks returns target with its empty fields filled in from the first
+// source that has them, and the genres and tags of every source added
+func mergeBooks(target models.Book, sources []models.Book) models.Book {
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	for _, source := range sources {
+		fill(&target.ISBN, source.ISBN)
+		fill(&target.Publisher, source.Publisher)
+		fill(&target.Language, source.Language)
+		fill(&target.Dewey, source.Dewey)
+		fill(&target.LCC, source.LCC)
+		if target.PublishedYear == 0 {
+			target.PublishedYear = source.PublishedYear
+		}
+		target.Genres = append(target.Genres, source.Genres...)
+		target.Tags = append(target.Tags, source.Tags...)
+	}
+	target.Genres = models.NormalizeLabels(target.Genres)
+	if target.Tags = models.NormalizeLabels(target.Tags); len(target.Tags) > models.MaxTags {
+		target.Tags = target.Tags[:models.MaxTags]
+	}
+	return target
+}
+
+// clusters groups the books scoring at least threshold, linking each book
+// to every book it is alike to, most alike clusters first
+func clusters(books []models.Book, threshold float64) []Cluster {
+	// Only books sharing an ISBN, an author surname or the start of a title
+	// are compared, rather than every pair
+	blocks := make(map[string][]int)
+	for i, book := range books {
+		for _, key := range blockKeys(book) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	parent := make([]int, len(books))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	compared := make(map[[2]int]bool)
+	var pairs []Pair
+	var members [][2]int
+	for _, block := range blocks {
+		for x := 0; x < len(block); x++ {
+			for y := x + 1; y < len(block); y++ {
+				i, j := block[x], block[y]
+				if compared[[2]int{i, j}] {
+					continue
+				}
+				compared[[2]int{i, j}] = true
+				score, reasons := Score(books[i], books[j])
+				if score < threshold {
+					continue
+				}
+				pairs = append(pairs, Pair{BookIDs: [2]int{books[i].ID, books[j].ID}, Score: score, Reasons: reasons})
+				members = append(members, [2]int{i, j})
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	byRoot := make(map[int]*Cluster)
+	indexes := make(map[int][]int)
+	for n, pair := range pairs {
+		root := find(members[n][0])
+		c, ok := byRoot[root]
+		if !ok {
+			c = &Cluster{}
+			byRoot[root] = c
+		}
+		c.Pairs = append(c.Pairs, pair)
+		c.Score = max(c.Score, pair.Score)
+		for _, i := range members[n] {
+			if !slices.Contains(indexes[root], i) {
+				indexes[root] = append(indexes[root], i)
+			}
+		}
+	}
+
+	list := make([]Cluster, 0, len(byRoot))
+	for root, c := range byRoot {
+		for _, i := range indexes[root] {
+			c.Books = append(c.Books, books[i])
+		}
+		sort.SliceStable(c.Books, func(i, j int) bool {
+			return c.Books[i].CreatedAt.Before(c.Books[j].CreatedAt)
+		})
+		sort.SliceStable(c.Pairs, func(i, j int) bool {
+			return c.Pairs[i].Score > c.Pairs[j].Score
+		})
+		list = append(list, *c)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].Books[0].CreatedAt.Before(list[j].Books[0].CreatedAt)
+	})
+	return list
+}
+
+// blockKeys returns the keys a book is filed under for comparison: its
+// ISBN, the surnames of its authors and the first letters of its title
+func blockKeys(book models.Book) []string {
+	var keys []string
+	if isbn := models.ISBN13(book.ISBN); isbn != "" {
+		keys = append(keys, "isbn:"+isbn)
+	}
+	for _, author := range book.Authors() {
+		if surname := Surname(author); surname != "" {
+			keys = append(keys, "author:"+surname)
+		}
+	}
+	if title := strings.Join(words(mainTitle(book.Title)), ""); title != "" {
+		prefix := []rune(title)
+		keys = append(keys, "title:"+string(prefix[:min(len(prefix), 4)]))
+	}
+	return keys
+}
+
+// shareKey reports whether two lists of block keys have a key in common
+func shareKey(a, b []string) bool {
+	for _, key := range a {
+		if slices.Contains(b, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// recordingMover records the books it is asked to move
+type recordingMover struct {
+	moves [][2]int
+	err   error
+}
+
+func (m *recordingMover) MoveBook(from, to int) error {
+	m.moves = append(m.moves, [2]int{from, to})
+	return m.err
+}
+
+// newTestService returns a service over a catalogue with two duplicated
+// books and one that is not
+func newTestService(t *testing.T) (*Service, *storage.MemoryStorage, []*models.Book) {
+	t.Helper()
+
+	books := storage.NewMemoryStorage()
+	var created []*models.Book
+	for _, book := range []models.Book{
+		{Title: "The Hobbit", Author: "J.R.R. Tolkien", Genres: []string{"fantasy"}},
+		{Title: "Dune", Author: "Frank Herbert", ISBN: "9780441013593"},
+		{Title: "Hobit, The", Author: "Tolkien, J. R. R.", Publisher: "Allen & Unwin", Tags: []string{"classic"}},
+		{Title: "Dune (Ace)", Author: "Herbert", ISBN: "0-441-01359-7", PublishedYear: 1990},
+		{Title: "Emma", Author: "Jane Austen"},
+	} {
+		b, err := books.Create(book)
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", book.Title, err)
+		}
+		created = append(created, b)
+	}
+	return NewService(books), books, created
+}
+
+// ids returns the IDs of books
+func ids(books []models.Book) []int {
+	var list []int
+	for _, b := range books {
+		list = append(list, b.ID)
+	}
+	slices.Sort(list)
+	return list
+}
+
+func TestService_Duplicates(t *testing.T) {
+	s, _, books := newTestService(t)
+	hobbit, dune := books[0], books[1]
+
+	clusters, err := s.Duplicates(0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %+v", clusters)
+	}
+	// The ISBN match scores 1 and comes first
+	if clusters[0].Score != 1 || !slices.Equal(ids(clusters[0].Books), ids([]models.Book{*dune, *books[3]})) {
+		t.Errorf("expected the Dune cluster first, got %+v", clusters[0])
+	}
+	if !slices.Equal(ids(clusters[1].Books), ids([]models.Book{*hobbit, *books[2]})) || len(clusters[1].Pairs) != 1 {
+		t.Errorf("expected the Hobbit cluster, got %+v", clusters[1])
+	}
+
+	if _, err := s.Duplicates(1.5); err != ErrInvalidThreshold {
+		t.Errorf("expected ErrInvalidThreshold, got %v", err)
+	}
+	if err := s.SetThreshold(0); err != ErrInvalidThreshold {
+		t.Errorf("expected ErrInvalidThreshold, got %v", err)
+	}
+}
+
+func TestService_Similar(t *testing.T) {
+	s, _, books := newTestService(t)
+
+	matches, err := s.Similar(models.Book{Title: "The Hobbit", Author: "J. R. R. Tolkien"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(matches) != 2 || matches[0].Score < matches[1].Score {
+		t.Fatalf("expected both Hobbits, most alike first, got %+v", matches)
+	}
+
+	// A book is not its own duplicate
+	matches, _ = s.Similar(*books[4])
+	if len(matches) != 0 {
+		t.Errorf("expected no matches, got %+v", matches)
+	}
+}
+
+func TestService_Merge(t *testing.T) {
+	s, books, created := newTestService(t)
+	hobbit, duplicate := created[0], created[2]
+	mover := &recordingMover{}
+	s.AddMover(mover)
+
+	merged, err := s.Merge(context.Background(), hobbit.ID, []int{duplicate.ID, duplicate.ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if merged.Title != "The Hobbit" || merged.Publisher != "Allen & Unwin" {
+		t.Errorf("expected the kept book's fields with the gaps filled, got %+v", merged)
+	}
+	if !slices.Equal(merged.Genres, []string{"fantasy"}) || !slices.Equal(merged.Tags, []string{"classic"}) {
+		t.Errorf("expected the genres and tags combined, got %v and %v", merged.Genres, merged.Tags)
+	}
+	if !slices.Equal(mover.moves, [][2]int{{duplicate.ID, hobbit.ID}}) {
+		t.Errorf("expected one move, got %v", mover.moves)
+	}
+	if _, err := books.GetByID(duplicate.ID); err != models.ErrBookNotFound {
+		t.Errorf("expected the duplicate removed, got %v", err)
+	}
+	if to, ok := books.Redirect(duplicate.ID); !ok || to != hobbit.ID {
+		t.Errorf("expected a redirect to %d, got %d", hobbit.ID, to)
+	}
+
+	tests := []struct {
+		name     string
+		into     int
+		ids      []int
+		expected error
+	}{
+		{"no books", hobbit.ID, nil, ErrNoBooks},
+		{"into itself", hobbit.ID, []int{hobbit.ID}, ErrMergeIntoSelf},
+		{"unknown target", 999999999, []int{created[4].ID}, models.ErrBookNotFound},
+		{"merged away already", hobbit.ID, []int{duplicate.ID}, models.ErrBookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Merge(context.Background(), tt.into, tt.ids); err != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestService_Merge_MoverFails(t *testing.T) {
+	s, books, created := newTestService(t)
+	s.AddMover(&recordingMover{err: errors.New("disk full")})
+
+	if _, err := s.Merge(context.Background(), created[1].ID, []int{created[3].ID}); err == nil {
+		t.Fatal("expected an error")
+	}
+	// The book is not removed while what belongs to it is left behind
+	if _, err := books.GetByID(created[3].ID); err != nil {
+		t.Errorf("expected the book kept, got %v", err)
+	}
+}
+
+// storageWithoutRedirects hides the Redirects methods of a memory storage
+type storageWithoutRedirects struct {
+	storage.Storage
+}
+
+func TestService_Merge_NotSupported(t *testing.T) {
+	_, books, created := newTestService(t)
+	s := NewService(storageWithoutRedirects{books})
+
+	if _, err := s.Merge(context.Background(), created[1].ID, []int{created[3].ID}); err != ErrNotSupported {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+}
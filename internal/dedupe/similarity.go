@@ -0,0 +1,200 @@
+package dedupe
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/fuzzy"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// Reasons a pair of books is scored as alike
+const (
+	ReasonISBN   = "isbn"
+	ReasonTitle  = "title"
+	ReasonAuthor = "author"
+)
+
+// Weights of the title and author similarities in the score of books
+// without a shared ISBN
+const (
+	titleWeight  = 0.6
+	authorWeight = 0.4
+)
+
+// subtitleScore is the title similarity of titles that differ only in that
+// one of them has a subtitle
+const subtitleScore = 0.9
+
+// initialScore is the similarity of names with the same surname where one
+// has only the initial of the other's forename
+const initialScore = 0.9
+
+// articles are left out when comparing titles, so that "The Hobbit" and
+// "Hobbit, The" match
+var articles = map[string]bool{"the": true, "a": true, "an": true}
+
+// Score rates how likely two books are to be the same record, from 0 to 1,
+// giving the reasons the score is high. Books with the same ISBN score 1;
+// books with different ISBNs score 0, since they are different editions,
+// which works link rather than merge. Other books are scored on the
+// similarity of their titles and authors
+func Score(a, b models.Book) (float64, []string) {
+	isbnA, isbnB := models.ISBN13(a.ISBN), models.ISBN13(b.ISBN)
+	if isbnA != "" && isbnB != "" {
+		if isbnA == isbnB {
+			return 1, []string{ReasonISBN}
+		}
+		return 0, nil
+	}
+
+	title := TitleSimilarity(a.Title, b.Title)
+	author := AuthorSimilarity(a.Authors(), b.Authors())
+	score := math.Round((titleWeight*title+authorWeight*author)*100) / 100
+
+	var reasons []string
+	if title >= DefaultThreshold {
+		reasons = append(reasons, ReasonTitle)
+	}
+	if author >= DefaultThreshold {
+		reasons = append(reasons, ReasonAuthor)
+	}
+	return score, reasons
+}
+
+// TitleSimilarity rates how alike two titles are, from 0 to 1, ignoring
+// case, punctuation, articles and word order
+func TitleSimilarity(a, b string) float64 {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	score := math.Max(
+		ratio(strings.Join(wordsA, " "), strings.Join(wordsB, " ")),
+		ratio(sortedWords(wordsA), sortedWords(wordsB)),
+	)
+
+	// "Dune" and "Dune: Deluxe Edition" are the same title
+	mainA, mainB := mainTitle(a), mainTitle(b)
+	if (mainA != a || mainB != b) && sortedWords(words(mainA)) == sortedWords(words(mainB)) {
+		score = math.Max(score, subtitleScore)
+	}
+	return score
+}
+
+// AuthorSimilarity rates how alike two lists of author names are, from 0 to
+// 1: the average, over the shorter list, of the similarity of each name to
+// its closest match in the other
+func AuthorSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	total := 0.0
+	for _, nameA := range a {
+		best := 0.0
+		for _, nameB := range b {
+			best = math.Max(best, NameSimilarity(nameA, nameB))
+		}
+		total += best
+	}
+	return total / float64(len(a))
+}
+
+// NameSimilarity rates how alike two personal names are, from 0 to 1, so
+// that "Herbert, Frank" matches "Frank Herbert" and "F. Herbert" nearly
+// matches it
+func NameSimilarity(a, b string) float64 {
+	wordsA, wordsB := nameWords(a), nameWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	score := ratio(sortedWords(wordsA), sortedWords(wordsB))
+
+	surnameA, forenameA := splitName(a)
+	surnameB, forenameB := splitName(b)
+	if surnameA != "" && surnameA == surnameB && forenameA != "" && forenameB != "" &&
+		[]rune(forenameA)[0] == []rune(forenameB)[0] {
+		score = math.Max(score, initialScore)
+	}
+	return score
+}
+
+// Surname returns the normalized surname of a name in either "Forename
+// Surname" or "Surname, Forename" form
+func Surname(name string) string {
+	surname, _ := splitName(name)
+	return surname
+}
+
+// splitName returns the normalized surname and forename of a name
+func splitName(name string) (surname, forename string) {
+	if before, after, ok := strings.Cut(name, ","); ok {
+		surnames, forenames := nameWords(before), nameWords(after)
+		if len(surnames) > 0 {
+			surname = surnames[len(surnames)-1]
+		}
+		if len(forenames) > 0 {
+			forename = forenames[0]
+		}
+		return surname, forename
+	}
+	parts := nameWords(name)
+	if len(parts) == 0 {
+		return "", ""
+	}
+	surname = parts[len(parts)-1]
+	if len(parts) > 1 {
+		forename = parts[0]
+	}
+	return surname, forename
+}
+
+// mainTitle returns a title without its subtitle
+func mainTitle(title string) string {
+	for _, sep := range []string{":", " - ", " — "} {
+		if before, _, ok := strings.Cut(title, sep); ok {
+			return before
+		}
+	}
+	return title
+}
+
+// words splits a title into lower-case words, dropping punctuation and
+// articles and spelling out ampersands
+func words(s string) []string {
+	s = strings.ReplaceAll(s, "&", " and ")
+	var list []string
+	for _, word := range fuzzy.Words(s) {
+		if !articles[word] {
+			list = append(list, word)
+		}
+	}
+	return list
+}
+
+// nameWords splits a name into lower-case words, dropping punctuation, so
+// that initials like "J.R.R." become single letters
+func nameWords(s string) []string {
+	return fuzzy.Words(strings.ReplaceAll(s, ".", " "))
+}
+
+// sortedWords joins words in alphabetical order
+func sortedWords(words []string) string {
+	sorted := append([]string(nil), words...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
+
+// ratio rates how alike two strings are, from 0 to 1, by their edit distance
+// relative to the longer of them
+func ratio(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(fuzzy.Distance(a, b))/float64(longest)
+}
@@ -0,0 +1,104 @@
+package dedupe
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{"The Hobbit", "The Hobbit", 1, 1},
+		{"The Hobbit", "hobbit, the", 1, 1},
+		{"The Hobbit", "The Hobit", 0.8, 0.9},
+		{"Pride & Prejudice", "Pride and Prejudice", 1, 1},
+		{"Dune", "Dune: Deluxe Edition", subtitleScore, subtitleScore},
+		{"Dune", "Emma", 0, 0.3},
+		{"", "Dune", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := TitleSimilarity(tt.a, tt.b); got < tt.min || got > tt.max {
+				t.Errorf("expected a similarity from %v to %v, got %v", tt.min, tt.max, got)
+			}
+		})
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{"Frank Herbert", "Herbert, Frank", 1, 1},
+		{"Frank Herbert", "F. Herbert", initialScore, initialScore},
+		{"J.R.R. Tolkien", "Tolkien, J. R. R.", 1, 1},
+		{"Frank Herbert", "Brian Herbert", 0.6, 0.8},
+		{"Frank Herbert", "Jane Austen", 0, 0.3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := NameSimilarity(tt.a, tt.b); got < tt.min || got > tt.max {
+				t.Errorf("expected a similarity from %v to %v, got %v", tt.min, tt.max, got)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    models.Book
+		min     float64
+		reasons []string
+	}{
+		{
+			"same ISBN in both forms",
+			models.Book{Title: "Dune", Author: "Frank Herbert", ISBN: "0-441-01359-7"},
+			models.Book{Title: "Dune (Ace)", Author: "Herbert", ISBN: "9780441013593"},
+			1, []string{ReasonISBN},
+		},
+		{
+			"typo in the title",
+			models.Book{Title: "The Hobbit", Author: "J.R.R. Tolkien"},
+			models.Book{Title: "The Hobit", Author: "Tolkien, J. R. R."},
+			DefaultThreshold, []string{ReasonAuthor},
+		},
+		{
+			"initial for a forename",
+			models.Book{Title: "Dune", Author: "Frank Herbert"},
+			models.Book{Title: "Dune", Author: "F. Herbert", ISBN: "9780441013593"},
+			DefaultThreshold, []string{ReasonTitle, ReasonAuthor},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := Score(tt.a, tt.b)
+			if score < tt.min || score > 1 {
+				t.Errorf("expected a score of at least %v, got %v", tt.min, score)
+			}
+			if !slices.Equal(reasons, tt.reasons) {
+				t.Errorf("expected reasons %v, got %v", tt.reasons, reasons)
+			}
+		})
+	}
+
+	// Different ISBNs are different editions, however alike the rest
+	a := models.Book{Title: "Dune", Author: "Frank Herbert", ISBN: "9780441013593"}
+	b := models.Book{Title: "Dune", Author: "Frank Herbert", ISBN: "9780593099322"}
+	if score, _ := Score(a, b); score != 0 {
+		t.Errorf("expected books with different ISBNs to score 0, got %v", score)
+	}
+	if score, _ := Score(a, models.Book{Title: "Emma", Author: "Jane Austen"}); score >= DefaultThreshold {
+		t.Errorf("expected different books to score below the threshold, got %v", score)
+	}
+}
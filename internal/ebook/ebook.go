@@ -0,0 +1,163 @@
+// Package ebook reads the bibliographic metadata of EPUB and PDF files: the
+// OPF package document of an EPUB, and the Info dictionary and XMP packet
+// of a PDF.
+package ebook
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// Format is the kind of an ebook file
+type Format string
+
+// Supported formats
+const (
+	EPUB Format = "epub"
+	PDF  Format = "pdf"
+)
+
+// ContentType returns the media type of files in the format
+func (f Format) ContentType() string {
+	switch f {
+	case EPUB:
+		return "application/epub+zip"
+	case PDF:
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+var (
+	// ErrUnsupportedFormat is returned for files that are neither EPUB nor
+	// PDF
+	ErrUnsupportedFormat = errors.New("file must be an EPUB or PDF")
+
+	// ErrInvalidEPUB is returned for EPUB files without a readable package
+	// document
+	ErrInvalidEPUB = errors.New("EPUB file is corrupt or has no package document")
+
+	// ErrInvalidPDF is returned for PDF files that cannot be read
+	ErrInvalidPDF = errors.New("PDF file is corrupt")
+)
+
+// Metadata is what a file says about the book it contains. Fields the file
+// does not give are left empty
+type Metadata struct {
+	Format    Format   `json:"format"`
+	Title     string   `json:"title,omitempty"`
+	Creators  []string `json:"creators,omitempty"`
+	ISBN      string   `json:"isbn,omitempty"`
+	Language  string   `json:"language,omitempty"`
+	Publisher string   `json:"publisher,omitempty"`
+	Year      int      `json:"year,omitempty"`
+	// Cover is the cover image of an EPUB, as stored in the file
+	Cover []byte `json:"-"`
+}
+
+// Detect returns the format of a file from its content
+func Detect(data []byte) (Format, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return PDF, nil
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return EPUB, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Read returns the metadata of an EPUB or PDF file
+func Read(data []byte) (*Metadata, error) {
+	format, err := Detect(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == PDF {
+		return ReadPDF(data)
+	}
+	return ReadEPUB(data)
+}
+
+// ToBook maps metadata to a book. The book still has to be validated
+func ToBook(m *Metadata) models.Book {
+	return models.Book{
+		Title:         m.Title,
+		Author:        models.JoinAuthors(m.Creators),
+		ISBN:          m.ISBN,
+		Publisher:     m.Publisher,
+		PublishedYear: m.Year,
+		Language:      m.Language,
+	}
+}
+
+// isbn returns the normalized ISBN in an identifier such as
+// urn:isbn:978-0-13-419044-0, or "" if it does not hold a valid one
+func isbn(identifier string) string {
+	identifier = strings.TrimSpace(identifier)
+	for _, prefix := range []string{"urn:isbn:", "isbn:", "isbn "} {
+		if len(identifier) > len(prefix) && strings.EqualFold(identifier[:len(prefix)], prefix) {
+			identifier = strings.TrimSpace(identifier[len(prefix):])
+			break
+		}
+	}
+	if !models.ValidISBN(identifier) {
+		return ""
+	}
+	return models.NormalizeISBN(identifier)
+}
+
+// pickISBN returns the best of the ISBNs in identifiers, preferring ISBN-13s
+func pickISBN(identifiers []string) string {
+	found := ""
+	for _, identifier := range identifiers {
+		if n := isbn(identifier); n != "" && (found == "" || len(n) > len(found)) {
+			found = n
+		}
+	}
+	return found
+}
+
+// year returns the year a date such as 2015-11-16 or D:20151116 starts with
+func year(date string) int {
+	date = strings.TrimPrefix(strings.TrimSpace(date), "D:")
+	if len(date) < 4 {
+		return 0
+	}
+	y, err := strconv.Atoi(date[:4])
+	if err != nil || y <= 0 {
+		return 0
+	}
+	return y
+}
+
+// clean collapses runs of white space in s and trims it
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// appendClean appends the cleaned values that are not empty or already
+// present
+func appendClean(list []string, values ...string) []string {
+	for _, v := range values {
+		if v = clean(v); v != "" && !contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
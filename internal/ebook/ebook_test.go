@@ -0,0 +1,398 @@
+package ebook
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// epubFile returns an EPUB holding the given files, with a container
+// pointing at OEBPS/content.opf
+func epubFile(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	all := map[string]string{
+		"mimetype": "application/epub+zip",
+		"META-INF/container.xml": `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>`,
+	}
+	for name, content := range files {
+		all[name] = content
+	}
+	for name, content := range all {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to write EPUB: %v", err)
+	}
+	return buf.Bytes()
+}
+
+const epub2OPF = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+    <dc:title>The Go
+      Programming Language</dc:title>
+    <dc:creator opf:role="aut">Alan A. A. Donovan</dc:creator>
+    <dc:creator opf:role="aut">Brian W. Kernighan</dc:creator>
+    <dc:creator opf:role="edt">Some Editor</dc:creator>
+    <dc:identifier id="uid">urn:uuid:0b4a2a0e-3d6e-4f4c-8a5e-2f5d4c3b2a1f</dc:identifier>
+    <dc:identifier opf:scheme="ISBN">978-0-13-419044-0</dc:identifier>
+    <dc:language>en</dc:language>
+    <dc:publisher>Addison-Wesley</dc:publisher>
+    <dc:date opf:event="publication">2015-10-26</dc:date>
+    <meta name="cover" content="cover-img"/>
+  </metadata>
+  <manifest>
+    <item id="cover-img" href="images/cover%20art.png" media-type="image/png"/>
+    <item id="ch1" href="ch1.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+</package>`
+
+const epub3OPF = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="uid">urn:isbn:0131103628</dc:identifier>
+    <dc:title>The C Programming Language</dc:title>
+    <dc:creator id="c1">Brian W. Kernighan</dc:creator>
+    <dc:creator id="c2">Dennis M. Ritchie</dc:creator>
+    <dc:creator id="c3">An Illustrator</dc:creator>
+    <meta refines="#c3" property="role" scheme="marc:relators">ill</meta>
+    <dc:language>en-US</dc:language>
+    <dc:date>1988</dc:date>
+  </metadata>
+  <manifest>
+    <item id="c" href="../cover.jpg" media-type="image/jpeg" properties="cover-image"/>
+  </manifest>
+</package>`
+
+func TestReadEPUB(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected *Metadata
+	}{
+		{
+			name: "EPUB 2 with role attributes and a cover meta",
+			files: map[string]string{
+				"OEBPS/content.opf":          epub2OPF,
+				"OEBPS/images/cover art.png": "png data",
+			},
+			expected: &Metadata{
+				Format:    EPUB,
+				Title:     "The Go Programming Language",
+				Creators:  []string{"Alan A. A. Donovan", "Brian W. Kernighan"},
+				ISBN:      "9780134190440",
+				Language:  "en",
+				Publisher: "Addison-Wesley",
+				Year:      2015,
+				Cover:     []byte("png data"),
+			},
+		},
+		{
+			name: "EPUB 3 with refined roles and a cover-image item",
+			files: map[string]string{
+				"OEBPS/content.opf": epub3OPF,
+				"cover.jpg":         "jpeg data",
+			},
+			expected: &Metadata{
+				Format:   EPUB,
+				Title:    "The C Programming Language",
+				Creators: []string{"Brian W. Kernighan", "Dennis M. Ritchie"},
+				ISBN:     "0131103628",
+				Language: "en-US",
+				Year:     1988,
+				Cover:    []byte("jpeg data"),
+			},
+		},
+		{
+			name: "missing cover file",
+			files: map[string]string{
+				"OEBPS/content.opf": epub3OPF,
+			},
+			expected: &Metadata{
+				Format:   EPUB,
+				Title:    "The C Programming Language",
+				Creators: []string{"Brian W. Kernighan", "Dennis M. Ritchie"},
+				ISBN:     "0131103628",
+				Language: "en-US",
+				Year:     1988,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := Read(epubFile(t, tt.files))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(meta, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, meta)
+			}
+		})
+	}
+}
+
+func TestReadEPUB_OnlyOtherRoles(t *testing.T) {
+	opf := `<package xmlns="http://www.idpf.org/2007/opf"><metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+<dc:title>Collected Essays</dc:title><dc:creator opf:role="edt">An Editor</dc:creator></metadata></package>`
+
+	meta, err := ReadEPUB(epubFile(t, map[string]string{"OEBPS/content.opf": opf}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(meta.Creators, []string{"An Editor"}) {
+		t.Errorf("expected the editor when there is no author, got %v", meta.Creators)
+	}
+}
+
+func TestReadEPUB_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not a zip", []byte("PK\x03\x04 truncated")},
+		{"empty file", nil},
+		{"no package document", epubFile(t, map[string]string{"OEBPS/other.opf": epub2OPF})},
+		{"malformed package document", epubFile(t, map[string]string{"OEBPS/content.opf": "<package><metadata>"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadEPUB(tt.data); err != ErrInvalidEPUB {
+				t.Errorf("expected ErrInvalidEPUB, got %v", err)
+			}
+		})
+	}
+}
+
+// pdfDocument returns a PDF made of the given objects, numbered from 1, with a
+// trailer holding trailer. The cross-reference table is left out, as
+// ReadPDF does not use it
+func pdfDocument(trailer string, objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d %s >>\nstartxref\n0\n%%%%EOF\n", len(objects)+1, trailer)
+	return buf.Bytes()
+}
+
+// flate returns a FlateDecode stream object holding content
+func flate(t *testing.T, dict, content string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(content))
+	w.Close()
+	return fmt.Sprintf("<< %s /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", dict, buf.Len(), buf.String())
+}
+
+const xmpPacket = `<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+  <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+    <rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/"
+        xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+        xmlns:prism="http://prismstandard.org/namespaces/basic/3.0/"
+        xmp:CreateDate="2019-05-01T10:00:00Z" prism:isbn="978-1-59327-865-6">
+      <dc:title><rdf:Alt><rdf:li xml:lang="x-default">Black Hat Go</rdf:li><rdf:li xml:lang="fr">Go chapeau noir</rdf:li></rdf:Alt></dc:title>
+      <dc:creator><rdf:Seq><rdf:li>Tom Steele</rdf:li><rdf:li>Chris Patten</rdf:li><rdf:li>Dan Kottmann</rdf:li></rdf:Seq></dc:creator>
+      <dc:language><rdf:Bag><rdf:li>en</rdf:li></rdf:Bag></dc:language>
+      <dc:publisher><rdf:Bag><rdf:li>No Starch Press</rdf:li></rdf:Bag></dc:publisher>
+      <dc:date><rdf:Seq><rdf:li>2020-02-04</rdf:li></rdf:Seq></dc:date>
+    </rdf:Description>
+  </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`
+
+func TestReadPDF(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected *Metadata
+	}{
+		{
+			name: "Info dictionary with literal and hex strings",
+			data: pdfDocument("/Root 1 0 R /Info 3 0 R",
+				"<< /Type /Catalog /Pages 2 0 R /Lang (de-DE) >>",
+				"<< /Type /Pages /Kids [] /Count 0 >>",
+				`<< /Title (Die Verwandlung \(Erstausgabe\)) /Author <FEFF004600720061006E007A0020004B00610066006B0061>
+/Subject (A novella. ISBN 3-596-20008-3) /CreationDate (D:19150101000000Z) >>`,
+			),
+			expected: &Metadata{
+				Format:   PDF,
+				Title:    "Die Verwandlung (Erstausgabe)",
+				Creators: []string{"Franz Kafka"},
+				Language: "de-DE",
+				ISBN:     "3596200083",
+				Year:     1915,
+			},
+		},
+		{
+			name: "Latin-1 author split into names and an indirect title",
+			data: pdfDocument("/Info 2 0 R",
+				"(Cien a\361os de soledad)",
+				"<< /Title 1 0 R /Author (Gabriel Garc\\355a M\\341rquez; Gregory Rabassa) /ISBN (978-0-06-088328-7) >>",
+			),
+			expected: &Metadata{
+				Format:   PDF,
+				Title:    "Cien años de soledad",
+				Creators: []string{"Gabriel García Márquez", "Gregory Rabassa"},
+				ISBN:     "9780060883287",
+			},
+		},
+		{
+			name: "XMP metadata stream wins over the Info dictionary",
+			data: pdfDocument("/Root 1 0 R /Info 3 0 R",
+				"<< /Type /Catalog /Metadata 2 0 R >>",
+				flate(t, "/Type /Metadata /Subtype /XML", xmpPacket),
+				"<< /Title (untitled.docx) /Author (someone) >>",
+			),
+			expected: &Metadata{
+				Format:    PDF,
+				Title:     "Black Hat Go",
+				Creators:  []string{"Tom Steele", "Chris Patten", "Dan Kottmann"},
+				ISBN:      "9781593278656",
+				Language:  "en",
+				Publisher: "No Starch Press",
+				Year:      2020,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := Read(tt.data)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(meta, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, meta)
+			}
+		})
+	}
+}
+
+func TestReadPDF_ObjectStream(t *testing.T) {
+	// Objects 3 and 4 are packed into the object stream 2, as PDF 1.5
+	// writers do; the trailer is a cross-reference stream
+	objects := "3 0 4 40 << /Type /Catalog /Lang (fr) >>\n<< /Title (L'\\311tranger) /Author (Albert Camus) >>"
+	first := strings.Index(objects, "<<")
+	data := pdfDocument("",
+		"<< /Type /XRef /Root 3 0 R /Info 4 0 R /Size 5 >>\nstream\nxref data\nendstream",
+		flate(t, fmt.Sprintf("/Type /ObjStm /N 2 /First %d", first), fixOffsets(objects, first)),
+	)
+
+	meta, err := ReadPDF(data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := &Metadata{Format: PDF, Title: "L'Étranger", Creators: []string{"Albert Camus"}, Language: "fr"}
+	if !reflect.DeepEqual(meta, expected) {
+		t.Errorf("expected %+v, got %+v", expected, meta)
+	}
+}
+
+// fixOffsets rewrites the offset of the second object in an object stream
+// header to where it really starts
+func fixOffsets(objects string, first int) string {
+	second := strings.Index(objects[first:], "\n<<") + 1
+	return strings.Replace(objects, " 40 ", fmt.Sprintf(" %d ", second), 1)
+}
+
+func TestReadPDF_MalformedObjectStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		dict    string
+		objects string
+	}{
+		{"negative first offset", "/Type /ObjStm /N 1 /First -1", "3 0 << /Title (T) >>"},
+		{"negative object offset", "/Type /ObjStm /N 1 /First 5", "3 -9 << /Title (T) >>"},
+		{"negative next offset", "/Type /ObjStm /N 2 /First 9", "3 0 4 -5 << /Title (T) >>"},
+		{"count overflowing the header", "/Type /ObjStm /N 4611686018427387904 /First 4", "3 0 << /Title (T) >>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := pdfDocument("",
+				"<< /Type /XRef /Info 3 0 R /Size 4 >>\nstream\nxref data\nendstream",
+				flate(t, tt.dict, tt.objects),
+			)
+			// Broken object streams are skipped rather than failing the read
+			ReadPDF(data)
+		})
+	}
+}
+
+func TestReadPDF_IncrementalUpdate(t *testing.T) {
+	data := pdfDocument("/Info 1 0 R", "<< /Title (First Draft) /Author (A. Writer) >>")
+	data = append(data, "1 0 obj\n<< /Title (Final Title) /Author (A. Writer) >>\nendobj\ntrailer\n<< /Info 1 0 R >>\n%%EOF\n"...)
+
+	meta, err := ReadPDF(data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if meta.Title != "Final Title" {
+		t.Errorf("expected the updated title, got %q", meta.Title)
+	}
+}
+
+func TestReadPDF_Invalid(t *testing.T) {
+	for _, data := range [][]byte{[]byte("%PDF-1.4\nno objects"), []byte("not a PDF")} {
+		if _, err := ReadPDF(data); err != ErrInvalidPDF {
+			t.Errorf("expected ErrInvalidPDF for %q, got %v", data, err)
+		}
+	}
+}
+
+func TestRead_UnsupportedFormat(t *testing.T) {
+	if _, err := Read([]byte("\x89PNG\r\n\x1a\n")); err != ErrUnsupportedFormat {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestToBook(t *testing.T) {
+	book := ToBook(&Metadata{
+		Format:    EPUB,
+		Title:     "The Go Programming Language",
+		Creators:  []string{"Alan A. A. Donovan", "Brian W. Kernighan"},
+		ISBN:      "9780134190440",
+		Language:  "en",
+		Publisher: "Addison-Wesley",
+		Year:      2015,
+	})
+
+	expected := models.Book{
+		Title:         "The Go Programming Language",
+		Author:        "Alan A. A. Donovan; Brian W. Kernighan",
+		ISBN:          "9780134190440",
+		Publisher:     "Addison-Wesley",
+		PublishedYear: 2015,
+		Language:      "en",
+	}
+	if !reflect.DeepEqual(book, expected) {
+		t.Errorf("expected %+v, got %+v", expected, book)
+	}
+	if err := book.Validate(); err != nil {
+		t.Errorf("expected a valid book, got %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package ebook
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// maxDocumentSize caps the container and package documents read from an
+	// EPUB, which are small in any real file
+	maxDocumentSize = 4 << 20
+
+	// maxCoverSize caps the cover image read from an EPUB
+	maxCoverSize = 20 << 20
+)
+
+// container is META-INF/container.xml, which points at the package document
+type container struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// opfPackage is the part of an OPF package document that describes the book
+type opfPackage struct {
+	Metadata struct {
+		Titles      []string     `xml:"title"`
+		Creators    []opfCreator `xml:"creator"`
+		Identifiers []string     `xml:"identifier"`
+		Languages   []string     `xml:"language"`
+		Publishers  []string     `xml:"publisher"`
+		Dates       []string     `xml:"date"`
+		Metas       []opfMeta    `xml:"meta"`
+	} `xml:"metadata"`
+	Manifest []opfItem `xml:"manifest>item"`
+}
+
+// opfCreator is a dc:creator, with its EPUB 2 role if it has one
+type opfCreator struct {
+	ID   string `xml:"id,attr"`
+	Role string `xml:"role,attr"`
+	Name string `xml:",chardata"`
+}
+
+// opfMeta is an EPUB 2 name/content meta or an EPUB 3 property meta
+type opfMeta struct {
+	Name     string `xml:"name,attr"`
+	Content  string `xml:"content,attr"`
+	Property string `xml:"property,attr"`
+	Refines  string `xml:"refines,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// opfItem is a file listed in the manifest
+type opfItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+// ReadEPUB returns the metadata in an EPUB's package document, with its
+// cover image if it names one
+func ReadEPUB(data []byte) (*Metadata, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidEPUB
+	}
+
+	var c container
+	if err := readXML(archive, "META-INF/container.xml", &c); err != nil {
+		return nil, ErrInvalidEPUB
+	}
+	opfPath := ""
+	for _, rootfile := range c.Rootfiles {
+		if rootfile.MediaType == "" || rootfile.MediaType == "application/oebps-package+xml" {
+			opfPath = rootfile.FullPath
+			break
+		}
+	}
+	var pkg opfPackage
+	if opfPath == "" || readXML(archive, opfPath, &pkg) != nil {
+		return nil, ErrInvalidEPUB
+	}
+
+	meta := &Metadata{
+		Format:   EPUB,
+		Creators: authors(pkg),
+		ISBN:     pickISBN(pkg.Metadata.Identifiers),
+	}
+	if len(pkg.Metadata.Titles) > 0 {
+		meta.Title = clean(pkg.Metadata.Titles[0])
+	}
+	if len(pkg.Metadata.Languages) > 0 {
+		meta.Language = clean(pkg.Metadata.Languages[0])
+	}
+	if len(pkg.Metadata.Publishers) > 0 {
+		meta.Publisher = clean(pkg.Metadata.Publishers[0])
+	}
+	for _, date := range pkg.Metadata.Dates {
+		if meta.Year = year(date); meta.Year != 0 {
+			break
+		}
+	}
+
+	if item := coverItem(pkg); item != nil {
+		// A cover that cannot be read is left out rather than failing the
+		// whole file
+		if href, err := url.PathUnescape(item.Href); err == nil {
+			meta.Cover, _ = readFile(archive, path.Join(path.Dir(opfPath), href), maxCoverSize)
+		}
+	}
+	return meta, nil
+}
+
+// authors returns the creators of a book that are its authors. Creators
+// with another role, such as editors and illustrators, are only used if
+// there is no author. EPUB 2 gives the role as an attribute; EPUB 3 refines
+// the creator with a role meta
+func authors(pkg opfPackage) []string {
+	roles := make(map[string]string)
+	for _, m := range pkg.Metadata.Metas {
+		if m.Property == "role" && strings.HasPrefix(m.Refines, "#") {
+			roles[strings.TrimPrefix(m.Refines, "#")] = clean(m.Value)
+		}
+	}
+
+	var names, others []string
+	for _, creator := range pkg.Metadata.Creators {
+		role := creator.Role
+		if role == "" && creator.ID != "" {
+			role = roles[creator.ID]
+		}
+		if role == "" || role == "aut" {
+			names = appendClean(names, creator.Name)
+		} else {
+			others = appendClean(others, creator.Name)
+		}
+	}
+	if len(names) == 0 {
+		return others
+	}
+	return names
+}
+
+// coverItem returns the manifest item of the cover image: the item with
+// the EPUB 3 cover-image property, or the one an EPUB 2 cover meta names
+func coverItem(pkg opfPackage) *opfItem {
+	for i, item := range pkg.Manifest {
+		if contains(strings.Fields(item.Properties), "cover-image") {
+			return &pkg.Manifest[i]
+		}
+	}
+	for _, m := range pkg.Metadata.Metas {
+		if m.Name != "cover" {
+			continue
+		}
+		for i, item := range pkg.Manifest {
+			if item.ID == m.Content && strings.HasPrefix(item.MediaType, "image/") {
+				return &pkg.Manifest[i]
+			}
+		}
+	}
+	return nil
+}
+
+// readXML decodes an XML file in the archive into v
+func readXML(archive *zip.Reader, name string, v interface{}) error {
+	data, err := readFile(archive, name, maxDocumentSize)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(data, v)
+}
+
+// readFile returns the content of a file in the archive, failing if it is
+// larger than limit
+func readFile(archive *zip.Reader, name string, limit int64) ([]byte, error) {
+	f, err := archive.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrInvalidEPUB
+	}
+	return data, nil
+}
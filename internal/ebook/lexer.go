@@ -0,0 +1,294 @@
+package ebook
+
+import (
+	"strconv"
+	"strings"
+)
+
+// pdfKind is the type of a PDF value, as far as reading metadata needs
+type pdfKind int
+
+const (
+	pdfOther pdfKind = iota + 1
+	pdfString
+	pdfName
+	pdfNumber
+	pdfRef
+	pdfArray
+	pdfDict
+)
+
+// pdfValue is a parsed PDF object. The zero value stands for a missing one
+type pdfValue struct {
+	kind  pdfKind
+	str   string
+	ref   [2]int
+	array []pdfValue
+	dict  map[string]pdfValue
+}
+
+// int returns the value of an integer
+func (v pdfValue) int() (int, bool) {
+	if v.kind != pdfNumber {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v.str)
+	return n, err == nil
+}
+
+// maxDepth caps the nesting of arrays and dictionaries
+const maxDepth = 32
+
+// lexer parses PDF objects from a byte slice
+type lexer struct {
+	b     []byte
+	i     int
+	depth int
+}
+
+func newLexer(b []byte) *lexer {
+	return &lexer{b: b}
+}
+
+// isDelimiter reports whether c ends a name, number or keyword
+func isDelimiter(c byte) bool {
+	return strings.IndexByte(" \t\r\n\f\x00()<>[]{}/%", c) >= 0
+}
+
+// skipSpace skips white space and comments
+func (l *lexer) skipSpace() {
+	for l.i < len(l.b) {
+		switch c := l.b[l.i]; {
+		case c == '%':
+			for l.i < len(l.b) && l.b[l.i] != '\n' && l.b[l.i] != '\r' {
+				l.i++
+			}
+		case strings.IndexByte(" \t\r\n\f\x00", c) >= 0:
+			l.i++
+		default:
+			return
+		}
+	}
+}
+
+// value parses the next object, reporting false at the end of the input or
+// on malformed input
+func (l *lexer) value() (pdfValue, bool) {
+	l.skipSpace()
+	if l.i >= len(l.b) {
+		return pdfValue{}, false
+	}
+
+	switch c := l.b[l.i]; {
+	case c == '<' && l.i+1 < len(l.b) && l.b[l.i+1] == '<':
+		l.i += 2
+		return l.dictionary()
+	case c == '<':
+		l.i++
+		return l.hexString()
+	case c == '(':
+		l.i++
+		return l.literalString()
+	case c == '/':
+		l.i++
+		return pdfValue{kind: pdfName, str: l.name()}, true
+	case c == '[':
+		l.i++
+		return l.arrayValue()
+	case c == '+' || c == '-' || c == '.' || c >= '0' && c <= '9':
+		return l.number(), true
+	case isDelimiter(c):
+		return pdfValue{}, false
+	default:
+		return pdfValue{kind: pdfOther, str: l.token()}, true
+	}
+}
+
+// dictionary parses the entries of a dictionary after its <<
+func (l *lexer) dictionary() (pdfValue, bool) {
+	if l.depth++; l.depth > maxDepth {
+		return pdfValue{}, false
+	}
+	defer func() { l.depth-- }()
+
+	dict := make(map[string]pdfValue)
+	for {
+		l.skipSpace()
+		if l.i+1 < len(l.b) && l.b[l.i] == '>' && l.b[l.i+1] == '>' {
+			l.i += 2
+			return pdfValue{kind: pdfDict, dict: dict}, true
+		}
+		key, ok := l.value()
+		if !ok || key.kind != pdfName {
+			return pdfValue{}, false
+		}
+		v, ok := l.value()
+		if !ok {
+			return pdfValue{}, false
+		}
+		dict[key.str] = v
+	}
+}
+
+// arrayValue parses the elements of an array after its [
+func (l *lexer) arrayValue() (pdfValue, bool) {
+	if l.depth++; l.depth > maxDepth {
+		return pdfValue{}, false
+	}
+	defer func() { l.depth-- }()
+
+	var array []pdfValue
+	for {
+		l.skipSpace()
+		if l.i < len(l.b) && l.b[l.i] == ']' {
+			l.i++
+			return pdfValue{kind: pdfArray, array: array}, true
+		}
+		v, ok := l.value()
+		if !ok {
+			return pdfValue{}, false
+		}
+		array = append(array, v)
+	}
+}
+
+// number parses a number, or a reference such as 12 0 R
+func (l *lexer) number() pdfValue {
+	n := pdfValue{kind: pdfNumber, str: l.token()}
+
+	// A reference is two integers followed by R
+	start := l.i
+	l.skipSpace()
+	gen := l.token()
+	l.skipSpace()
+	if _, err := strconv.Atoi(gen); err == nil && l.token() == "R" {
+		if num, err := strconv.Atoi(n.str); err == nil {
+			g, _ := strconv.Atoi(gen)
+			return pdfValue{kind: pdfRef, ref: [2]int{num, g}}
+		}
+	}
+	l.i = start
+	return n
+}
+
+// token reads up to the next delimiter
+func (l *lexer) token() string {
+	start := l.i
+	for l.i < len(l.b) && !isDelimiter(l.b[l.i]) {
+		l.i++
+	}
+	return string(l.b[start:l.i])
+}
+
+// name reads a name after its /, decoding #xx escapes
+func (l *lexer) name() string {
+	raw := l.token()
+	if !strings.Contains(raw, "#") {
+		return raw
+	}
+	var sb strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if c, err := strconv.ParseUint(raw[i+1:i+3], 16, 8); err == nil {
+				sb.WriteByte(byte(c))
+				i += 2
+				continue
+			}
+		}
+		sb.WriteByte(raw[i])
+	}
+	return sb.String()
+}
+
+// hexString parses a hexadecimal string after its <
+func (l *lexer) hexString() (pdfValue, bool) {
+	var sb strings.Builder
+	var digits []byte
+	for ; l.i < len(l.b); l.i++ {
+		c := l.b[l.i]
+		if c == '>' {
+			l.i++
+			if len(digits) == 1 {
+				// A missing final digit is taken to be 0
+				digits = append(digits, '0')
+			}
+			if len(digits) == 2 {
+				v, _ := strconv.ParseUint(string(digits), 16, 8)
+				sb.WriteByte(byte(v))
+			}
+			return pdfValue{kind: pdfString, str: sb.String()}, true
+		}
+		if _, err := strconv.ParseUint(string(c), 16, 8); err != nil {
+			continue
+		}
+		if digits = append(digits, c); len(digits) == 2 {
+			v, _ := strconv.ParseUint(string(digits), 16, 8)
+			sb.WriteByte(byte(v))
+			digits = digits[:0]
+		}
+	}
+	return pdfValue{}, false
+}
+
+// literalString parses a literal string after its (, handling balanced
+// parentheses and escapes
+func (l *lexer) literalString() (pdfValue, bool) {
+	var sb strings.Builder
+	nesting := 0
+	for l.i < len(l.b) {
+		c := l.b[l.i]
+		l.i++
+		switch c {
+		case '(':
+			nesting++
+			sb.WriteByte(c)
+		case ')':
+			if nesting == 0 {
+				return pdfValue{kind: pdfString, str: sb.String()}, true
+			}
+			nesting--
+			sb.WriteByte(c)
+		case '\\':
+			l.escape(&sb)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return pdfValue{}, false
+}
+
+// escape writes the character escaped after a backslash
+func (l *lexer) escape(sb *strings.Builder) {
+	if l.i >= len(l.b) {
+		return
+	}
+	c := l.b[l.i]
+	l.i++
+	switch c {
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case '\r':
+		// A backslash at the end of a line continues the string
+		if l.i < len(l.b) && l.b[l.i] == '\n' {
+			l.i++
+		}
+	case '\n':
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		v := int(c - '0')
+		for n := 1; n < 3 && l.i < len(l.b) && l.b[l.i] >= '0' && l.b[l.i] <= '7'; n++ {
+			v = v*8 + int(l.b[l.i]-'0')
+			l.i++
+		}
+		sb.WriteByte(byte(v))
+	default:
+		sb.WriteByte(c)
+	}
+}
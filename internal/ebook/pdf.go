@@ -0,0 +1,290 @@
+package ebook
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// maxStreamSize caps the decompressed size of a PDF stream
+const maxStreamSize = 16 << 20
+
+var (
+	// objectStart matches the start of an indirect object, "12 0 obj"
+	objectStart = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+	// streamStart matches the end of a stream dictionary and the line break
+	// before its data
+	streamStart = regexp.MustCompile(`>>\s*stream\r?\n`)
+
+	// infoRef and rootRef match the trailer entries pointing at the Info
+	// dictionary and the document catalog
+	infoRef = regexp.MustCompile(`/Info\s+(\d+)\s+(\d+)\s+R`)
+	rootRef = regexp.MustCompile(`/Root\s+(\d+)\s+(\d+)\s+R`)
+
+	// isbnText matches an ISBN mentioned in free text such as a subject
+	isbnText = regexp.MustCompile(`(?i)\bISBN(?:-1[03])?:?\s*([0-9][0-9Xx\- ]{8,15}[0-9Xx])`)
+)
+
+// ReadPDF returns the metadata in a PDF's Info dictionary and XMP packet.
+// Where both give a field the XMP value wins, as it is the one PDF 2.0 keeps
+// up to date. Without a publication date the year the file was created is
+// used
+//
+// Objects are found by scanning the file rather than through the
+// cross-reference table, so files with a damaged table still read; when an
+// object is defined more than once, as in incrementally updated files, the
+// last definition wins
+func ReadPDF(data []byte) (*Metadata, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, ErrInvalidPDF
+	}
+	f := newPDFFile(data)
+	if len(f.objects) == 0 {
+		return nil, ErrInvalidPDF
+	}
+
+	info := f.dict(lastRef(data, infoRef))
+	catalog := f.dict(lastRef(data, rootRef))
+	var x xmp
+	if packet := f.xmpPacket(catalog); packet != nil {
+		x = parseXMP(packet)
+	}
+
+	meta := &Metadata{
+		Format:    PDF,
+		Title:     first(x.title, f.text(info, "Title")),
+		Creators:  x.creators,
+		Language:  first(x.language, f.text(catalog, "Lang")),
+		Publisher: first(x.publisher, f.text(info, "Publisher")),
+	}
+	if len(meta.Creators) == 0 {
+		meta.Creators = appendClean(nil, models.Book{Author: f.text(info, "Author")}.Authors()...)
+	}
+
+	identifiers := append([]string{x.isbn}, x.identifiers...)
+	identifiers = append(identifiers, f.text(info, "ISBN"))
+	for _, text := range []string{f.text(info, "Subject"), f.text(info, "Keywords"), x.description} {
+		for _, m := range isbnText.FindAllStringSubmatch(text, -1) {
+			identifiers = append(identifiers, m[1])
+		}
+	}
+	meta.ISBN = pickISBN(identifiers)
+
+	for _, date := range []string{x.date, x.createDate, f.text(info, "CreationDate")} {
+		if meta.Year = year(date); meta.Year != 0 {
+			break
+		}
+	}
+	return meta, nil
+}
+
+// pdfFile holds the objects of a PDF by number and generation
+type pdfFile struct {
+	objects map[[2]int][]byte
+}
+
+// newPDFFile indexes the objects in data, including those packed into
+// object streams
+func newPDFFile(data []byte) *pdfFile {
+	f := &pdfFile{objects: make(map[[2]int][]byte)}
+	for pos := 0; pos < len(data); {
+		loc := objectStart.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		num, _ := strconv.Atoi(string(data[pos+loc[2] : pos+loc[3]]))
+		gen, _ := strconv.Atoi(string(data[pos+loc[4] : pos+loc[5]]))
+		start := pos + loc[1]
+		end := bytes.Index(data[start:], []byte("endobj"))
+		if end < 0 {
+			f.objects[[2]int{num, gen}] = data[start:]
+			break
+		}
+		f.objects[[2]int{num, gen}] = data[start : start+end]
+		// Continuing after endobj skips stream data, which could otherwise
+		// look like an object by chance
+		pos = start + end + len("endobj")
+	}
+
+	for _, body := range f.objects {
+		if bytes.Contains(body, []byte("/ObjStm")) {
+			f.unpack(body)
+		}
+	}
+	return f
+}
+
+// unpack indexes the objects in an object stream. Objects defined directly
+// in the file take precedence
+func (f *pdfFile) unpack(body []byte) {
+	dict, content := stream(body)
+	if dict == nil || content == nil {
+		return
+	}
+	n, ok1 := dict["N"].int()
+	firstOffset, ok2 := dict["First"].int()
+	if !ok1 || !ok2 || n <= 0 || firstOffset < 0 || firstOffset > len(content) {
+		return
+	}
+
+	header := strings.Fields(string(content[:firstOffset]))
+	if n > len(header)/2 {
+		return
+	}
+	for i := 0; i < n; i++ {
+		num, err1 := strconv.Atoi(header[2*i])
+		offset, err2 := strconv.Atoi(header[2*i+1])
+		if err1 != nil || err2 != nil || offset < 0 || firstOffset+offset > len(content) {
+			return
+		}
+		end := len(content)
+		if i+1 < n {
+			if next, err := strconv.Atoi(header[2*i+3]); err == nil && next >= offset && firstOffset+next <= end {
+				end = firstOffset + next
+			}
+		}
+		if _, ok := f.objects[[2]int{num, 0}]; !ok {
+			f.objects[[2]int{num, 0}] = content[firstOffset+offset : end]
+		}
+	}
+}
+
+// resolve follows a reference to the value it points at
+func (f *pdfFile) resolve(v pdfValue) pdfValue {
+	if v.kind != pdfRef {
+		return v
+	}
+	body, ok := f.objects[v.ref]
+	if !ok {
+		return pdfValue{}
+	}
+	resolved, _ := newLexer(body).value()
+	return resolved
+}
+
+// dict returns the dictionary with a given reference, or nil
+func (f *pdfFile) dict(ref pdfValue) map[string]pdfValue {
+	return f.resolve(ref).dict
+}
+
+// text returns a text string entry of a dictionary, decoded to UTF-8
+func (f *pdfFile) text(dict map[string]pdfValue, key string) string {
+	v := f.resolve(dict[key])
+	if v.kind != pdfString {
+		return ""
+	}
+	return clean(decodeText(v.str))
+}
+
+// xmpPacket returns the XMP packet the catalog points at or, failing that,
+// the first uncompressed packet in the file
+func (f *pdfFile) xmpPacket(catalog map[string]pdfValue) []byte {
+	if ref, ok := catalog["Metadata"]; ok && ref.kind == pdfRef {
+		if _, content := stream(f.objects[ref.ref]); content != nil {
+			return content
+		}
+	}
+	for _, body := range f.objects {
+		if start := bytes.Index(body, []byte("<x:xmpmeta")); start >= 0 {
+			if end := bytes.Index(body[start:], []byte("</x:xmpmeta>")); end >= 0 {
+				return body[start : start+end+len("</x:xmpmeta>")]
+			}
+		}
+	}
+	return nil
+}
+
+// stream returns the dictionary and decoded content of a stream object. The
+// content is nil if the stream uses a filter other than FlateDecode
+func stream(body []byte) (map[string]pdfValue, []byte) {
+	loc := streamStart.FindIndex(body)
+	if loc == nil {
+		return nil, nil
+	}
+	dictValue, _ := newLexer(body[:loc[0]+2]).value()
+	dict := dictValue.dict
+	if dict == nil {
+		return nil, nil
+	}
+
+	raw := body[loc[1]:]
+	if end := bytes.LastIndex(raw, []byte("endstream")); end >= 0 {
+		raw = raw[:end]
+	}
+	if length, ok := dict["Length"].int(); ok && length <= len(raw) {
+		raw = raw[:length]
+	}
+
+	filter := dict["Filter"]
+	switch {
+	case filter.kind == 0:
+		return dict, raw
+	case filter.kind == pdfName && filter.str == "FlateDecode",
+		filter.kind == pdfArray && len(filter.array) == 1 && filter.array[0].str == "FlateDecode":
+		r, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return dict, nil
+		}
+		defer r.Close()
+		content, err := io.ReadAll(io.LimitReader(r, maxStreamSize))
+		// Truncated streams are common and usually hold all that matters
+		if err != nil && len(content) == 0 {
+			return dict, nil
+		}
+		return dict, content
+	default:
+		return dict, nil
+	}
+}
+
+// lastRef returns the reference in the last match of pattern in data, so
+// that the trailer of the latest incremental update wins
+func lastRef(data []byte, pattern *regexp.Regexp) pdfValue {
+	matches := pattern.FindAllSubmatch(data, -1)
+	if len(matches) == 0 {
+		return pdfValue{}
+	}
+	m := matches[len(matches)-1]
+	num, _ := strconv.Atoi(string(m[1]))
+	gen, _ := strconv.Atoi(string(m[2]))
+	return pdfValue{kind: pdfRef, ref: [2]int{num, gen}}
+}
+
+// decodeText decodes a PDF text string, which is UTF-16BE with a byte order
+// mark, UTF-8 with one, or otherwise PDFDocEncoding, read here as Latin-1
+func decodeText(s string) string {
+	switch {
+	case strings.HasPrefix(s, "\xfe\xff"):
+		b := []byte(s[2:])
+		units := make([]uint16, 0, len(b)/2)
+		for i := 0; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return string(utf16.Decode(units))
+	case strings.HasPrefix(s, "\xef\xbb\xbf"):
+		return s[3:]
+	default:
+		runes := make([]rune, len(s))
+		for i := 0; i < len(s); i++ {
+			runes[i] = rune(s[i])
+		}
+		return string(runes)
+	}
+}
+
+// first returns the first of values that is not empty
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
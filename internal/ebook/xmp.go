@@ -0,0 +1,128 @@
+package ebook
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// Namespaces of the XMP properties read from PDFs
+const (
+	nsRDF   = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	nsDC    = "http://purl.org/dc/elements/1.1/"
+	nsXMP   = "http://ns.adobe.com/xap/1.0/"
+	nsPRISM = "http://prismstandard.org/namespaces/"
+)
+
+// xmp holds the properties of an XMP packet that describe a book
+type xmp struct {
+	title       string
+	creators    []string
+	language    string
+	publisher   string
+	identifiers []string
+	isbn        string
+	description string
+	date        string
+	createDate  string
+}
+
+// parseXMP reads an XMP packet. Properties may be written as elements,
+// with rdf:Alt, rdf:Bag and rdf:Seq containers, or as attributes of
+// rdf:Description. A malformed packet yields whatever was read before the
+// error
+func parseXMP(packet []byte) xmp {
+	var x xmp
+	d := xml.NewDecoder(bytes.NewReader(packet))
+	d.Strict = false
+
+	var (
+		property xml.Name
+		text     strings.Builder
+		depth    int
+		// propertyDepth is the depth of the property element being read,
+		// or 0 outside one
+		propertyDepth int
+	)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return x
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case propertyDepth == 0 && t.Name.Space == nsRDF && t.Name.Local == "Description":
+				for _, attr := range t.Attr {
+					x.set(attr.Name, attr.Value)
+				}
+			case propertyDepth == 0 && wanted(t.Name):
+				property, propertyDepth = t.Name, depth
+				text.Reset()
+			case propertyDepth != 0 && t.Name.Space == nsRDF && t.Name.Local == "li":
+				text.Reset()
+			}
+		case xml.CharData:
+			if propertyDepth != 0 {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if propertyDepth != 0 && (depth == propertyDepth || t.Name.Space == nsRDF && t.Name.Local == "li") {
+				x.set(property, text.String())
+				text.Reset()
+			}
+			if depth == propertyDepth {
+				propertyDepth = 0
+			}
+			depth--
+		}
+	}
+}
+
+// wanted reports whether a property is one xmp holds
+func wanted(name xml.Name) bool {
+	switch {
+	case name.Space == nsDC:
+		return true
+	case name.Space == nsXMP:
+		return name.Local == "CreateDate"
+	case strings.HasPrefix(name.Space, nsPRISM):
+		return name.Local == "isbn"
+	default:
+		return false
+	}
+}
+
+// set records a value of a property. Lists collect every value; other
+// properties keep the first, which for dc:title is the default language
+func (x *xmp) set(name xml.Name, value string) {
+	if value = clean(value); value == "" || !wanted(name) {
+		return
+	}
+	keep := func(field *string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	switch name.Local {
+	case "title":
+		keep(&x.title)
+	case "creator":
+		x.creators = appendClean(x.creators, value)
+	case "language":
+		keep(&x.language)
+	case "publisher":
+		keep(&x.publisher)
+	case "identifier":
+		x.identifiers = append(x.identifiers, value)
+	case "description":
+		keep(&x.description)
+	case "date":
+		keep(&x.date)
+	case "CreateDate":
+		keep(&x.createDate)
+	case "isbn":
+		keep(&x.isbn)
+	}
+}
@@ -0,0 +1,84 @@
+package enrich
+
+import "time"
+
+// defaultCacheSize bounds the number of ISBNs a cache remembers
+const defaultCacheSize = 10000
+
+// cacheEntry is a cached answer for an ISBN: a record, or that no provider
+// had one
+type cacheEntry struct {
+	record  Record
+	found   bool
+	expires time.Time
+}
+
+// cache remembers lookups until they expire, dropping the entries closest
+// to expiry once it is full. It is not safe for concurrent use
+type cache struct {
+	ttl     time.Duration
+	size    int
+	entries map[string]cacheEntry
+}
+
+// newCache returns a cache keeping up to size answers for ttl; a ttl of 0
+// keeps nothing
+func newCache(ttl time.Duration, size int) *cache {
+	return &cache{ttl: ttl, size: size, entries: make(map[string]cacheEntry)}
+}
+
+// get returns a copy of the cached answer for an ISBN. ok is false if there
+// is none or it has expired
+func (c *cache) get(isbn string, now time.Time) (record *Record, found, ok bool) {
+	entry, ok := c.entries[isbn]
+	if !ok {
+		return nil, false, false
+	}
+	if !now.Before(entry.expires) {
+		delete(c.entries, isbn)
+		return nil, false, false
+	}
+	if !entry.found {
+		return nil, false, true
+	}
+	r := entry.record
+	r.Authors = append([]string(nil), r.Authors...)
+	r.Sources = append([]string(nil), r.Sources...)
+	return &r, true, true
+}
+
+// put caches the answer for an ISBN
+func (c *cache) put(isbn string, record *Record, found bool, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	if _, ok := c.entries[isbn]; !ok && len(c.entries) >= c.size {
+		c.evict(now)
+	}
+
+	entry := cacheEntry{found: found, expires: now.Add(c.ttl)}
+	if found {
+		entry.record = *record
+		entry.record.Authors = append([]string(nil), record.Authors...)
+		entry.record.Sources = append([]string(nil), record.Sources...)
+	}
+	c.entries[isbn] = entry
+}
+
+// evict drops the expired entries, or the one closest to expiry if none
+// have expired
+func (c *cache) evict(now time.Time) {
+	oldest := ""
+	for isbn, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, isbn)
+			continue
+		}
+		if oldest == "" || entry.expires.Before(c.entries[oldest].expires) {
+			oldest = isbn
+		}
+	}
+	if len(c.entries) >= c.size && oldest != "" {
+		delete(c.entries, oldest)
+	}
+}
@@ -0,0 +1,263 @@
+// Package enrich fills in the details of a book from its ISBN by asking
+// metadata providers, such as Open Library, and caching their answers.
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/covers"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+var (
+	// ErrNotFound is returned when no provider has a record of an ISBN
+	ErrNotFound = errors.New("no metadata found for the ISBN")
+
+	// ErrUnavailable is returned when no provider has a record of an ISBN
+	// and at least one of them could not be asked
+	ErrUnavailable = errors.New("metadata providers are unavailable")
+
+	// ErrNoCover is returned when attaching the cover of a record that has
+	// none
+	ErrNoCover = errors.New("record has no cover")
+)
+
+// Record is what the providers know about the edition with an ISBN
+type Record struct {
+	// ISBN is the ISBN-13 the record was looked up by
+	ISBN          string   `json:"isbn"`
+	Title         string   `json:"title,omitempty"`
+	Authors       []string `json:"authors,omitempty"`
+	Publisher     string   `json:"publisher,omitempty"`
+	PublishedYear int      `json:"published_year,omitempty"`
+	Language      string   `json:"language,omitempty"`
+	CoverURL      string   `json:"cover_url,omitempty"`
+	// Sources names the providers the record was put together from, in
+	// the order they were asked
+	Sources []string `json:"sources"`
+	// Cached reports whether the record came from the cache rather than
+	// from the providers
+	Cached bool `json:"cached"`
+}
+
+// complete reports whether the record has every field a provider can fill
+func (r *Record) complete() bool {
+	return r.Title != "" && len(r.Authors) > 0 && r.Publisher != "" &&
+		r.PublishedYear != 0 && r.Language != "" && r.CoverURL != ""
+}
+
+// merge fills the empty fields of r from other, noting other's source if it
+// contributed anything
+func (r *Record) merge(other *Record, source string) {
+	used := false
+	fill := func(field *string, value string) {
+		if *field == "" && value != "" {
+			*field, used = value, true
+		}
+	}
+	fill(&r.Title, other.Title)
+	fill(&r.Publisher, other.Publisher)
+	fill(&r.Language, other.Language)
+	fill(&r.CoverURL, other.CoverURL)
+	if len(r.Authors) == 0 && len(other.Authors) > 0 {
+		r.Authors, used = append([]string(nil), other.Authors...), true
+	}
+	if r.PublishedYear == 0 && other.PublishedYear != 0 {
+		r.PublishedYear, used = other.PublishedYear, true
+	}
+	if used {
+		r.Sources = append(r.Sources, source)
+	}
+}
+
+// Provider looks up the metadata of an edition by its ISBN. Implementations
+// must be safe for concurrent use
+type Provider interface {
+	// Name identifies the provider in record sources and logs
+	Name() string
+
+	// Lookup returns what the provider knows about an ISBN-13, or
+	// ErrNotFound if it has no record of it
+	Lookup(ctx context.Context, isbn string) (*Record, error)
+}
+
+// Providers lists the provider names NewProvider accepts
+var Providers = []string{"openlibrary"}
+
+// NewProvider returns the named provider: openlibrary asks the Open Library
+// books API at baseURL, or at openlibrary.org if baseURL is empty
+func NewProvider(name, baseURL string, client *http.Client) (Provider, error) {
+	switch name {
+	case "openlibrary":
+		return NewOpenLibrary(baseURL, client), nil
+	default:
+		return nil, fmt.Errorf("unknown metadata provider %q, must be one of %s", name, strings.Join(Providers, ", "))
+	}
+}
+
+// Default service settings
+const (
+	DefaultTimeout  = 5 * time.Second
+	DefaultCacheTTL = 24 * time.Hour
+)
+
+// Service looks ISBNs up with its providers in order, merging their records,
+// and keeps the result in a cache so repeated lookups stay local
+type Service struct {
+	providers []Provider
+	covers    *covers.Service
+	client    *http.Client
+	cache     *cache
+	mu        sync.Mutex
+	now       func() time.Time
+}
+
+// NewService creates a service asking providers in the order given. The
+// fields of a record come from the first provider that has them
+func NewService(providers ...Provider) *Service {
+	return &Service{
+		providers: providers,
+		client:    &http.Client{Timeout: DefaultTimeout},
+		cache:     newCache(DefaultCacheTTL, defaultCacheSize),
+		now:       time.Now,
+	}
+}
+
+// SetCovers makes AttachCover upload cover images with c
+func (s *Service) SetCovers(c *covers.Service) {
+	s.covers = c
+}
+
+// SetClient sets the client cover images are downloaded with
+func (s *Service) SetClient(client *http.Client) {
+	s.client = client
+}
+
+// SetCacheTTL sets how long records, and ISBNs no provider knows, are
+// cached; 0 disables the cache
+func (s *Service) SetCacheTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = newCache(ttl, defaultCacheSize)
+}
+
+// Lookup returns what the providers know about an ISBN, which may be in
+// either ISBN-10 or ISBN-13 form
+func (s *Service) Lookup(ctx context.Context, isbn string) (*Record, error) {
+	isbn = models.ISBN13(isbn)
+	if isbn == "" {
+		return nil, models.ErrInvalidISBN
+	}
+
+	s.mu.Lock()
+	record, found, ok := s.cache.get(isbn, s.now())
+	s.mu.Unlock()
+	if ok {
+		if !found {
+			return nil, ErrNotFound
+		}
+		record.Cached = true
+		return record, nil
+	}
+
+	record = &Record{ISBN: isbn, Sources: []string{}}
+	failed := false
+	for _, provider := range s.providers {
+		r, err := provider.Lookup(ctx, isbn)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			logger.Warning.Printf("Metadata lookup of %s with %s failed: %v", isbn, provider.Name(), err)
+			failed = true
+			continue
+		}
+		record.merge(r, provider.Name())
+		if record.complete() {
+			break
+		}
+	}
+
+	found = len(record.Sources) > 0
+	if !found && failed {
+		return nil, ErrUnavailable
+	}
+	// A provider that failed may have had more to add, so only a complete
+	// answer is cached
+	if !failed || record.complete() {
+		s.mu.Lock()
+		s.cache.put(isbn, record, found, s.now())
+		s.mu.Unlock()
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	return record, nil
+}
+
+// Enrich looks up the ISBN of a book and fills in its empty title, author,
+// publisher, year and language from the record, which it returns. Fields
+// that are already set are left alone
+func (s *Service) Enrich(ctx context.Context, book *models.Book) (*Record, error) {
+	record, err := s.Lookup(ctx, book.ISBN)
+	if err != nil {
+		return nil, err
+	}
+
+	if book.Title == "" {
+		book.Title = record.Title
+	}
+	if book.Author == "" {
+		book.Author = models.JoinAuthors(record.Authors)
+	}
+	if book.Publisher == "" {
+		book.Publisher = record.Publisher
+	}
+	if book.PublishedYear == 0 {
+		book.PublishedYear = record.PublishedYear
+	}
+	if book.Language == "" {
+		book.Language = record.Language
+	}
+	return record, nil
+}
+
+// AttachCover downloads the cover image of a record and uploads it as the
+// cover of a book. It needs the covers service set with SetCovers
+func (s *Service) AttachCover(ctx context.Context, bookID int, record *Record) (*models.Cover, error) {
+	if s.covers == nil {
+		return nil, errors.New("no cover service configured")
+	}
+	if record.CoverURL == "" {
+		return nil, ErrNoCover
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, record.CoverURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cover download returned %s", resp.Status)
+	}
+
+	// Read one byte past the limit so that the upload rejects covers that
+	// are too large rather than storing them truncated
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(s.covers.MaxBytes())+1))
+	if err != nil {
+		return nil, err
+	}
+	return s.covers.Upload(bookID, data)
+}
@@ -0,0 +1,195 @@
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/blob"
+	"github.com/codeforgood-org/golang-book-api/internal/covers"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// dune is the record the primary fake provider knows
+var dune = Record{
+	ISBN:      "9780441013593",
+	Title:     "Dune",
+	Authors:   []string{"Frank Herbert"},
+	Publisher: "Ace Books",
+}
+
+// newTestService returns a service asking a primary and a secondary fake
+// provider, with a clock tests can move
+func newTestService(t *testing.T) (*Service, *Fake, *Fake, *time.Time) {
+	t.Helper()
+	primary := NewFake("primary", dune)
+	secondary := NewFake("secondary", Record{ISBN: "9780441013593", Title: "Dune (Ace)", PublishedYear: 1990, Language: "eng"})
+	s := NewService(primary, secondary)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, primary, secondary, &now
+}
+
+func TestService_Lookup(t *testing.T) {
+	s, primary, secondary, _ := newTestService(t)
+
+	// The ISBN-10 form finds the same record
+	record, err := s.Lookup(context.Background(), "0-441-01359-7")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if record.ISBN != "9780441013593" || record.Title != "Dune" || record.Publisher != "Ace Books" {
+		t.Errorf("expected the primary provider's fields first, got %+v", record)
+	}
+	if record.PublishedYear != 1990 || record.Language != "eng" {
+		t.Errorf("expected the secondary provider to fill the gaps, got %+v", record)
+	}
+	if strings.Join(record.Sources, ",") != "primary,secondary" || record.Cached {
+		t.Errorf("unexpected sources %v or cached %v", record.Sources, record.Cached)
+	}
+	if primary.Calls() != 1 || secondary.Calls() != 1 {
+		t.Errorf("expected one call to each provider, got %d and %d", primary.Calls(), secondary.Calls())
+	}
+}
+
+func TestService_Lookup_StopsWhenComplete(t *testing.T) {
+	complete := dune
+	complete.PublishedYear, complete.Language, complete.CoverURL = 1965, "eng", "http://covers.example/dune.jpg"
+	primary, secondary := NewFake("primary", complete), NewFake("secondary")
+	s := NewService(primary, secondary)
+
+	if _, err := s.Lookup(context.Background(), dune.ISBN); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if secondary.Calls() != 0 {
+		t.Errorf("expected the secondary provider not to be asked, got %d calls", secondary.Calls())
+	}
+}
+
+func TestService_Lookup_Cache(t *testing.T) {
+	s, primary, _, now := newTestService(t)
+	ctx := context.Background()
+
+	s.Lookup(ctx, dune.ISBN)
+	record, err := s.Lookup(ctx, dune.ISBN)
+	if err != nil || !record.Cached || primary.Calls() != 1 {
+		t.Fatalf("expected a cached record, got %+v, %v after %d calls", record, err, primary.Calls())
+	}
+
+	// Changing a cached record does not change the cache
+	record.Authors[0] = "Someone Else"
+	if again, _ := s.Lookup(ctx, dune.ISBN); again.Authors[0] != "Frank Herbert" {
+		t.Errorf("expected the cache to be unaffected, got %v", again.Authors)
+	}
+
+	*now = now.Add(DefaultCacheTTL)
+	if record, _ := s.Lookup(ctx, dune.ISBN); record.Cached || primary.Calls() != 2 {
+		t.Errorf("expected an expired record to be looked up again, got %+v after %d calls", record, primary.Calls())
+	}
+}
+
+func TestService_Lookup_NotFound(t *testing.T) {
+	s, primary, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.Lookup(ctx, "9780262033848"); err != ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	}
+	if primary.Calls() != 1 {
+		t.Errorf("expected the miss to be cached, got %d calls", primary.Calls())
+	}
+
+	if _, err := s.Lookup(ctx, "12345"); err != models.ErrInvalidISBN {
+		t.Errorf("expected ErrInvalidISBN, got %v", err)
+	}
+}
+
+func TestService_Lookup_Unavailable(t *testing.T) {
+	s, primary, secondary, _ := newTestService(t)
+	ctx := context.Background()
+	primary.SetError(errors.New("connection refused"))
+	secondary.SetError(errors.New("connection refused"))
+
+	if _, err := s.Lookup(ctx, dune.ISBN); err != ErrUnavailable {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+
+	// A partial answer while a provider is down is returned but not cached
+	secondary.SetError(nil)
+	record, err := s.Lookup(ctx, dune.ISBN)
+	if err != nil || strings.Join(record.Sources, ",") != "secondary" {
+		t.Fatalf("expected the secondary provider's record, got %+v, %v", record, err)
+	}
+	primary.SetError(nil)
+	if record, _ := s.Lookup(ctx, dune.ISBN); record.Cached || record.Publisher != "Ace Books" {
+		t.Errorf("expected a fresh lookup once the provider is back, got %+v", record)
+	}
+}
+
+func TestService_Enrich(t *testing.T) {
+	s, _, _, _ := newTestService(t)
+
+	book := models.Book{ISBN: dune.ISBN, Author: "F. Herbert"}
+	record, err := s.Enrich(context.Background(), &book)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if book.Title != "Dune" || book.Author != "F. Herbert" || book.Publisher != "Ace Books" || book.PublishedYear != 1990 || book.Language != "eng" {
+		t.Errorf("expected only the empty fields filled in, got %+v", book)
+	}
+	if record.Title != "Dune" {
+		t.Errorf("expected the record, got %+v", record)
+	}
+}
+
+func TestService_AttachCover(t *testing.T) {
+	var img bytes.Buffer
+	png.Encode(&img, image.NewGray(image.Rect(0, 0, 200, 300)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dune.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(img.Bytes())
+	}))
+	defer server.Close()
+
+	books := storage.NewMemoryStorage()
+	book, _ := books.Create(models.Book{Title: "Dune", Author: "Frank Herbert"})
+	s := NewService()
+	s.SetCovers(covers.NewService(books, blob.NewMemoryStore()))
+
+	cover, err := s.AttachCover(context.Background(), book.ID, &Record{CoverURL: server.URL + "/dune.png"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cover.ContentType != "image/png" || cover.Width != 200 {
+		t.Errorf("unexpected cover %+v", cover)
+	}
+
+	if _, err := s.AttachCover(context.Background(), book.ID, &Record{CoverURL: server.URL + "/missing.png"}); err == nil {
+		t.Error("expected an error for a missing cover")
+	}
+	if _, err := s.AttachCover(context.Background(), book.ID, &Record{}); err != ErrNoCover {
+		t.Errorf("expected ErrNoCover, got %v", err)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	if p, err := NewProvider("openlibrary", "", nil); err != nil || p.Name() != "openlibrary" {
+		t.Errorf("expected the openlibrary provider, got %v, %v", p, err)
+	}
+	if _, err := NewProvider("worldcat", "", nil); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
@@ -0,0 +1,71 @@
+package enrich
+
+import (
+	"context"
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// Fake is a provider answering from records held in memory, for tests and
+// for running without network access
+type Fake struct {
+	name    string
+	mu      sync.Mutex
+	records map[string]Record
+	err     error
+	calls   int
+}
+
+// NewFake returns a provider named name that knows records
+func NewFake(name string, records ...Record) *Fake {
+	f := &Fake{name: name, records: make(map[string]Record)}
+	for _, r := range records {
+		f.Add(r)
+	}
+	return f
+}
+
+// Name identifies the provider
+func (f *Fake) Name() string {
+	return f.name
+}
+
+// Add makes the provider know a record, by the ISBN-13 form of its ISBN
+func (f *Fake) Add(record Record) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record.ISBN = models.ISBN13(record.ISBN)
+	f.records[record.ISBN] = record
+}
+
+// SetError makes every lookup fail with err, or succeed again if err is nil
+func (f *Fake) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// Calls returns the number of lookups the provider has answered
+func (f *Fake) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// Lookup returns the record for an ISBN-13
+func (f *Fake) Lookup(ctx context.Context, isbn string) (*Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	record, ok := f.records[isbn]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	record.Authors = append([]string(nil), record.Authors...)
+	return &record, nil
+}
@@ -0,0 +1,139 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultOpenLibraryURL is the Open Library server asked when no other is
+// configured
+const DefaultOpenLibraryURL = "https://openlibrary.org"
+
+// maxResponseBytes bounds the size of a provider response
+const maxResponseBytes = 1 << 20
+
+// yearPattern finds the year in free-form publication dates such as
+// "March 1965" or "1965-08-01"
+var yearPattern = regexp.MustCompile(`\b(1[5-9]\d\d|20\d\d)\b`)
+
+// OpenLibrary looks editions up with the books API of Open Library, or of
+// any server answering the same requests
+type OpenLibrary struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenLibrary returns a provider asking the server at baseURL, or
+// DefaultOpenLibraryURL if it is empty, with client, or a client with
+// DefaultTimeout if it is nil
+func NewOpenLibrary(baseURL string, client *http.Client) *OpenLibrary {
+	if baseURL == "" {
+		baseURL = DefaultOpenLibraryURL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &OpenLibrary{baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+// Name identifies the provider
+func (p *OpenLibrary) Name() string {
+	return "openlibrary"
+}
+
+// openLibraryName is a named object in an Open Library response, such as
+// an author or publisher
+type openLibraryName struct {
+	Name string `json:"name"`
+}
+
+// openLibraryBook is the part of an Open Library books API record the
+// provider reads
+type openLibraryBook struct {
+	Title       string            `json:"title"`
+	Subtitle    string            `json:"subtitle"`
+	Authors     []openLibraryName `json:"authors"`
+	Publishers  []openLibraryName `json:"publishers"`
+	PublishDate string            `json:"publish_date"`
+	Languages   []struct {
+		Key string `json:"key"`
+	} `json:"languages"`
+	Cover struct {
+		Small  string `json:"small"`
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+}
+
+// Lookup asks Open Library for the edition with an ISBN
+func (p *OpenLibrary) Lookup(ctx context.Context, isbn string) (*Record, error) {
+	query := url.Values{
+		"bibkeys": {"ISBN:" + isbn},
+		"format":  {"json"},
+		"jscmd":   {"data"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/books?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openlibrary returned %s", resp.Status)
+	}
+
+	var books map[string]openLibraryBook
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&books); err != nil {
+		return nil, fmt.Errorf("openlibrary returned an invalid response: %w", err)
+	}
+	book, ok := books["ISBN:"+isbn]
+	if !ok || book.Title == "" {
+		return nil, ErrNotFound
+	}
+
+	record := &Record{
+		ISBN:     isbn,
+		Title:    strings.TrimSpace(book.Title),
+		CoverURL: firstNonEmpty(book.Cover.Large, book.Cover.Medium, book.Cover.Small),
+	}
+	if subtitle := strings.TrimSpace(book.Subtitle); subtitle != "" {
+		record.Title += ": " + subtitle
+	}
+	for _, author := range book.Authors {
+		if name := strings.TrimSpace(author.Name); name != "" {
+			record.Authors = append(record.Authors, name)
+		}
+	}
+	if len(book.Publishers) > 0 {
+		record.Publisher = strings.TrimSpace(book.Publishers[0].Name)
+	}
+	if m := yearPattern.FindString(book.PublishDate); m != "" {
+		record.PublishedYear, _ = strconv.Atoi(m)
+	}
+	if len(book.Languages) > 0 {
+		record.Language = strings.TrimPrefix(book.Languages[0].Key, "/languages/")
+	}
+	return record, nil
+}
+
+// firstNonEmpty returns the first of values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// openLibraryResponse is an Open Library books API answer for Dune
+const openLibraryResponse = `{
+  "ISBN:9780441013593": {
+    "title": "Dune",
+    "subtitle": "Deluxe Edition",
+    "authors": [{"url": "https://openlibrary.org/authors/OL79034A", "name": "Frank Herbert"}],
+    "publishers": [{"name": "Ace Books"}, {"name": "Penguin"}],
+    "publish_date": "August 2005",
+    "languages": [{"key": "/languages/eng"}],
+    "cover": {
+      "small": "https://covers.openlibrary.org/b/id/1-S.jpg",
+      "medium": "https://covers.openlibrary.org/b/id/1-M.jpg",
+      "large": "https://covers.openlibrary.org/b/id/1-L.jpg"
+    }
+  }
+}`
+
+func TestOpenLibrary_Lookup(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		switch r.URL.Query().Get("bibkeys") {
+		case "ISBN:9780441013593":
+			w.Write([]byte(openLibraryResponse))
+		case "ISBN:9780000000002":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	p := NewOpenLibrary(server.URL+"/", server.Client())
+	record, err := p.Lookup(context.Background(), "9780441013593")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(query, "jscmd=data") || !strings.Contains(query, "format=json") {
+		t.Errorf("unexpected query %s", query)
+	}
+
+	if record.Title != "Dune: Deluxe Edition" || strings.Join(record.Authors, ";") != "Frank Herbert" {
+		t.Errorf("unexpected title or authors %+v", record)
+	}
+	if record.Publisher != "Ace Books" || record.PublishedYear != 2005 || record.Language != "eng" {
+		t.Errorf("unexpected publisher, year or language %+v", record)
+	}
+	if record.CoverURL != "https://covers.openlibrary.org/b/id/1-L.jpg" {
+		t.Errorf("expected the large cover, got %s", record.CoverURL)
+	}
+
+	if _, err := p.Lookup(context.Background(), "9780262033848"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := p.Lookup(context.Background(), "9780000000002"); err == nil || err == ErrNotFound {
+		t.Errorf("expected a server error, got %v", err)
+	}
+}
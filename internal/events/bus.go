@@ -0,0 +1,195 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// Type identifies the kind of change an event describes
+type Type string
+
+// Event types published for book changes
+const (
+	BookCreated  Type = "book.created"
+	BookUpdated  Type = "book.updated"
+	BookDeleted  Type = "book.deleted"
+	BookRestored Type = "book.restored"
+	// BookMerged is published for a book merged into another and removed;
+	// the event's book is the one it was merged into
+	BookMerged Type = "book.merged"
+)
+
+// Event types published for circulation
+const (
+	// HoldReady is published when a copy is set aside for a hold
+	HoldReady Type = "hold.ready"
+)
+
+// Event describes a single change to the catalog or its circulation
+type Event struct {
+	ID     uint64       `json:"id"`
+	Type   Type         `json:"type"`
+	BookID int          `json:"book_id"`
+	Book   *models.Book `json:"book,omitempty"`
+	Hold   *models.Hold `json:"hold,omitempty"`
+	Time   time.Time    `json:"time"`
+}
+
+// DefaultLogSize is the number of events retained for replay when no size is given
+const DefaultLogSize = 1000
+
+// subscriberBuffer is the number of events queued per subscriber before it is
+// considered too slow and dropped
+const subscriberBuffer = 64
+
+// Bus fans published events out to subscribers and keeps a bounded log of
+// recent events so that subscribers can resume after a disconnect
+type Bus struct {
+	mu     sync.Mutex
+	nextID uint64
+	log    []Event
+	start  int
+	size   int
+	subs   map[*Subscription]struct{}
+	now    func() time.Time
+}
+
+// NewBus creates an event bus retaining up to logSize events for replay
+func NewBus(logSize int) *Bus {
+	if logSize < 1 {
+		logSize = DefaultLogSize
+	}
+	return &Bus{
+		nextID: 1,
+		log:    make([]Event, 0, logSize),
+		size:   logSize,
+		subs:   make(map[*Subscription]struct{}),
+		now:    time.Now,
+	}
+}
+
+// Publish assigns the next ID to an event of the given type, records it in
+// the log and delivers it to every subscriber. It never blocks: subscribers
+// whose buffer is full are closed and must resubscribe
+func (b *Bus) Publish(typ Type, bookID int, book *models.Book) Event {
+	event := Event{Type: typ, BookID: bookID}
+	if book != nil {
+		bookCopy := *book
+		event.Book = &bookCopy
+	}
+	return b.publish(event)
+}
+
+// PublishHold publishes an event about a hold, in the same way as Publish
+func (b *Bus) PublishHold(typ Type, hold models.Hold) Event {
+	return b.publish(Event{Type: typ, BookID: hold.BookID, Hold: &hold})
+}
+
+// publish stamps an event with the next ID and the current time and fans it out
+func (b *Bus) publish(event Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	event.ID = b.nextID
+	event.Time = b.now().UTC()
+	b.nextID++
+	b.append(event)
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+	return event
+}
+
+// Subscribe registers a new subscriber. Events published after lastID that
+// are still held in the log are returned as the backlog; complete reports
+// whether the log still covered every event since lastID. A lastID of zero
+// subscribes to new events only
+func (b *Bus) Subscribe(lastID uint64) (sub *Subscription, backlog []Event, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &Subscription{
+		ch:  make(chan Event, subscriberBuffer),
+		bus: b,
+	}
+	b.subs[sub] = struct{}{}
+
+	complete = true
+	if lastID > 0 {
+		backlog, complete = b.since(lastID)
+	}
+	return sub, backlog, complete
+}
+
+// LastID returns the ID of the most recently published event, or zero
+func (b *Bus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nextID - 1
+}
+
+// append adds an event to the ring buffer, overwriting the oldest when full
+func (b *Bus) append(event Event) {
+	if len(b.log) < b.size {
+		b.log = append(b.log, event)
+		return
+	}
+	b.log[b.start] = event
+	b.start = (b.start + 1) % b.size
+}
+
+// since returns the logged events with an ID greater than lastID. An ID
+// newer than anything published (for example one issued before a restart)
+// or older than the log retains is reported as incomplete
+func (b *Bus) since(lastID uint64) ([]Event, bool) {
+	n := len(b.log)
+	if n == 0 || lastID >= b.nextID {
+		return nil, false
+	}
+	oldest := b.log[b.start].ID
+	complete := lastID+1 >= oldest
+
+	var events []Event
+	for i := 0; i < n; i++ {
+		event := b.log[(b.start+i)%n]
+		if event.ID > lastID {
+			events = append(events, event)
+		}
+	}
+	return events, complete
+}
+
+// remove unregisters a subscriber and closes its channel; b.mu must be held
+func (b *Bus) remove(sub *Subscription) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
+// Subscription receives events published on a Bus
+type Subscription struct {
+	ch  chan Event
+	bus *Bus
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// subscription is cancelled or falls too far behind
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Close cancels the subscription
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s)
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestBus_PublishDeliversToSubscribers(t *testing.T) {
+	bus := NewBus(10)
+	sub, backlog, complete := bus.Subscribe(0)
+	defer sub.Close()
+
+	if len(backlog) != 0 || !complete {
+		t.Fatalf("expected empty complete backlog, got %d events (complete=%v)", len(backlog), complete)
+	}
+
+	book := &models.Book{ID: 7, Title: "Title", Author: "Author"}
+	published := bus.Publish(BookCreated, 7, book)
+	book.Title = "Changed"
+
+	select {
+	case event := <-sub.Events():
+		if event.ID != published.ID || event.Type != BookCreated || event.BookID != 7 {
+			t.Errorf("unexpected event %+v", event)
+		}
+		if event.Book == nil || event.Book.Title != "Title" {
+			t.Errorf("expected event to hold a copy of the book, got %+v", event.Book)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected event to be delivered")
+	}
+}
+
+func TestBus_Subscribe_Replay(t *testing.T) {
+	bus := NewBus(3)
+	for i := 1; i <= 5; i++ {
+		bus.Publish(BookUpdated, i, nil)
+	}
+
+	tests := []struct {
+		name         string
+		lastID       uint64
+		wantIDs      []uint64
+		wantComplete bool
+	}{
+		{"new events only", 0, nil, true},
+		{"up to date", 5, nil, true},
+		{"within log", 3, []uint64{4, 5}, true},
+		{"oldest retained boundary", 2, []uint64{3, 4, 5}, true},
+		{"older than log", 1, []uint64{3, 4, 5}, false},
+		{"unknown future id", 9, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, backlog, complete := bus.Subscribe(tt.lastID)
+			defer sub.Close()
+
+			if complete != tt.wantComplete {
+				t.Errorf("expected complete %v, got %v", tt.wantComplete, complete)
+			}
+			if len(backlog) != len(tt.wantIDs) {
+				t.Fatalf("expected %d events, got %d", len(tt.wantIDs), len(backlog))
+			}
+			for i, event := range backlog {
+				if event.ID != tt.wantIDs[i] {
+					t.Errorf("expected event %d at %d, got %d", tt.wantIDs[i], i, event.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestBus_SlowSubscriberIsDropped(t *testing.T) {
+	bus := NewBus(10)
+	sub, _, _ := bus.Subscribe(0)
+
+	for i := 0; i < subscriberBuffer+1; i++ {
+		bus.Publish(BookCreated, i, nil)
+	}
+
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("expected %d buffered events before close, got %d", subscriberBuffer, received)
+	}
+
+	// Closing an already dropped subscription must be safe
+	sub.Close()
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := NewBus(10)
+	sub, _, _ := bus.Subscribe(0)
+	sub.Close()
+
+	bus.Publish(BookDeleted, 1, nil)
+
+	if _, ok := <-sub.Events(); ok {
+		t.Error("expected closed subscription to receive no events")
+	}
+	if bus.LastID() != 1 {
+		t.Errorf("expected last ID 1, got %d", bus.LastID())
+	}
+}
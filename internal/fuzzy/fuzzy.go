@@ -0,0 +1,128 @@
+// Package fuzzy matches words that are spelled alike, for searches that
+// forgive typos: edit distances between words, trigram similarity for
+// finding corrections, and a word-by-word match of a query against a text.
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxFuzziness is the most edits a word may be from the word it matches
+const MaxFuzziness = 2
+
+// Words lower-cases s and splits it into runs of letters and digits,
+// dropping apostrophes within words
+func Words(s string) []string {
+	s = strings.NewReplacer("'", "", "’", "").Replace(strings.ToLower(s))
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Tolerance returns the number of edits allowed between word and a word it
+// matches, at most fuzziness: none for words of up to two letters, where
+// any edit makes a different word, one for words of up to five letters
+// and two for longer words
+func Tolerance(word string, fuzziness int) int {
+	n := len([]rune(word))
+	allowed := 2
+	switch {
+	case n <= 2:
+		allowed = 0
+	case n <= 5:
+		allowed = 1
+	}
+	return max(0, min(allowed, fuzziness, MaxFuzziness))
+}
+
+// Match reports whether every word of query matches a word of text: the
+// same word within its Tolerance of edits, or the start of a word, so that
+// a query still being typed matches
+func Match(text, query string, fuzziness int) bool {
+	queryWords := Words(query)
+	if len(queryWords) == 0 {
+		return false
+	}
+	textWords := Words(text)
+	for _, q := range queryWords {
+		tolerance := Tolerance(q, fuzziness)
+		found := false
+		for _, t := range textWords {
+			if strings.HasPrefix(t, q) || Within(q, t, tolerance) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Within reports whether a and b are at most limit edits apart, without
+// computing the full distance of words whose lengths already differ by more
+func Within(a, b string, limit int) bool {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > limit || -diff > limit {
+		return false
+	}
+	return levenshtein(ra, rb) <= limit
+}
+
+// Distance returns the number of single-letter insertions, deletions and
+// substitutions that turn a into b
+func Distance(a, b string) int {
+	return levenshtein([]rune(a), []rune(b))
+}
+
+// Similarity rates how alike two words are, from 0 to 1, by the share of
+// their three-letter sequences they have in common. Unlike the edit
+// distance it changes little for swapped letters, so it finds corrections
+// of typos that Match does not forgive
+func Similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// trigrams returns the three-letter sequences of a word padded with spaces,
+// so that its first and last letters count as much as the rest
+func trigrams(word string) map[string]bool {
+	runes := []rune("  " + word + " ")
+	set := make(map[string]bool, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		set[string(runes[i:i+3])] = true
+	}
+	return set
+}
+
+// levenshtein returns the edit distance between two words
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
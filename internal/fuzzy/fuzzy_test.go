@@ -0,0 +1,80 @@
+package fuzzy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	if got := Words("The Hitchhiker's Guide, Vol. 2"); !slices.Equal(got, []string{"the", "hitchhikers", "guide", "vol", "2"}) {
+		t.Errorf("unexpected words %q", got)
+	}
+}
+
+func TestTolerance(t *testing.T) {
+	tests := []struct {
+		word      string
+		fuzziness int
+		want      int
+	}{
+		{"go", 2, 0},
+		{"dune", 2, 1},
+		{"programmer", 2, 2},
+		{"programmer", 1, 1},
+		{"programmer", 0, 0},
+		{"programmer", 5, MaxFuzziness},
+	}
+
+	for _, tt := range tests {
+		if got := Tolerance(tt.word, tt.fuzziness); got != tt.want {
+			t.Errorf("Tolerance(%q, %d): expected %d, got %d", tt.word, tt.fuzziness, tt.want, got)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		query     string
+		fuzziness int
+		want      bool
+	}{
+		{"typo in every word", "The Pragmatic Programmer", "pragmatik programer", 2, true},
+		{"words in any order", "The Pragmatic Programmer", "programmer pragmatic", 2, true},
+		{"start of a word", "The Pragmatic Programmer", "pragmatic prog", 2, true},
+		{"too many typos", "The Pragmatic Programmer", "pragmtk programer", 2, false},
+		{"typos not forgiven", "The Pragmatic Programmer", "pragmatik programer", 0, false},
+		{"short words exactly", "Go in Action", "so in action", 2, false},
+		{"word missing", "The Pragmatic Programmer", "pragmatic gardener", 2, false},
+		{"empty query", "The Pragmatic Programmer", " ", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.text, tt.query, tt.fuzziness); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	if d := Distance("kitten", "sitting"); d != 3 {
+		t.Errorf("expected 3, got %d", d)
+	}
+	if !Within("programer", "programmer", 1) || Within("go", "golang", 2) {
+		t.Error("unexpected Within results")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	swapped := Similarity("pargmatic", "pragmatic")
+	unrelated := Similarity("pargmatic", "austen")
+	if swapped < 0.3 || unrelated > 0.1 {
+		t.Errorf("expected swapped letters to stay similar, got %v and %v for unrelated words", swapped, unrelated)
+	}
+	if Similarity("dune", "dune") != 1 || Similarity("", "dune") != 0 {
+		t.Error("unexpected similarity of identical or empty words")
+	}
+}
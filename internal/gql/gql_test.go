@@ -0,0 +1,339 @@
+package gql
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/reviews"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func newTestHandler(t *testing.T, limits Limits) (*Handler, *storage.MemoryStorage) {
+	t.Helper()
+	store := storage.NewMemoryStorage()
+	schema, err := NewSchema(store, Services{})
+	if err != nil {
+		t.Fatalf("failed to build schema: %v", err)
+	}
+	return NewHandler(schema, limits), store
+}
+
+func post(t *testing.T, h *Handler, query string, variables map[string]interface{}) (int, response) {
+	t.Helper()
+	body, _ := json.Marshal(Request{Query: query, Variables: variables})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	var resp response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return w.Code, resp
+}
+
+func TestHandler_QueryBooks(t *testing.T) {
+	h, store := newTestHandler(t, Limits{MaxDepth: 5, MaxComplexity: 500})
+	store.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin", PublishedYear: 2008})
+	store.Create(models.Book{Title: "Refactoring", Author: "Martin Fowler"})
+	store.Create(models.Book{Title: "Design Patterns", Author: "Erich Gamma, Richard Helm"})
+
+	code, resp := post(t, h, `{
+		books(search: "martin", pageSize: 1) {
+			total totalPages
+			items { title publishedYear authors { family given } }
+		}
+	}`, nil)
+
+	if code != http.StatusOK || len(resp.Errors) > 0 {
+		t.Fatalf("expected success, got %d: %+v", code, resp.Errors)
+	}
+
+	page := resp.Data["books"].(map[string]interface{})
+	if page["total"].(float64) != 2 || page["totalPages"].(float64) != 2 {
+		t.Errorf("expected 2 results on 2 pages, got %v", page)
+	}
+	items := page["items"].([]interface{})
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+}
+
+func TestHandler_QueryBookByID(t *testing.T) {
+	h, store := newTestHandler(t, Limits{})
+	created, _ := store.Create(models.Book{Title: "Design Patterns", Author: "Erich Gamma, Richard Helm"})
+
+	_, resp := post(t, h, `query($id: Int!) { book(id: $id) { title isbn authors { name family } } }`,
+		map[string]interface{}{"id": created.ID})
+
+	book := resp.Data["book"].(map[string]interface{})
+	if book["title"] != "Design Patterns" || book["isbn"] != nil {
+		t.Errorf("unexpected book: %v", book)
+	}
+	authors := book["authors"].([]interface{})
+	if len(authors) != 2 || authors[1].(map[string]interface{})["family"] != "Helm" {
+		t.Errorf("unexpected authors: %v", authors)
+	}
+
+	_, resp = post(t, h, `{ book(id: 999999) { title } }`, nil)
+	if resp.Data["book"] != nil {
+		t.Errorf("expected null for a missing book, got %v", resp.Data["book"])
+	}
+}
+
+func TestHandler_Mutations(t *testing.T) {
+	h, store := newTestHandler(t, Limits{})
+
+	_, resp := post(t, h, `mutation { createBook(input: {title: "Clean Code", author: "Robert C. Martin"}) { id } }`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", resp.Errors)
+	}
+	id := int(resp.Data["createBook"].(map[string]interface{})["id"].(float64))
+
+	_, resp = post(t, h, `mutation($id: Int!) { updateBook(id: $id, input: {title: "Clean Coder", author: "Robert C. Martin"}) { title } }`,
+		map[string]interface{}{"id": id})
+	if got := resp.Data["updateBook"].(map[string]interface{})["title"]; got != "Clean Coder" {
+		t.Errorf("expected updated title, got %v", got)
+	}
+
+	_, resp = post(t, h, `mutation { createBook(input: {title: "", author: "Nobody"}) { id } }`, nil)
+	if len(resp.Errors) == 0 || resp.Errors[0].Message != models.ErrInvalidTitle.Error() {
+		t.Errorf("expected validation error, got %+v", resp.Errors)
+	}
+
+	_, resp = post(t, h, `mutation($id: Int!) { deleteBook(id: $id) }`, map[string]interface{}{"id": id})
+	if resp.Data["deleteBook"] != true {
+		t.Errorf("expected deleteBook to return true, got %v", resp.Data["deleteBook"])
+	}
+	if books, _ := store.GetAll(); len(books) != 0 {
+		t.Errorf("expected no books left, got %d", len(books))
+	}
+}
+
+func TestHandler_Limits(t *testing.T) {
+	h, _ := newTestHandler(t, Limits{MaxDepth: 3, MaxComplexity: 100})
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:    "within limits",
+			query:   `{ books(pageSize: 5) { items { title author } } }`,
+			wantErr: "",
+		},
+		{
+			name:    "too deep",
+			query:   `{ books { items { authors { name } } } }`,
+			wantErr: "query depth 4 exceeds the maximum of 3",
+		},
+		{
+			name:    "too deep via fragment",
+			query:   `{ books { ...page } } fragment page on BookPage { items { authors { name } } }`,
+			wantErr: "query depth 4 exceeds the maximum of 3",
+		},
+		{
+			name:    "too complex",
+			query:   `{ books(pageSize: 100) { items { id title author isbn } } }`,
+			wantErr: "query complexity 501 exceeds the maximum of 100",
+		},
+		{
+			name:    "page size out of range resolves the default",
+			query:   `{ books(pageSize: 500) { items { title author } } }`,
+			wantErr: "",
+		},
+		{
+			name:    "introspection is not counted",
+			query:   `{ __schema { types { fields { type { ofType { name } } } } } }`,
+			wantErr: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, resp := post(t, h, tt.query, nil)
+			if tt.wantErr == "" {
+				if code != http.StatusOK || len(resp.Errors) > 0 {
+					t.Errorf("expected success, got %d: %+v", code, resp.Errors)
+				}
+				return
+			}
+			if code != http.StatusBadRequest || len(resp.Errors) == 0 || resp.Errors[0].Message != tt.wantErr {
+				t.Errorf("expected 400 %q, got %d: %+v", tt.wantErr, code, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestHandler_LimitsDoNotOverflow(t *testing.T) {
+	h, _ := newTestHandler(t, Limits{MaxComplexity: 100})
+
+	query := "title"
+	for i := 0; i < 10; i++ {
+		query = "books(pageSize: 100) { items { " + query + " } }"
+	}
+	code, resp := post(t, h, "{ "+query+" }", nil)
+	if code != http.StatusBadRequest || len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, "exceeds the maximum of 100") {
+		t.Errorf("expected the query to exceed the complexity limit, got %d: %+v", code, resp.Errors)
+	}
+}
+
+func TestHandler_GET(t *testing.T) {
+	h, _ := newTestHandler(t, Limits{})
+
+	tests := []struct {
+		name           string
+		url            string
+		accept         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "query",
+			url:            "/graphql?query=" + url.QueryEscape("{ books { total } }"),
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"total":0`,
+		},
+		{
+			name:           "mutation rejected",
+			url:            "/graphql?query=" + url.QueryEscape(`mutation { deleteBook(id: 1) }`),
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "graphiql",
+			url:            "/graphql",
+			accept:         "text/html,application/xhtml+xml",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "GraphiQL",
+		},
+		{
+			name:           "syntax error",
+			url:            "/graphql?query=" + url.QueryEscape("{ books {"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_QueryBookReviews(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	reviewService := reviews.NewService(store, patrons, storage.NewMemoryReviewStorage())
+	schema, err := NewSchema(store, Services{Reviews: reviewService})
+	if err != nil {
+		t.Fatalf("failed to build schema: %v", err)
+	}
+	h := NewHandler(schema, Limits{MaxDepth: 5, MaxComplexity: 100})
+
+	book, _ := store.Create(models.Book{Title: "Dune", Author: "Frank Herbert"})
+	for i, rating := range []int{5, 3, 4} {
+		patron, _ := patrons.CreatePatron(models.Patron{Name: "Patron", Type: models.PatronAdult})
+		review, err := reviewService.Submit(book.ID, models.Review{PatronID: patron.ID, Rating: rating, Text: "Review"})
+		if err != nil {
+			t.Fatalf("failed to submit review: %v", err)
+		}
+		// The last review is left pending moderation
+		if i < 2 {
+			reviewService.Moderate(review.ID, models.ReviewApproved, "mod", "")
+		}
+	}
+
+	_, resp := post(t, h, `query($id: Int!) { book(id: $id) { ratingCount reviews(pageSize: 1) { total totalPages items { rating text } } } }`,
+		map[string]interface{}{"id": book.ID})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	page := resp.Data["book"].(map[string]interface{})["reviews"].(map[string]interface{})
+	items := page["items"].([]interface{})
+	if page["total"] != float64(2) || page["totalPages"] != float64(2) || len(items) != 1 {
+		t.Fatalf("expected the first of 2 approved reviews, got %v", page)
+	}
+	if items[0].(map[string]interface{})["rating"] != float64(3) {
+		t.Errorf("expected the newest review first, got %v", items[0])
+	}
+
+	// The page size multiplies the cost of the fields within reviews
+	_, resp = post(t, h, `{ books(pageSize: 10) { items { reviews(pageSize: 100) { items { rating } } } } }`, nil)
+	if len(resp.Errors) == 0 {
+		t.Error("expected the query to exceed the complexity limit")
+	}
+}
+
+func TestHandler_QueryBookReviews_NotConfigured(t *testing.T) {
+	h, store := newTestHandler(t, Limits{})
+	book, _ := store.Create(models.Book{Title: "Dune", Author: "Frank Herbert"})
+
+	_, resp := post(t, h, `query($id: Int!) { book(id: $id) { reviews { total } } }`, map[string]interface{}{"id": book.ID})
+	if len(resp.Errors) == 0 {
+		t.Error("expected an error without a reviews service")
+	}
+}
+
+func TestHandler_QueryBookAvailability(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	circulationService := circulation.NewService(store, storage.NewMemoryCopyStorage(), patrons, storage.NewMemoryLoanStorage(),
+		storage.NewMemoryHoldStorage(), storage.NewMemoryFineStorage(), circulation.DefaultPolicies())
+	schema, err := NewSchema(store, Services{Circulation: circulationService})
+	if err != nil {
+		t.Fatalf("failed to build schema: %v", err)
+	}
+	h := NewHandler(schema, Limits{})
+
+	book, _ := store.Create(models.Book{Title: "Dune", Author: "Frank Herbert"})
+	patron, _ := patrons.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	for _, barcode := range []string{"D-1", "D-2"} {
+		if _, err := circulationService.AddCopy(book.ID, models.Copy{Barcode: barcode, Branch: "Main"}); err != nil {
+			t.Fatalf("failed to add copy: %v", err)
+		}
+	}
+	if _, err := circulationService.Checkout(book.ID, 0, patron.ID); err != nil {
+		t.Fatalf("failed to check out: %v", err)
+	}
+
+	_, resp := post(t, h, `query($id: Int!) { book(id: $id) { availability { status totalCopies availableCopies nextDueAt copies { barcode status dueAt } } } }`,
+		map[string]interface{}{"id": book.ID})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	availability := resp.Data["book"].(map[string]interface{})["availability"].(map[string]interface{})
+	if availability["status"] != circulation.StatusAvailable || availability["totalCopies"] != float64(2) || availability["availableCopies"] != float64(1) {
+		t.Errorf("expected 1 of 2 copies available, got %v", availability)
+	}
+	if availability["nextDueAt"] == nil || len(availability["copies"].([]interface{})) != 2 {
+		t.Errorf("expected a due date and both copies, got %v", availability)
+	}
+}
@@ -0,0 +1,170 @@
+package gql
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// maxRequestSize caps the size of a GraphQL request body
+const maxRequestSize = 1 << 20
+
+// Request is a GraphQL-over-HTTP request
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Handler serves GraphQL queries over HTTP and the GraphiQL explorer
+type Handler struct {
+	schema graphql.Schema
+	limits Limits
+}
+
+// NewHandler creates a GraphQL HTTP handler
+func NewHandler(schema graphql.Schema, limits Limits) *Handler {
+	return &Handler{
+		schema: schema,
+		limits: limits,
+	}
+}
+
+// ServeHTTP handles requests to /graphql endpoint. Browsers asking for HTML
+// get the GraphiQL page; queries may be sent with GET (queries only) or POST.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	switch r.Method {
+	case http.MethodGet:
+		if r.URL.Query().Get("query") == "" && strings.Contains(r.Header.Get("Accept"), "text/html") {
+			serveGraphiQL(w)
+			return
+		}
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if vars := r.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				respondWithErrors(w, http.StatusBadRequest, errors.New("variables must be a JSON object"))
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithErrors(w, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		respondWithErrors(w, http.StatusMethodNotAllowed, errors.New("Method not allowed"))
+		return
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		respondWithErrors(w, http.StatusBadRequest, errors.New("query is required"))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		respond(w, http.StatusBadRequest, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+
+	op := operation(doc, req.OperationName)
+	if op == nil {
+		respondWithErrors(w, http.StatusBadRequest, errors.New("unknown operation"))
+		return
+	}
+	if r.Method == http.MethodGet && op.Operation != ast.OperationTypeQuery {
+		w.Header().Set("Allow", http.MethodPost)
+		respondWithErrors(w, http.StatusMethodNotAllowed, errors.New("mutations must use POST"))
+		return
+	}
+	if err := checkLimits(op, doc, req.Variables, h.limits); err != nil {
+		respondWithErrors(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        r.Context(),
+	})
+	if result.HasErrors() {
+		logger.Warning.Printf("GraphQL request returned errors: %v", result.Errors)
+	}
+
+	respond(w, http.StatusOK, result)
+}
+
+// decodeRequest reads a POST body sent as application/json or application/graphql
+func decodeRequest(r *http.Request, req *Request) error {
+	defer r.Body.Close()
+	body := io.LimitReader(r.Body, maxRequestSize)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/graphql" {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		req.Query = string(data)
+		return nil
+	}
+
+	if err := json.NewDecoder(body).Decode(req); err != nil {
+		return errors.New("Invalid request payload")
+	}
+	return nil
+}
+
+// operation selects the operation to run: the named one, or the only one
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var ops []*ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			ops = append(ops, op)
+		}
+	}
+
+	if name == "" {
+		if len(ops) == 1 {
+			return ops[0]
+		}
+		return nil
+	}
+	for _, op := range ops {
+		if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return nil
+}
+
+// respond writes a GraphQL result as JSON
+func respond(w http.ResponseWriter, code int, result *graphql.Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		logger.Error.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// respondWithErrors writes a GraphQL result carrying a single error
+func respondWithErrors(w http.ResponseWriter, code int, err error) {
+	respond(w, code, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+}
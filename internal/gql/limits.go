@@ -0,0 +1,177 @@
+package gql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// Limits bounds how expensive a single query may be
+type Limits struct {
+	// MaxDepth is the deepest allowed nesting of selection sets
+	MaxDepth int
+	// MaxComplexity is the highest allowed estimated number of resolved fields
+	MaxComplexity int
+}
+
+// paginatedFields lists fields whose nested selections are resolved once per
+// item on the page, together with their default page size
+var paginatedFields = map[string]int{
+	"books":   10,
+	"reviews": 10,
+}
+
+// analyzer computes the depth and complexity of an operation
+type analyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// checkLimits rejects an operation that is nested too deeply or whose
+// estimated complexity is too high. Introspection fields are not counted so
+// that tools such as GraphiQL keep working.
+func checkLimits(op *ast.OperationDefinition, doc *ast.Document, variables map[string]interface{}, limits Limits) error {
+	a := &analyzer{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok {
+			a.fragments[frag.Name.Value] = frag
+		}
+	}
+
+	if limits.MaxDepth > 0 {
+		if depth := a.depth(op.SelectionSet, map[string]bool{}); depth > limits.MaxDepth {
+			return fmt.Errorf("query depth %d exceeds the maximum of %d", depth, limits.MaxDepth)
+		}
+	}
+	if limits.MaxComplexity > 0 {
+		if complexity := a.complexity(op.SelectionSet, map[string]bool{}); complexity > limits.MaxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the maximum of %d", complexity, limits.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+func (a *analyzer) depth(ss *ast.SelectionSet, visiting map[string]bool) int {
+	if ss == nil {
+		return 0
+	}
+
+	max := 0
+	for _, sel := range ss.Selections {
+		d := 0
+		switch s := sel.(type) {
+		case *ast.Field:
+			if isIntrospection(s) {
+				continue
+			}
+			d = 1 + a.depth(s.SelectionSet, visiting)
+		case *ast.InlineFragment:
+			d = a.depth(s.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			d = a.withFragment(s, visiting, a.depth)
+		}
+		if d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+func (a *analyzer) complexity(ss *ast.SelectionSet, visiting map[string]bool) int {
+	if ss == nil {
+		return 0
+	}
+
+	total := 0
+	for _, sel := range ss.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if isIntrospection(s) {
+				continue
+			}
+			nested := saturatingMul(a.multiplier(s), a.complexity(s.SelectionSet, visiting))
+			total = saturatingAdd(total, saturatingAdd(1, nested))
+		case *ast.InlineFragment:
+			total = saturatingAdd(total, a.complexity(s.SelectionSet, visiting))
+		case *ast.FragmentSpread:
+			total = saturatingAdd(total, a.withFragment(s, visiting, a.complexity))
+		}
+	}
+	return total
+}
+
+// saturatingAdd adds two non-negative complexities, stopping at math.MaxInt
+// so that absurdly nested queries cannot wrap around below the limit
+func saturatingAdd(x, y int) int {
+	if x > math.MaxInt-y {
+		return math.MaxInt
+	}
+	return x + y
+}
+
+// saturatingMul multiplies two non-negative complexities like saturatingAdd
+func saturatingMul(x, y int) int {
+	if x != 0 && y > math.MaxInt/x {
+		return math.MaxInt
+	}
+	return x * y
+}
+
+// withFragment applies measure to a fragment's selections, guarding against
+// fragment cycles (which validation rejects later)
+func (a *analyzer) withFragment(spread *ast.FragmentSpread, visiting map[string]bool, measure func(*ast.SelectionSet, map[string]bool) int) int {
+	name := spread.Name.Value
+	frag, ok := a.fragments[name]
+	if !ok || visiting[name] {
+		return 0
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+	return measure(frag.SelectionSet, visiting)
+}
+
+// multiplier returns how many times a field's nested selections are
+// resolved, taking the page size the resolver will actually use
+func (a *analyzer) multiplier(field *ast.Field) int {
+	size, paginated := paginatedFields[field.Name.Value]
+	if !paginated {
+		return 1
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name.Value == "pageSize" {
+			if n, ok := a.intValue(arg.Value); ok {
+				size = models.NewPaginationParams(1, n).PageSize
+			}
+		}
+	}
+	return size
+}
+
+// intValue resolves an integer literal or variable
+func (a *analyzer) intValue(v ast.Value) (int, bool) {
+	switch val := v.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(val.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := a.variables[val.Name.Value].(type) {
+		case int:
+			return n, true
+		case float64:
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+func isIntrospection(field *ast.Field) bool {
+	return strings.HasPrefix(field.Name.Value, "__")
+}
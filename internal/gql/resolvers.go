@@ -0,0 +1,162 @@
+package gql
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func (r *resolver) book(p graphql.ResolveParams) (interface{}, error) {
+	book, err := r.storage.GetByID(p.Args["id"].(int))
+	if errors.Is(err, models.ErrBookNotFound) {
+		return nil, nil
+	}
+	return book, err
+}
+
+func (r *resolver) books(p graphql.ResolveParams) (interface{}, error) {
+	books, err := r.storage.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	filters := models.BookFilters{
+		Title:  stringArg(p.Args, "title"),
+		Author: stringArg(p.Args, "author"),
+		Search: stringArg(p.Args, "search"),
+		Genres: models.NormalizeLabels(stringListArg(p.Args, "genres")),
+		Tags:   models.NormalizeLabels(stringListArg(p.Args, "tags")),
+	}
+	filters.MatchAny, _ = p.Args["matchAny"].(bool)
+	if filters.HasFilters() {
+		filtered := make([]models.Book, 0)
+		for _, book := range books {
+			if filters.Match(book) {
+				filtered = append(filtered, book)
+			}
+		}
+		books = filtered
+	}
+
+	return page(books, p.Args), nil
+}
+
+// page returns the page of items asked for by the page and pageSize
+// arguments, in the shape of the BookPage and ReviewPage types
+func page[T any](items []T, args map[string]interface{}) map[string]interface{} {
+	params := models.NewPaginationParams(intArg(args, "page"), intArg(args, "pageSize"))
+	start, end := params.Bounds(len(items))
+	response := models.NewPaginatedResponse(items[start:end], params.Page, params.PageSize, len(items))
+
+	return map[string]interface{}{
+		"items":      items[start:end],
+		"page":       response.Page,
+		"pageSize":   response.PageSize,
+		"total":      response.Total,
+		"totalPages": response.TotalPages,
+	}
+}
+
+// sourceBook returns the book a field is resolved on
+func sourceBook(source interface{}) (models.Book, bool) {
+	switch book := source.(type) {
+	case models.Book:
+		return book, true
+	case *models.Book:
+		return *book, true
+	}
+	return models.Book{}, false
+}
+
+// errNotConfigured is returned for a field whose service is not set
+var errNotConfigured = errors.New("not configured on this server")
+
+func (r *resolver) reviews(p graphql.ResolveParams) (interface{}, error) {
+	if r.services.Reviews == nil {
+		return nil, errNotConfigured
+	}
+	book, ok := sourceBook(p.Source)
+	if !ok {
+		return nil, nil
+	}
+	reviews, err := r.services.Reviews.BookReviews(book.ID)
+	if err != nil {
+		return nil, err
+	}
+	return page(reviews, p.Args), nil
+}
+
+func (r *resolver) availability(p graphql.ResolveParams) (interface{}, error) {
+	if r.services.Circulation == nil {
+		return nil, errNotConfigured
+	}
+	book, ok := sourceBook(p.Source)
+	if !ok {
+		return nil, nil
+	}
+	return r.services.Circulation.Availability(book.ID)
+}
+
+func (r *resolver) createBook(p graphql.ResolveParams) (interface{}, error) {
+	book := bookInput(p.Args["input"])
+	if err := book.Validate(); err != nil {
+		return nil, err
+	}
+	return storage.WithContext(r.storage, p.Context).Create(book)
+}
+
+func (r *resolver) updateBook(p graphql.ResolveParams) (interface{}, error) {
+	book := bookInput(p.Args["input"])
+	if err := book.Validate(); err != nil {
+		return nil, err
+	}
+	return storage.WithContext(r.storage, p.Context).Update(p.Args["id"].(int), book)
+}
+
+func (r *resolver) deleteBook(p graphql.ResolveParams) (interface{}, error) {
+	if err := storage.WithContext(r.storage, p.Context).Delete(p.Args["id"].(int)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// bookInput converts a BookInput argument into a book
+func bookInput(arg interface{}) models.Book {
+	input, _ := arg.(map[string]interface{})
+	return models.Book{
+		Title:         stringArg(input, "title"),
+		Author:        stringArg(input, "author"),
+		ISBN:          stringArg(input, "isbn"),
+		Publisher:     stringArg(input, "publisher"),
+		PublishedYear: intArg(input, "publishedYear"),
+		Language:      stringArg(input, "language"),
+		Dewey:         stringArg(input, "dewey"),
+		LCC:           stringArg(input, "lcc"),
+		Genres:        stringListArg(input, "genres"),
+		Tags:          stringListArg(input, "tags"),
+	}
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+func stringListArg(args map[string]interface{}, name string) []string {
+	list, _ := args[name].([]interface{})
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+func intArg(args map[string]interface{}, name string) int {
+	n, _ := args[name].(int)
+	return n
+}
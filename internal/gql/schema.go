@@ -0,0 +1,312 @@
+// Package gql serves a GraphQL API over the book catalog.
+package gql
+
+import (
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
+	"github.com/codeforgood-org/golang-book-api/internal/citation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/reviews"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// Services are the services that resolve the fields of a book kept outside
+// book storage. Fields whose service is nil resolve to an error
+type Services struct {
+	Reviews     *reviews.Service
+	Circulation *circulation.Service
+}
+
+// resolver resolves GraphQL fields against book storage and services
+type resolver struct {
+	storage  storage.Storage
+	services Services
+}
+
+// NewSchema builds the GraphQL schema for books backed by store and services
+func NewSchema(store storage.Storage, services Services) (graphql.Schema, error) {
+	res := &resolver{storage: store, services: services}
+
+	reviewType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Review",
+		Description: "An approved review of a book by a patron",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: reviewField(func(r models.Review) interface{} { return r.ID })},
+			"patronId":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: reviewField(func(r models.Review) interface{} { return r.PatronID })},
+			"rating":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: reviewField(func(r models.Review) interface{} { return r.Rating })},
+			"text":      &graphql.Field{Type: graphql.String, Resolve: reviewField(func(r models.Review) interface{} { return optional(r.Text) })},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: reviewField(func(r models.Review) interface{} { return r.CreatedAt.Format(time.RFC3339) })},
+		},
+	})
+
+	reviewPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ReviewPage",
+		Description: "A page of reviews",
+		Fields: graphql.Fields{
+			"items":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reviewType)))},
+			"page":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"pageSize":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"total":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"totalPages": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Author",
+		Description: "A person credited as an author of a book",
+		Fields: graphql.Fields{
+			"name":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"family": &graphql.Field{Type: graphql.String},
+			"given":  &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	labelList := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))
+
+	copyAvailabilityType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "CopyAvailability",
+		Description: "The status of one copy of a book",
+		Fields: graphql.Fields{
+			"copyId":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: copyField(func(c circulation.CopyAvailability) interface{} { return c.CopyID })},
+			"barcode": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: copyField(func(c circulation.CopyAvailability) interface{} { return c.Barcode })},
+			"branch":  &graphql.Field{Type: graphql.String, Resolve: copyField(func(c circulation.CopyAvailability) interface{} { return optional(c.Branch) })},
+			"status":  &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: copyField(func(c circulation.CopyAvailability) interface{} { return string(c.Status) })},
+			"dueAt":   &graphql.Field{Type: graphql.String, Resolve: copyField(func(c circulation.CopyAvailability) interface{} { return timestamp(c.DueAt) })},
+		},
+	})
+
+	availabilityType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Availability",
+		Description: "Whether a book can be borrowed now, and when it next can be",
+		Fields: graphql.Fields{
+			"status":          &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: availabilityField(func(a circulation.Availability) interface{} { return a.Status })},
+			"totalCopies":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: availabilityField(func(a circulation.Availability) interface{} { return a.TotalCopies })},
+			"availableCopies": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: availabilityField(func(a circulation.Availability) interface{} { return a.AvailableCopies })},
+			"nextDueAt":       &graphql.Field{Type: graphql.String, Resolve: availabilityField(func(a circulation.Availability) interface{} { return timestamp(a.NextDueAt) })},
+			"holdsWaiting":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: availabilityField(func(a circulation.Availability) interface{} { return a.HoldsWaiting })},
+			"copies":          &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(copyAvailabilityType))), Resolve: availabilityField(func(a circulation.Availability) interface{} { return a.Copies })},
+		},
+	})
+
+	bookType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Book",
+		Description: "A book in the library",
+		Fields: graphql.Fields{
+			"id":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: bookField(func(b models.Book) interface{} { return b.ID })},
+			"title":         &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: bookField(func(b models.Book) interface{} { return b.Title })},
+			"author":        &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: bookField(func(b models.Book) interface{} { return b.Author })},
+			"isbn":          &graphql.Field{Type: graphql.String, Resolve: bookField(func(b models.Book) interface{} { return optional(b.ISBN) })},
+			"publisher":     &graphql.Field{Type: graphql.String, Resolve: bookField(func(b models.Book) interface{} { return optional(b.Publisher) })},
+			"publishedYear": &graphql.Field{Type: graphql.Int, Resolve: bookField(func(b models.Book) interface{} { return optional(b.PublishedYear) })},
+			"language":      &graphql.Field{Type: graphql.String, Resolve: bookField(func(b models.Book) interface{} { return optional(b.Language) })},
+			"dewey":         &graphql.Field{Type: graphql.String, Resolve: bookField(func(b models.Book) interface{} { return optional(b.Dewey) })},
+			"lcc":           &graphql.Field{Type: graphql.String, Resolve: bookField(func(b models.Book) interface{} { return optional(b.LCC) })},
+			"genres":        &graphql.Field{Type: labelList, Resolve: bookField(func(b models.Book) interface{} { return labels(b.Genres) })},
+			"tags":          &graphql.Field{Type: labelList, Resolve: bookField(func(b models.Book) interface{} { return labels(b.Tags) })},
+			"averageRating": &graphql.Field{Type: graphql.Float, Resolve: bookField(func(b models.Book) interface{} { return optional(b.AverageRating) })},
+			"ratingCount":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: bookField(func(b models.Book) interface{} { return b.RatingCount })},
+			"workId":        &graphql.Field{Type: graphql.Int, Resolve: bookField(func(b models.Book) interface{} { return optional(b.WorkID) })},
+			"coverUrl":      &graphql.Field{Type: graphql.String, Resolve: bookField(coverURL)},
+			"authors": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(authorType))),
+				Resolve: bookField(resolveAuthors),
+			},
+			"reviews": &graphql.Field{
+				Type:        graphql.NewNonNull(reviewPageType),
+				Description: "The approved reviews of the book, newest first",
+				Args: graphql.FieldConfigArgument{
+					"page":     &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+					"pageSize": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+				},
+				Resolve: res.reviews,
+			},
+			"availability": &graphql.Field{
+				Type:        graphql.NewNonNull(availabilityType),
+				Description: "The book's copies and whether one can be borrowed now",
+				Resolve:     res.availability,
+			},
+		},
+	})
+
+	bookPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "BookPage",
+		Description: "A page of books",
+		Fields: graphql.Fields{
+			"items":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookType)))},
+			"page":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"pageSize":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"total":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"totalPages": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	bookInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "BookInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":         &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"author":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"isbn":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"publisher":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"publishedYear": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"language":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"dewey":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"lcc":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"genres":        &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"tags":          &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"book": &graphql.Field{
+				Type:        bookType,
+				Description: "Look up a book by ID",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: res.book,
+			},
+			"books": &graphql.Field{
+				Type:        graphql.NewNonNull(bookPageType),
+				Description: "List books with optional filtering and pagination",
+				Args: graphql.FieldConfigArgument{
+					"title":    &graphql.ArgumentConfig{Type: graphql.String},
+					"author":   &graphql.ArgumentConfig{Type: graphql.String},
+					"search":   &graphql.ArgumentConfig{Type: graphql.String},
+					"genres":   &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Books with all of these genres, or any with matchAny"},
+					"tags":     &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Books with all of these tags, or any with matchAny"},
+					"matchAny": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+					"page":     &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+					"pageSize": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+				},
+				Resolve: res.books,
+			},
+		},
+	})
+
+	mutationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBook": &graphql.Field{
+				Type: graphql.NewNonNull(bookType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(bookInputType)},
+				},
+				Resolve: res.createBook,
+			},
+			"updateBook": &graphql.Field{
+				Type: graphql.NewNonNull(bookType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(bookInputType)},
+				},
+				Resolve: res.updateBook,
+			},
+			"deleteBook": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: res.deleteBook,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    queryType,
+		Mutation: mutationType,
+	})
+}
+
+// bookField adapts an accessor on models.Book into a field resolver
+func bookField(get func(models.Book) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if book, ok := sourceBook(p.Source); ok {
+			return get(book), nil
+		}
+		return nil, nil
+	}
+}
+
+// reviewField adapts an accessor on models.Review into a field resolver
+func reviewField(get func(models.Review) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if review, ok := p.Source.(models.Review); ok {
+			return get(review), nil
+		}
+		return nil, nil
+	}
+}
+
+// availabilityField adapts an accessor on circulation.Availability into a
+// field resolver
+func availabilityField(get func(circulation.Availability) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if availability, ok := p.Source.(*circulation.Availability); ok {
+			return get(*availability), nil
+		}
+		return nil, nil
+	}
+}
+
+// copyField adapts an accessor on circulation.CopyAvailability into a field
+// resolver
+func copyField(get func(circulation.CopyAvailability) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if c, ok := p.Source.(circulation.CopyAvailability); ok {
+			return get(c), nil
+		}
+		return nil, nil
+	}
+}
+
+// timestamp formats a time as RFC 3339, or returns nil for no time
+func timestamp(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+// optional maps a zero value to null
+func optional[T comparable](v T) interface{} {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return v
+}
+
+// coverURL returns the URL of a book's cover, or nil if it has none
+func coverURL(b models.Book) interface{} {
+	if b.Cover == nil {
+		return nil
+	}
+	return b.Cover.URL
+}
+
+// labels maps nil genres or tags to an empty list
+func labels(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+func resolveAuthors(book models.Book) interface{} {
+	authors := book.Authors()
+	out := make([]map[string]interface{}, len(authors))
+	for i, author := range authors {
+		name := citation.ParseName(author)
+		out[i] = map[string]interface{}{
+			"name":   author,
+			"family": optional(name.Family),
+			"given":  optional(name.Given),
+		}
+	}
+	return out
+}
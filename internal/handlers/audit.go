@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// AuditHandler handles audit log and revision history HTTP requests
+type AuditHandler struct {
+	storage storage.Storage
+	log     *audit.Log
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(storage storage.Storage, log *audit.Log) *AuditHandler {
+	return &AuditHandler{
+		storage: storage,
+		log:     log,
+	}
+}
+
+// HandleAudit handles requests to /audit endpoint
+func (h *AuditHandler) HandleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries := h.log.Query(filter)
+	params := models.ParsePaginationParams(r)
+	start, end := params.Bounds(len(entries))
+	respond(w, r, http.StatusOK, models.NewPaginatedResponse(entries[start:end], params.Page, params.PageSize, len(entries)))
+}
+
+// HandleHistory handles requests to /books/{id}/history endpoint
+func (h *AuditHandler) HandleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	history := h.log.History(id)
+	if len(history) == 0 {
+		respondWithError(w, r, http.StatusNotFound, "Book not found")
+		return
+	}
+	respond(w, r, http.StatusOK, history)
+}
+
+// HandleRevision handles requests to /books/{id}/history/{revision} endpoint
+func (h *AuditHandler) HandleRevision(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	entry, ok := h.revision(w, r)
+	if !ok {
+		return
+	}
+	respond(w, r, http.StatusOK, entry)
+}
+
+// HandleRestore handles requests to /books/{id}/history/{revision}/restore
+// endpoint, putting the book back to the state it had after that revision
+func (h *AuditHandler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	entry, ok := h.revision(w, r)
+	if !ok {
+		return
+	}
+	state := entry.State()
+	if state == nil {
+		respondWithError(w, r, http.StatusConflict, "Revision deleted the book; restore an earlier revision")
+		return
+	}
+	// Revisions recorded before a validation rule was added may break it.
+	// Validating normalizes the book, so work on a copy rather than the
+	// revision held by the log
+	book := *state
+	if err := book.Validate(); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reverter, ok := storage.WithContext(h.storage, r.Context()).(storage.Reverter)
+	if !ok {
+		respondWithError(w, r, http.StatusNotImplemented, "Restore is not supported by this storage")
+		return
+	}
+
+	restored, err := reverter.Revert(entry.BookID, book, entry.Revision)
+	if err != nil {
+		logger.Error.Printf("Failed to restore book: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to restore book")
+		return
+	}
+	respond(w, r, http.StatusOK, restored)
+}
+
+// revision looks up the revision named in the request path, writing an
+// error response if it cannot
+func (h *AuditHandler) revision(w http.ResponseWriter, r *http.Request) (*audit.Entry, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return nil, false
+	}
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid revision")
+		return nil, false
+	}
+
+	entry, err := h.log.Revision(id, revision)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, "Revision not found")
+		return nil, false
+	}
+	return entry, true
+}
+
+// parseAuditFilter extracts audit log filters from the query parameters
+func parseAuditFilter(r *http.Request) (audit.Filter, error) {
+	q := r.URL.Query()
+	filter := audit.Filter{
+		Actor:     q.Get("actor"),
+		Action:    audit.Action(q.Get("action")),
+		RequestID: q.Get("request_id"),
+	}
+
+	if v := q.Get("book_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, models.ErrInvalidID
+		}
+		filter.BookID = id
+	}
+	for param, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := q.Get(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, fmt.Errorf("Invalid %s time; use RFC 3339", param)
+			}
+			*dst = t
+		}
+	}
+	return filter, nil
+}
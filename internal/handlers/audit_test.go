@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func newAuditRequest(method, target, id, revision string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	req.SetPathValue("id", id)
+	req.SetPathValue("revision", revision)
+	return req
+}
+
+func TestAuditHandler_HistoryAndRestore(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	log := audit.NewLog(0)
+	store.SetAuditLog(log)
+	handler := NewAuditHandler(store, log)
+
+	ctx := audit.NewContext(context.Background(), audit.Source{Actor: "alice", RequestID: "req-1"})
+	scoped := store.WithContext(ctx)
+	book, _ := scoped.Create(models.Book{Title: "Original", Author: "Author"})
+	scoped.Update(book.ID, models.Book{Title: "Edited", Author: "Author"})
+	store.Delete(book.ID)
+	id := strconv.Itoa(book.ID)
+
+	w := httptest.NewRecorder()
+	handler.HandleHistory(w, newAuditRequest(http.MethodGet, "/books/"+id+"/history", id, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var history []audit.Entry
+	json.NewDecoder(w.Body).Decode(&history)
+	if len(history) != 3 {
+		t.Fatalf("expected 3 revisions, got %d", len(history))
+	}
+	if history[0].Action != audit.ActionDelete || history[0].Actor != audit.Anonymous {
+		t.Errorf("expected anonymous delete first, got %+v", history[0])
+	}
+	if history[1].Actor != "alice" || history[1].RequestID != "req-1" || len(history[1].Changes) != 1 {
+		t.Errorf("expected alice's update with one change, got %+v", history[1])
+	}
+
+	// Restoring the delete revision is refused
+	w = httptest.NewRecorder()
+	handler.HandleRestore(w, newAuditRequest(http.MethodPost, "/books/"+id+"/history/3/restore", id, "3"))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleRestore(w, newAuditRequest(http.MethodPost, "/books/"+id+"/history/1/restore", id, "1"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	restored, err := store.GetByID(book.ID)
+	if err != nil || restored.Title != "Original" {
+		t.Fatalf("expected book recreated with original title, got %+v (%v)", restored, err)
+	}
+
+	rev, _ := log.Revision(book.ID, 4)
+	if rev == nil || rev.Action != audit.ActionRevert || rev.RevertedTo != 1 {
+		t.Errorf("expected revision 4 to record the revert, got %+v", rev)
+	}
+}
+
+func TestAuditHandler_RestoreInvalidRevision(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	log := audit.NewLog(0)
+	store.SetAuditLog(log)
+	handler := NewAuditHandler(store, log)
+
+	book, _ := store.Create(models.Book{Title: "Title", Author: "Author"})
+	log.Record(context.Background(), audit.ActionUpdate, book.ID, book, &models.Book{ID: book.ID, Author: "Author"})
+	id := strconv.Itoa(book.ID)
+
+	w := httptest.NewRecorder()
+	handler.HandleRestore(w, newAuditRequest(http.MethodPost, "/books/"+id+"/history/2/restore", id, "2"))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	if current, _ := store.GetByID(book.ID); current.Title != "Title" {
+		t.Errorf("expected book left unchanged, got %+v", current)
+	}
+}
+
+func TestAuditHandler_RestoreLeavesRevisionUnchanged(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	log := audit.NewLog(0)
+	store.SetAuditLog(log)
+	handler := NewAuditHandler(store, log)
+
+	book, _ := store.Create(models.Book{Title: "Title", Author: "Author"})
+	log.Record(context.Background(), audit.ActionUpdate, book.ID, book,
+		&models.Book{ID: book.ID, Title: "Title", Author: "Author", Genres: []string{" Fantasy "}})
+	id := strconv.Itoa(book.ID)
+
+	w := httptest.NewRecorder()
+	handler.HandleRestore(w, newAuditRequest(http.MethodPost, "/books/"+id+"/history/2/restore", id, "2"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if current, _ := store.GetByID(book.ID); len(current.Genres) != 1 || current.Genres[0] != "fantasy" {
+		t.Errorf("expected normalized genres on the book, got %q", current.Genres)
+	}
+	if rev, _ := log.Revision(book.ID, 2); rev.After.Genres[0] != " Fantasy " {
+		t.Errorf("expected the revision left as recorded, got %q", rev.After.Genres)
+	}
+}
+
+func TestAuditHandler_NotFound(t *testing.T) {
+	handler := NewAuditHandler(storage.NewMemoryStorage(), audit.NewLog(0))
+
+	tests := []struct {
+		name           string
+		call           func(http.ResponseWriter, *http.Request)
+		id, revision   string
+		expectedStatus int
+	}{
+		{"history of unknown book", handler.HandleHistory, "1", "", http.StatusNotFound},
+		{"history with invalid id", handler.HandleHistory, "abc", "", http.StatusBadRequest},
+		{"unknown revision", handler.HandleRevision, "1", "1", http.StatusNotFound},
+		{"invalid revision", handler.HandleRevision, "1", "x", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.call(w, newAuditRequest(http.MethodGet, "/books/", tt.id, tt.revision))
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestAuditHandler_Audit(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	log := audit.NewLog(0)
+	store.SetAuditLog(log)
+	handler := NewAuditHandler(store, log)
+
+	alice := store.WithContext(audit.NewContext(context.Background(), audit.Source{Actor: "alice"}))
+	bob := store.WithContext(audit.NewContext(context.Background(), audit.Source{Actor: "bob"}))
+	book, _ := alice.Create(models.Book{Title: "A", Author: "A"})
+	bob.Create(models.Book{Title: "B", Author: "B"})
+	bob.Delete(book.ID)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedTotal  int
+	}{
+		{"all", "", http.StatusOK, 3},
+		{"by actor", "?actor=bob", http.StatusOK, 2},
+		{"by action", "?action=delete", http.StatusOK, 1},
+		{"by book", "?book_id=" + strconv.Itoa(book.ID), http.StatusOK, 2},
+		{"since future", "?since=2999-01-01T00:00:00Z", http.StatusOK, 0},
+		{"invalid since", "?since=yesterday", http.StatusBadRequest, 0},
+		{"invalid book", "?book_id=x", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleAudit(w, httptest.NewRequest(http.MethodGet, "/audit"+tt.query, nil))
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var response models.PaginatedResponse
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Total != tt.expectedTotal {
+				t.Errorf("expected total %d, got %d", tt.expectedTotal, response.Total)
+			}
+		})
+	}
+}
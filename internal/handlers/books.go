@@ -1,19 +1,41 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/codeforgood-org/golang-book-api/internal/codec"
+	"github.com/codeforgood-org/golang-book-api/internal/dedupe"
+	"github.com/codeforgood-org/golang-book-api/internal/enrich"
+	"github.com/codeforgood-org/golang-book-api/internal/events"
 	"github.com/codeforgood-org/golang-book-api/internal/models"
 	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/internal/typeahead"
 	"github.com/codeforgood-org/golang-book-api/pkg/logger"
 )
 
+// Notifier is told about every book created, updated or deleted through the
+// handler, for example to trigger outgoing webhooks
+type Notifier interface {
+	Notify(typ events.Type, bookID int, book *models.Book)
+}
+
 // BookHandler handles book-related HTTP requests
 type BookHandler struct {
-	storage storage.Storage
+	storage  storage.Storage
+	notifier Notifier
+	// enricher fills in new books from their ISBN, by default if
+	// enrichOnCreate is set
+	enricher       *enrich.Service
+	enrichOnCreate bool
+	// duplicates finds books a new one looks like, and merges books
+	duplicates *dedupe.Service
+	// fuzziness is the number of typos forgiven in each word of a search
+	// that does not set the fuzziness parameter
+	fuzziness int
+	// typeahead completes titles and authors for /books/suggest
+	typeahead *typeahead.Index
 }
 
 // NewBookHandler creates a new book handler
@@ -23,6 +45,31 @@ func NewBookHandler(storage storage.Storage) *BookHandler {
 	}
 }
 
+// SetNotifier registers n to be told about book changes
+func (h *BookHandler) SetNotifier(n Notifier) {
+	h.notifier = n
+}
+
+// SetFuzziness sets the number of typos, up to fuzzy.MaxFuzziness,
+// forgiven in each word of the title, author and search filters unless the
+// request sets the fuzziness parameter
+func (h *BookHandler) SetFuzziness(n int) {
+	h.fuzziness = n
+}
+
+// store returns the storage scoped to the request, so that writes are
+// attributed to its actor and request ID
+func (h *BookHandler) store(r *http.Request) storage.Storage {
+	return storage.WithContext(h.storage, r.Context())
+}
+
+// notify passes a change on to the notifier, if one is set
+func (h *BookHandler) notify(typ events.Type, bookID int, book *models.Book) {
+	if h.notifier != nil {
+		h.notifier.Notify(typ, bookID, book)
+	}
+}
+
 // HandleBooks handles requests to /books endpoint
 func (h *BookHandler) HandleBooks(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -31,7 +78,7 @@ func (h *BookHandler) HandleBooks(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		h.createBook(w, r)
 	default:
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
 
@@ -41,7 +88,10 @@ func (h *BookHandler) HandleBookByID(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/books/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, models.ErrInvalidID.Error())
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+	if h.redirect(w, r, id) {
 		return
 	}
 
@@ -53,21 +103,96 @@ func (h *BookHandler) HandleBookByID(w http.ResponseWriter, r *http.Request) {
 	case http.MethodDelete:
 		h.deleteBook(w, r, id)
 	default:
-		respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleGenres handles requests to /genres endpoint, listing the controlled
+// vocabulary of book genres
+func (h *BookHandler) HandleGenres(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
 	}
+	respond(w, r, http.StatusOK, models.Genres)
 }
 
-// getBooks returns all books with optional filtering and pagination
+// getBooks returns all books with optional filtering, sorting and pagination
 func (h *BookHandler) getBooks(w http.ResponseWriter, r *http.Request) {
-	books, err := h.storage.GetAll()
+	order, err := models.ParseBookSort(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filters, err := h.bookFilters(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	books, err := h.filteredBooks(r, filters)
 	if err != nil {
 		logger.Error.Printf("Failed to get books: %v", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve books")
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve books")
 		return
 	}
+	order.Apply(books)
 
-	// Apply filters
+	// Parse pagination parameters
+	params := models.ParsePaginationParams(r)
+
+	// Create paginated response, with facets counted across every page.
+	// Collapsed lists page through works, but facets still count editions
+	var response models.PaginatedResponse
+	switch r.URL.Query().Get("collapse") {
+	case "":
+		start, end := params.Bounds(len(books))
+		response = models.NewPaginatedResponse(books[start:end], params.Page, params.PageSize, len(books))
+	case "work":
+		entries := models.CollapseEditions(books)
+		start, end := params.Bounds(len(entries))
+		response = models.NewPaginatedResponse(entries[start:end], params.Page, params.PageSize, len(entries))
+	default:
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidCollapse.Error())
+		return
+	}
+	facets := models.NewFacets(books)
+	response.Facets = &facets
+
+	// A search that found nothing may have been misspelled
+	if len(books) == 0 {
+		all, err := h.listedBooks(r)
+		if err != nil {
+			logger.Error.Printf("Failed to get books: %v", err)
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve books")
+			return
+		}
+		response.Suggestions = models.SuggestQueries(all, filters)
+	}
+
+	respond(w, r, http.StatusOK, response)
+}
+
+// bookFilters returns the filters in the request query, forgiving the
+// handler's number of typos unless the fuzziness parameter is set
+func (h *BookHandler) bookFilters(r *http.Request) (models.BookFilters, error) {
 	filters := models.ParseBookFilters(r)
+	fuzziness, err := models.ParseFuzziness(r, h.fuzziness)
+	if err != nil {
+		return models.BookFilters{}, err
+	}
+	filters.Fuzziness = fuzziness
+	return filters, nil
+}
+
+// filteredBooks returns the listed books matching filters
+func (h *BookHandler) filteredBooks(r *http.Request, filters models.BookFilters) ([]models.Book, error) {
+	books, err := h.listedBooks(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply filters
 	if filters.HasFilters() {
 		filteredBooks := make([]models.Book, 0)
 		for _, book := range books {
@@ -78,55 +203,76 @@ func (h *BookHandler) getBooks(w http.ResponseWriter, r *http.Request) {
 		books = filteredBooks
 	}
 
-	// Parse pagination parameters
-	params := models.ParsePaginationParams(r)
-
-	// Calculate total count before pagination
-	total := len(books)
-
-	// Apply pagination
-	start := params.Offset
-	end := start + params.PageSize
+	return books, nil
+}
 
-	if start > total {
-		start = total
-	}
-	if end > total {
-		end = total
+// listedBooks returns all books, including those in the trash only when
+// include_deleted=true
+func (h *BookHandler) listedBooks(r *http.Request) ([]models.Book, error) {
+	books, err := h.storage.GetAll()
+	if err != nil {
+		return nil, err
 	}
 
-	paginatedBooks := books[start:end]
-
-	// Create paginated response
-	response := models.NewPaginatedResponse(paginatedBooks, params.Page, params.PageSize, total)
-
-	respondWithJSON(w, http.StatusOK, response)
+	if includeDeleted, _ := strconv.ParseBool(r.URL.Query().Get("include_deleted")); includeDeleted {
+		if trash, ok := h.storage.(storage.Trash); ok {
+			trashed, err := trash.Trashed()
+			if err != nil {
+				return nil, err
+			}
+			books = append(books, trashed...)
+		}
+	}
+	return books, nil
 }
 
-// createBook creates a new book
+// createBook creates a new book, first filling in its empty fields from
+// its ISBN if enrichment is asked for, and warns if it looks like a book
+// already in the catalogue
 func (h *BookHandler) createBook(w http.ResponseWriter, r *http.Request) {
+	enrichBook, ok := h.wantsEnrich(r)
+	if !ok {
+		respondWithError(w, r, http.StatusBadRequest, "enrich must be true or false")
+		return
+	}
 	var book models.Book
-	if err := json.NewDecoder(r.Body).Decode(&book); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeRequest(w, r, &book) {
 		return
 	}
-	defer r.Body.Close()
+
+	var record *enrich.Record
+	var enrichErr error
+	if enrichBook && book.ISBN != "" {
+		record, enrichErr = h.enricher.Enrich(r.Context(), &book)
+		if enrichErr != nil && enrichErr != enrich.ErrNotFound && enrichErr != models.ErrInvalidISBN {
+			logger.Warning.Printf("Failed to enrich book with ISBN %s: %v", book.ISBN, enrichErr)
+		}
+	}
 
 	// Validate the book
 	if err := book.Validate(); err != nil {
-		respondWithError(w, http.StatusBadRequest, err.Error())
+		// A book left without a title or author because its ISBN could not
+		// be looked up is better explained by the lookup error
+		if enrichErr != nil && (err == models.ErrInvalidTitle || err == models.ErrInvalidAuthor) {
+			respondWithEnrichError(w, r, enrichErr, "Failed to enrich book")
+			return
+		}
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Create the book
-	createdBook, err := h.storage.Create(book)
+	createdBook, err := h.store(r).Create(book)
 	if err != nil {
 		logger.Error.Printf("Failed to create book: %v", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to create book")
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to create book")
 		return
 	}
+	createdBook = h.attachCover(r.Context(), createdBook, record)
+	h.warnDuplicates(w, *createdBook)
+	h.notify(events.BookCreated, createdBook.ID, createdBook)
 
-	respondWithJSON(w, http.StatusCreated, createdBook)
+	respond(w, r, http.StatusCreated, createdBook)
 }
 
 // getBookByID returns a book by ID
@@ -134,73 +280,123 @@ func (h *BookHandler) getBookByID(w http.ResponseWriter, r *http.Request, id int
 	book, err := h.storage.GetByID(id)
 	if err != nil {
 		if err == models.ErrBookNotFound {
-			respondWithError(w, http.StatusNotFound, "Book not found")
+			respondWithError(w, r, http.StatusNotFound, "Book not found")
 			return
 		}
 		logger.Error.Printf("Failed to get book: %v", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve book")
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve book")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, book)
+	respond(w, r, http.StatusOK, book)
 }
 
 // updateBook updates a book by ID
 func (h *BookHandler) updateBook(w http.ResponseWriter, r *http.Request, id int) {
 	var book models.Book
-	if err := json.NewDecoder(r.Body).Decode(&book); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeRequest(w, r, &book) {
 		return
 	}
-	defer r.Body.Close()
 
 	// Validate the book
 	if err := book.Validate(); err != nil {
-		respondWithError(w, http.StatusBadRequest, err.Error())
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Update the book
-	updatedBook, err := h.storage.Update(id, book)
+	updatedBook, err := h.store(r).Update(id, book)
 	if err != nil {
 		if err == models.ErrBookNotFound {
-			respondWithError(w, http.StatusNotFound, "Book not found")
+			respondWithError(w, r, http.StatusNotFound, "Book not found")
 			return
 		}
 		logger.Error.Printf("Failed to update book: %v", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to update book")
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to update book")
 		return
 	}
+	h.notify(events.BookUpdated, id, updatedBook)
 
-	respondWithJSON(w, http.StatusOK, updatedBook)
+	respond(w, r, http.StatusOK, updatedBook)
 }
 
 // deleteBook deletes a book by ID
 func (h *BookHandler) deleteBook(w http.ResponseWriter, r *http.Request, id int) {
-	err := h.storage.Delete(id)
+	err := h.store(r).Delete(id)
 	if err != nil {
 		if err == models.ErrBookNotFound {
-			respondWithError(w, http.StatusNotFound, "Book not found")
+			respondWithError(w, r, http.StatusNotFound, "Book not found")
 			return
 		}
 		logger.Error.Printf("Failed to delete book: %v", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to delete book")
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to delete book")
 		return
 	}
+	h.notify(events.BookDeleted, id, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// parseBookID extracts the book ID from a /books/{id}/... path
+func parseBookID(path string) (int, error) {
+	rest := strings.TrimPrefix(path, "/books/")
+	idStr, _, _ := strings.Cut(rest, "/")
+	return strconv.Atoi(idStr)
+}
+
+// codecs holds the response encoders and request body decoders available
+// to handlers
+var codecs = codec.Default()
+
+// respond writes a response in the format negotiated from the Accept header,
+// or 406 Not Acceptable if none of the requested formats can be produced
+func respond(w http.ResponseWriter, r *http.Request, code int, payload interface{}) {
+	enc, err := codecs.Negotiate(r.Header.Get("Accept"), payload)
+	if err != nil {
+		respondWithJSON(w, http.StatusNotAcceptable, map[string]string{"error": err.Error()})
+		return
+	}
+	writeEncoded(w, enc, code, payload)
+}
+
 // respondWithJSON writes a JSON response
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+	writeEncoded(w, codec.JSON{}, code, payload)
+}
+
+// writeEncoded writes a response with the given encoder
+func writeEncoded(w http.ResponseWriter, enc codec.Encoder, code int, payload interface{}) {
+	w.Header().Set("Content-Type", enc.MediaTypes()[0])
+	w.Header().Add("Vary", "Accept")
 	w.WriteHeader(code)
-	if err := json.NewEncoder(w).Encode(payload); err != nil {
+	if err := enc.Encode(w, payload); err != nil {
 		logger.Error.Printf("Failed to encode response: %v", err)
 	}
 }
 
-// respondWithError writes an error response
-func respondWithError(w http.ResponseWriter, code int, message string) {
-	respondWithJSON(w, code, map[string]string{"error": message})
+// respondWithError writes an error response, falling back to JSON when the
+// client accepts none of the supported formats
+func respondWithError(w http.ResponseWriter, r *http.Request, code int, message string) {
+	payload := map[string]string{"error": message}
+	enc, err := codecs.Negotiate(r.Header.Get("Accept"), payload)
+	if err != nil {
+		enc = codec.JSON{}
+	}
+	writeEncoded(w, enc, code, payload)
+}
+
+// decodeRequest decodes the request body according to its Content-Type,
+// writing a 415 or 400 response and returning false on failure
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	defer r.Body.Close()
+
+	if err := codecs.Decode(r.Header.Get("Content-Type"), r.Body, v); err != nil {
+		if err == codec.ErrUnsupportedMediaType {
+			respondWithError(w, r, http.StatusUnsupportedMediaType, "Unsupported media type")
+			return false
+		}
+		respondWithError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return false
+	}
+	return true
 }
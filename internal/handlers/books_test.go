@@ -206,6 +206,158 @@ func TestBookHandler_HandleBookByID_DELETE(t *testing.T) {
 	}
 }
 
+func TestBookHandler_HandleBooks_SortByRating(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+
+	for i, average := range []float64{3.5, 4.5, 2} {
+		book, _ := store.Create(models.Book{Title: fmt.Sprintf("Book %d", i+1), Author: "Author"})
+		store.SetRating(book.ID, average, 1)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/books?sort=-rating", nil)
+	w := httptest.NewRecorder()
+	handler.HandleBooks(w, req)
+
+	var response struct {
+		Data []models.Book `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	for i, title := range []string{"Book 2", "Book 1", "Book 3"} {
+		if i >= len(response.Data) || response.Data[i].Title != title {
+			t.Fatalf("expected books 2, 1, 3 highest rated first, got %+v", response.Data)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/books?sort=title", nil)
+	w = httptest.NewRecorder()
+	handler.HandleBooks(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBookHandler_HandleBooks_FuzzySearch(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+	handler.SetFuzziness(2)
+
+	store.Create(models.Book{Title: "The Pragmatic Programmer", Author: "Andrew Hunt"})
+	store.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+
+	tests := []struct {
+		name                string
+		query               string
+		expectedStatus      int
+		expectedTotal       int
+		expectedSuggestions []models.SearchSuggestion
+	}{
+		{"typos forgiven", "?search=pragmatik+programer", http.StatusOK, 1, nil},
+		{"typos not forgiven", "?search=pragmatik+programer&fuzziness=0", http.StatusOK, 0, []models.SearchSuggestion{{Search: "pragmatic programmer"}}},
+		{"too many typos", "?author=robret&fuzziness=1", http.StatusOK, 0, []models.SearchSuggestion{{Author: "robert"}}},
+		{"nothing close", "?search=xylophone", http.StatusOK, 0, nil},
+		{"invalid fuzziness", "?search=clean&fuzziness=3", http.StatusBadRequest, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/books"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleBooks(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var response struct {
+				Total       int                       `json:"total"`
+				Suggestions []models.SearchSuggestion `json:"suggestions"`
+			}
+			json.NewDecoder(w.Body).Decode(&response)
+			if response.Total != tt.expectedTotal {
+				t.Errorf("expected %d books, got %d", tt.expectedTotal, response.Total)
+			}
+			if fmt.Sprint(response.Suggestions) != fmt.Sprint(tt.expectedSuggestions) {
+				t.Errorf("expected suggestions %+v, got %+v", tt.expectedSuggestions, response.Suggestions)
+			}
+		})
+	}
+}
+
+func TestBookHandler_HandleBooks_LabelsAndFacets(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+
+	for _, payload := range []string{
+		`{"title": "Murder on the Orient Express", "author": "Agatha Christie", "genres": ["Mystery"], "tags": ["classic"], "published_year": 1934}`,
+		`{"title": "The Cat Who Could Read Backwards", "author": "Lilian Jackson Braun", "genres": ["mystery"], "tags": ["cozy", "cats"], "published_year": 1966}`,
+		`{"title": "Dune", "author": "Frank Herbert", "genres": ["science-fiction"], "tags": ["classic"], "published_year": 1965}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.HandleBooks(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+	}
+
+	tests := []struct {
+		name          string
+		url           string
+		expectedTotal int
+		expectedGenre []models.FacetCount
+	}{
+		{"all books", "/books?page_size=1", 3, []models.FacetCount{{Value: "mystery", Count: 2}, {Value: "science-fiction", Count: 1}}},
+		{"genre and tag", "/books?genre=mystery&tag=classic", 1, []models.FacetCount{{Value: "mystery", Count: 1}}},
+		{"any of the tags", "/books?tag=cats,classic&match=any", 3, []models.FacetCount{{Value: "mystery", Count: 2}, {Value: "science-fiction", Count: 1}}},
+		{"no matches", "/books?genre=poetry", 0, []models.FacetCount{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleBooks(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			var response struct {
+				Total  int           `json:"total"`
+				Facets models.Facets `json:"facets"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Total != tt.expectedTotal {
+				t.Errorf("expected total %d, got %d", tt.expectedTotal, response.Total)
+			}
+			if fmt.Sprint(response.Facets.Genres) != fmt.Sprint(tt.expectedGenre) {
+				t.Errorf("expected genre facets %v, got %v", tt.expectedGenre, response.Facets.Genres)
+			}
+		})
+	}
+
+	// Genres outside the vocabulary are rejected
+	req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewBufferString(`{"title": "T", "author": "A", "genres": ["cozy"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.HandleBooks(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleGenres(w, httptest.NewRequest(http.MethodGet, "/genres", nil))
+	var genres []string
+	json.NewDecoder(w.Body).Decode(&genres)
+	if len(genres) != len(models.Genres) {
+		t.Errorf("expected %d genres, got %d", len(models.Genres), len(genres))
+	}
+}
+
 func TestBookHandler_HandleBooks_MethodNotAllowed(t *testing.T) {
 	store := storage.NewMemoryStorage()
 	handler := NewBookHandler(store)
@@ -219,3 +371,174 @@ func TestBookHandler_HandleBooks_MethodNotAllowed(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
 	}
 }
+
+func TestBookHandler_ContentNegotiation(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+
+	created, _ := store.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+
+	tests := []struct {
+		name           string
+		url            string
+		accept         string
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{
+			name:           "xml list",
+			url:            "/books",
+			accept:         "application/xml",
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/xml",
+			expectedBody:   "<title>Clean Code</title>",
+		},
+		{
+			name:           "csv list",
+			url:            "/books",
+			accept:         "text/csv",
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv",
+			expectedBody:   "id,title,author,created_at\n",
+		},
+		{
+			name:           "yaml preferred by q-value",
+			url:            fmt.Sprintf("/books/%d", created.ID),
+			accept:         "application/json;q=0.2, application/yaml",
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/yaml",
+			expectedBody:   "title: Clean Code\n",
+		},
+		{
+			name:           "csv not available for a single book",
+			url:            fmt.Sprintf("/books/%d", created.ID),
+			accept:         "text/csv",
+			expectedStatus: http.StatusNotAcceptable,
+			expectedType:   "application/json",
+		},
+		{
+			name:           "errors use the negotiated format",
+			url:            "/books/999999",
+			accept:         "application/xml",
+			expectedStatus: http.StatusNotFound,
+			expectedType:   "application/xml",
+			expectedBody:   "<error>Book not found</error>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			if tt.url == "/books" {
+				handler.HandleBooks(w, req)
+			} else {
+				handler.HandleBookByID(w, req)
+			}
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.expectedType {
+				t.Errorf("expected Content-Type %s, got %s", tt.expectedType, ct)
+			}
+			if !bytes.Contains(w.Body.Bytes(), []byte(tt.expectedBody)) {
+				t.Errorf("expected body to contain %q, got %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBookHandler_HandleBooks_POST_ContentTypes(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "yaml body",
+			contentType:    "application/yaml",
+			body:           "title: Clean Code\nauthor: Robert C. Martin\npublished_year: 2008\n",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "xml body",
+			contentType:    "application/xml",
+			body:           "<book><title>Clean Code</title><author>Robert C. Martin</author></book>",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unsupported body",
+			contentType:    "text/plain",
+			body:           "Clean Code by Robert C. Martin",
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewBookHandler(storage.NewMemoryStorage())
+
+			req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			handler.HandleBooks(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBookHandler_HandleBooks_CollapseWorks(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+
+	english, _ := store.Create(models.Book{Title: "Don Quixote", Author: "Cervantes", Language: "en"})
+	spanish, _ := store.Create(models.Book{Title: "Don Quijote", Author: "Cervantes", Language: "es"})
+	store.Create(models.Book{Title: "Dune", Author: "Frank Herbert", Language: "en"})
+	store.SetWork(english.ID, 1)
+	store.SetWork(spanish.ID, 1)
+
+	w := httptest.NewRecorder()
+	handler.HandleBooks(w, httptest.NewRequest(http.MethodGet, "/books?collapse=work&sort=lcc", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data   []models.WorkEntry `json:"data"`
+		Total  int                `json:"total"`
+		Facets models.Facets      `json:"facets"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Total != 2 || len(response.Data) != 2 {
+		t.Fatalf("expected 2 entries, got %d of %d", len(response.Data), response.Total)
+	}
+	for _, entry := range response.Data {
+		if entry.WorkID == 1 && len(entry.EditionIDs) != 2 {
+			t.Errorf("expected the work to list both editions, got %v", entry.EditionIDs)
+		}
+		if entry.WorkID == 0 && (entry.Title != "Dune" || entry.EditionIDs != nil) {
+			t.Errorf("expected Dune to stand for itself, got %+v", entry)
+		}
+	}
+	// Facets still count every edition
+	if fmt.Sprint(response.Facets.Languages) != "[{en 2} {es 1}]" {
+		t.Errorf("expected language facets for every edition, got %v", response.Facets.Languages)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleBooks(w, httptest.NewRequest(http.MethodGet, "/books?collapse=series", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/codeforgood-org/golang-book-api/internal/catalog"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// CatalogHandler handles series and work HTTP requests
+type CatalogHandler struct {
+	catalog *catalog.Service
+}
+
+// NewCatalogHandler creates a new catalog handler
+func NewCatalogHandler(catalog *catalog.Service) *CatalogHandler {
+	return &CatalogHandler{
+		catalog: catalog,
+	}
+}
+
+// respondWithCatalogError maps series and work errors to HTTP responses,
+// logging and hiding unexpected ones
+func respondWithCatalogError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch err {
+	case models.ErrBookNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Book not found")
+	case models.ErrSeriesNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Series not found")
+	case models.ErrWorkNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Work not found")
+	case catalog.ErrNotInSeries, catalog.ErrNotAnEdition:
+		respondWithError(w, r, http.StatusNotFound, err.Error())
+	case models.ErrInvalidSeriesTitle, models.ErrInvalidWorkTitle, catalog.ErrInvalidPosition, catalog.ErrInvalidOrder:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+	case catalog.ErrAlreadyInSeries:
+		respondWithError(w, r, http.StatusConflict, err.Error())
+	default:
+		logger.Error.Printf("%s: %v", message, err)
+		respondWithError(w, r, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// CirculationHandler handles checkout, return and renewal HTTP requests
+type CirculationHandler struct {
+	circulation *circulation.Service
+}
+
+// NewCirculationHandler creates a new circulation handler
+func NewCirculationHandler(circulation *circulation.Service) *CirculationHandler {
+	return &CirculationHandler{
+		circulation: circulation,
+	}
+}
+
+// CheckoutRequest is the body accepted when checking out a book. The copy
+// may be chosen by ID or barcode; otherwise any available copy is lent
+type CheckoutRequest struct {
+	PatronID int    `json:"patron_id"`
+	CopyID   int    `json:"copy_id"`
+	Barcode  string `json:"barcode"`
+}
+
+// ReturnRequest is the optional body accepted when returning a book, naming
+// the copy by ID or barcode when more than one is checked out
+type ReturnRequest struct {
+	CopyID  int    `json:"copy_id"`
+	Barcode string `json:"barcode"`
+}
+
+// HandleCheckout handles requests to /books/{id}/checkout endpoint
+func (h *CirculationHandler) HandleCheckout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+	var req CheckoutRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.PatronID <= 0 {
+		respondWithError(w, r, http.StatusBadRequest, "patron_id is required")
+		return
+	}
+
+	copyID, ok := h.copyID(w, r, req.CopyID, req.Barcode)
+	if !ok {
+		return
+	}
+
+	loan, err := h.circulation.Checkout(bookID, copyID, req.PatronID)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to check out book")
+		return
+	}
+	respond(w, r, http.StatusCreated, loan)
+}
+
+// HandleReturn handles requests to /books/{id}/return endpoint
+func (h *CirculationHandler) HandleReturn(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	var req ReturnRequest
+	if r.ContentLength != 0 && !decodeRequest(w, r, &req) {
+		return
+	}
+	copyID, ok := h.copyID(w, r, req.CopyID, req.Barcode)
+	if !ok {
+		return
+	}
+
+	loan, err := h.circulation.Return(bookID, copyID)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to return book")
+		return
+	}
+	respond(w, r, http.StatusOK, loan)
+}
+
+// HandleAvailability handles requests to /books/{id}/availability endpoint
+func (h *CirculationHandler) HandleAvailability(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	availability, err := h.circulation.Availability(bookID)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to retrieve availability")
+		return
+	}
+	respond(w, r, http.StatusOK, availability)
+}
+
+// HandlePatronLoans handles requests to /patrons/{id}/loans endpoint; pass
+// active=true to leave out returned loans
+func (h *CirculationHandler) HandlePatronLoans(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	patronID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid patron ID")
+		return
+	}
+	activeOnly, _ := strconv.ParseBool(r.URL.Query().Get("active"))
+
+	loans, err := h.circulation.PatronLoans(patronID, activeOnly)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to retrieve loans")
+		return
+	}
+	respond(w, r, http.StatusOK, loans)
+}
+
+// HandleLoan handles requests to /loans/{id} endpoint
+func (h *CirculationHandler) HandleLoan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid loan ID")
+		return
+	}
+
+	loan, err := h.circulation.Loan(id)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to retrieve loan")
+		return
+	}
+	respond(w, r, http.StatusOK, loan)
+}
+
+// HandleRenew handles requests to /loans/{id}/renew endpoint
+func (h *CirculationHandler) HandleRenew(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid loan ID")
+		return
+	}
+
+	loan, err := h.circulation.Renew(id)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to renew loan")
+		return
+	}
+	respond(w, r, http.StatusOK, loan)
+}
+
+// HandlePolicies handles requests to /loan-policies endpoint
+func (h *CirculationHandler) HandlePolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	respond(w, r, http.StatusOK, h.circulation.Policies())
+}
+
+// copyID resolves a copy given by ID or barcode, writing an error response
+// if the barcode is unknown. It returns 0 if neither is given
+func (h *CirculationHandler) copyID(w http.ResponseWriter, r *http.Request, id int, barcode string) (int, bool) {
+	if id != 0 || barcode == "" {
+		return id, true
+	}
+	item, err := h.circulation.CopyByBarcode(barcode)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to find copy")
+		return 0, false
+	}
+	return item.ID, true
+}
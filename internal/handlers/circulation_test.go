@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func newCirculationRequest(method, target, id, body string) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.SetPathValue("id", id)
+	return req
+}
+
+func TestCirculationHandler_CheckoutAndReturn(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	service := circulation.NewService(books, storage.NewMemoryCopyStorage(), patrons, storage.NewMemoryLoanStorage(), storage.NewMemoryHoldStorage(), storage.NewMemoryFineStorage(), circulation.DefaultPolicies())
+	handler := NewCirculationHandler(service)
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	service.AddCopy(book.ID, models.Copy{Barcode: "CC-1", Branch: "Main"})
+	patron, _ := patrons.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	bookID, patronID := strconv.Itoa(book.ID), strconv.Itoa(patron.ID)
+	checkout := `{"patron_id": ` + patronID + `}`
+
+	tests := []struct {
+		name           string
+		handle         http.HandlerFunc
+		req            *http.Request
+		expectedStatus int
+	}{
+		{"checkout", handler.HandleCheckout, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/checkout", bookID, checkout), http.StatusCreated},
+		{"checkout again", handler.HandleCheckout, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/checkout", bookID, checkout), http.StatusConflict},
+		{"checkout unknown barcode", handler.HandleCheckout, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/checkout", bookID, `{"patron_id": 1, "barcode": "NOPE"}`), http.StatusNotFound},
+		{"checkout missing patron", handler.HandleCheckout, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/checkout", bookID, `{}`), http.StatusBadRequest},
+		{"checkout unknown book", handler.HandleCheckout, newCirculationRequest(http.MethodPost, "/books/999999/checkout", "999999", checkout), http.StatusNotFound},
+		{"availability", handler.HandleAvailability, newCirculationRequest(http.MethodGet, "/books/"+bookID+"/availability", bookID, ""), http.StatusOK},
+		{"renew", handler.HandleRenew, newCirculationRequest(http.MethodPost, "/loans/1/renew", "1", ""), http.StatusOK},
+		{"patron loans", handler.HandlePatronLoans, newCirculationRequest(http.MethodGet, "/patrons/"+patronID+"/loans?active=true", patronID, ""), http.StatusOK},
+		{"unknown patron loans", handler.HandlePatronLoans, newCirculationRequest(http.MethodGet, "/patrons/999/loans", "999", ""), http.StatusNotFound},
+		{"return", handler.HandleReturn, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/return", bookID, ""), http.StatusOK},
+		{"return again", handler.HandleReturn, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/return", bookID, ""), http.StatusConflict},
+		{"loan", handler.HandleLoan, newCirculationRequest(http.MethodGet, "/loans/1", "1", ""), http.StatusOK},
+		{"unknown loan", handler.HandleLoan, newCirculationRequest(http.MethodGet, "/loans/999", "999", ""), http.StatusNotFound},
+		{"renew returned loan", handler.HandleRenew, newCirculationRequest(http.MethodPost, "/loans/1/renew", "1", ""), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handle(w, tt.req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	loan, _ := service.Loan(1)
+	if loan == nil || loan.Active() || loan.Renewals != 1 {
+		t.Errorf("expected a returned loan renewed once, got %+v", loan)
+	}
+}
+
+func TestCirculationHandler_HandleAvailability(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	service := circulation.NewService(books, storage.NewMemoryCopyStorage(), storage.NewMemoryPatronStorage(), storage.NewMemoryLoanStorage(), storage.NewMemoryHoldStorage(), storage.NewMemoryFineStorage(), circulation.DefaultPolicies())
+	handler := NewCirculationHandler(service)
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	service.AddCopy(book.ID, models.Copy{Barcode: "CC-1", Branch: "Main"})
+	id := strconv.Itoa(book.ID)
+
+	w := httptest.NewRecorder()
+	handler.HandleAvailability(w, newCirculationRequest(http.MethodGet, "/books/"+id+"/availability", id, ""))
+
+	var availability circulation.Availability
+	json.NewDecoder(w.Body).Decode(&availability)
+	if availability.Status != circulation.StatusAvailable || availability.AvailableCopies != 1 {
+		t.Errorf("expected one available copy, got %+v", availability)
+	}
+}
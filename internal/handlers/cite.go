@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/citation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// HandleCite handles requests to /books/{id}/cite endpoint
+func (h *BookHandler) HandleCite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, err := parseBookID(r.URL.Path)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	book, err := h.storage.GetByID(id)
+	if err != nil {
+		if err == models.ErrBookNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Book not found")
+			return
+		}
+		logger.Error.Printf("Failed to get book: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve book")
+		return
+	}
+
+	respondWithCitations(w, r, []models.Book{*book})
+}
+
+// HandleCiteList handles requests to /books/cite endpoint, citing either the
+// books listed in the ids parameter or all books matching the filters
+func (h *BookHandler) HandleCiteList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var books []models.Book
+	if idsParam := r.URL.Query().Get("ids"); idsParam != "" {
+		for _, idStr := range strings.Split(idsParam, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(idStr))
+			if err != nil {
+				respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+				return
+			}
+			book, err := h.storage.GetByID(id)
+			if err != nil {
+				if err == models.ErrBookNotFound {
+					respondWithError(w, r, http.StatusNotFound, "Book not found")
+					return
+				}
+				logger.Error.Printf("Failed to get book: %v", err)
+				respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve book")
+				return
+			}
+			books = append(books, *book)
+		}
+	} else {
+		filters, err := h.bookFilters(r)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		books, err = h.filteredBooks(r, filters)
+		if err != nil {
+			logger.Error.Printf("Failed to get books: %v", err)
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve books")
+			return
+		}
+	}
+
+	respondWithCitations(w, r, books)
+}
+
+// respondWithCitations writes books as citations in the format requested by
+// the format query parameter
+func respondWithCitations(w http.ResponseWriter, r *http.Request, books []models.Book) {
+	format, err := citation.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := citation.Write(&buf, format, books); err != nil {
+		logger.Error.Printf("Failed to format citations: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to format citations")
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType()+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
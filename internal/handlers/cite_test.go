@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestBookHandler_HandleCite(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+
+	created, _ := store.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin", PublishedYear: 2008})
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{
+			name:           "default bibtex",
+			url:            fmt.Sprintf("/books/%d/cite", created.ID),
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/x-bibtex; charset=utf-8",
+			expectedBody:   "@book{martin2008clean,",
+		},
+		{
+			name:           "ris",
+			url:            fmt.Sprintf("/books/%d/cite?format=ris", created.ID),
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/x-research-info-systems; charset=utf-8",
+			expectedBody:   "TY  - BOOK",
+		},
+		{
+			name:           "unsupported format",
+			url:            fmt.Sprintf("/books/%d/cite?format=endnote", created.ID),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "non-existing book",
+			url:            "/books/999999/cite",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid ID",
+			url:            "/books/invalid/cite",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleCite(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedType != "" && w.Header().Get("Content-Type") != tt.expectedType {
+				t.Errorf("expected Content-Type %s, got %s", tt.expectedType, w.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBookHandler_HandleCiteList(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+
+	first, _ := store.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	store.Create(models.Book{Title: "Clean Architecture", Author: "Robert C. Martin"})
+	third, _ := store.Create(models.Book{Title: "Refactoring", Author: "Martin Fowler"})
+
+	tests := []struct {
+		name            string
+		url             string
+		expectedStatus  int
+		expectedEntries int
+	}{
+		{
+			name:            "filtered list",
+			url:             "/books/cite?author=Robert",
+			expectedStatus:  http.StatusOK,
+			expectedEntries: 2,
+		},
+		{
+			name:            "explicit ids",
+			url:             fmt.Sprintf("/books/cite?ids=%d,%d", first.ID, third.ID),
+			expectedStatus:  http.StatusOK,
+			expectedEntries: 2,
+		},
+		{
+			name:           "unknown id",
+			url:            "/books/cite?ids=999999",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleCiteList(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if got := strings.Count(w.Body.String(), "@book{"); got != tt.expectedEntries {
+				t.Errorf("expected %d entries, got %d", tt.expectedEntries, got)
+			}
+		})
+	}
+}
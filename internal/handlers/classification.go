@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/classification"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// HandleClassTree handles requests to /classification/{scheme} endpoint,
+// returning the classes of the scheme that books are shelved in with the
+// number of books in each, down to depth levels
+func (h *BookHandler) HandleClassTree(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	scheme, err := classification.ParseScheme(r.PathValue("scheme"))
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+	depth := classification.MaxDepth
+	if d := r.URL.Query().Get("depth"); d != "" {
+		depth, err = strconv.Atoi(d)
+		if err != nil || depth < 1 || depth > classification.MaxDepth {
+			respondWithError(w, r, http.StatusBadRequest, "depth must be from 1 to 3")
+			return
+		}
+	}
+
+	books, err := h.storage.GetAll()
+	if err != nil {
+		logger.Error.Printf("Failed to get books: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve books")
+		return
+	}
+	callNumbers := make([]string, 0, len(books))
+	for _, book := range books {
+		if cn := book.CallNumber(scheme); cn != "" {
+			callNumbers = append(callNumbers, cn)
+		}
+	}
+	classes, err := classification.Tree(scheme, callNumbers, depth)
+	if err != nil {
+		logger.Error.Printf("Failed to build class tree: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to build class tree")
+		return
+	}
+
+	count := 0
+	for _, class := range classes {
+		count += class.Count
+	}
+	respond(w, r, http.StatusOK, struct {
+		Scheme  classification.Scheme  `json:"scheme"`
+		Count   int                    `json:"count"`
+		Classes []*classification.Node `json:"classes"`
+	}{scheme, count, classes})
+}
+
+// HandleShelf handles requests to /classification/{scheme}/books endpoint,
+// browsing the books shelved from the call number in the from parameter up
+// to the one in to, in shelf order. Either end may be left open
+func (h *BookHandler) HandleShelf(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	scheme, err := classification.ParseScheme(r.PathValue("scheme"))
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+	shelves, err := classification.ParseRange(scheme, r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	books, err := h.storage.GetAll()
+	if err != nil {
+		logger.Error.Printf("Failed to get books: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve books")
+		return
+	}
+	shelf := make([]models.Book, 0)
+	for _, book := range books {
+		if c, err := classification.Parse(scheme, book.CallNumber(scheme)); err == nil && shelves.Contains(c) {
+			shelf = append(shelf, book)
+		}
+	}
+	models.ShelfOrder(shelf, scheme, false)
+
+	params := models.ParsePaginationParams(r)
+	start, end := params.Bounds(len(shelf))
+	respond(w, r, http.StatusOK, models.NewPaginatedResponse(shelf[start:end], params.Page, params.PageSize, len(shelf)))
+}
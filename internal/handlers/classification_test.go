@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/classification"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func newClassificationHandler(t *testing.T) *BookHandler {
+	t.Helper()
+
+	store := storage.NewMemoryStorage()
+	for _, book := range []models.Book{
+		{Title: "The Go Programming Language", Author: "Alan A. A. Donovan", Dewey: "005.133 D66", LCC: "QA76.73.G63 D66"},
+		{Title: "Clean Code", Author: "Robert C. Martin", Dewey: "005.1 M37", LCC: "QA76.76.D47 M37"},
+		{Title: "The Selfish Gene", Author: "Richard Dawkins", Dewey: "591.5 D32"},
+		{Title: "Uncatalogued", Author: "Anonymous"},
+	} {
+		store.Create(book)
+	}
+	return NewBookHandler(store)
+}
+
+func TestBookHandler_HandleShelf(t *testing.T) {
+	handler := newClassificationHandler(t)
+
+	tests := []struct {
+		name           string
+		scheme         string
+		query          string
+		expectedStatus int
+		expectedTitles []string
+	}{
+		{"whole dewey shelf", "dewey", "", http.StatusOK, []string{"Clean Code", "The Go Programming Language", "The Selfish Gene"}},
+		{"dewey range", "dewey", "?from=000&to=099", http.StatusOK, []string{"Clean Code", "The Go Programming Language"}},
+		{"open-ended dewey range", "dewey", "?from=100", http.StatusOK, []string{"The Selfish Gene"}},
+		{"lcc class", "lcc", "?from=QA&to=QA", http.StatusOK, []string{"The Go Programming Language", "Clean Code"}},
+		{"invalid bound", "dewey", "?from=QA", http.StatusBadRequest, nil},
+		{"unknown scheme", "udc", "", http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/classification/"+tt.scheme+"/books"+tt.query, nil)
+			req.SetPathValue("scheme", tt.scheme)
+			w := httptest.NewRecorder()
+			handler.HandleShelf(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedTitles == nil {
+				return
+			}
+			var response struct {
+				Data []models.Book `json:"data"`
+			}
+			json.NewDecoder(w.Body).Decode(&response)
+			if len(response.Data) != len(tt.expectedTitles) {
+				t.Fatalf("expected %d books, got %d", len(tt.expectedTitles), len(response.Data))
+			}
+			for i, title := range tt.expectedTitles {
+				if response.Data[i].Title != title {
+					t.Errorf("expected %q at position %d, got %q", title, i, response.Data[i].Title)
+				}
+			}
+		})
+	}
+}
+
+func TestBookHandler_HandleClassTree(t *testing.T) {
+	handler := newClassificationHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/classification/dewey?depth=2", nil)
+	req.SetPathValue("scheme", "dewey")
+	w := httptest.NewRecorder()
+	handler.HandleClassTree(w, req)
+
+	var tree struct {
+		Count   int                    `json:"count"`
+		Classes []*classification.Node `json:"classes"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&tree); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if tree.Count != 3 || len(tree.Classes) != 2 {
+		t.Fatalf("expected 3 books in 2 classes, got %+v", tree)
+	}
+	if class := tree.Classes[0]; class.Code != "000" || class.Count != 2 || len(class.Children) != 1 || class.Children[0].Children != nil {
+		t.Errorf("expected class 000 with 2 books in one division, got %+v", class)
+	}
+
+	for _, query := range []string{"?depth=0", "?depth=4", "?depth=x"} {
+		req := httptest.NewRequest(http.MethodGet, "/classification/dewey"+query, nil)
+		req.SetPathValue("scheme", "dewey")
+		w := httptest.NewRecorder()
+		handler.HandleClassTree(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// HandleCopies handles requests to /books/{id}/copies endpoint
+func (h *CirculationHandler) HandleCopies(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		copies, err := h.circulation.Copies(bookID)
+		if err != nil {
+			respondWithCirculationError(w, r, err, "Failed to retrieve copies")
+			return
+		}
+		respond(w, r, http.StatusOK, copies)
+	case http.MethodPost:
+		var item models.Copy
+		if !decodeRequest(w, r, &item) {
+			return
+		}
+		created, err := h.circulation.AddCopy(bookID, item)
+		if err != nil {
+			respondWithCirculationError(w, r, err, "Failed to add copy")
+			return
+		}
+		respond(w, r, http.StatusCreated, created)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleCopy handles requests to /books/{id}/copies/{copy} endpoint
+func (h *CirculationHandler) HandleCopy(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+	copyID, err := strconv.Atoi(r.PathValue("copy"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid copy ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		item, err := h.circulation.Copy(bookID, copyID)
+		if err != nil {
+			respondWithCirculationError(w, r, err, "Failed to retrieve copy")
+			return
+		}
+		respond(w, r, http.StatusOK, item)
+	case http.MethodPut, http.MethodPatch:
+		var item models.Copy
+		if !decodeRequest(w, r, &item) {
+			return
+		}
+		updated, err := h.circulation.UpdateCopy(bookID, copyID, item)
+		if err != nil {
+			respondWithCirculationError(w, r, err, "Failed to update copy")
+			return
+		}
+		respond(w, r, http.StatusOK, updated)
+	case http.MethodDelete:
+		if err := h.circulation.DeleteCopy(bookID, copyID); err != nil {
+			respondWithCirculationError(w, r, err, "Failed to delete copy")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestCirculationHandler_Copies(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	service := circulation.NewService(books, storage.NewMemoryCopyStorage(), patrons, storage.NewMemoryLoanStorage(), storage.NewMemoryHoldStorage(), storage.NewMemoryFineStorage(), circulation.DefaultPolicies())
+	handler := NewCirculationHandler(service)
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	patron, _ := patrons.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	id := strconv.Itoa(book.ID)
+
+	tests := []struct {
+		name           string
+		method         string
+		copy           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "add copy", method: http.MethodPost, body: `{"barcode": "CC-1", "branch": "Main"}`, expectedStatus: http.StatusCreated},
+		{name: "add second copy", method: http.MethodPost, body: `{"barcode": "CC-2", "branch": "East", "condition": "fair"}`, expectedStatus: http.StatusCreated},
+		{name: "duplicate barcode", method: http.MethodPost, body: `{"barcode": "CC-1", "branch": "West"}`, expectedStatus: http.StatusConflict},
+		{name: "missing branch", method: http.MethodPost, body: `{"barcode": "CC-3"}`, expectedStatus: http.StatusBadRequest},
+		{name: "list copies", method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "get copy", method: http.MethodGet, copy: "1", expectedStatus: http.StatusOK},
+		{name: "get unknown copy", method: http.MethodGet, copy: "99", expectedStatus: http.StatusNotFound},
+		{name: "send to repair", method: http.MethodPut, copy: "2", body: `{"barcode": "CC-2", "branch": "East", "status": "in_repair"}`, expectedStatus: http.StatusOK},
+		{name: "put on loan directly", method: http.MethodPut, copy: "1", body: `{"barcode": "CC-1", "branch": "Main", "status": "on_loan"}`, expectedStatus: http.StatusBadRequest},
+		{name: "delete copy", method: http.MethodDelete, copy: "2", expectedStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/books/"+id+"/copies/"+tt.copy, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.SetPathValue("id", id)
+			w := httptest.NewRecorder()
+
+			if tt.copy == "" {
+				handler.HandleCopies(w, req)
+			} else {
+				req.SetPathValue("copy", tt.copy)
+				handler.HandleCopy(w, req)
+			}
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Check out and return the remaining copy by barcode
+	body := `{"patron_id": ` + strconv.Itoa(patron.ID) + `, "barcode": "CC-1"}`
+	w := httptest.NewRecorder()
+	handler.HandleCheckout(w, newCirculationRequest(http.MethodPost, "/books/"+id+"/checkout", id, body))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if item, _ := service.Copy(book.ID, 1); item.Status != models.CopyOnLoan {
+		t.Errorf("expected copy on loan, got %s", item.Status)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleReturn(w, newCirculationRequest(http.MethodPost, "/books/"+id+"/return", id, `{"barcode": "CC-1"}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if item, _ := service.Copy(book.ID, 1); item.Status != models.CopyAvailable {
+		t.Errorf("expected copy available, got %s", item.Status)
+	}
+}
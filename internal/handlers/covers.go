@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/covers"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// MultipartOverhead is how far a multipart upload may go over the size limit
+// to leave room for its boundaries and part headers
+const MultipartOverhead = 64 << 10
+
+// CoverHandler handles book cover HTTP requests
+type CoverHandler struct {
+	covers *covers.Service
+}
+
+// NewCoverHandler creates a new cover handler
+func NewCoverHandler(covers *covers.Service) *CoverHandler {
+	return &CoverHandler{
+		covers: covers,
+	}
+}
+
+// HandleCover handles requests to /books/{id}/cover endpoint. PUT uploads a
+// JPEG, PNG or WebP cover as the request body or as the cover field of a
+// multipart form; GET serves the cover, or the thumbnail named by the size
+// parameter, with an ETag for conditional requests
+func (h *CoverHandler) HandleCover(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.getCover(w, r, id)
+	case http.MethodPut:
+		h.putCover(w, r, id)
+	case http.MethodDelete:
+		if err := h.covers.Delete(id); err != nil {
+			respondWithCoverError(w, r, err, "Failed to delete cover")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getCover serves a cover or one of its thumbnails. Caches may keep it but
+// must revalidate, which costs a 304 while the ETag still matches
+func (h *CoverHandler) getCover(w http.ResponseWriter, r *http.Request, id int) {
+	img, err := h.covers.Image(id, r.URL.Query().Get("size"))
+	if err != nil {
+		respondWithCoverError(w, r, err, "Failed to retrieve cover")
+		return
+	}
+
+	w.Header().Set("Content-Type", img.ContentType)
+	w.Header().Set("ETag", img.ETag)
+	w.Header().Set("Cache-Control", "public, no-cache")
+	http.ServeContent(w, r, "", img.ModTime, bytes.NewReader(img.Data))
+}
+
+// putCover stores an uploaded cover
+func (h *CoverHandler) putCover(w http.ResponseWriter, r *http.Request, id int) {
+	r.Body = http.MaxBytesReader(w, r.Body, int64(h.covers.MaxBytes()+MultipartOverhead))
+	data, _, err := readUpload(r, "cover")
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		respondWithCoverError(w, r, covers.ErrTooLarge, "")
+		return
+	case err != nil:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	case len(data) == 0:
+		respondWithError(w, r, http.StatusBadRequest, "cover image is required")
+		return
+	}
+
+	cover, err := h.covers.Upload(id, data)
+	if err != nil {
+		respondWithCoverError(w, r, err, "Failed to upload cover")
+		return
+	}
+	respond(w, r, http.StatusOK, cover)
+}
+
+// readUpload returns an uploaded file and its name: the named field of a
+// multipart form, or else the whole request body, named by the filename in
+// its Content-Disposition header if it has one
+func readUpload(r *http.Request, field string) ([]byte, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Disposition"))
+		data, err := io.ReadAll(r.Body)
+		return data, params["filename"], err
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, "", fmt.Errorf("%s field is required", field)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if part.FormName() == field {
+			data, err := io.ReadAll(part)
+			return data, part.FileName(), err
+		}
+	}
+}
+
+// respondWithCoverError maps cover errors to HTTP responses, logging and
+// hiding unexpected ones
+func respondWithCoverError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch err {
+	case models.ErrBookNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Book not found")
+	case covers.ErrNoCover:
+		respondWithError(w, r, http.StatusNotFound, "Cover not found")
+	case covers.ErrUnknownSize, covers.ErrInvalidImage, covers.ErrTooManyPixels:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+	case covers.ErrUnsupportedType:
+		respondWithError(w, r, http.StatusUnsupportedMediaType, err.Error())
+	case covers.ErrTooLarge:
+		respondWithError(w, r, http.StatusRequestEntityTooLarge, err.Error())
+	default:
+		logger.Error.Printf("%s: %v", message, err)
+		respondWithError(w, r, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/blob"
+	"github.com/codeforgood-org/golang-book-api/internal/covers"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newCoverRequest returns a request for the cover of a book
+func newCoverRequest(method, target, id string, body []byte, contentType string) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.SetPathValue("id", id)
+	return req
+}
+
+func TestCoverHandler(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	service := covers.NewService(books, blob.NewMemoryStore())
+	service.SetMaxBytes(64 << 10)
+	handler := NewCoverHandler(service)
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	id := strconv.Itoa(book.ID)
+	target := "/books/" + id + "/cover"
+
+	var cover bytes.Buffer
+	png.Encode(&cover, image.NewGray(image.Rect(0, 0, 200, 300)))
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, _ := writer.CreateFormFile("cover", "cover.png")
+	part.Write(cover.Bytes())
+	writer.Close()
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		expectedStatus int
+	}{
+		{"get before upload", newCoverRequest(http.MethodGet, target, id, nil, ""), http.StatusNotFound},
+		{"upload", newCoverRequest(http.MethodPut, target, id, cover.Bytes(), "image/png"), http.StatusOK},
+		{"upload as a form", newCoverRequest(http.MethodPut, target, id, form.Bytes(), writer.FormDataContentType()), http.StatusOK},
+		{"upload text", newCoverRequest(http.MethodPut, target, id, []byte("not an image"), "image/png"), http.StatusUnsupportedMediaType},
+		{"upload nothing", newCoverRequest(http.MethodPut, target, id, nil, "image/png"), http.StatusBadRequest},
+		{"upload too much", newCoverRequest(http.MethodPut, target, id, make([]byte, 64<<10+1), "image/png"), http.StatusRequestEntityTooLarge},
+		{"upload far too much", newCoverRequest(http.MethodPut, target, id, make([]byte, 1<<20), "image/png"), http.StatusRequestEntityTooLarge},
+		{"upload for unknown book", newCoverRequest(http.MethodPut, "/books/999/cover", "999", cover.Bytes(), "image/png"), http.StatusNotFound},
+		{"get", newCoverRequest(http.MethodGet, target, id, nil, ""), http.StatusOK},
+		{"get thumbnail", newCoverRequest(http.MethodGet, target+"?size=small", id, nil, ""), http.StatusOK},
+		{"get unknown size", newCoverRequest(http.MethodGet, target+"?size=huge", id, nil, ""), http.StatusBadRequest},
+		{"delete", newCoverRequest(http.MethodDelete, target, id, nil, ""), http.StatusNoContent},
+		{"delete again", newCoverRequest(http.MethodDelete, target, id, nil, ""), http.StatusNotFound},
+		{"invalid ID", newCoverRequest(http.MethodGet, "/books/abc/cover", "abc", nil, ""), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleCover(w, tt.req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCoverHandler_ConditionalGet(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	handler := NewCoverHandler(covers.NewService(books, blob.NewMemoryStore()))
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	id := strconv.Itoa(book.ID)
+	target := "/books/" + id + "/cover"
+
+	var img bytes.Buffer
+	png.Encode(&img, image.NewGray(image.Rect(0, 0, 200, 300)))
+	w := httptest.NewRecorder()
+	handler.HandleCover(w, newCoverRequest(http.MethodPut, target, id, img.Bytes(), "image/png"))
+	var cover models.Cover
+	json.NewDecoder(w.Body).Decode(&cover)
+
+	w = httptest.NewRecorder()
+	handler.HandleCover(w, newCoverRequest(http.MethodGet, target, id, nil, ""))
+	if w.Header().Get("ETag") != cover.ETag || w.Header().Get("Content-Type") != "image/png" || !bytes.Equal(w.Body.Bytes(), img.Bytes()) {
+		t.Errorf("expected the PNG with ETag %s, got %s with ETag %s", cover.ETag, w.Header().Get("Content-Type"), w.Header().Get("ETag"))
+	}
+
+	req := newCoverRequest(http.MethodGet, target, id, nil, "")
+	req.Header.Set("If-None-Match", cover.ETag)
+	w = httptest.NewRecorder()
+	handler.HandleCover(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected status %d, got %d", http.StatusNotModified, w.Code)
+	}
+
+	// Cover URLs appear on the book
+	if stored, _ := books.GetByID(book.ID); stored.Cover == nil || stored.Cover.URL != target || stored.Cover.Thumbnail("small").URL != target+"?size=small" {
+		t.Errorf("expected cover URLs on the book, got %+v", stored.Cover)
+	}
+}
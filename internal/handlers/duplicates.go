@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/dedupe"
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// MergeRequest is the body accepted when merging books: the books with the
+// listed IDs are merged into the book with ID into
+type MergeRequest struct {
+	Into int   `json:"into"`
+	IDs  []int `json:"ids"`
+}
+
+// SetDuplicates makes the handler find and merge duplicate books with s,
+// and warn when a new book looks like one already in the catalogue
+func (h *BookHandler) SetDuplicates(s *dedupe.Service) {
+	h.duplicates = s
+}
+
+// HandleDuplicates handles requests to /books/duplicates endpoint, listing
+// the clusters of books that are likely to be the same record. The
+// threshold parameter overrides the configured score from which books are
+// taken to be duplicates
+func (h *BookHandler) HandleDuplicates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.duplicates == nil {
+		respondWithError(w, r, http.StatusNotImplemented, "Duplicate detection is not configured")
+		return
+	}
+
+	threshold := 0.0
+	if value := r.URL.Query().Get("threshold"); value != "" {
+		var err error
+		if threshold, err = strconv.ParseFloat(value, 64); err != nil || threshold == 0 {
+			respondWithError(w, r, http.StatusBadRequest, dedupe.ErrInvalidThreshold.Error())
+			return
+		}
+	}
+	clusters, err := h.duplicates.Duplicates(threshold)
+	if err != nil {
+		respondWithDedupeError(w, r, err, "Failed to find duplicates")
+		return
+	}
+
+	params := models.ParsePaginationParams(r)
+	start, end := params.Bounds(len(clusters))
+	respond(w, r, http.StatusOK, models.NewPaginatedResponse(clusters[start:end], params.Page, params.PageSize, len(clusters)))
+}
+
+// HandleMerge handles requests to /books/merge endpoint, merging books into
+// another and returning it as merged
+func (h *BookHandler) HandleMerge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.duplicates == nil {
+		respondWithError(w, r, http.StatusNotImplemented, "Duplicate detection is not configured")
+		return
+	}
+
+	var req MergeRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	merged, err := h.duplicates.Merge(r.Context(), req.Into, req.IDs)
+	if err != nil {
+		respondWithDedupeError(w, r, err, "Failed to merge books")
+		return
+	}
+	h.notify(events.BookUpdated, merged.ID, merged)
+	for _, id := range req.IDs {
+		h.notify(events.BookMerged, id, merged)
+	}
+
+	respond(w, r, http.StatusOK, merged)
+}
+
+// warnDuplicates adds a Warning header naming the books a new book looks
+// like, with a Link to each, so clients can offer a merge instead
+func (h *BookHandler) warnDuplicates(w http.ResponseWriter, book models.Book) {
+	if h.duplicates == nil {
+		return
+	}
+	matches, err := h.duplicates.Similar(book)
+	if err != nil {
+		logger.Warning.Printf("Failed to check for duplicates of %q: %v", book.Title, err)
+		return
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = strconv.Itoa(match.Book.ID)
+		w.Header().Add("Link", fmt.Sprintf(`</books/%d>; rel="duplicate"`, match.Book.ID))
+	}
+	w.Header().Set("Warning", fmt.Sprintf(`299 - "Possible duplicate of books %s"`, strings.Join(ids, ", ")))
+}
+
+// redirect sends a permanent redirect to the book a merged book's ID now
+// stands for, reporting whether it did. A live book with the ID is never
+// redirected
+func (h *BookHandler) redirect(w http.ResponseWriter, r *http.Request, id int) bool {
+	redirects, ok := h.storage.(storage.Redirects)
+	if !ok {
+		return false
+	}
+	if _, err := h.storage.GetByID(id); err != models.ErrBookNotFound {
+		return false
+	}
+	into, ok := redirects.Redirect(id)
+	if !ok {
+		return false
+	}
+	http.Redirect(w, r, fmt.Sprintf("/books/%d", into), http.StatusPermanentRedirect)
+	return true
+}
+
+// respondWithDedupeError maps duplicate detection and merge errors to HTTP
+// responses, logging and hiding unexpected ones
+func respondWithDedupeError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch err {
+	case models.ErrBookNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Book not found")
+	case dedupe.ErrInvalidThreshold, dedupe.ErrNoBooks, dedupe.ErrMergeIntoSelf:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+	case dedupe.ErrNotSupported:
+		respondWithError(w, r, http.StatusNotImplemented, err.Error())
+	default:
+		logger.Error.Printf("%s: %v", message, err)
+		respondWithError(w, r, http.StatusInternalServerError, message)
+	}
+}
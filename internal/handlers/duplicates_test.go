@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/dedupe"
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newDedupeBookHandler returns a book handler detecting duplicates in a
+// storage holding two copies of Dune entered differently and one other book
+func newDedupeBookHandler(t *testing.T) (*BookHandler, *storage.MemoryStorage, []*models.Book) {
+	t.Helper()
+
+	store := storage.NewMemoryStorage()
+	var books []*models.Book
+	for _, book := range []models.Book{
+		{Title: "Dune", Author: "Frank Herbert", ISBN: "9780441013593"},
+		{Title: "Dune", Author: "F. Herbert", Publisher: "Ace Books"},
+		{Title: "Emma", Author: "Jane Austen"},
+	} {
+		created, _ := store.Create(book)
+		books = append(books, created)
+	}
+	handler := NewBookHandler(store)
+	handler.SetDuplicates(dedupe.NewService(store))
+	return handler, store, books
+}
+
+func TestBookHandler_HandleDuplicates(t *testing.T) {
+	handler, _, books := newDedupeBookHandler(t)
+
+	tests := []struct {
+		name             string
+		method           string
+		target           string
+		expectedStatus   int
+		expectedClusters int
+	}{
+		{"default threshold", http.MethodGet, "/books/duplicates", http.StatusOK, 1},
+		{"higher threshold", http.MethodGet, "/books/duplicates?threshold=0.99", http.StatusOK, 0},
+		{"threshold above 1", http.MethodGet, "/books/duplicates?threshold=2", http.StatusBadRequest, 0},
+		{"zero threshold", http.MethodGet, "/books/duplicates?threshold=0", http.StatusBadRequest, 0},
+		{"invalid threshold", http.MethodGet, "/books/duplicates?threshold=high", http.StatusBadRequest, 0},
+		{"POST", http.MethodPost, "/books/duplicates", http.StatusMethodNotAllowed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleDuplicates(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Data  []dedupe.Cluster `json:"data"`
+				Total int              `json:"total"`
+			}
+			json.NewDecoder(w.Body).Decode(&resp)
+			if len(resp.Data) != tt.expectedClusters {
+				t.Fatalf("expected %d clusters, got %+v", tt.expectedClusters, resp.Data)
+			}
+			if tt.expectedClusters > 0 && (len(resp.Data[0].Books) != 2 || resp.Data[0].Books[0].ID != books[0].ID) {
+				t.Errorf("expected both copies of Dune, oldest first, got %+v", resp.Data[0].Books)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	NewBookHandler(storage.NewMemoryStorage()).HandleDuplicates(w, httptest.NewRequest(http.MethodGet, "/books/duplicates", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("expected status %d without duplicate detection, got %d", http.StatusNotImplemented, w.Code)
+	}
+}
+
+func TestBookHandler_HandleBooks_POST_WarnsOfDuplicates(t *testing.T) {
+	handler, _, books := newDedupeBookHandler(t)
+
+	tests := []struct {
+		name          string
+		body          string
+		expectedLinks []string
+	}{
+		{"same ISBN", `{"title": "Dune (Ace)", "author": "Herbert", "isbn": "0-441-01359-7"}`, []string{fmt.Sprintf(`</books/%d>; rel="duplicate"`, books[0].ID)}},
+		{"new book", `{"title": "Persuasion", "author": "Jane Austen"}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler.HandleBooks(w, req)
+
+			if w.Code != http.StatusCreated {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+			}
+			if links := w.Header().Values("Link"); !slices.Equal(links, tt.expectedLinks) {
+				t.Errorf("expected links %v, got %v", tt.expectedLinks, links)
+			}
+			warning := w.Header().Get("Warning")
+			if (tt.expectedLinks != nil) != strings.HasPrefix(warning, "299 - ") {
+				t.Errorf("unexpected warning %q", warning)
+			}
+		})
+	}
+}
+
+func TestBookHandler_HandleMerge(t *testing.T) {
+	handler, store, books := newDedupeBookHandler(t)
+	notifier := &recordingNotifier{}
+	handler.SetNotifier(notifier)
+	kept, merged := books[0], books[1]
+
+	body := fmt.Sprintf(`{"into": %d, "ids": [%d]}`, kept.ID, merged.ID)
+	req := httptest.NewRequest(http.MethodPost, "/books/merge", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.HandleMerge(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var book models.Book
+	json.NewDecoder(w.Body).Decode(&book)
+	if book.ID != kept.ID || book.Publisher != "Ace Books" {
+		t.Errorf("expected the kept book with the publisher filled in, got %+v", book)
+	}
+	if !slices.Equal(notifier.types, []events.Type{events.BookUpdated, events.BookMerged}) {
+		t.Errorf("unexpected notifications %v", notifier.types)
+	}
+	if _, err := store.GetByID(merged.ID); err != models.ErrBookNotFound {
+		t.Errorf("expected the merged book removed, got %v", err)
+	}
+
+	// The merged book's ID now leads to the kept book
+	w = httptest.NewRecorder()
+	handler.HandleBookByID(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/books/%d", merged.ID), nil))
+	if w.Code != http.StatusPermanentRedirect || w.Header().Get("Location") != fmt.Sprintf("/books/%d", kept.ID) {
+		t.Errorf("expected a permanent redirect to the kept book, got %d to %q", w.Code, w.Header().Get("Location"))
+	}
+
+	// A live book is served even where its ID is redirected
+	handler.storage = redirectingStorage{store, merged.ID}
+	w = httptest.NewRecorder()
+	handler.HandleBookByID(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/books/%d", books[2].ID), nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected the live book, got %d to %q", w.Code, w.Header().Get("Location"))
+	}
+	handler.storage = store
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+	}{
+		{"no books", http.MethodPost, fmt.Sprintf(`{"into": %d}`, kept.ID), http.StatusBadRequest},
+		{"into itself", http.MethodPost, fmt.Sprintf(`{"into": %d, "ids": [%d]}`, kept.ID, kept.ID), http.StatusBadRequest},
+		{"already merged", http.MethodPost, fmt.Sprintf(`{"into": %d, "ids": [%d]}`, kept.ID, merged.ID), http.StatusNotFound},
+		{"invalid body", http.MethodPost, `{"into": "one"}`, http.StatusBadRequest},
+		{"GET", http.MethodGet, "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/books/merge", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler.HandleMerge(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// redirectingStorage redirects every ID to the book with ID into
+type redirectingStorage struct {
+	*storage.MemoryStorage
+	into int
+}
+
+func (s redirectingStorage) Redirect(int) (int, bool) {
+	return s.into, true
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/enrich"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// SetEnricher makes the handler look up ISBNs with s. If onCreate is true,
+// books created with an ISBN are filled in from its record unless the
+// request sets enrich=false; otherwise only requests setting enrich=true are
+func (h *BookHandler) SetEnricher(s *enrich.Service, onCreate bool) {
+	h.enricher = s
+	h.enrichOnCreate = onCreate
+}
+
+// HandleLookup handles requests to /books/lookup endpoint, returning what
+// the metadata providers know about the ISBN given by the isbn parameter
+func (h *BookHandler) HandleLookup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.enricher == nil {
+		respondWithError(w, r, http.StatusNotImplemented, "ISBN lookup is not configured")
+		return
+	}
+
+	isbn := r.URL.Query().Get("isbn")
+	if isbn == "" {
+		respondWithError(w, r, http.StatusBadRequest, "isbn is required")
+		return
+	}
+	record, err := h.enricher.Lookup(r.Context(), isbn)
+	if err != nil {
+		respondWithEnrichError(w, r, err, "Failed to look up ISBN")
+		return
+	}
+	respond(w, r, http.StatusOK, record)
+}
+
+// wantsEnrich reports whether a new book should be filled in from its ISBN,
+// by the enrich parameter or else the handler's default. ok is false if the
+// parameter is not a boolean
+func (h *BookHandler) wantsEnrich(r *http.Request) (enrich, ok bool) {
+	value := r.URL.Query().Get("enrich")
+	if value == "" {
+		return h.enricher != nil && h.enrichOnCreate, true
+	}
+	enrich, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, false
+	}
+	return enrich && h.enricher != nil, true
+}
+
+// attachCover uses the cover of a record for a new book, returning the book
+// with its cover, or as it was if the cover could not be used
+func (h *BookHandler) attachCover(ctx context.Context, book *models.Book, record *enrich.Record) *models.Book {
+	if record == nil || record.CoverURL == "" {
+		return book
+	}
+	cover, err := h.enricher.AttachCover(ctx, book.ID, record)
+	if err != nil {
+		logger.Warning.Printf("Cover of ISBN %s not used for book %d: %v", record.ISBN, book.ID, err)
+		return book
+	}
+	book.Cover = cover
+	return book
+}
+
+// respondWithEnrichError maps lookup errors to HTTP responses, logging and
+// hiding unexpected ones
+func respondWithEnrichError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch err {
+	case models.ErrInvalidISBN:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+	case enrich.ErrNotFound:
+		respondWithError(w, r, http.StatusNotFound, err.Error())
+	case enrich.ErrUnavailable:
+		respondWithError(w, r, http.StatusBadGateway, err.Error())
+	default:
+		logger.Error.Printf("%s: %v", message, err)
+		respondWithError(w, r, http.StatusInternalServerError, message)
+	}
+}
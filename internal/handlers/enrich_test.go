@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/enrich"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newEnrichedBookHandler returns a book handler looking ISBNs up with a fake
+// provider that knows Dune
+func newEnrichedBookHandler(onCreate bool) (*BookHandler, *enrich.Fake) {
+	provider := enrich.NewFake("fake", enrich.Record{
+		ISBN:          "9780441013593",
+		Title:         "Dune",
+		Authors:       []string{"Frank Herbert"},
+		Publisher:     "Ace Books",
+		PublishedYear: 1965,
+	})
+	handler := NewBookHandler(storage.NewMemoryStorage())
+	handler.SetEnricher(enrich.NewService(provider), onCreate)
+	return handler, provider
+}
+
+func TestBookHandler_HandleLookup(t *testing.T) {
+	handler, _ := newEnrichedBookHandler(false)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		expectedStatus int
+	}{
+		{"ISBN-13", http.MethodPost, "/books/lookup?isbn=9780441013593", http.StatusOK},
+		{"ISBN-10", http.MethodPost, "/books/lookup?isbn=0-441-01359-7", http.StatusOK},
+		{"unknown ISBN", http.MethodPost, "/books/lookup?isbn=9780262033848", http.StatusNotFound},
+		{"invalid ISBN", http.MethodPost, "/books/lookup?isbn=12345", http.StatusBadRequest},
+		{"no ISBN", http.MethodPost, "/books/lookup", http.StatusBadRequest},
+		{"GET", http.MethodGet, "/books/lookup?isbn=9780441013593", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleLookup(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/books/lookup?isbn=0441013597", nil)
+	w := httptest.NewRecorder()
+	handler.HandleLookup(w, req)
+	var record enrich.Record
+	json.NewDecoder(w.Body).Decode(&record)
+	if record.ISBN != "9780441013593" || record.Title != "Dune" || !record.Cached {
+		t.Errorf("expected the cached record, got %+v", record)
+	}
+}
+
+func TestBookHandler_HandleLookup_Unavailable(t *testing.T) {
+	handler, provider := newEnrichedBookHandler(false)
+	provider.SetError(errors.New("connection refused"))
+
+	req := httptest.NewRequest(http.MethodPost, "/books/lookup?isbn=9780441013593", nil)
+	w := httptest.NewRecorder()
+	handler.HandleLookup(w, req)
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("expected status %d, got %d", http.StatusBadGateway, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	NewBookHandler(storage.NewMemoryStorage()).HandleLookup(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("expected status %d without an enricher, got %d", http.StatusNotImplemented, w.Code)
+	}
+}
+
+func TestBookHandler_HandleBooks_POST_Enrich(t *testing.T) {
+	tests := []struct {
+		name           string
+		onCreate       bool
+		target         string
+		body           string
+		expectedStatus int
+		expectedTitle  string
+	}{
+		{"opt in", false, "/books?enrich=true", `{"isbn": "9780441013593"}`, http.StatusCreated, "Dune"},
+		{"on by default", true, "/books", `{"isbn": "9780441013593"}`, http.StatusCreated, "Dune"},
+		{"own fields kept", true, "/books", `{"isbn": "9780441013593", "title": "Dune (Deluxe)"}`, http.StatusCreated, "Dune (Deluxe)"},
+		{"off by default", false, "/books", `{"isbn": "9780441013593"}`, http.StatusBadRequest, ""},
+		{"opt out", true, "/books?enrich=false", `{"isbn": "9780441013593"}`, http.StatusBadRequest, ""},
+		{"unknown ISBN", true, "/books", `{"isbn": "9780262033848"}`, http.StatusNotFound, ""},
+		{"unknown ISBN with details", true, "/books", `{"isbn": "9780262033848", "title": "CLRS", "author": "Cormen"}`, http.StatusCreated, "CLRS"},
+		{"invalid enrich", false, "/books?enrich=maybe", `{"isbn": "9780441013593"}`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _ := newEnrichedBookHandler(tt.onCreate)
+			notifier := &recordingNotifier{}
+			handler.SetNotifier(notifier)
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler.HandleBooks(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			var book models.Book
+			json.NewDecoder(w.Body).Decode(&book)
+			if book.Title != tt.expectedTitle {
+				t.Errorf("expected title %q, got %q", tt.expectedTitle, book.Title)
+			}
+			if len(notifier.types) != 1 {
+				t.Errorf("expected one notification, got %v", notifier.types)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// sseRetry is the reconnection delay, in milliseconds, suggested to clients
+const sseRetry = 3000
+
+// EventsHandler streams catalog changes as Server-Sent Events
+type EventsHandler struct {
+	bus       *events.Bus
+	heartbeat time.Duration
+}
+
+// NewEventsHandler creates a handler streaming events from bus, writing a
+// heartbeat comment whenever the stream has been idle for the given interval
+func NewEventsHandler(bus *events.Bus, heartbeat time.Duration) *EventsHandler {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	return &EventsHandler{
+		bus:       bus,
+		heartbeat: heartbeat,
+	}
+}
+
+// ServeHTTP handles requests to /books/events endpoint
+func (h *EventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	lastID, err := parseLastEventID(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid Last-Event-ID")
+		return
+	}
+
+	sub, backlog, complete := h.bus.Subscribe(lastID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry)
+
+	if complete {
+		for _, event := range backlog {
+			writeEvent(w, event)
+		}
+	} else {
+		// The log no longer holds every event the client missed, so tell it
+		// to reload its state and resume from the newest event we know of
+		var resumeID uint64
+		if len(backlog) > 0 {
+			resumeID = backlog[len(backlog)-1].ID
+		}
+		fmt.Fprintf(w, "id: %d\nevent: reset\ndata: {}\n\n", resumeID)
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Error.Printf("Event stream not supported: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind; the client reconnects with
+				// Last-Event-ID and replays from the log
+				return
+			}
+			writeEvent(w, event)
+			ticker.Reset(h.heartbeat)
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// parseLastEventID reads the resume position from the Last-Event-ID header,
+// falling back to the lastEventId query parameter for clients that cannot
+// set headers
+func parseLastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// writeEvent writes a single event in Server-Sent Events framing
+func writeEvent(w http.ResponseWriter, event events.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger.Error.Printf("Failed to encode event %d: %v", event.ID, err)
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
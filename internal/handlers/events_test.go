@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// sseEvent is a parsed Server-Sent Events frame
+type sseEvent struct {
+	id, event, data, comment string
+}
+
+// readEvents parses frames from an event stream until n have been read
+func readEvents(t *testing.T, scanner *bufio.Scanner, n int) []sseEvent {
+	t.Helper()
+
+	var frames []sseEvent
+	var cur sseEvent
+	for len(frames) < n && scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			frames = append(frames, cur)
+			cur = sseEvent{}
+		case strings.HasPrefix(line, ":"):
+			cur.comment = strings.TrimSpace(line[1:])
+		case strings.HasPrefix(line, "id: "):
+			cur.id = line[len("id: "):]
+		case strings.HasPrefix(line, "event: "):
+			cur.event = line[len("event: "):]
+		case strings.HasPrefix(line, "data: "):
+			cur.data = line[len("data: "):]
+		}
+	}
+	if len(frames) < n {
+		t.Fatalf("expected %d frames, got %d (%v)", n, len(frames), scanner.Err())
+	}
+	return frames
+}
+
+func openStream(t *testing.T, url, lastEventID string) (*http.Response, *bufio.Scanner) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewScanner(resp.Body)
+}
+
+func TestEventsHandler_StreamsStorageChanges(t *testing.T) {
+	bus := events.NewBus(10)
+	store := storage.NewMemoryStorage()
+	store.SetEventBus(bus)
+
+	server := httptest.NewServer(NewEventsHandler(bus, time.Minute))
+	t.Cleanup(server.Close)
+
+	resp, scanner := openStream(t, server.URL, "")
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected Content-Type text/event-stream, got %s", ct)
+	}
+	readEvents(t, scanner, 1) // retry hint
+
+	book, _ := store.Create(models.Book{Title: "Title", Author: "Author"})
+	store.Update(book.ID, models.Book{Title: "New Title", Author: "Author"})
+	store.Delete(book.ID)
+
+	frames := readEvents(t, scanner, 3)
+	wantTypes := []string{"book.created", "book.updated", "book.deleted"}
+	for i, frame := range frames {
+		if frame.event != wantTypes[i] {
+			t.Errorf("expected event %s, got %s", wantTypes[i], frame.event)
+		}
+		if want := string(rune('1' + i)); frame.id != want {
+			t.Errorf("expected id %s, got %s", want, frame.id)
+		}
+	}
+	if !strings.Contains(frames[1].data, `"title":"New Title"`) {
+		t.Errorf("expected updated book in data, got %s", frames[1].data)
+	}
+}
+
+func TestEventsHandler_LastEventID(t *testing.T) {
+	bus := events.NewBus(3)
+	for i := 1; i <= 5; i++ {
+		bus.Publish(events.BookCreated, i, nil)
+	}
+
+	server := httptest.NewServer(NewEventsHandler(bus, time.Minute))
+	t.Cleanup(server.Close)
+
+	t.Run("replays missed events", func(t *testing.T) {
+		_, scanner := openStream(t, server.URL, "3")
+		frames := readEvents(t, scanner, 3)
+		if frames[1].id != "4" || frames[2].id != "5" {
+			t.Errorf("expected replay of events 4 and 5, got %q and %q", frames[1].id, frames[2].id)
+		}
+	})
+
+	t.Run("resets when log is exceeded", func(t *testing.T) {
+		_, scanner := openStream(t, server.URL, "1")
+		frames := readEvents(t, scanner, 2)
+		if frames[1].event != "reset" || frames[1].id != "5" {
+			t.Errorf("expected reset resuming at 5, got %+v", frames[1])
+		}
+	})
+
+	t.Run("rejects invalid id", func(t *testing.T) {
+		resp, _ := openStream(t, server.URL, "abc")
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestEventsHandler_Heartbeat(t *testing.T) {
+	bus := events.NewBus(10)
+	server := httptest.NewServer(NewEventsHandler(bus, 10*time.Millisecond))
+	t.Cleanup(server.Close)
+
+	_, scanner := openStream(t, server.URL, "")
+	frames := readEvents(t, scanner, 2)
+	if frames[1].comment != "heartbeat" {
+		t.Errorf("expected heartbeat comment, got %+v", frames[1])
+	}
+}
+
+func TestEventsHandler_MethodNotAllowed(t *testing.T) {
+	handler := NewEventsHandler(events.NewBus(10), time.Minute)
+
+	req := httptest.NewRequest(http.MethodPost, "/books/events", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// FineTransactionRequest is the body accepted when paying or waiving a fine.
+// A waiver without an amount forgives the whole balance
+type FineTransactionRequest struct {
+	AmountCents int    `json:"amount_cents"`
+	Note        string `json:"note"`
+}
+
+// HandlePatronFines handles requests to /patrons/{id}/fines endpoint; pass
+// open=true to leave out settled fines
+func (h *CirculationHandler) HandlePatronFines(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	patronID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid patron ID")
+		return
+	}
+	openOnly, _ := strconv.ParseBool(r.URL.Query().Get("open"))
+
+	summary, err := h.circulation.PatronFines(patronID, openOnly)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to retrieve fines")
+		return
+	}
+	respond(w, r, http.StatusOK, summary)
+}
+
+// HandleFine handles requests to /fines/{id} endpoint
+func (h *CirculationHandler) HandleFine(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, ok := fineID(w, r)
+	if !ok {
+		return
+	}
+	fine, err := h.circulation.Fine(id)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to retrieve fine")
+		return
+	}
+	respond(w, r, http.StatusOK, fine)
+}
+
+// HandleFinePayment handles requests to /fines/{id}/payments endpoint
+func (h *CirculationHandler) HandleFinePayment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, ok := fineID(w, r)
+	if !ok {
+		return
+	}
+	var req FineTransactionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	fine, err := h.circulation.PayFine(id, req.AmountCents, req.Note)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to pay fine")
+		return
+	}
+	respond(w, r, http.StatusOK, fine)
+}
+
+// HandleFineWaiver handles requests to /fines/{id}/waive endpoint
+func (h *CirculationHandler) HandleFineWaiver(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, ok := fineID(w, r)
+	if !ok {
+		return
+	}
+	var req FineTransactionRequest
+	if r.ContentLength != 0 && !decodeRequest(w, r, &req) {
+		return
+	}
+
+	fine, err := h.circulation.WaiveFine(id, req.AmountCents, req.Note)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to waive fine")
+		return
+	}
+	respond(w, r, http.StatusOK, fine)
+}
+
+// fineID parses the fine ID from the path, writing a 400 response if it is
+// not a number
+func fineID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid fine ID")
+		return 0, false
+	}
+	return id, true
+}
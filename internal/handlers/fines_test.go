@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestCirculationHandler_Fines(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	service := circulation.NewService(books, storage.NewMemoryCopyStorage(), patrons, storage.NewMemoryLoanStorage(), storage.NewMemoryHoldStorage(), storage.NewMemoryFineStorage(), circulation.DefaultPolicies())
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	service.SetClock(func() time.Time { return now })
+	handler := NewCirculationHandler(service)
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	service.AddCopy(book.ID, models.Copy{Barcode: "CC-1", Branch: "Main"})
+	patron, _ := patrons.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	service.Checkout(book.ID, 0, patron.ID)
+	now = now.AddDate(0, 0, 30)
+	service.ScanOverdue()
+	patronID := strconv.Itoa(patron.ID)
+
+	tests := []struct {
+		name           string
+		handle         http.HandlerFunc
+		req            *http.Request
+		expectedStatus int
+	}{
+		{"patron fines", handler.HandlePatronFines, newCirculationRequest(http.MethodGet, "/patrons/"+patronID+"/fines?open=true", patronID, ""), http.StatusOK},
+		{"unknown patron fines", handler.HandlePatronFines, newCirculationRequest(http.MethodGet, "/patrons/999/fines", "999", ""), http.StatusNotFound},
+		{"fine", handler.HandleFine, newCirculationRequest(http.MethodGet, "/fines/1", "1", ""), http.StatusOK},
+		{"unknown fine", handler.HandleFine, newCirculationRequest(http.MethodGet, "/fines/999", "999", ""), http.StatusNotFound},
+		{"invalid fine ID", handler.HandleFine, newCirculationRequest(http.MethodGet, "/fines/abc", "abc", ""), http.StatusBadRequest},
+		{"negative payment", handler.HandleFinePayment, newCirculationRequest(http.MethodPost, "/fines/1/payments", "1", `{"amount_cents": -5}`), http.StatusBadRequest},
+		{"overpayment", handler.HandleFinePayment, newCirculationRequest(http.MethodPost, "/fines/1/payments", "1", `{"amount_cents": 100000}`), http.StatusConflict},
+		{"payment", handler.HandleFinePayment, newCirculationRequest(http.MethodPost, "/fines/1/payments", "1", `{"amount_cents": 100, "note": "cash"}`), http.StatusOK},
+		{"waive the rest", handler.HandleFineWaiver, newCirculationRequest(http.MethodPost, "/fines/1/waive", "1", ""), http.StatusOK},
+		{"waive again", handler.HandleFineWaiver, newCirculationRequest(http.MethodPost, "/fines/1/waive", "1", ""), http.StatusConflict},
+		{"payment to unknown fine", handler.HandleFinePayment, newCirculationRequest(http.MethodPost, "/fines/999/payments", "999", `{"amount_cents": 100}`), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handle(w, tt.req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	summary, _ := service.PatronFines(patron.ID, false)
+	if summary.BalanceCents != 0 || len(summary.Fines) != 1 || summary.Fines[0].PaidCents != 100 {
+		t.Errorf("expected a settled fine with 100 cents paid, got %+v", summary)
+	}
+}
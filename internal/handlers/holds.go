@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// HoldRequest is the body accepted when placing a hold on a book
+type HoldRequest struct {
+	PatronID int `json:"patron_id"`
+}
+
+// HandleBookHolds handles requests to /books/{id}/holds endpoint
+func (h *CirculationHandler) HandleBookHolds(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		holds, err := h.circulation.BookHolds(bookID)
+		if err != nil {
+			respondWithCirculationError(w, r, err, "Failed to retrieve holds")
+			return
+		}
+		respond(w, r, http.StatusOK, holds)
+	case http.MethodPost:
+		var req HoldRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if req.PatronID <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, "patron_id is required")
+			return
+		}
+		hold, err := h.circulation.PlaceHold(bookID, req.PatronID)
+		if err != nil {
+			respondWithCirculationError(w, r, err, "Failed to place hold")
+			return
+		}
+		respond(w, r, http.StatusCreated, hold)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleHold handles requests to /holds/{id} endpoint; DELETE cancels the hold
+func (h *CirculationHandler) HandleHold(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid hold ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		hold, err := h.circulation.Hold(id)
+		if err != nil {
+			respondWithCirculationError(w, r, err, "Failed to retrieve hold")
+			return
+		}
+		respond(w, r, http.StatusOK, hold)
+	case http.MethodDelete:
+		if _, err := h.circulation.CancelHold(id); err != nil {
+			respondWithCirculationError(w, r, err, "Failed to cancel hold")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandlePatronHolds handles requests to /patrons/{id}/holds endpoint; pass
+// active=true to leave out holds that have ended
+func (h *CirculationHandler) HandlePatronHolds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	patronID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid patron ID")
+		return
+	}
+	activeOnly, _ := strconv.ParseBool(r.URL.Query().Get("active"))
+
+	holds, err := h.circulation.PatronHolds(patronID, activeOnly)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to retrieve holds")
+		return
+	}
+	respond(w, r, http.StatusOK, holds)
+}
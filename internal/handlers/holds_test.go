@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestCirculationHandler_Holds(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	service := circulation.NewService(books, storage.NewMemoryCopyStorage(), patrons, storage.NewMemoryLoanStorage(), storage.NewMemoryHoldStorage(), storage.NewMemoryFineStorage(), circulation.DefaultPolicies())
+	handler := NewCirculationHandler(service)
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	service.AddCopy(book.ID, models.Copy{Barcode: "CC-1", Branch: "Main"})
+	ada, _ := patrons.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	kit, _ := patrons.CreatePatron(models.Patron{Name: "Kit", Type: models.PatronChild})
+	service.Checkout(book.ID, 0, ada.ID)
+	bookID, kitID := strconv.Itoa(book.ID), strconv.Itoa(kit.ID)
+	place := `{"patron_id": ` + kitID + `}`
+
+	tests := []struct {
+		name           string
+		handle         http.HandlerFunc
+		req            *http.Request
+		expectedStatus int
+	}{
+		{"place hold", handler.HandleBookHolds, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/holds", bookID, place), http.StatusCreated},
+		{"place duplicate hold", handler.HandleBookHolds, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/holds", bookID, place), http.StatusConflict},
+		{"hold on borrowed book", handler.HandleBookHolds, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/holds", bookID, `{"patron_id": 1}`), http.StatusConflict},
+		{"hold missing patron", handler.HandleBookHolds, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/holds", bookID, `{}`), http.StatusBadRequest},
+		{"hold on unknown book", handler.HandleBookHolds, newCirculationRequest(http.MethodPost, "/books/999999/holds", "999999", place), http.StatusNotFound},
+		{"book holds", handler.HandleBookHolds, newCirculationRequest(http.MethodGet, "/books/"+bookID+"/holds", bookID, ""), http.StatusOK},
+		{"hold", handler.HandleHold, newCirculationRequest(http.MethodGet, "/holds/1", "1", ""), http.StatusOK},
+		{"unknown hold", handler.HandleHold, newCirculationRequest(http.MethodGet, "/holds/999", "999", ""), http.StatusNotFound},
+		{"invalid hold ID", handler.HandleHold, newCirculationRequest(http.MethodGet, "/holds/abc", "abc", ""), http.StatusBadRequest},
+		{"patron holds", handler.HandlePatronHolds, newCirculationRequest(http.MethodGet, "/patrons/"+kitID+"/holds?active=true", kitID, ""), http.StatusOK},
+		{"unknown patron holds", handler.HandlePatronHolds, newCirculationRequest(http.MethodGet, "/patrons/999/holds", "999", ""), http.StatusNotFound},
+		{"cancel hold", handler.HandleHold, newCirculationRequest(http.MethodDelete, "/holds/1", "1", ""), http.StatusNoContent},
+		{"cancel hold again", handler.HandleHold, newCirculationRequest(http.MethodDelete, "/holds/1", "1", ""), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handle(w, tt.req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	hold, _ := service.Hold(1)
+	if hold == nil || hold.Status != models.HoldCancelled {
+		t.Errorf("expected a cancelled hold, got %+v", hold)
+	}
+}
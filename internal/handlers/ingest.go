@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/ebook"
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/ingest"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// IngestHandler handles ebook ingestion and book asset HTTP requests
+type IngestHandler struct {
+	ingest   *ingest.Service
+	notifier Notifier
+}
+
+// NewIngestHandler creates a new ingest handler
+func NewIngestHandler(ingest *ingest.Service) *IngestHandler {
+	return &IngestHandler{
+		ingest: ingest,
+	}
+}
+
+// SetNotifier registers n to be told about books created from ingested
+// files
+func (h *IngestHandler) SetNotifier(n Notifier) {
+	h.notifier = n
+}
+
+// HandleIngest handles requests to /books/ingest endpoint. POST takes an
+// EPUB or PDF as the request body or as the file field of a multipart form,
+// attaches it to the book given by the book_id parameter or matched from
+// its metadata, creating the book if there is none, and responds with 201
+// if a book was created and 200 otherwise
+func (h *IngestHandler) HandleIngest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	bookID := 0
+	if raw := r.URL.Query().Get("book_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+			return
+		}
+		bookID = id
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(h.ingest.MaxBytes()+MultipartOverhead))
+	data, filename, err := readUpload(r, "file")
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		respondWithIngestError(w, r, ingest.ErrTooLarge, "")
+		return
+	case err != nil:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	case len(data) == 0:
+		respondWithError(w, r, http.StatusBadRequest, "file is required")
+		return
+	}
+
+	result, err := h.ingest.Ingest(r.Context(), filename, data, bookID)
+	if err != nil {
+		respondWithIngestError(w, r, err, "Failed to ingest file")
+		return
+	}
+	if !result.Created {
+		respond(w, r, http.StatusOK, result)
+		return
+	}
+	if h.notifier != nil {
+		h.notifier.Notify(events.BookCreated, result.Book.ID, result.Book)
+	}
+	respond(w, r, http.StatusCreated, result)
+}
+
+// HandleAssets handles requests to /books/{id}/assets endpoint, listing the
+// files attached to a book
+func (h *IngestHandler) HandleAssets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	assets, err := h.ingest.Assets(bookID)
+	if err != nil {
+		respondWithIngestError(w, r, err, "Failed to retrieve assets")
+		return
+	}
+	respond(w, r, http.StatusOK, assets)
+}
+
+// HandleAsset handles requests to /books/{id}/assets/{asset} endpoint. GET
+// downloads the file as an attachment, with an ETag for conditional
+// requests
+func (h *IngestHandler) HandleAsset(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+	assetID, err := strconv.Atoi(r.PathValue("asset"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid asset ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		asset, data, err := h.ingest.Download(bookID, assetID)
+		if err != nil {
+			respondWithIngestError(w, r, err, "Failed to retrieve asset")
+			return
+		}
+		w.Header().Set("Content-Type", asset.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": asset.Filename}))
+		w.Header().Set("ETag", asset.ETag())
+		w.Header().Set("Cache-Control", "private, no-cache")
+		http.ServeContent(w, r, "", asset.CreatedAt, bytes.NewReader(data))
+	case http.MethodDelete:
+		if err := h.ingest.Delete(bookID, assetID); err != nil {
+			respondWithIngestError(w, r, err, "Failed to delete asset")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// respondWithIngestError maps ingest errors to HTTP responses, logging and
+// hiding unexpected ones
+func respondWithIngestError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch err {
+	case models.ErrBookNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Book not found")
+	case models.ErrAssetNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Asset not found")
+	case ebook.ErrInvalidEPUB, ebook.ErrInvalidPDF:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+	case ebook.ErrUnsupportedFormat:
+		respondWithError(w, r, http.StatusUnsupportedMediaType, err.Error())
+	case ingest.ErrTooLarge:
+		respondWithError(w, r, http.StatusRequestEntityTooLarge, err.Error())
+	case ingest.ErrIncompleteMetadata:
+		respondWithError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		logger.Error.Printf("%s: %v", message, err)
+		respondWithError(w, r, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/blob"
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/ingest"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// testPDF is a minimal PDF whose Info dictionary describes a book
+const testPDF = "%PDF-1.4\n1 0 obj\n<< /Title (Go in Practice) /Author (Matt Butcher; Matt Farina) >>\nendobj\n" +
+	"trailer\n<< /Info 1 0 R >>\n%%EOF\n"
+
+// newIngestHandler returns a handler with a small upload limit, its book
+// storage and the notifier it reports to
+func newIngestHandler() (*IngestHandler, *storage.MemoryStorage, *recordingNotifier) {
+	books := storage.NewMemoryStorage()
+	service := ingest.NewService(books, storage.NewMemoryAssetStorage(), blob.NewMemoryStore())
+	service.SetMaxBytes(64 << 10)
+	handler := NewIngestHandler(service)
+	notifier := &recordingNotifier{}
+	handler.SetNotifier(notifier)
+	return handler, books, notifier
+}
+
+func TestIngestHandler_HandleIngest(t *testing.T) {
+	handler, books, notifier := newIngestHandler()
+	other, _ := books.Create(models.Book{Title: "Other", Author: "Someone"})
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, _ := writer.CreateFormFile("file", "practice.pdf")
+	part.Write([]byte(testPDF))
+	writer.Close()
+
+	tests := []struct {
+		name           string
+		target         string
+		body           []byte
+		contentType    string
+		expectedStatus int
+	}{
+		{"create a book", "/books/ingest", []byte(testPDF), "application/pdf", http.StatusCreated},
+		{"same file again", "/books/ingest", []byte(testPDF), "application/pdf", http.StatusOK},
+		{"attach to a book as a form", "/books/ingest?book_id=" + strconv.Itoa(other.ID), form.Bytes(), writer.FormDataContentType(), http.StatusOK},
+		{"unknown book", "/books/ingest?book_id=999999999", []byte(testPDF), "application/pdf", http.StatusNotFound},
+		{"invalid book_id", "/books/ingest?book_id=abc", []byte(testPDF), "application/pdf", http.StatusBadRequest},
+		{"unsupported format", "/books/ingest", []byte("plain text"), "text/plain", http.StatusUnsupportedMediaType},
+		{"corrupt EPUB", "/books/ingest", []byte("PK\x03\x04 broken"), "application/epub+zip", http.StatusBadRequest},
+		{"no author", "/books/ingest", []byte("%PDF-1.4\n1 0 obj\n<< /Title (Notes) >>\nendobj\ntrailer\n<< /Info 1 0 R >>\n"), "application/pdf", http.StatusUnprocessableEntity},
+		{"nothing", "/books/ingest", nil, "application/pdf", http.StatusBadRequest},
+		{"form without a file", "/books/ingest", []byte("--x--\r\n"), "multipart/form-data; boundary=x", http.StatusBadRequest},
+		{"too large", "/books/ingest", make([]byte, 1<<20), "application/pdf", http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("Content-Disposition", `attachment; filename="practice.pdf"`)
+			w := httptest.NewRecorder()
+			handler.HandleIngest(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if len(notifier.types) != 1 || notifier.types[0] != events.BookCreated {
+		t.Errorf("expected one book.created notification, got %v", notifier.types)
+	}
+
+	w := httptest.NewRecorder()
+	handler.HandleIngest(w, httptest.NewRequest(http.MethodGet, "/books/ingest", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
+
+func TestIngestHandler_Assets(t *testing.T) {
+	handler, _, _ := newIngestHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/books/ingest", bytes.NewBufferString(testPDF))
+	req.Header.Set("Content-Type", "application/pdf")
+	req.Header.Set("Content-Disposition", `attachment; filename="practice.pdf"`)
+	w := httptest.NewRecorder()
+	handler.HandleIngest(w, req)
+
+	var result ingest.Result
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !result.Created || result.Book.Title != "Go in Practice" || result.Asset.Filename != "practice.pdf" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	id := strconv.Itoa(result.Book.ID)
+	assetID := strconv.Itoa(result.Asset.ID)
+	target := "/books/" + id + "/assets/" + assetID
+
+	newAssetRequest := func(method string) *http.Request {
+		req := httptest.NewRequest(method, target, nil)
+		req.SetPathValue("id", id)
+		req.SetPathValue("asset", assetID)
+		return req
+	}
+
+	list := newAssetRequest(http.MethodGet)
+	w = httptest.NewRecorder()
+	handler.HandleAssets(w, list)
+	var assets []models.Asset
+	json.NewDecoder(w.Body).Decode(&assets)
+	if w.Code != http.StatusOK || len(assets) != 1 || assets[0].URL != target {
+		t.Errorf("expected the one asset, got %d: %+v", w.Code, assets)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleAsset(w, newAssetRequest(http.MethodGet))
+	if w.Code != http.StatusOK || w.Body.String() != testPDF {
+		t.Errorf("expected the file, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename=practice.pdf` {
+		t.Errorf("unexpected Content-Disposition %q", got)
+	}
+	if w.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("expected application/pdf, got %q", w.Header().Get("Content-Type"))
+	}
+
+	conditional := newAssetRequest(http.MethodGet)
+	conditional.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	handler.HandleAsset(w, conditional)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected status %d, got %d", http.StatusNotModified, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleAsset(w, newAssetRequest(http.MethodDelete))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	w = httptest.NewRecorder()
+	handler.HandleAsset(w, newAssetRequest(http.MethodGet))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	unknown := httptest.NewRequest(http.MethodGet, "/books/999999999/assets", nil)
+	unknown.SetPathValue("id", "999999999")
+	w = httptest.NewRecorder()
+	handler.HandleAssets(w, unknown)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
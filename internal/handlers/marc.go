@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/marc"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+const (
+	// maxImportSize caps the size of an uploaded MARC file
+	maxImportSize = 10 << 20
+
+	contentTypeMARC    = "application/marc"
+	contentTypeMARCXML = "application/marcxml+xml"
+)
+
+// ImportResult summarizes a batch import
+type ImportResult struct {
+	Imported int           `json:"imported"`
+	Failed   int           `json:"failed"`
+	Books    []models.Book `json:"books"`
+	Errors   []ImportError `json:"errors,omitempty"`
+}
+
+// ImportError describes a record that could not be imported
+type ImportError struct {
+	Record int    `json:"record"`
+	Error  string `json:"error"`
+}
+
+// HandleImport handles requests to /books/import endpoint
+func (h *BookHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	format := marcFormat(r)
+	if format == "" {
+		respondWithError(w, r, http.StatusUnsupportedMediaType, "Unsupported import format")
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		respondWithError(w, r, http.StatusRequestEntityTooLarge, "Import file too large")
+		return
+	case err != nil:
+		respondWithError(w, r, http.StatusBadRequest, "Failed to read import file")
+		return
+	}
+	defer r.Body.Close()
+
+	var records []*marc.Record
+	if format == "marc" {
+		records, err = marc.ReadAll(bytes.NewReader(data))
+	} else {
+		records, err = marc.ReadXML(bytes.NewReader(data))
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := ImportResult{Books: make([]models.Book, 0, len(records))}
+	for i, rec := range records {
+		book := marc.ToBook(rec)
+		if err := book.Validate(); err != nil {
+			result.Errors = append(result.Errors, ImportError{Record: i + 1, Error: err.Error()})
+			continue
+		}
+
+		created, err := h.store(r).Create(book)
+		if err != nil {
+			logger.Error.Printf("Failed to import record %d: %v", i+1, err)
+			result.Errors = append(result.Errors, ImportError{Record: i + 1, Error: "Failed to create book"})
+			continue
+		}
+		h.notify(events.BookCreated, created.ID, created)
+		result.Books = append(result.Books, *created)
+	}
+	result.Imported = len(result.Books)
+	result.Failed = len(result.Errors)
+
+	code := http.StatusCreated
+	if result.Imported == 0 && result.Failed > 0 {
+		code = http.StatusUnprocessableEntity
+	}
+	respond(w, r, code, result)
+}
+
+// HandleExport handles requests to /books/export endpoint
+func (h *BookHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "marcxml"
+	}
+	if format != "marc" && format != "marcxml" {
+		respondWithError(w, r, http.StatusBadRequest, "Unsupported export format")
+		return
+	}
+
+	filters, err := h.bookFilters(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	books, err := h.filteredBooks(r, filters)
+	if err != nil {
+		logger.Error.Printf("Failed to get books: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve books")
+		return
+	}
+
+	records := make([]*marc.Record, 0, len(books))
+	for _, book := range books {
+		records = append(records, marc.FromBook(book))
+	}
+
+	var buf bytes.Buffer
+	contentType, filename := contentTypeMARCXML, "books.xml"
+	if format == "marc" {
+		contentType, filename = contentTypeMARC, "books.mrc"
+		err = marc.WriteAll(&buf, records)
+	} else {
+		err = marc.WriteXML(&buf, records)
+	}
+	if err != nil {
+		logger.Error.Printf("Failed to export books: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to export books")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// marcFormat determines the MARC serialization of a request body from the
+// format query parameter or the Content-Type header
+func marcFormat(r *http.Request) string {
+	switch r.URL.Query().Get("format") {
+	case "marc":
+		return "marc"
+	case "marcxml":
+		return "marcxml"
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case contentTypeMARC:
+		return "marc"
+	case contentTypeMARCXML, "application/xml", "text/xml":
+		return "marcxml"
+	}
+	return ""
+}
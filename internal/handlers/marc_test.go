@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/iotest"
+
+	"github.com/codeforgood-org/golang-book-api/internal/marc"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestBookHandler_HandleImport(t *testing.T) {
+	tests := []struct {
+		name           string
+		file           string
+		contentType    string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:           "marcxml",
+			file:           "../marc/testdata/sample.xml",
+			contentType:    "application/marcxml+xml",
+			expectedStatus: http.StatusCreated,
+			expectedCount:  2,
+		},
+		{
+			name:           "binary marc",
+			file:           "../marc/testdata/sample.mrc",
+			contentType:    "application/marc",
+			expectedStatus: http.StatusCreated,
+			expectedCount:  2,
+		},
+		{
+			name:           "unsupported content type",
+			file:           "../marc/testdata/sample.xml",
+			contentType:    "text/plain",
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemoryStorage()
+			handler := NewBookHandler(store)
+
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatalf("failed to read sample: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/books/import", bytes.NewReader(data))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			handler.HandleImport(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var result ImportResult
+			if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if result.Imported != tt.expectedCount {
+				t.Errorf("expected %d imported, got %d", tt.expectedCount, result.Imported)
+			}
+
+			books, _ := store.GetAll()
+			if len(books) != tt.expectedCount {
+				t.Errorf("expected %d stored books, got %d", tt.expectedCount, len(books))
+			}
+		})
+	}
+}
+
+func TestBookHandler_HandleImport_ReadErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+	}{
+		{"too large", bytes.NewReader(make([]byte, maxImportSize+1)), http.StatusRequestEntityTooLarge},
+		{"broken body", iotest.ErrReader(io.ErrUnexpectedEOF), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewBookHandler(storage.NewMemoryStorage())
+			req := httptest.NewRequest(http.MethodPost, "/books/import", tt.body)
+			req.Header.Set("Content-Type", "application/marc")
+			w := httptest.NewRecorder()
+
+			handler.HandleImport(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestBookHandler_HandleExport(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+
+	store.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin", ISBN: "9780132350884"})
+	store.Create(models.Book{Title: "Refactoring", Author: "Martin Fowler"})
+
+	req := httptest.NewRequest(http.MethodGet, "/books/export?format=marc&title=clean", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleExport(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/marc" {
+		t.Errorf("expected Content-Type application/marc, got %s", ct)
+	}
+
+	records, err := marc.ReadAll(w.Body)
+	if err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if book := marc.ToBook(records[0]); book.Title != "Clean Code" || book.ISBN != "9780132350884" {
+		t.Errorf("unexpected exported book: %+v", book)
+	}
+}
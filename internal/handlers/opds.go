@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/codeforgood-org/golang-book-api/internal/codec"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/opds"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// OPDSHandler handles OPDS catalog requests from e-reader apps
+type OPDSHandler struct {
+	catalog *opds.Catalog
+}
+
+// NewOPDSHandler creates a new OPDS handler
+func NewOPDSHandler(catalog *opds.Catalog) *OPDSHandler {
+	return &OPDSHandler{
+		catalog: catalog,
+	}
+}
+
+// HandleStart handles requests to /opds endpoint, the navigation feed apps
+// start from. Every OPDS feed is written as OPDS 1.2 Atom or, if the format
+// parameter is json or the Accept header prefers application/opds+json, as
+// OPDS 2.0 JSON
+func (h *OPDSHandler) HandleStart(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func() (*opds.Feed, error) {
+		return h.catalog.Start()
+	})
+}
+
+// HandleNew handles requests to /opds/new endpoint, the books most recently
+// added first
+func (h *OPDSHandler) HandleNew(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func() (*opds.Feed, error) {
+		return h.catalog.New(models.ParsePaginationParams(r))
+	})
+}
+
+// HandleAll handles requests to /opds/books endpoint, every book by title
+func (h *OPDSHandler) HandleAll(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func() (*opds.Feed, error) {
+		return h.catalog.All(models.ParsePaginationParams(r))
+	})
+}
+
+// HandleAuthors handles requests to /opds/authors endpoint
+func (h *OPDSHandler) HandleAuthors(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, h.catalog.Authors)
+}
+
+// HandleAuthor handles requests to /opds/authors/{author} endpoint
+func (h *OPDSHandler) HandleAuthor(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func() (*opds.Feed, error) {
+		return h.catalog.Author(r.PathValue("author"), models.ParsePaginationParams(r))
+	})
+}
+
+// HandleGenres handles requests to /opds/genres endpoint
+func (h *OPDSHandler) HandleGenres(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, h.catalog.Genres)
+}
+
+// HandleGenre handles requests to /opds/genres/{genre} endpoint
+func (h *OPDSHandler) HandleGenre(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func() (*opds.Feed, error) {
+		return h.catalog.Genre(r.PathValue("genre"), models.ParsePaginationParams(r))
+	})
+}
+
+// HandleSearch handles requests to /opds/search endpoint. The search terms
+// are the q parameter, or query as OPDS 2.0 names it
+func (h *OPDSHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		query = r.URL.Query().Get("query")
+	}
+	h.serve(w, r, func() (*opds.Feed, error) {
+		return h.catalog.Search(query, models.ParsePaginationParams(r))
+	})
+}
+
+// HandleOpenSearch handles requests to /opds/opensearch.xml endpoint, the
+// OpenSearch description of /opds/search
+func (h *OPDSHandler) HandleOpenSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := opds.WriteOpenSearch(&buf, h.catalog.Title()); err != nil {
+		respondWithOPDSError(w, r, err, "Failed to write OpenSearch description")
+		return
+	}
+	w.Header().Set("Content-Type", opds.OpenSearchType+";charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// serve builds a feed and writes it in the OPDS version the request asks for
+func (h *OPDSHandler) serve(w http.ResponseWriter, r *http.Request, build func() (*opds.Feed, error)) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	asJSON, ok := wantsOPDSJSON(r)
+	if !ok {
+		respondWithError(w, r, http.StatusBadRequest, "format must be atom or json")
+		return
+	}
+
+	feed, err := build()
+	if err != nil {
+		respondWithOPDSError(w, r, err, "Failed to build feed")
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := feed.Kind.AtomType() + ";charset=utf-8"
+	if asJSON {
+		contentType = opds.JSONType
+		err = opds.WriteJSON(&buf, h.catalog.Title(), feed)
+	} else {
+		err = opds.WriteAtom(&buf, h.catalog.Title(), feed)
+	}
+	if err != nil {
+		respondWithOPDSError(w, r, err, "Failed to write feed")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Vary", "Accept")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// wantsOPDSJSON reports whether a request asks for OPDS 2.0 rather than
+// OPDS 1.2, by the format parameter or else by the first media range of the
+// Accept header that names either. ok is false for an unknown format
+func wantsOPDSJSON(r *http.Request) (asJSON, ok bool) {
+	switch r.URL.Query().Get("format") {
+	case "json":
+		return true, true
+	case "atom":
+		return false, true
+	case "":
+	default:
+		return false, false
+	}
+
+	for _, mr := range codec.ParseAccept(r.Header.Get("Accept")) {
+		switch {
+		case mr.Q == 0:
+		case mr.Type == "application" && (mr.Subtype == "opds+json" || mr.Subtype == "json"):
+			return true, true
+		case mr.Type == "application" && mr.Subtype == "atom+xml":
+			return false, true
+		}
+	}
+	return false, true
+}
+
+// respondWithOPDSError maps OPDS errors to HTTP responses, logging and
+// hiding unexpected ones
+func respondWithOPDSError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch err {
+	case opds.ErrNotFound:
+		respondWithError(w, r, http.StatusNotFound, err.Error())
+	case opds.ErrEmptyQuery:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+	default:
+		logger.Error.Printf("%s: %v", message, err)
+		respondWithError(w, r, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/opds"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newOPDSHandler returns a handler over a catalog of two books
+func newOPDSHandler() *OPDSHandler {
+	books := storage.NewMemoryStorage()
+	books.Create(models.Book{Title: "Dune", Author: "Frank Herbert", Genres: []string{"science-fiction"}})
+	books.Create(models.Book{Title: "Emma", Author: "Jane Austen", Genres: []string{"romance"}})
+	return NewOPDSHandler(opds.NewCatalog(books, storage.NewMemoryAssetStorage()))
+}
+
+func TestOPDSHandler_Feeds(t *testing.T) {
+	handler := newOPDSHandler()
+
+	tests := []struct {
+		name                string
+		method              string
+		target              string
+		accept              string
+		pathValues          map[string]string
+		serve               http.HandlerFunc
+		expectedStatus      int
+		expectedContentType string
+	}{
+		{"start", http.MethodGet, "/opds", "", nil, handler.HandleStart, http.StatusOK, opds.AtomNavigationType + ";charset=utf-8"},
+		{"start as json", http.MethodGet, "/opds?format=json", "", nil, handler.HandleStart, http.StatusOK, opds.JSONType},
+		{"json by Accept", http.MethodGet, "/opds/new", "application/opds+json", nil, handler.HandleNew, http.StatusOK, opds.JSONType},
+		{"atom preferred by Accept", http.MethodGet, "/opds/books", "application/atom+xml, application/json;q=0.5", nil, handler.HandleAll, http.StatusOK, opds.AtomAcquisitionType + ";charset=utf-8"},
+		{"format beats Accept", http.MethodGet, "/opds/books?format=atom", "application/opds+json", nil, handler.HandleAll, http.StatusOK, opds.AtomAcquisitionType + ";charset=utf-8"},
+		{"head", http.MethodHead, "/opds/authors", "", nil, handler.HandleAuthors, http.StatusOK, opds.AtomNavigationType + ";charset=utf-8"},
+		{"author", http.MethodGet, "/opds/authors/Jane%20Austen", "", map[string]string{"author": "Jane Austen"}, handler.HandleAuthor, http.StatusOK, opds.AtomAcquisitionType + ";charset=utf-8"},
+		{"unknown author", http.MethodGet, "/opds/authors/Nobody", "", map[string]string{"author": "Nobody"}, handler.HandleAuthor, http.StatusNotFound, "application/json"},
+		{"genres", http.MethodGet, "/opds/genres", "", nil, handler.HandleGenres, http.StatusOK, opds.AtomNavigationType + ";charset=utf-8"},
+		{"genre", http.MethodGet, "/opds/genres/romance", "", map[string]string{"genre": "romance"}, handler.HandleGenre, http.StatusOK, opds.AtomAcquisitionType + ";charset=utf-8"},
+		{"empty genre", http.MethodGet, "/opds/genres/horror", "", map[string]string{"genre": "horror"}, handler.HandleGenre, http.StatusNotFound, "application/json"},
+		{"search", http.MethodGet, "/opds/search?q=dune", "", nil, handler.HandleSearch, http.StatusOK, opds.AtomAcquisitionType + ";charset=utf-8"},
+		{"search by query", http.MethodGet, "/opds/search?query=dune&format=json", "", nil, handler.HandleSearch, http.StatusOK, opds.JSONType},
+		{"empty search", http.MethodGet, "/opds/search", "", nil, handler.HandleSearch, http.StatusBadRequest, "application/json"},
+		{"unknown format", http.MethodGet, "/opds?format=rss", "", nil, handler.HandleStart, http.StatusBadRequest, "application/json"},
+		{"post", http.MethodPost, "/opds", "", nil, handler.HandleStart, http.StatusMethodNotAllowed, "application/json"},
+		{"opensearch", http.MethodGet, "/opds/opensearch.xml", "", nil, handler.HandleOpenSearch, http.StatusOK, opds.OpenSearchType + ";charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			for name, value := range tt.pathValues {
+				req.SetPathValue(name, value)
+			}
+			w := httptest.NewRecorder()
+
+			tt.serve(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.expectedContentType) {
+				t.Errorf("expected content type %s, got %s", tt.expectedContentType, got)
+			}
+		})
+	}
+}
+
+func TestOPDSHandler_HandleSearch(t *testing.T) {
+	handler := newOPDSHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/opds/search?q=austen", nil)
+	w := httptest.NewRecorder()
+	handler.HandleSearch(w, req)
+
+	body := w.Body.String()
+	if !strings.Contains(body, "<title>Emma</title>") || strings.Contains(body, "<title>Dune</title>") {
+		t.Errorf("expected only Emma, got:\n%s", body)
+	}
+	if w.Header().Get("Vary") != "Accept" {
+		t.Errorf("expected Vary: Accept, got %q", w.Header().Get("Vary"))
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// PatronHandler handles patron-related HTTP requests
+type PatronHandler struct {
+	patrons     storage.PatronStorage
+	circulation *circulation.Service
+}
+
+// NewPatronHandler creates a new patron handler
+func NewPatronHandler(patrons storage.PatronStorage, circulation *circulation.Service) *PatronHandler {
+	return &PatronHandler{
+		patrons:     patrons,
+		circulation: circulation,
+	}
+}
+
+// HandlePatrons handles requests to /patrons endpoint
+func (h *PatronHandler) HandlePatrons(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getPatrons(w, r)
+	case http.MethodPost:
+		h.createPatron(w, r)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandlePatronByID handles requests to /patrons/{id} endpoint
+func (h *PatronHandler) HandlePatronByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid patron ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		patron, err := h.patrons.GetPatron(id)
+		if err != nil {
+			respondWithCirculationError(w, r, err, "Failed to retrieve patron")
+			return
+		}
+		respond(w, r, http.StatusOK, patron)
+	case http.MethodPut, http.MethodPatch:
+		h.updatePatron(w, r, id)
+	case http.MethodDelete:
+		if err := h.circulation.DeletePatron(id); err != nil {
+			respondWithCirculationError(w, r, err, "Failed to delete patron")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getPatrons returns a page of patrons
+func (h *PatronHandler) getPatrons(w http.ResponseWriter, r *http.Request) {
+	patrons, err := h.patrons.GetAllPatrons()
+	if err != nil {
+		logger.Error.Printf("Failed to get patrons: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve patrons")
+		return
+	}
+
+	params := models.ParsePaginationParams(r)
+	start, end := params.Bounds(len(patrons))
+	respond(w, r, http.StatusOK, models.NewPaginatedResponse(patrons[start:end], params.Page, params.PageSize, len(patrons)))
+}
+
+// createPatron registers a new patron
+func (h *PatronHandler) createPatron(w http.ResponseWriter, r *http.Request) {
+	var patron models.Patron
+	if !decodeRequest(w, r, &patron) {
+		return
+	}
+	if err := patron.Validate(); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	patron.CreatedAt = time.Now().UTC()
+	created, err := h.patrons.CreatePatron(patron)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to create patron")
+		return
+	}
+	respond(w, r, http.StatusCreated, created)
+}
+
+// updatePatron updates a patron by ID
+func (h *PatronHandler) updatePatron(w http.ResponseWriter, r *http.Request, id int) {
+	var patron models.Patron
+	if !decodeRequest(w, r, &patron) {
+		return
+	}
+	if err := patron.Validate(); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := h.patrons.UpdatePatron(id, patron)
+	if err != nil {
+		respondWithCirculationError(w, r, err, "Failed to update patron")
+		return
+	}
+	respond(w, r, http.StatusOK, updated)
+}
+
+// respondWithCirculationError maps patron and circulation errors to responses,
+// logging and hiding anything unexpected behind message
+func respondWithCirculationError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch err {
+	case models.ErrPatronNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Patron not found")
+	case models.ErrBookNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Book not found")
+	case models.ErrLoanNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Loan not found")
+	case models.ErrCopyNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Copy not found")
+	case models.ErrHoldNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Hold not found")
+	case models.ErrFineNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Fine not found")
+	case models.ErrInvalidBarcode, models.ErrInvalidBranch, models.ErrInvalidCondition, models.ErrInvalidCopyStatus,
+		circulation.ErrCopyRequired, circulation.ErrInvalidAmount:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+	case circulation.ErrBookUnavailable, circulation.ErrCopyUnavailable, circulation.ErrNotOnLoan,
+		circulation.ErrLoanReturned, circulation.ErrLoanLimitReached, circulation.ErrRenewalLimitReached,
+		circulation.ErrPatronHasLoans, circulation.ErrCopyOnLoan, circulation.ErrCopyOnHold, models.ErrDuplicateBarcode,
+		circulation.ErrDuplicateHold, circulation.ErrAlreadyBorrowed, circulation.ErrHoldClosed,
+		circulation.ErrPatronHasFines, circulation.ErrAmountExceedsBalance:
+		respondWithError(w, r, http.StatusConflict, err.Error())
+	default:
+		logger.Error.Printf("%s: %v", message, err)
+		respondWithError(w, r, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/circulation"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestPatronHandler(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	service := circulation.NewService(books, storage.NewMemoryCopyStorage(), patrons, storage.NewMemoryLoanStorage(), storage.NewMemoryHoldStorage(), storage.NewMemoryFineStorage(), circulation.DefaultPolicies())
+	handler := NewPatronHandler(patrons, service)
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	service.AddCopy(book.ID, models.Copy{Barcode: "CC-1", Branch: "Main"})
+
+	tests := []struct {
+		name           string
+		method         string
+		id             string
+		body           string
+		setup          func()
+		expectedStatus int
+	}{
+		{name: "create", method: http.MethodPost, body: `{"name": "Ada", "email": "ada@example.com"}`, expectedStatus: http.StatusCreated},
+		{name: "create invalid", method: http.MethodPost, body: `{"name": "Ada", "type": "robot"}`, expectedStatus: http.StatusBadRequest},
+		{name: "list", method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "get", method: http.MethodGet, id: "1", expectedStatus: http.StatusOK},
+		{name: "get unknown", method: http.MethodGet, id: "999", expectedStatus: http.StatusNotFound},
+		{name: "get invalid id", method: http.MethodGet, id: "abc", expectedStatus: http.StatusBadRequest},
+		{name: "update", method: http.MethodPut, id: "1", body: `{"name": "Ada Lovelace", "type": "staff"}`, expectedStatus: http.StatusOK},
+		{
+			name:   "delete with loans",
+			method: http.MethodDelete,
+			id:     "1",
+			setup: func() {
+				service.Checkout(book.ID, 0, 1)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			id:     "1",
+			setup: func() {
+				service.Return(book.ID, 0)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+			req := httptest.NewRequest(tt.method, "/patrons/"+tt.id, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			if tt.id == "" {
+				handler.HandlePatrons(w, req)
+			} else {
+				req.SetPathValue("id", tt.id)
+				handler.HandlePatronByID(w, req)
+			}
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
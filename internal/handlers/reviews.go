@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/reviews"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// ReviewHandler handles book review and moderation HTTP requests
+type ReviewHandler struct {
+	reviews *reviews.Service
+}
+
+// NewReviewHandler creates a new review handler
+func NewReviewHandler(reviews *reviews.Service) *ReviewHandler {
+	return &ReviewHandler{
+		reviews: reviews,
+	}
+}
+
+// ModerationRequest is the body accepted when moderating a review
+type ModerationRequest struct {
+	Status models.ReviewStatus `json:"status"`
+	Note   string              `json:"note"`
+}
+
+// HandleReviews handles requests to /books/{id}/reviews endpoint. GET lists
+// the approved reviews, newest first; POST submits a review for moderation
+func (h *ReviewHandler) HandleReviews(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		list, err := h.reviews.BookReviews(bookID)
+		if err != nil {
+			respondWithReviewError(w, r, err, "Failed to retrieve reviews")
+			return
+		}
+		respondWithReviewPage(w, r, list)
+	case http.MethodPost:
+		var review models.Review
+		if !decodeRequest(w, r, &review) {
+			return
+		}
+		if review.PatronID <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, "patron_id is required")
+			return
+		}
+		created, err := h.reviews.Submit(bookID, review)
+		if err != nil {
+			respondWithReviewError(w, r, err, "Failed to submit review")
+			return
+		}
+		respond(w, r, http.StatusCreated, created)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleReview handles requests to /books/{id}/reviews/{review} endpoint
+func (h *ReviewHandler) HandleReview(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+	reviewID, err := strconv.Atoi(r.PathValue("review"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid review ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		review, err := h.reviews.Review(bookID, reviewID)
+		if err != nil {
+			respondWithReviewError(w, r, err, "Failed to retrieve review")
+			return
+		}
+		respond(w, r, http.StatusOK, review)
+	case http.MethodPut, http.MethodPatch:
+		var review models.Review
+		if !decodeRequest(w, r, &review) {
+			return
+		}
+		updated, err := h.reviews.Update(bookID, reviewID, review)
+		if err != nil {
+			respondWithReviewError(w, r, err, "Failed to update review")
+			return
+		}
+		respond(w, r, http.StatusOK, updated)
+	case http.MethodDelete:
+		if err := h.reviews.Delete(bookID, reviewID); err != nil {
+			respondWithReviewError(w, r, err, "Failed to delete review")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleModerationQueue handles requests to /admin/reviews endpoint, listing
+// reviews oldest first with the status given by the status parameter:
+// pending by default, or all for every review
+func (h *ReviewHandler) HandleModerationQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	status := models.ReviewStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "":
+		status = models.ReviewPending
+	case "all":
+		status = ""
+	}
+
+	list, err := h.reviews.Reviews(status)
+	if err != nil {
+		respondWithReviewError(w, r, err, "Failed to retrieve reviews")
+		return
+	}
+	respondWithReviewPage(w, r, list)
+}
+
+// HandleModerate handles requests to /admin/reviews/{id}/moderate endpoint.
+// The moderator is the actor the request is attributed to
+func (h *ReviewHandler) HandleModerate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid review ID")
+		return
+	}
+	var req ModerationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	moderator := audit.FromContext(r.Context()).Actor
+	review, err := h.reviews.Moderate(id, req.Status, moderator, req.Note)
+	if err != nil {
+		respondWithReviewError(w, r, err, "Failed to moderate review")
+		return
+	}
+	respond(w, r, http.StatusOK, review)
+}
+
+// respondWithReviewPage writes the page of reviews asked for by the page and
+// page_size parameters
+func respondWithReviewPage(w http.ResponseWriter, r *http.Request, list []models.Review) {
+	params := models.ParsePaginationParams(r)
+	start, end := params.Bounds(len(list))
+	respond(w, r, http.StatusOK, models.NewPaginatedResponse(list[start:end], params.Page, params.PageSize, len(list)))
+}
+
+// respondWithReviewError maps review errors to HTTP responses, logging and
+// hiding unexpected ones
+func respondWithReviewError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch err {
+	case models.ErrBookNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Book not found")
+	case models.ErrPatronNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Patron not found")
+	case models.ErrReviewNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Review not found")
+	case models.ErrInvalidRating, models.ErrReviewTooLong, models.ErrInvalidReviewStatus:
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+	case models.ErrDuplicateReview:
+		respondWithError(w, r, http.StatusConflict, err.Error())
+	default:
+		logger.Error.Printf("%s: %v", message, err)
+		respondWithError(w, r, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/reviews"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestReviewHandler(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	handler := NewReviewHandler(reviews.NewService(books, patrons, storage.NewMemoryReviewStorage()))
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	patron, _ := patrons.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	bookID := strconv.Itoa(book.ID)
+	submit := `{"patron_id": ` + strconv.Itoa(patron.ID) + `, "rating": 4, "text": "Worth reading"}`
+
+	reviewRequest := func(method, id, review, body string) *http.Request {
+		req := newCirculationRequest(method, "/books/"+id+"/reviews/"+review, id, body)
+		req.SetPathValue("review", review)
+		return req
+	}
+	moderate := newCirculationRequest(http.MethodPost, "/admin/reviews/1/moderate", "1", `{"status": "approved"}`)
+	moderate = moderate.WithContext(audit.NewContext(moderate.Context(), audit.Source{Actor: "librarian"}))
+
+	tests := []struct {
+		name           string
+		handle         http.HandlerFunc
+		req            *http.Request
+		expectedStatus int
+	}{
+		{"submit", handler.HandleReviews, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/reviews", bookID, submit), http.StatusCreated},
+		{"submit again", handler.HandleReviews, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/reviews", bookID, submit), http.StatusConflict},
+		{"submit missing patron", handler.HandleReviews, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/reviews", bookID, `{"rating": 4}`), http.StatusBadRequest},
+		{"submit invalid rating", handler.HandleReviews, newCirculationRequest(http.MethodPost, "/books/"+bookID+"/reviews", bookID, `{"patron_id": 1, "rating": 6}`), http.StatusBadRequest},
+		{"submit unknown book", handler.HandleReviews, newCirculationRequest(http.MethodPost, "/books/999/reviews", "999", submit), http.StatusNotFound},
+		{"review", handler.HandleReview, reviewRequest(http.MethodGet, bookID, "1", ""), http.StatusOK},
+		{"review of another book", handler.HandleReview, reviewRequest(http.MethodGet, "999", "1", ""), http.StatusNotFound},
+		{"moderation queue", handler.HandleModerationQueue, newCirculationRequest(http.MethodGet, "/admin/reviews", "", ""), http.StatusOK},
+		{"moderation queue invalid status", handler.HandleModerationQueue, newCirculationRequest(http.MethodGet, "/admin/reviews?status=spam", "", ""), http.StatusBadRequest},
+		{"moderate invalid status", handler.HandleModerate, newCirculationRequest(http.MethodPost, "/admin/reviews/1/moderate", "1", `{"status": "pending"}`), http.StatusBadRequest},
+		{"moderate", handler.HandleModerate, moderate, http.StatusOK},
+		{"moderate unknown review", handler.HandleModerate, newCirculationRequest(http.MethodPost, "/admin/reviews/999/moderate", "999", `{"status": "approved"}`), http.StatusNotFound},
+		{"reviews", handler.HandleReviews, newCirculationRequest(http.MethodGet, "/books/"+bookID+"/reviews", bookID, ""), http.StatusOK},
+		{"update", handler.HandleReview, reviewRequest(http.MethodPut, bookID, "1", `{"rating": 2}`), http.StatusOK},
+		{"delete", handler.HandleReview, reviewRequest(http.MethodDelete, bookID, "1", ""), http.StatusNoContent},
+		{"delete again", handler.HandleReview, reviewRequest(http.MethodDelete, bookID, "1", ""), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handle(w, tt.req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestReviewHandler_ModerationUpdatesRating(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	handler := NewReviewHandler(reviews.NewService(books, patrons, storage.NewMemoryReviewStorage()))
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	patron, _ := patrons.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	bookID := strconv.Itoa(book.ID)
+
+	handler.HandleReviews(httptest.NewRecorder(), newCirculationRequest(http.MethodPost, "/books/"+bookID+"/reviews", bookID, `{"patron_id": `+strconv.Itoa(patron.ID)+`, "rating": 5}`))
+	w := httptest.NewRecorder()
+	handler.HandleModerate(w, newCirculationRequest(http.MethodPost, "/admin/reviews/1/moderate", "1", `{"status": "approved", "note": "ok"}`))
+
+	var review models.Review
+	json.NewDecoder(w.Body).Decode(&review)
+	if review.Status != models.ReviewApproved || review.ModeratedBy != audit.Anonymous || review.ModerationNote != "ok" {
+		t.Errorf("expected an approved review, got %+v", review)
+	}
+	if rated, _ := books.GetByID(book.ID); rated.AverageRating != 5 || rated.RatingCount != 1 {
+		t.Errorf("expected rating 5 from 1 review, got %v from %d", rated.AverageRating, rated.RatingCount)
+	}
+}
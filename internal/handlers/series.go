@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// SeriesBookRequest is the body accepted when adding a book to a series. A
+// position of 0 or none adds the book at the end
+type SeriesBookRequest struct {
+	BookID   int `json:"book_id"`
+	Position int `json:"position"`
+}
+
+// SeriesOrderRequest is the body accepted when reordering a series
+type SeriesOrderRequest struct {
+	BookIDs []int `json:"book_ids"`
+}
+
+// HandleSeries handles requests to /series endpoint
+func (h *CatalogHandler) HandleSeries(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		list, err := h.catalog.AllSeries()
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to retrieve series")
+			return
+		}
+		params := models.ParsePaginationParams(r)
+		start, end := params.Bounds(len(list))
+		respond(w, r, http.StatusOK, models.NewPaginatedResponse(list[start:end], params.Page, params.PageSize, len(list)))
+	case http.MethodPost:
+		var series models.Series
+		if !decodeRequest(w, r, &series) {
+			return
+		}
+		created, err := h.catalog.CreateSeries(series)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to create series")
+			return
+		}
+		respond(w, r, http.StatusCreated, created)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleSeriesByID handles requests to /series/{id} endpoint. Updates change
+// the title and description; the books are managed through
+// /series/{id}/books
+func (h *CatalogHandler) HandleSeriesByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid series ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		series, err := h.catalog.Series(id)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to retrieve series")
+			return
+		}
+		respond(w, r, http.StatusOK, series)
+	case http.MethodPut, http.MethodPatch:
+		var series models.Series
+		if !decodeRequest(w, r, &series) {
+			return
+		}
+		updated, err := h.catalog.UpdateSeries(id, series)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to update series")
+			return
+		}
+		respond(w, r, http.StatusOK, updated)
+	case http.MethodDelete:
+		if err := h.catalog.DeleteSeries(id); err != nil {
+			respondWithCatalogError(w, r, err, "Failed to delete series")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleSeriesBooks handles requests to /series/{id}/books endpoint. GET
+// lists the books in order with their positions, POST adds a book and PUT
+// reorders the books already in the series
+func (h *CatalogHandler) HandleSeriesBooks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid series ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		entries, err := h.catalog.SeriesBooks(id)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to retrieve series books")
+			return
+		}
+		params := models.ParsePaginationParams(r)
+		start, end := params.Bounds(len(entries))
+		respond(w, r, http.StatusOK, models.NewPaginatedResponse(entries[start:end], params.Page, params.PageSize, len(entries)))
+	case http.MethodPost:
+		var req SeriesBookRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if req.BookID <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, "book_id is required")
+			return
+		}
+		series, err := h.catalog.AddToSeries(id, req.BookID, req.Position)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to add book to series")
+			return
+		}
+		respond(w, r, http.StatusOK, series)
+	case http.MethodPut:
+		var req SeriesOrderRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		series, err := h.catalog.ReorderSeries(id, req.BookIDs)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to reorder series")
+			return
+		}
+		respond(w, r, http.StatusOK, series)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleSeriesBook handles requests to /series/{id}/books/{book} endpoint,
+// removing a book from a series
+func (h *CatalogHandler) HandleSeriesBook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid series ID")
+		return
+	}
+	bookID, err := strconv.Atoi(r.PathValue("book"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	if _, err := h.catalog.RemoveFromSeries(id, bookID); err != nil {
+		respondWithCatalogError(w, r, err, "Failed to remove book from series")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/catalog"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestCatalogHandler_Series(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	handler := NewCatalogHandler(catalog.NewService(books, storage.NewMemorySeriesStorage(), storage.NewMemoryWorkStorage()))
+
+	first, _ := books.Create(models.Book{Title: "A Wizard of Earthsea", Author: "Ursula K. Le Guin"})
+	second, _ := books.Create(models.Book{Title: "The Tombs of Atuan", Author: "Ursula K. Le Guin"})
+	third, _ := books.Create(models.Book{Title: "The Farthest Shore", Author: "Ursula K. Le Guin"})
+	firstID, secondID, thirdID := strconv.Itoa(first.ID), strconv.Itoa(second.ID), strconv.Itoa(third.ID)
+
+	seriesBookRequest := func(book string) *http.Request {
+		req := newCirculationRequest(http.MethodDelete, "/series/1/books/"+book, "1", "")
+		req.SetPathValue("book", book)
+		return req
+	}
+
+	tests := []struct {
+		name           string
+		handle         http.HandlerFunc
+		req            *http.Request
+		expectedStatus int
+	}{
+		{"create", handler.HandleSeries, newCirculationRequest(http.MethodPost, "/series", "", `{"title": "Earthsea"}`), http.StatusCreated},
+		{"create without title", handler.HandleSeries, newCirculationRequest(http.MethodPost, "/series", "", `{"description": "Untitled"}`), http.StatusBadRequest},
+		{"list", handler.HandleSeries, newCirculationRequest(http.MethodGet, "/series", "", ""), http.StatusOK},
+		{"get", handler.HandleSeriesByID, newCirculationRequest(http.MethodGet, "/series/1", "1", ""), http.StatusOK},
+		{"get unknown", handler.HandleSeriesByID, newCirculationRequest(http.MethodGet, "/series/999", "999", ""), http.StatusNotFound},
+		{"get invalid ID", handler.HandleSeriesByID, newCirculationRequest(http.MethodGet, "/series/abc", "abc", ""), http.StatusBadRequest},
+		{"update", handler.HandleSeriesByID, newCirculationRequest(http.MethodPut, "/series/1", "1", `{"title": "The Earthsea Cycle"}`), http.StatusOK},
+		{"add", handler.HandleSeriesBooks, newCirculationRequest(http.MethodPost, "/series/1/books", "1", `{"book_id": `+secondID+`}`), http.StatusOK},
+		{"add at the front", handler.HandleSeriesBooks, newCirculationRequest(http.MethodPost, "/series/1/books", "1", `{"book_id": `+firstID+`, "position": 1}`), http.StatusOK},
+		{"add twice", handler.HandleSeriesBooks, newCirculationRequest(http.MethodPost, "/series/1/books", "1", `{"book_id": `+firstID+`}`), http.StatusConflict},
+		{"add without book", handler.HandleSeriesBooks, newCirculationRequest(http.MethodPost, "/series/1/books", "1", `{}`), http.StatusBadRequest},
+		{"add past the end", handler.HandleSeriesBooks, newCirculationRequest(http.MethodPost, "/series/1/books", "1", `{"book_id": `+thirdID+`, "position": 4}`), http.StatusBadRequest},
+		{"add unknown book", handler.HandleSeriesBooks, newCirculationRequest(http.MethodPost, "/series/1/books", "1", `{"book_id": 999}`), http.StatusNotFound},
+		{"reorder with a missing book", handler.HandleSeriesBooks, newCirculationRequest(http.MethodPut, "/series/1/books", "1", `{"book_ids": [`+firstID+`]}`), http.StatusBadRequest},
+		{"reorder", handler.HandleSeriesBooks, newCirculationRequest(http.MethodPut, "/series/1/books", "1", `{"book_ids": [`+secondID+`, `+firstID+`]}`), http.StatusOK},
+		{"books", handler.HandleSeriesBooks, newCirculationRequest(http.MethodGet, "/series/1/books", "1", ""), http.StatusOK},
+		{"remove", handler.HandleSeriesBook, seriesBookRequest(secondID), http.StatusNoContent},
+		{"remove again", handler.HandleSeriesBook, seriesBookRequest(secondID), http.StatusNotFound},
+		{"delete", handler.HandleSeriesByID, newCirculationRequest(http.MethodDelete, "/series/1", "1", ""), http.StatusNoContent},
+		{"delete again", handler.HandleSeriesByID, newCirculationRequest(http.MethodDelete, "/series/1", "1", ""), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handle(w, tt.req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCatalogHandler_SeriesBooksInOrder(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	handler := NewCatalogHandler(catalog.NewService(books, storage.NewMemorySeriesStorage(), storage.NewMemoryWorkStorage()))
+
+	first, _ := books.Create(models.Book{Title: "A Wizard of Earthsea", Author: "Ursula K. Le Guin"})
+	second, _ := books.Create(models.Book{Title: "The Tombs of Atuan", Author: "Ursula K. Le Guin"})
+	handler.HandleSeries(httptest.NewRecorder(), newCirculationRequest(http.MethodPost, "/series", "", `{"title": "Earthsea"}`))
+	for _, body := range []string{`{"book_id": ` + strconv.Itoa(second.ID) + `}`, `{"book_id": ` + strconv.Itoa(first.ID) + `, "position": 1}`} {
+		handler.HandleSeriesBooks(httptest.NewRecorder(), newCirculationRequest(http.MethodPost, "/series/1/books", "1", body))
+	}
+
+	w := httptest.NewRecorder()
+	handler.HandleSeriesBooks(w, newCirculationRequest(http.MethodGet, "/series/1/books", "1", ""))
+
+	var response struct {
+		Data []models.SeriesEntry `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Data) != 2 || response.Data[0].Title != first.Title || response.Data[0].Position != 1 || response.Data[1].Title != second.Title || response.Data[1].Position != 2 {
+		t.Errorf("expected %q then %q, got %+v", first.Title, second.Title, response.Data)
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// HandleTrash handles requests to /trash endpoint
+func (h *BookHandler) HandleTrash(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	trash, ok := h.storage.(storage.Trash)
+	if !ok {
+		respondWithError(w, r, http.StatusNotImplemented, "Trash is not supported by this storage")
+		return
+	}
+
+	books, err := trash.Trashed()
+	if err != nil {
+		logger.Error.Printf("Failed to get trash: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve trash")
+		return
+	}
+
+	params := models.ParsePaginationParams(r)
+	start, end := params.Bounds(len(books))
+	respond(w, r, http.StatusOK, models.NewPaginatedResponse(books[start:end], params.Page, params.PageSize, len(books)))
+}
+
+// HandleRestore handles requests to /books/{id}/restore endpoint, moving a
+// deleted book out of the trash
+func (h *BookHandler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, err := parseBookID(r.URL.Path)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	trash, ok := h.store(r).(storage.Trash)
+	if !ok {
+		respondWithError(w, r, http.StatusNotImplemented, "Trash is not supported by this storage")
+		return
+	}
+
+	book, err := trash.Restore(id)
+	if err != nil {
+		if err == models.ErrBookNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Book not found in trash")
+			return
+		}
+		if err == models.ErrBookExists {
+			respondWithError(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		logger.Error.Printf("Failed to restore book: %v", err)
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to restore book")
+		return
+	}
+	h.notify(events.BookRestored, id, book)
+
+	respond(w, r, http.StatusOK, book)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestBookHandler_TrashAndRestore(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+
+	kept, _ := store.Create(models.Book{Title: "Kept", Author: "Author"})
+	deleted, _ := store.Create(models.Book{Title: "Deleted", Author: "Author"})
+
+	w := httptest.NewRecorder()
+	handler.HandleBookByID(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/books/%d", deleted.ID), nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+
+	// Deleted books are hidden from the listing unless asked for
+	for query, want := range map[string]int{"": 1, "?include_deleted=true": 2} {
+		w = httptest.NewRecorder()
+		handler.HandleBooks(w, httptest.NewRequest(http.MethodGet, "/books"+query, nil))
+		var resp struct {
+			Data []models.Book `json:"data"`
+		}
+		json.NewDecoder(w.Body).Decode(&resp)
+		if len(resp.Data) != want {
+			t.Errorf("GET /books%s: expected %d books, got %d", query, want, len(resp.Data))
+		}
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleTrash(w, httptest.NewRequest(http.MethodGet, "/trash", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var trash struct {
+		Data []models.Book `json:"data"`
+	}
+	json.NewDecoder(w.Body).Decode(&trash)
+	if len(trash.Data) != 1 || trash.Data[0].ID != deleted.ID || trash.Data[0].DeletedAt == nil {
+		t.Fatalf("expected the deleted book in the trash, got %+v", trash.Data)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleRestore(w, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/books/%d/restore", deleted.ID), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if _, err := store.GetByID(deleted.ID); err != nil {
+		t.Errorf("expected restored book to be found, got %v", err)
+	}
+	if _, err := store.GetByID(kept.ID); err != nil {
+		t.Errorf("expected kept book to be found, got %v", err)
+	}
+}
+
+func TestBookHandler_HandleRestore_Errors(t *testing.T) {
+	handler := NewBookHandler(storage.NewMemoryStorage())
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		expectedStatus int
+	}{
+		{"not in trash", http.MethodPost, "/books/999999/restore", http.StatusNotFound},
+		{"invalid id", http.MethodPost, "/books/abc/restore", http.StatusBadRequest},
+		{"wrong method", http.MethodGet, "/books/1/restore", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleRestore(w, httptest.NewRequest(tt.method, tt.url, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
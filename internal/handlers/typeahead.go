@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/typeahead"
+)
+
+// SuggestResponse is the body returned by /books/suggest: completions of
+// the prefix given as query, best first
+type SuggestResponse struct {
+	Query       string                 `json:"query"`
+	Suggestions []typeahead.Suggestion `json:"suggestions"`
+}
+
+// SetTypeahead makes the handler complete titles and authors from index
+func (h *BookHandler) SetTypeahead(index *typeahead.Index) {
+	h.typeahead = index
+}
+
+// HandleSuggest handles requests to /books/suggest endpoint, completing
+// the prefix given by the q parameter into the titles and authors of the
+// most popular books. The limit parameter sets how many are returned and
+// kind restricts them to titles or authors
+func (h *BookHandler) HandleSuggest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.typeahead == nil {
+		respondWithError(w, r, http.StatusNotImplemented, "Typeahead is not configured")
+		return
+	}
+
+	query := r.URL.Query()
+	limit := typeahead.DefaultLimit
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, typeahead.ErrInvalidLimit.Error())
+			return
+		}
+	}
+	suggestions, err := h.typeahead.Suggest(query.Get("q"), typeahead.Kind(query.Get("kind")), limit)
+	if err != nil {
+		// Suggest fails only on invalid parameters
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respond(w, r, http.StatusOK, SuggestResponse{Query: query.Get("q"), Suggestions: suggestions})
+}
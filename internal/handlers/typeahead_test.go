@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/internal/typeahead"
+)
+
+func TestBookHandler_HandleSuggest(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	index := typeahead.NewIndex()
+	index.Rebuild([]models.Book{
+		{ID: 1, Title: "Dune", Author: "Frank Herbert", RatingCount: 4},
+		{ID: 2, Title: "Dune Messiah", Author: "Frank Herbert"},
+		{ID: 3, Title: "Emma", Author: "Jane Austen"},
+	})
+	handler := NewBookHandler(store)
+	handler.SetTypeahead(index)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		expectedStatus int
+		expected       []string
+	}{
+		{"prefix", http.MethodGet, "/books/suggest?q=du", http.StatusOK, []string{"Dune", "Dune Messiah"}},
+		{"author", http.MethodGet, "/books/suggest?q=herb", http.StatusOK, []string{"Frank Herbert"}},
+		{"limit", http.MethodGet, "/books/suggest?q=du&limit=1", http.StatusOK, []string{"Dune"}},
+		{"kind", http.MethodGet, "/books/suggest?q=e&kind=author", http.StatusOK, []string{}},
+		{"no prefix", http.MethodGet, "/books/suggest", http.StatusOK, []string{}},
+		{"invalid limit", http.MethodGet, "/books/suggest?q=du&limit=many", http.StatusBadRequest, nil},
+		{"limit too high", http.MethodGet, "/books/suggest?q=du&limit=51", http.StatusBadRequest, nil},
+		{"invalid kind", http.MethodGet, "/books/suggest?q=du&kind=isbn", http.StatusBadRequest, nil},
+		{"POST", http.MethodPost, "/books/suggest?q=du", http.StatusMethodNotAllowed, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleSuggest(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var resp SuggestResponse
+			json.NewDecoder(w.Body).Decode(&resp)
+			if resp.Suggestions == nil {
+				t.Fatal("expected a suggestions array, got null")
+			}
+			if len(resp.Suggestions) != len(tt.expected) {
+				t.Fatalf("expected %q, got %+v", tt.expected, resp.Suggestions)
+			}
+			for i, text := range tt.expected {
+				if resp.Suggestions[i].Text != text {
+					t.Errorf("expected suggestion %d to be %q, got %q", i, text, resp.Suggestions[i].Text)
+				}
+			}
+		})
+	}
+}
+
+func TestBookHandler_HandleSuggest_NotConfigured(t *testing.T) {
+	handler := NewBookHandler(storage.NewMemoryStorage())
+	req := httptest.NewRequest(http.MethodGet, "/books/suggest?q=du", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleSuggest(w, req)
+
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("expected status %d, got %d", http.StatusNotImplemented, w.Code)
+	}
+}
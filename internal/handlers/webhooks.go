@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/webhook"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// WebhookHandler handles webhook subscription HTTP requests
+type WebhookHandler struct {
+	store      *webhook.Store
+	dispatcher *webhook.Dispatcher
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(store *webhook.Store, dispatcher *webhook.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{
+		store:      store,
+		dispatcher: dispatcher,
+	}
+}
+
+// SubscriptionRequest is the body accepted when creating or updating a
+// webhook subscription
+type SubscriptionRequest struct {
+	URL    string        `json:"url"`
+	Events []events.Type `json:"events"`
+	Secret string        `json:"secret"`
+	Active *bool         `json:"active"`
+}
+
+// HandleWebhooks handles requests to /webhooks endpoint
+func (h *WebhookHandler) HandleWebhooks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		subs := h.store.ListSubscriptions()
+		for i := range subs {
+			subs[i].Secret = ""
+		}
+		respond(w, r, http.StatusOK, subs)
+	case http.MethodPost:
+		h.createSubscription(w, r)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleWebhookByID handles requests to /webhooks/{id} endpoint
+func (h *WebhookHandler) HandleWebhookByID(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		sub, err := h.store.GetSubscription(id)
+		if err != nil {
+			respondWithWebhookError(w, r, err, "Failed to retrieve webhook")
+			return
+		}
+		sub.Secret = ""
+		respond(w, r, http.StatusOK, sub)
+	case http.MethodPut, http.MethodPatch:
+		h.updateSubscription(w, r, id)
+	case http.MethodDelete:
+		if err := h.store.DeleteSubscription(id); err != nil {
+			respondWithWebhookError(w, r, err, "Failed to delete webhook")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleDeliveries handles requests to /webhooks/{id}/deliveries endpoint
+func (h *WebhookHandler) HandleDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	deliveries, err := h.store.Deliveries(r.PathValue("id"))
+	if err != nil {
+		respondWithWebhookError(w, r, err, "Failed to retrieve deliveries")
+		return
+	}
+	respond(w, r, http.StatusOK, deliveries)
+}
+
+// HandleDeadLetters handles requests to /webhooks/dead-letters endpoint
+func (h *WebhookHandler) HandleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	respond(w, r, http.StatusOK, h.store.DeadLetters())
+}
+
+// HandleDelivery handles requests to /webhooks/{id}/deliveries/{delivery}
+// endpoint; only dead-lettered deliveries can be deleted
+func (h *WebhookHandler) HandleDelivery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if err := h.store.DeleteDeadLetter(r.PathValue("id"), r.PathValue("delivery")); err != nil {
+		respondWithWebhookError(w, r, err, "Failed to delete delivery")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleRedeliver handles requests to /webhooks/{id}/deliveries/{delivery}/retry
+// endpoint, requeueing a dead-lettered delivery
+func (h *WebhookHandler) HandleRedeliver(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	delivery, err := h.dispatcher.Redeliver(r.PathValue("id"), r.PathValue("delivery"))
+	if err != nil {
+		respondWithWebhookError(w, r, err, "Failed to retry delivery")
+		return
+	}
+	respond(w, r, http.StatusAccepted, delivery)
+}
+
+// createSubscription creates a new webhook subscription. The response is the
+// only one that includes the signing secret
+func (h *WebhookHandler) createSubscription(w http.ResponseWriter, r *http.Request) {
+	var req SubscriptionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	sub := req.subscription()
+	if err := sub.Validate(); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	created, err := h.store.CreateSubscription(sub)
+	if err != nil {
+		respondWithWebhookError(w, r, err, "Failed to create webhook")
+		return
+	}
+	respond(w, r, http.StatusCreated, created)
+}
+
+// updateSubscription updates a webhook subscription by ID
+func (h *WebhookHandler) updateSubscription(w http.ResponseWriter, r *http.Request, id string) {
+	var req SubscriptionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	sub := req.subscription()
+	if err := sub.Validate(); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := h.store.UpdateSubscription(id, sub)
+	if err != nil {
+		respondWithWebhookError(w, r, err, "Failed to update webhook")
+		return
+	}
+	updated.Secret = ""
+	respond(w, r, http.StatusOK, updated)
+}
+
+// subscription converts the request to a subscription, active by default
+func (req SubscriptionRequest) subscription() webhook.Subscription {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	return webhook.Subscription{
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: req.Secret,
+		Active: active,
+	}
+}
+
+// respondWithWebhookError maps webhook errors to responses, logging and
+// hiding anything unexpected behind message
+func respondWithWebhookError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch err {
+	case webhook.ErrSubscriptionNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Webhook not found")
+	case webhook.ErrDeliveryNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Delivery not found")
+	default:
+		logger.Error.Printf("%s: %v", message, err)
+		respondWithError(w, r, http.StatusInternalServerError, message)
+	}
+}
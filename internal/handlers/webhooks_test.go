@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/internal/webhook"
+)
+
+func newTestWebhookHandler(t *testing.T) (*WebhookHandler, *webhook.Store) {
+	t.Helper()
+	store, err := webhook.OpenStore("")
+	if err != nil {
+		t.Fatalf("failed to open webhook store: %v", err)
+	}
+	return NewWebhookHandler(store, webhook.NewDispatcher(store, webhook.Options{})), store
+}
+
+func TestWebhookHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		payload        string
+		expectedStatus int
+	}{
+		{"valid", `{"url": "https://example.com/hook", "events": ["book.created"]}`, http.StatusCreated},
+		{"invalid url", `{"url": "example.com/hook"}`, http.StatusBadRequest},
+		{"unknown event", `{"url": "https://example.com/hook", "events": ["book.read"]}`, http.StatusBadRequest},
+		{"invalid json", `{"url":`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _ := newTestWebhookHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tt.payload))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.HandleWebhooks(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var sub webhook.Subscription
+			json.NewDecoder(w.Body).Decode(&sub)
+			if sub.ID == "" || sub.Secret == "" || !sub.Active {
+				t.Errorf("expected active subscription with ID and secret, got %+v", sub)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_ByID(t *testing.T) {
+	handler, store := newTestWebhookHandler(t)
+	sub, _ := store.CreateSubscription(webhook.Subscription{URL: "https://example.com/hook", Active: true})
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/webhooks/"+id, nil)
+		req.SetPathValue("id", id)
+		w := httptest.NewRecorder()
+		handler.HandleWebhookByID(w, req)
+		return w
+	}
+
+	w := get(sub.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var got webhook.Subscription
+	json.NewDecoder(w.Body).Decode(&got)
+	if got.Secret != "" {
+		t.Error("expected secret to be hidden")
+	}
+
+	body := `{"url": "https://example.com/other", "active": false}`
+	req := httptest.NewRequest(http.MethodPut, "/webhooks/"+sub.ID, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", sub.ID)
+	w = httptest.NewRecorder()
+	handler.HandleWebhookByID(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	updated, _ := store.GetSubscription(sub.ID)
+	if updated.URL != "https://example.com/other" || updated.Active || updated.Secret != sub.Secret {
+		t.Errorf("expected updated inactive subscription with original secret, got %+v", updated)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/webhooks/"+sub.ID, nil)
+	req.SetPathValue("id", sub.ID)
+	w = httptest.NewRecorder()
+	handler.HandleWebhookByID(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+
+	if w := get(sub.ID); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 after delete, got %d", w.Code)
+	}
+}
+
+func TestWebhookHandler_DeadLetters(t *testing.T) {
+	handler, store := newTestWebhookHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/dead-letters", nil)
+	w := httptest.NewRecorder()
+	handler.HandleDeadLetters(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "[]\n" {
+		t.Errorf("expected empty list, got %d %s", w.Code, w.Body.String())
+	}
+
+	sub, _ := store.CreateSubscription(webhook.Subscription{URL: "https://example.com/hook", Active: true})
+	req = httptest.NewRequest(http.MethodPost, "/webhooks/"+sub.ID+"/deliveries/missing/retry", nil)
+	req.SetPathValue("id", sub.ID)
+	req.SetPathValue("delivery", "missing")
+	w = httptest.NewRecorder()
+	handler.HandleRedeliver(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+// recordingNotifier records the changes it is notified of
+type recordingNotifier struct {
+	types []events.Type
+}
+
+func (n *recordingNotifier) Notify(typ events.Type, bookID int, book *models.Book) {
+	n.types = append(n.types, typ)
+}
+
+func TestBookHandler_Notifies(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := NewBookHandler(store)
+	notifier := &recordingNotifier{}
+	handler.SetNotifier(notifier)
+
+	req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewBufferString(`{"title": "T", "author": "A"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.HandleBooks(w, req)
+
+	var created models.Book
+	json.NewDecoder(w.Body).Decode(&created)
+	id := strconv.Itoa(created.ID)
+
+	req = httptest.NewRequest(http.MethodPut, "/books/"+id, bytes.NewBufferString(`{"title": "T2", "author": "A"}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.HandleBookByID(httptest.NewRecorder(), req)
+
+	// A failed write must not notify
+	req = httptest.NewRequest(http.MethodDelete, "/books/0", nil)
+	handler.HandleBookByID(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodDelete, "/books/"+id, nil)
+	handler.HandleBookByID(httptest.NewRecorder(), req)
+
+	want := []events.Type{events.BookCreated, events.BookUpdated, events.BookDeleted}
+	if len(notifier.types) != len(want) {
+		t.Fatalf("expected %v, got %v", want, notifier.types)
+	}
+	for i := range want {
+		if notifier.types[i] != want[i] {
+			t.Errorf("expected %s at %d, got %s", want[i], i, notifier.types[i])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// EditionRequest is the body accepted when linking a book to a work
+type EditionRequest struct {
+	BookID int `json:"book_id"`
+}
+
+// HandleWorks handles requests to /works endpoint
+func (h *CatalogHandler) HandleWorks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		works, err := h.catalog.Works()
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to retrieve works")
+			return
+		}
+		params := models.ParsePaginationParams(r)
+		start, end := params.Bounds(len(works))
+		respond(w, r, http.StatusOK, models.NewPaginatedResponse(works[start:end], params.Page, params.PageSize, len(works)))
+	case http.MethodPost:
+		var work models.Work
+		if !decodeRequest(w, r, &work) {
+			return
+		}
+		created, err := h.catalog.CreateWork(work)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to create work")
+			return
+		}
+		respond(w, r, http.StatusCreated, created)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleWorkByID handles requests to /works/{id} endpoint. Deleting a work
+// unlinks its editions
+func (h *CatalogHandler) HandleWorkByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid work ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		work, err := h.catalog.Work(id)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to retrieve work")
+			return
+		}
+		respond(w, r, http.StatusOK, work)
+	case http.MethodPut, http.MethodPatch:
+		var work models.Work
+		if !decodeRequest(w, r, &work) {
+			return
+		}
+		updated, err := h.catalog.UpdateWork(id, work)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to update work")
+			return
+		}
+		respond(w, r, http.StatusOK, updated)
+	case http.MethodDelete:
+		if err := h.catalog.DeleteWork(id); err != nil {
+			respondWithCatalogError(w, r, err, "Failed to delete work")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleEditions handles requests to /works/{id}/editions endpoint. GET lists
+// the editions, oldest publication first, and POST links a book to the work
+func (h *CatalogHandler) HandleEditions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid work ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		editions, err := h.catalog.Editions(id)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to retrieve editions")
+			return
+		}
+		params := models.ParsePaginationParams(r)
+		start, end := params.Bounds(len(editions))
+		respond(w, r, http.StatusOK, models.NewPaginatedResponse(editions[start:end], params.Page, params.PageSize, len(editions)))
+	case http.MethodPost:
+		var req EditionRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if req.BookID <= 0 {
+			respondWithError(w, r, http.StatusBadRequest, "book_id is required")
+			return
+		}
+		book, err := h.catalog.AddEdition(id, req.BookID)
+		if err != nil {
+			respondWithCatalogError(w, r, err, "Failed to add edition")
+			return
+		}
+		respond(w, r, http.StatusOK, book)
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleEdition handles requests to /works/{id}/editions/{book} endpoint,
+// unlinking a book from a work
+func (h *CatalogHandler) HandleEdition(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid work ID")
+		return
+	}
+	bookID, err := strconv.Atoi(r.PathValue("book"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, models.ErrInvalidID.Error())
+		return
+	}
+
+	if _, err := h.catalog.RemoveEdition(id, bookID); err != nil {
+		respondWithCatalogError(w, r, err, "Failed to remove edition")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
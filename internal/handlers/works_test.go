@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/catalog"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func TestCatalogHandler_Works(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	handler := NewCatalogHandler(catalog.NewService(books, storage.NewMemorySeriesStorage(), storage.NewMemoryWorkStorage()))
+
+	book, _ := books.Create(models.Book{Title: "Don Quijote", Author: "Cervantes", Language: "es"})
+	bookID := strconv.Itoa(book.ID)
+
+	editionRequest := func(book string) *http.Request {
+		req := newCirculationRequest(http.MethodDelete, "/works/1/editions/"+book, "1", "")
+		req.SetPathValue("book", book)
+		return req
+	}
+
+	tests := []struct {
+		name           string
+		handle         http.HandlerFunc
+		req            *http.Request
+		expectedStatus int
+	}{
+		{"create", handler.HandleWorks, newCirculationRequest(http.MethodPost, "/works", "", `{"title": "Don Quixote", "author": "Miguel de Cervantes"}`), http.StatusCreated},
+		{"create without title", handler.HandleWorks, newCirculationRequest(http.MethodPost, "/works", "", `{"author": "Anonymous"}`), http.StatusBadRequest},
+		{"list", handler.HandleWorks, newCirculationRequest(http.MethodGet, "/works", "", ""), http.StatusOK},
+		{"get", handler.HandleWorkByID, newCirculationRequest(http.MethodGet, "/works/1", "1", ""), http.StatusOK},
+		{"get unknown", handler.HandleWorkByID, newCirculationRequest(http.MethodGet, "/works/999", "999", ""), http.StatusNotFound},
+		{"update", handler.HandleWorkByID, newCirculationRequest(http.MethodPatch, "/works/1", "1", `{"title": "The Ingenious Gentleman Don Quixote of La Mancha"}`), http.StatusOK},
+		{"add edition", handler.HandleEditions, newCirculationRequest(http.MethodPost, "/works/1/editions", "1", `{"book_id": `+bookID+`}`), http.StatusOK},
+		{"add edition without book", handler.HandleEditions, newCirculationRequest(http.MethodPost, "/works/1/editions", "1", `{}`), http.StatusBadRequest},
+		{"add edition to unknown work", handler.HandleEditions, newCirculationRequest(http.MethodPost, "/works/999/editions", "999", `{"book_id": `+bookID+`}`), http.StatusNotFound},
+		{"editions", handler.HandleEditions, newCirculationRequest(http.MethodGet, "/works/1/editions", "1", ""), http.StatusOK},
+		{"remove edition", handler.HandleEdition, editionRequest(bookID), http.StatusNoContent},
+		{"remove edition again", handler.HandleEdition, editionRequest(bookID), http.StatusNotFound},
+		{"delete", handler.HandleWorkByID, newCirculationRequest(http.MethodDelete, "/works/1", "1", ""), http.StatusNoContent},
+		{"delete again", handler.HandleWorkByID, newCirculationRequest(http.MethodDelete, "/works/1", "1", ""), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handle(w, tt.req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCatalogHandler_AddEditionLinksBook(t *testing.T) {
+	books := storage.NewMemoryStorage()
+	handler := NewCatalogHandler(catalog.NewService(books, storage.NewMemorySeriesStorage(), storage.NewMemoryWorkStorage()))
+
+	book, _ := books.Create(models.Book{Title: "Don Quijote", Author: "Cervantes"})
+	handler.HandleWorks(httptest.NewRecorder(), newCirculationRequest(http.MethodPost, "/works", "", `{"title": "Don Quixote"}`))
+
+	w := httptest.NewRecorder()
+	handler.HandleEditions(w, newCirculationRequest(http.MethodPost, "/works/1/editions", "1", `{"book_id": `+strconv.Itoa(book.ID)+`}`))
+
+	var linked models.Book
+	json.NewDecoder(w.Body).Decode(&linked)
+	if linked.ID != book.ID || linked.WorkID != 1 {
+		t.Errorf("expected book %d linked to work 1, got %+v", book.ID, linked)
+	}
+	if stored, _ := books.GetByID(book.ID); stored.WorkID != 1 {
+		t.Errorf("expected the stored book to be linked to work 1, got %d", stored.WorkID)
+	}
+}
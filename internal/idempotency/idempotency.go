@@ -0,0 +1,126 @@
+// Package idempotency remembers the responses to requests sent with an
+// Idempotency-Key so that retries of the same request can be answered
+// without repeating its side effects.
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long responses are kept when no TTL is configured
+const DefaultTTL = 24 * time.Hour
+
+var (
+	// ErrInProgress is returned when the first request with a key has not
+	// finished yet
+	ErrInProgress = errors.New("a request with this idempotency key is still in progress")
+	// ErrMismatch is returned when a key is reused for a different request
+	ErrMismatch = errors.New("idempotency key was already used for a different request")
+)
+
+// Response is a stored response, replayed for retries of its request
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// record tracks one key; response is nil while the first request runs
+type record struct {
+	hash      string
+	response  *Response
+	expiresAt time.Time
+}
+
+// Store holds idempotency records in memory, keyed by client and key
+type Store struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	records   map[string]*record
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewStore creates a store that forgets keys ttl after their first use
+func NewStore(ttl time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Store{
+		ttl:     ttl,
+		records: make(map[string]*record),
+		now:     time.Now,
+	}
+}
+
+// Hash fingerprints a request from its method, target and body
+func Hash(method, target string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + target + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Begin claims key for a client's request with the given hash. It returns
+// the stored response if the request already completed, ErrInProgress or
+// ErrMismatch if the key cannot be used, or nil with no error if the caller
+// should handle the request and then call Complete or Release
+func (s *Store) Begin(client, key, hash string) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	id := client + "\x00" + key
+	if rec, ok := s.records[id]; ok && now.Before(rec.expiresAt) {
+		switch {
+		case rec.hash != hash:
+			return nil, ErrMismatch
+		case rec.response == nil:
+			return nil, ErrInProgress
+		default:
+			return rec.response, nil
+		}
+	}
+
+	s.records[id] = &record{hash: hash, expiresAt: now.Add(s.ttl)}
+	return nil, nil
+}
+
+// Complete stores the response to a request claimed with Begin
+func (s *Store) Complete(client, key string, resp Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.records[client+"\x00"+key]; ok {
+		rec.response = &resp
+	}
+}
+
+// Release forgets a key claimed with Begin without storing a response, so
+// the request can be retried
+func (s *Store) Release(client, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, client+"\x00"+key)
+}
+
+// sweep drops expired records, at most once a minute; s.mu must be held
+func (s *Store) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for id, rec := range s.records {
+		if !now.Before(rec.expiresAt) {
+			delete(s.records, id)
+		}
+	}
+}
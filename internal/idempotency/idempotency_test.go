@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStore_Begin(t *testing.T) {
+	store := NewStore(time.Hour)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	hash := Hash(http.MethodPost, "/books", []byte(`{"title":"Go"}`))
+
+	resp, err := store.Begin("alice", "key-1", hash)
+	if resp != nil || err != nil {
+		t.Fatalf("expected first use to proceed, got %v, %v", resp, err)
+	}
+
+	if _, err := store.Begin("alice", "key-1", hash); err != ErrInProgress {
+		t.Errorf("expected ErrInProgress, got %v", err)
+	}
+
+	store.Complete("alice", "key-1", Response{StatusCode: http.StatusCreated, Body: []byte("created")})
+
+	resp, err = store.Begin("alice", "key-1", hash)
+	if err != nil || resp == nil || resp.StatusCode != http.StatusCreated || string(resp.Body) != "created" {
+		t.Errorf("expected stored response, got %+v, %v", resp, err)
+	}
+
+	other := Hash(http.MethodPost, "/books", []byte(`{"title":"Rust"}`))
+	if _, err := store.Begin("alice", "key-1", other); err != ErrMismatch {
+		t.Errorf("expected ErrMismatch, got %v", err)
+	}
+
+	// Keys are scoped per client
+	if resp, err := store.Begin("bob", "key-1", other); resp != nil || err != nil {
+		t.Errorf("expected another client's key to proceed, got %v, %v", resp, err)
+	}
+
+	// Expired keys can be used again
+	now = now.Add(2 * time.Hour)
+	if resp, err := store.Begin("alice", "key-1", other); resp != nil || err != nil {
+		t.Errorf("expected expired key to proceed, got %v, %v", resp, err)
+	}
+}
+
+func TestStore_Release(t *testing.T) {
+	store := NewStore(time.Hour)
+	hash := Hash(http.MethodPost, "/books", nil)
+
+	store.Begin("alice", "key-1", hash)
+	store.Release("alice", "key-1")
+
+	if resp, err := store.Begin("alice", "key-1", hash); resp != nil || err != nil {
+		t.Errorf("expected released key to proceed, got %v, %v", resp, err)
+	}
+}
+
+func TestStore_Sweep(t *testing.T) {
+	store := NewStore(time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	store.Begin("alice", "old", "hash")
+	now = now.Add(2 * time.Minute)
+	store.Begin("alice", "new", "hash")
+
+	if len(store.records) != 1 {
+		t.Errorf("expected expired record to be swept, got %d records", len(store.records))
+	}
+}
@@ -0,0 +1,393 @@
+// Package ingest turns uploaded EPUB and PDF files into catalogue records:
+// it reads their metadata, matches them to an existing book or creates one,
+// and keeps the file in a blob store as a downloadable asset of the book.
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/codeforgood-org/golang-book-api/internal/blob"
+	"github.com/codeforgood-org/golang-book-api/internal/covers"
+	"github.com/codeforgood-org/golang-book-api/internal/ebook"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// DefaultMaxBytes is the default limit on the size of an upload
+const DefaultMaxBytes = 50 << 20
+
+// How an ingested file was matched to an existing book
+const (
+	MatchedByBookID = "book_id"
+	MatchedByISBN   = "isbn"
+	MatchedByTitle  = "title"
+)
+
+var (
+	// ErrTooLarge is returned when an upload has more bytes than allowed
+	ErrTooLarge = errors.New("file is too large")
+
+	// ErrIncompleteMetadata is returned when a file matches no book and does
+	// not give the title and author needed to create one
+	ErrIncompleteMetadata = errors.New("file has no title or author to create a book from; pass book_id to attach it to an existing book")
+)
+
+// Result describes an ingested file and the book it was attached to
+type Result struct {
+	Book     *models.Book    `json:"book"`
+	Asset    *models.Asset   `json:"asset"`
+	Metadata *ebook.Metadata `json:"metadata"`
+	// Created is true if the book was created from the file's metadata
+	Created bool `json:"created"`
+	// MatchedBy says how an existing book was found: book_id, isbn or title
+	MatchedBy string `json:"matched_by,omitempty"`
+	// Duplicate is true if the book already had an asset with the same
+	// content, which is returned instead of a new one
+	Duplicate bool `json:"duplicate"`
+	// Warnings lists the parts of the file that could not be used, such as
+	// an unreadable cover image
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Service ingests files against the book and asset storages and a blob
+// store
+type Service struct {
+	books    storage.Storage
+	assets   storage.AssetStorage
+	blobs    blob.Store
+	covers   *covers.Service
+	maxBytes int
+	// mu serializes ingestion so that the same file uploaded twice at once
+	// creates one book
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewService creates an ingest service
+func NewService(books storage.Storage, assets storage.AssetStorage, blobs blob.Store) *Service {
+	return &Service{
+		books:    books,
+		assets:   assets,
+		blobs:    blobs,
+		maxBytes: DefaultMaxBytes,
+		now:      time.Now,
+	}
+}
+
+// SetCovers makes the service use the cover image of an EPUB as the cover
+// of a book that has none
+func (s *Service) SetCovers(c *covers.Service) {
+	s.covers = c
+}
+
+// SetMaxBytes sets the largest upload accepted, in bytes
+func (s *Service) SetMaxBytes(n int) {
+	s.maxBytes = n
+}
+
+// MaxBytes returns the largest upload accepted, in bytes
+func (s *Service) MaxBytes() int {
+	return s.maxBytes
+}
+
+// Ingest reads the metadata of an EPUB or PDF file and attaches the file to
+// a book: the one with ID bookID if it is not 0, otherwise the book with the
+// same ISBN, or the same title and an author in common, or else a new book
+// made from the metadata. Books are created through storage.WithContext, so
+// the creation is attributed to the actor carried by ctx
+func (s *Service) Ingest(ctx context.Context, filename string, data []byte, bookID int) (*Result, error) {
+	if len(data) > s.maxBytes {
+		return nil, ErrTooLarge
+	}
+	meta, err := ebook.Read(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &Result{Metadata: meta}
+	if bookID != 0 {
+		if result.Book, err = s.books.GetByID(bookID); err != nil {
+			return nil, err
+		}
+		result.MatchedBy = MatchedByBookID
+	} else {
+		if result.Book, result.MatchedBy, err = s.match(meta); err != nil {
+			return nil, err
+		}
+		if result.Book == nil {
+			if result.Book, err = s.create(ctx, meta); err != nil {
+				return nil, err
+			}
+			result.Created = true
+		}
+	}
+
+	if result.Asset, result.Duplicate, err = s.attach(result.Book.ID, filename, meta.Format, data); err != nil {
+		return nil, err
+	}
+
+	if len(meta.Cover) > 0 && s.covers != nil && result.Book.Cover == nil {
+		if _, err := s.covers.Upload(result.Book.ID, meta.Cover); err != nil {
+			result.Warnings = append(result.Warnings, "cover image not used: "+err.Error())
+		} else if book, err := s.books.GetByID(result.Book.ID); err == nil {
+			result.Book = book
+		}
+	}
+	return result, nil
+}
+
+// match finds the book a file's metadata describes, by ISBN and then by
+// title and author, returning nil if there is none
+func (s *Service) match(meta *ebook.Metadata) (*models.Book, string, error) {
+	books, err := s.books.GetAll()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if isbn := models.ISBN13(meta.ISBN); isbn != "" {
+		for i := range books {
+			if models.ISBN13(books[i].ISBN) == isbn {
+				return &books[i], MatchedByISBN, nil
+			}
+		}
+	}
+
+	title := strings.TrimSpace(meta.Title)
+	if title == "" || len(meta.Creators) == 0 {
+		return nil, "", nil
+	}
+	for i := range books {
+		if strings.EqualFold(strings.TrimSpace(books[i].Title), title) && shareAuthor(books[i].Authors(), meta.Creators) {
+			return &books[i], MatchedByTitle, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// shareAuthor reports whether two lists of names have one in common,
+// ignoring case
+func shareAuthor(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if strings.EqualFold(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// create adds a book made from a file's metadata
+func (s *Service) create(ctx context.Context, meta *ebook.Metadata) (*models.Book, error) {
+	book := ebook.ToBook(meta)
+	if err := book.Validate(); err != nil {
+		if err == models.ErrInvalidTitle || err == models.ErrInvalidAuthor {
+			return nil, ErrIncompleteMetadata
+		}
+		return nil, err
+	}
+	return storage.WithContext(s.books, ctx).Create(book)
+}
+
+// attach stores a file as an asset of a book, unless the book already has
+// an asset with the same content, in which case that one is returned
+func (s *Service) attach(bookID int, filename string, format ebook.Format, data []byte) (*models.Asset, bool, error) {
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+
+	existing, err := s.assets.GetAssets(bookID)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range existing {
+		if existing[i].SHA256 == digest {
+			return withURL(&existing[i]), true, nil
+		}
+	}
+
+	asset, err := s.assets.CreateAsset(models.Asset{
+		BookID:      bookID,
+		Filename:    cleanFilename(filename, bookID, format),
+		Format:      string(format),
+		ContentType: format.ContentType(),
+		Bytes:       len(data),
+		SHA256:      digest,
+		CreatedAt:   s.now().UTC(),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.blobs.Put(key(bookID, asset.ID), data); err != nil {
+		// Without its file the record would only lead to failed downloads
+		s.assets.DeleteAsset(asset.ID)
+		return nil, false, err
+	}
+	return withURL(asset), false, nil
+}
+
+// Assets returns the assets of a book, oldest first
+func (s *Service) Assets(bookID int) ([]models.Asset, error) {
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	assets, err := s.assets.GetAssets(bookID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		withURL(&assets[i])
+	}
+	return assets, nil
+}
+
+// Asset returns an asset of a book by its ID
+func (s *Service) Asset(bookID, assetID int) (*models.Asset, error) {
+	asset, err := s.assets.GetAsset(assetID)
+	if err != nil {
+		return nil, err
+	}
+	if asset.BookID != bookID {
+		return nil, models.ErrAssetNotFound
+	}
+	return withURL(asset), nil
+}
+
+// Download returns an asset of a book with its content
+func (s *Service) Download(bookID, assetID int) (*models.Asset, []byte, error) {
+	asset, err := s.Asset(bookID, assetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := s.blobs.Get(key(bookID, assetID))
+	if err == blob.ErrNotFound {
+		return nil, nil, models.ErrAssetNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return asset, data, nil
+}
+
+// Delete removes an asset of a book and its file
+func (s *Service) Delete(bookID, assetID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.Asset(bookID, assetID); err != nil {
+		return err
+	}
+	if err := s.assets.DeleteAsset(assetID); err != nil {
+		return err
+	}
+	if err := s.blobs.Delete(key(bookID, assetID)); err != nil && err != blob.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// ForgetBook removes the assets of a book purged from the book storage and
+// their files
+func (s *Service) ForgetBook(book models.Book) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assets, err := s.assets.GetAssets(book.ID)
+	if err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		if err := s.assets.DeleteAsset(asset.ID); err != nil {
+			return err
+		}
+		if err := s.blobs.Delete(key(book.ID, asset.ID)); err != nil && err != blob.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// MoveBook moves the assets of one book and their files to another when the
+// first is merged into it. Files the other book already has are removed
+func (s *Service) MoveBook(from, to int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assets, err := s.assets.GetAssets(from)
+	if err != nil {
+		return err
+	}
+	existing, err := s.assets.GetAssets(to)
+	if err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		duplicate := slices.ContainsFunc(existing, func(a models.Asset) bool {
+			return a.SHA256 == asset.SHA256
+		})
+		if duplicate {
+			if err := s.assets.DeleteAsset(asset.ID); err != nil {
+				return err
+			}
+		} else {
+			data, err := s.blobs.Get(key(from, asset.ID))
+			if err != nil && err != blob.ErrNotFound {
+				return err
+			}
+			if err == nil {
+				if err := s.blobs.Put(key(to, asset.ID), data); err != nil {
+					return err
+				}
+			}
+			asset.BookID = to
+			if _, err := s.assets.UpdateAsset(asset); err != nil {
+				return err
+			}
+		}
+		if err := s.blobs.Delete(key(from, asset.ID)); err != nil && err != blob.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// withURL sets the download URL of an asset
+func withURL(asset *models.Asset) *models.Asset {
+	asset.URL = asset.Path()
+	return asset
+}
+
+// key returns the blob key of an asset's file
+func key(bookID, assetID int) string {
+	return fmt.Sprintf("assets/%d/%d", bookID, assetID)
+}
+
+// cleanFilename returns the base name of an uploaded file without control
+// characters or quotes, so it is safe to send back in a
+// Content-Disposition header, or a name made from the book ID if nothing is
+// left
+func cleanFilename(name string, bookID int, format ebook.Format) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name = strings.TrimSpace(name); name == "" || name == "." || name == "/" {
+		return fmt.Sprintf("book-%d.%s", bookID, format)
+	}
+	return name
+}
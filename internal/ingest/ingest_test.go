@@ -0,0 +1,323 @@
+package ingest
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/blob"
+	"github.com/codeforgood-org/golang-book-api/internal/covers"
+	"github.com/codeforgood-org/golang-book-api/internal/ebook"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newTestService returns a service with covers enabled, its book storage
+// and its blob store
+func newTestService(t *testing.T) (*Service, *storage.MemoryStorage, *blob.MemoryStore) {
+	t.Helper()
+
+	books := storage.NewMemoryStorage()
+	blobs := blob.NewMemoryStore()
+	s := NewService(books, storage.NewMemoryAssetStorage(), blobs)
+	s.SetCovers(covers.NewService(books, blobs))
+	s.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+	return s, books, blobs
+}
+
+// testEPUB returns an EPUB with the given metadata elements and, if cover
+// is not nil, a cover image
+func testEPUB(t *testing.T, metadata string, cover []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string][]byte{
+		"META-INF/container.xml": []byte(`<container><rootfiles><rootfile full-path="content.opf" media-type="application/oebps-package+xml"/></rootfiles></container>`),
+		"content.opf": []byte(`<package xmlns="http://www.idpf.org/2007/opf"><metadata xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+			metadata + `</metadata><manifest><item id="c" href="cover.png" media-type="image/png" properties="cover-image"/></manifest></package>`),
+	}
+	if cover != nil {
+		files["cover.png"] = cover
+	}
+	for name, content := range files {
+		f, _ := w.Create(name)
+		f.Write(content)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to write EPUB: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// testPDF returns a PDF with an Info dictionary holding info
+func testPDF(info string) []byte {
+	return []byte(fmt.Sprintf("%%PDF-1.4\n1 0 obj\n<< %s >>\nendobj\ntrailer\n<< /Info 1 0 R >>\n%%%%EOF\n", info))
+}
+
+// testPNG returns a small PNG image
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 40, 60))); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+const goBook = `<dc:title>The Go Programming Language</dc:title><dc:creator>Alan A. A. Donovan</dc:creator>
+<dc:creator>Brian W. Kernighan</dc:creator><dc:identifier>urn:isbn:9780134190440</dc:identifier><dc:date>2015</dc:date>`
+
+func TestService_Ingest_CreatesBook(t *testing.T) {
+	s, books, blobs := newTestService(t)
+
+	result, err := s.Ingest(context.Background(), "gopl.epub", testEPUB(t, goBook, testPNG(t)), 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Created || result.MatchedBy != "" || result.Duplicate {
+		t.Errorf("expected a created book, got %+v", result)
+	}
+	book := result.Book
+	if book.Title != "The Go Programming Language" || book.Author != "Alan A. A. Donovan; Brian W. Kernighan" ||
+		book.ISBN != "9780134190440" || book.PublishedYear != 2015 {
+		t.Errorf("expected the book from the metadata, got %+v", book)
+	}
+	if book.Cover == nil {
+		t.Error("expected the EPUB cover on the book")
+	}
+	if stored, _ := books.GetByID(book.ID); stored == nil || stored.Cover == nil {
+		t.Error("expected the book with its cover in storage")
+	}
+
+	asset := result.Asset
+	if asset.BookID != book.ID || asset.Filename != "gopl.epub" || asset.Format != "epub" ||
+		asset.ContentType != "application/epub+zip" || asset.SHA256 == "" ||
+		asset.URL != fmt.Sprintf("/books/%d/assets/%d", book.ID, asset.ID) {
+		t.Errorf("unexpected asset %+v", asset)
+	}
+	if _, err := blobs.Get(fmt.Sprintf("assets/%d/%d", book.ID, asset.ID)); err != nil {
+		t.Errorf("expected the file in the blob store, got %v", err)
+	}
+}
+
+func TestService_Ingest_Matches(t *testing.T) {
+	s, books, _ := newTestService(t)
+	byISBN, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin", ISBN: "9780132350884"})
+	byTitle, _ := books.Create(models.Book{Title: "The C Programming Language", Author: "Brian W. Kernighan; Dennis M. Ritchie"})
+
+	tests := []struct {
+		name      string
+		data      []byte
+		bookID    int
+		expected  int
+		matchedBy string
+	}{
+		{
+			name:      "ISBN-10 in a PDF matches the ISBN-13",
+			data:      testPDF("/Title (Clean Code: A Handbook) /Author (Bob Martin) /ISBN (0-13-235088-2)"),
+			expected:  byISBN.ID,
+			matchedBy: MatchedByISBN,
+		},
+		{
+			name:      "title and a shared author",
+			data:      testEPUB(t, "<dc:title>the c programming language</dc:title><dc:creator>Dennis M. Ritchie</dc:creator>", nil),
+			expected:  byTitle.ID,
+			matchedBy: MatchedByTitle,
+		},
+		{
+			name:      "book_id wins over the metadata",
+			data:      testEPUB(t, goBook, nil),
+			bookID:    byTitle.ID,
+			expected:  byTitle.ID,
+			matchedBy: MatchedByBookID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Ingest(context.Background(), "file", tt.data, tt.bookID)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if result.Created || result.Book.ID != tt.expected || result.MatchedBy != tt.matchedBy {
+				t.Errorf("expected book %d matched by %s, got book %d matched by %q (created %v)",
+					tt.expected, tt.matchedBy, result.Book.ID, result.MatchedBy, result.Created)
+			}
+		})
+	}
+
+	if all, _ := books.GetAll(); len(all) != 2 {
+		t.Errorf("expected no new books, got %d books", len(all))
+	}
+}
+
+func TestService_Ingest_Duplicate(t *testing.T) {
+	s, _, _ := newTestService(t)
+	data := testEPUB(t, goBook, nil)
+
+	first, _ := s.Ingest(context.Background(), "gopl.epub", data, 0)
+	second, err := s.Ingest(context.Background(), "copy.epub", data, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if second.Created || second.MatchedBy != MatchedByISBN || !second.Duplicate || second.Asset.ID != first.Asset.ID {
+		t.Errorf("expected the existing asset of the matched book, got %+v", second)
+	}
+	if assets, _ := s.Assets(first.Book.ID); len(assets) != 1 {
+		t.Errorf("expected one asset, got %d", len(assets))
+	}
+}
+
+func TestService_Ingest_Errors(t *testing.T) {
+	s, _, _ := newTestService(t)
+	s.SetMaxBytes(1 << 10)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		bookID   int
+		expected error
+	}{
+		{"too large", append([]byte("%PDF-1.4\n"), make([]byte, 1<<10)...), 0, ErrTooLarge},
+		{"unsupported format", []byte("plain text"), 0, ebook.ErrUnsupportedFormat},
+		{"corrupt EPUB", []byte("PK\x03\x04 not really"), 0, ebook.ErrInvalidEPUB},
+		{"no author", testPDF("/Title (Anonymous Pamphlet)"), 0, ErrIncompleteMetadata},
+		{"unknown book", testPDF("/Title (Anything)"), 999999999, models.ErrBookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Ingest(context.Background(), "file", tt.data, tt.bookID); err != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestService_Ingest_BadCover(t *testing.T) {
+	s, _, _ := newTestService(t)
+
+	result, err := s.Ingest(context.Background(), "gopl.epub", testEPUB(t, goBook, []byte("not an image")), 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Book.Cover != nil || len(result.Warnings) != 1 {
+		t.Errorf("expected no cover and a warning, got %+v", result)
+	}
+}
+
+func TestService_DownloadAndDelete(t *testing.T) {
+	s, books, blobs := newTestService(t)
+	other, _ := books.Create(models.Book{Title: "Other", Author: "Someone"})
+	data := testPDF("/Title (Go in Practice) /Author (Matt Butcher; Matt Farina)")
+	result, _ := s.Ingest(context.Background(), "", data, 0)
+	book, asset := result.Book, result.Asset
+
+	if asset.Filename != fmt.Sprintf("book-%d.pdf", book.ID) {
+		t.Errorf("expected a filename made from the book ID, got %q", asset.Filename)
+	}
+	got, content, err := s.Download(book.ID, asset.ID)
+	if err != nil || got.ID != asset.ID || !bytes.Equal(content, data) {
+		t.Errorf("expected the uploaded file, got %+v, %v", got, err)
+	}
+	if _, _, err := s.Download(other.ID, asset.ID); err != models.ErrAssetNotFound {
+		t.Errorf("expected ErrAssetNotFound for another book, got %v", err)
+	}
+
+	if err := s.Delete(book.ID, asset.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := s.Asset(book.ID, asset.ID); err != models.ErrAssetNotFound {
+		t.Errorf("expected ErrAssetNotFound, got %v", err)
+	}
+	if _, err := blobs.Get(fmt.Sprintf("assets/%d/%d", book.ID, asset.ID)); err != blob.ErrNotFound {
+		t.Errorf("expected the file to be deleted, got %v", err)
+	}
+	if err := s.Delete(book.ID, asset.ID); err != models.ErrAssetNotFound {
+		t.Errorf("expected ErrAssetNotFound, got %v", err)
+	}
+}
+
+func TestCleanFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"gopl.epub", "gopl.epub"},
+		{"C:\\Users\\me\\My Book.pdf", "My Book.pdf"},
+		{"../../etc/passwd", "passwd"},
+		{"bad\"name\n.epub", "badname.epub"},
+		{"", "book-7.epub"},
+		{"/", "book-7.epub"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanFilename(tt.name, 7, ebook.EPUB); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestService_ForgetBook(t *testing.T) {
+	s, books, blobs := newTestService(t)
+	book, _ := books.Create(models.Book{Title: "Go Programming", Author: "Donovan"})
+	other, _ := books.Create(models.Book{Title: "Other", Author: "Someone"})
+	purged, _ := s.Ingest(context.Background(), "gopl.pdf", testPDF("/Title (Go)"), book.ID)
+	kept, _ := s.Ingest(context.Background(), "other.pdf", testPDF("/Title (Other)"), other.ID)
+
+	books.Delete(book.ID)
+	// A negative retention purges everything in the trash
+	purger := storage.NewPurger(books, -time.Minute, 0)
+	purger.AddForgetter(s)
+	if n, err := purger.PurgeOnce(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected 1 book purged, got %d (%v)", n, err)
+	}
+
+	if _, err := s.assets.GetAsset(purged.Asset.ID); err != models.ErrAssetNotFound {
+		t.Errorf("expected the asset removed, got %v", err)
+	}
+	if _, err := blobs.Get(key(book.ID, purged.Asset.ID)); err != blob.ErrNotFound {
+		t.Errorf("expected the file removed, got %v", err)
+	}
+	if _, _, err := s.Download(other.ID, kept.Asset.ID); err != nil {
+		t.Errorf("expected the other book's file kept, got %v", err)
+	}
+}
+
+func TestService_MoveBook(t *testing.T) {
+	s, books, blobs := newTestService(t)
+	from, _ := books.Create(models.Book{Title: "Go Programming", Author: "Donovan"})
+	to, _ := books.Create(models.Book{Title: "The Go Programming Language", Author: "Alan A. A. Donovan"})
+	epub := testEPUB(t, goBook, nil)
+
+	shared, _ := s.Ingest(context.Background(), "gopl.epub", epub, from.ID)
+	s.Ingest(context.Background(), "gopl.epub", epub, to.ID)
+	moved, _ := s.Ingest(context.Background(), "gopl.pdf", testPDF("/Title (Go)"), from.ID)
+
+	if err := s.MoveBook(from.ID, to.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if assets, _ := s.Assets(to.ID); len(assets) != 2 || assets[1].ID != moved.Asset.ID {
+		t.Errorf("expected the PDF moved alongside the EPUB, got %+v", assets)
+	}
+	if _, data, err := s.Download(to.ID, moved.Asset.ID); err != nil || len(data) == 0 {
+		t.Errorf("expected the moved file to download, got %v", err)
+	}
+	if _, err := s.assets.GetAsset(shared.Asset.ID); err != models.ErrAssetNotFound {
+		t.Errorf("expected the duplicate EPUB removed, got %v", err)
+	}
+	for _, id := range []int{shared.Asset.ID, moved.Asset.ID} {
+		if _, err := blobs.Get(key(from.ID, id)); err != blob.ErrNotFound {
+			t.Errorf("expected the file of asset %d removed from the old book, got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	leaderLength     = 24
+	directoryEntry   = 12
+	subfieldDelim    = 0x1F
+	fieldTerminator  = 0x1E
+	recordTerminator = 0x1D
+)
+
+// ErrInvalidRecord is returned when binary MARC data is malformed
+var ErrInvalidRecord = errors.New("invalid MARC record")
+
+// Reader reads binary MARC21 records from a stream
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a reader for a stream of binary MARC21 records
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next record, or io.EOF when the stream is exhausted
+func (rd *Reader) Read() (*Record, error) {
+	// Skip whitespace some tools put between records
+	for {
+		b, err := rd.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != '\n' && b[0] != '\r' && b[0] != ' ' {
+			break
+		}
+		rd.r.ReadByte()
+	}
+
+	prefix, err := rd.r.Peek(5)
+	if err != nil {
+		return nil, fmt.Errorf("%w: truncated leader", ErrInvalidRecord)
+	}
+	length, err := strconv.Atoi(string(prefix))
+	if err != nil || length < leaderLength+1 {
+		return nil, fmt.Errorf("%w: bad record length %q", ErrInvalidRecord, prefix)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(rd.r, data); err != nil {
+		return nil, fmt.Errorf("%w: record shorter than declared length", ErrInvalidRecord)
+	}
+	return Unmarshal(data)
+}
+
+// ReadAll reads every binary MARC21 record from r
+func ReadAll(r io.Reader) ([]*Record, error) {
+	rd := NewReader(r)
+	var records []*Record
+	for {
+		rec, err := rd.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// Unmarshal decodes a single binary MARC21 record
+func Unmarshal(data []byte) (*Record, error) {
+	if len(data) < leaderLength+1 || data[len(data)-1] != recordTerminator {
+		return nil, fmt.Errorf("%w: missing record terminator", ErrInvalidRecord)
+	}
+
+	leader := string(data[:leaderLength])
+	base, err := strconv.Atoi(leader[12:17])
+	if err != nil || base <= leaderLength || base > len(data) {
+		return nil, fmt.Errorf("%w: bad base address %q", ErrInvalidRecord, leader[12:17])
+	}
+
+	directory := data[leaderLength : base-1]
+	if data[base-1] != fieldTerminator || len(directory)%directoryEntry != 0 {
+		return nil, fmt.Errorf("%w: malformed directory", ErrInvalidRecord)
+	}
+
+	rec := &Record{Leader: leader}
+	for i := 0; i < len(directory); i += directoryEntry {
+		entry := directory[i : i+directoryEntry]
+		tag := string(entry[:3])
+		length, err1 := strconv.Atoi(string(entry[3:7]))
+		start, err2 := strconv.Atoi(string(entry[7:12]))
+		if err1 != nil || err2 != nil || length < 1 || base+start+length > len(data) {
+			return nil, fmt.Errorf("%w: bad directory entry for tag %s", ErrInvalidRecord, tag)
+		}
+
+		field := data[base+start : base+start+length]
+		if field[len(field)-1] != fieldTerminator {
+			return nil, fmt.Errorf("%w: unterminated field %s", ErrInvalidRecord, tag)
+		}
+		field = field[:len(field)-1]
+
+		if isControlTag(tag) {
+			rec.ControlFields = append(rec.ControlFields, ControlField{Tag: tag, Value: string(field)})
+			continue
+		}
+
+		if len(field) < 2 {
+			return nil, fmt.Errorf("%w: field %s missing indicators", ErrInvalidRecord, tag)
+		}
+		df := DataField{Tag: tag, Ind1: field[0], Ind2: field[1]}
+		for _, sf := range bytes.Split(field[2:], []byte{subfieldDelim}) {
+			if len(sf) == 0 {
+				continue
+			}
+			df.Subfields = append(df.Subfields, Subfield{Code: sf[0], Value: string(sf[1:])})
+		}
+		rec.DataFields = append(rec.DataFields, df)
+	}
+
+	return rec, nil
+}
+
+// Marshal encodes a record in the binary MARC21 format, recomputing the
+// record length and base address in the leader
+func Marshal(rec *Record) ([]byte, error) {
+	var directory, fields bytes.Buffer
+
+	addField := func(tag string, data []byte) error {
+		if len(tag) != 3 {
+			return fmt.Errorf("%w: tag %q must be three characters", ErrInvalidRecord, tag)
+		}
+		length := len(data) + 1
+		if length > 9999 || fields.Len() > 99999 {
+			return fmt.Errorf("%w: field %s too long", ErrInvalidRecord, tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", tag, length, fields.Len())
+		fields.Write(data)
+		fields.WriteByte(fieldTerminator)
+		return nil
+	}
+
+	for _, cf := range rec.ControlFields {
+		if err := addField(cf.Tag, []byte(cf.Value)); err != nil {
+			return nil, err
+		}
+	}
+	for _, df := range rec.DataFields {
+		var data bytes.Buffer
+		data.WriteByte(indicator(df.Ind1))
+		data.WriteByte(indicator(df.Ind2))
+		for _, sf := range df.Subfields {
+			data.WriteByte(subfieldDelim)
+			data.WriteByte(sf.Code)
+			data.WriteString(sf.Value)
+		}
+		if err := addField(df.Tag, data.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	total := base + fields.Len() + 1
+	if total > 99999 {
+		return nil, fmt.Errorf("%w: record exceeds 99999 bytes", ErrInvalidRecord)
+	}
+
+	leader := []byte(normalizeLeader(rec.Leader))
+	copy(leader[0:5], fmt.Sprintf("%05d", total))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	out := make([]byte, 0, total)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, fields.Bytes()...)
+	out = append(out, recordTerminator)
+	return out, nil
+}
+
+// WriteAll writes records to w in the binary MARC21 format
+func WriteAll(w io.Writer, records []*Record) error {
+	for _, rec := range records {
+		data, err := Marshal(rec)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeLeader pads or fills a leader so it is always 24 characters with
+// the fixed values MARC21 requires for indicator and subfield counts
+func normalizeLeader(leader string) string {
+	if len(leader) != leaderLength {
+		leader = defaultLeader
+	}
+	b := []byte(leader)
+	b[10], b[11] = '2', '2'
+	copy(b[20:24], "4500")
+	return string(b)
+}
+
+// indicator maps an unset indicator to the MARC blank
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
@@ -0,0 +1,317 @@
+package marc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/codeforgood-org/golang-book-api/internal/classification"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// defaultLeader describes a new, Unicode-encoded monograph record; the
+// length and base address are filled in when the record is marshaled
+const defaultLeader = "00000nam a2200000 i 4500"
+
+// ToBook maps the bibliographic fields of a record onto a book:
+// 245 title, 100/700 authors, 020 ISBN, 264/260 publisher and year,
+// 041/008 language, 082 Dewey and 050 LC call numbers, 655 genres and
+// 653 tags. Malformed call numbers, genre terms outside the controlled
+// vocabulary and tags a book could not carry are dropped
+func ToBook(rec *Record) models.Book {
+	var book models.Book
+
+	if f := first(rec.Fields("245")); f != nil {
+		title := trimISBD(f.Subfield('a'))
+		if sub := trimISBD(f.Subfield('b')); sub != "" {
+			title += ": " + sub
+		}
+		book.Title = title
+	}
+
+	var authors []string
+	for _, tag := range []string{"100", "700"} {
+		for _, f := range rec.Fields(tag) {
+			if name := personalName(f); name != "" {
+				authors = append(authors, name)
+			}
+		}
+	}
+	book.Author = models.JoinAuthors(authors)
+
+	for _, f := range rec.Fields("020") {
+		if isbn := firstToken(f.Subfield('a')); isbn != "" {
+			book.ISBN = models.NormalizeISBN(isbn)
+			break
+		}
+	}
+
+	publication := publicationField(rec)
+	if publication != nil {
+		book.Publisher = trimISBD(publication.Subfield('b'))
+		book.PublishedYear = parseYear(publication.Subfield('c'))
+	}
+
+	fixed := rec.ControlField("008")
+	if book.PublishedYear == 0 && len(fixed) >= 11 {
+		book.PublishedYear = parseYear(fixed[7:11])
+	}
+
+	if f := first(rec.Fields("041")); f != nil {
+		book.Language = f.Subfield('a')
+	}
+	if book.Language == "" && len(fixed) >= 38 {
+		// "und" marks an undetermined language
+		if lang := strings.TrimSpace(fixed[35:38]); lang != "und" {
+			book.Language = lang
+		}
+	}
+
+	book.Dewey = callNumber(rec, "082", classification.Dewey)
+	book.LCC = callNumber(rec, "050", classification.LCC)
+
+	var genres, tags []string
+	for _, f := range rec.Fields("655") {
+		genres = append(genres, trimISBD(f.Subfield('a')))
+	}
+	for _, genre := range models.NormalizeLabels(genres) {
+		if models.ValidGenre(genre) {
+			book.Genres = append(book.Genres, genre)
+		}
+	}
+	for _, f := range rec.Fields("653") {
+		tags = append(tags, trimISBD(f.Subfield('a')))
+	}
+	for _, tag := range models.NormalizeLabels(tags) {
+		if len(book.Tags) < models.MaxTags && utf8.RuneCountInString(tag) <= models.MaxTagLength && !strings.Contains(tag, ",") {
+			book.Tags = append(book.Tags, tag)
+		}
+	}
+
+	return book
+}
+
+// FromBook builds a MARC21 record describing a book. The book ID is written
+// to the 001 control number.
+func FromBook(book models.Book) *Record {
+	rec := &Record{Leader: defaultLeader}
+
+	if book.ID != 0 {
+		rec.ControlFields = append(rec.ControlFields, ControlField{Tag: "001", Value: strconv.Itoa(book.ID)})
+	}
+	rec.ControlFields = append(rec.ControlFields, ControlField{Tag: "008", Value: fixedField(book)})
+
+	if book.ISBN != "" {
+		rec.DataFields = append(rec.DataFields, DataField{
+			Tag: "020", Ind1: ' ', Ind2: ' ',
+			Subfields: []Subfield{{Code: 'a', Value: models.NormalizeISBN(book.ISBN)}},
+		})
+	}
+	if book.Language != "" {
+		rec.DataFields = append(rec.DataFields, DataField{
+			Tag: "041", Ind1: '0', Ind2: ' ',
+			Subfields: []Subfield{{Code: 'a', Value: book.Language}},
+		})
+	}
+	// Call numbers are split into the class number and the item number;
+	// second indicator 4 marks them as assigned by this library
+	if book.LCC != "" {
+		rec.DataFields = append(rec.DataFields, callNumberField("050", ' ', book.LCC))
+	}
+	if book.Dewey != "" {
+		rec.DataFields = append(rec.DataFields, callNumberField("082", '0', book.Dewey))
+	}
+
+	authors := book.Authors()
+	for i, name := range authors {
+		tag := "700"
+		if i == 0 {
+			tag = "100"
+		}
+		inverted, ind1 := invertName(name)
+		rec.DataFields = append(rec.DataFields, DataField{
+			Tag: tag, Ind1: ind1, Ind2: ' ',
+			Subfields: []Subfield{{Code: 'a', Value: inverted}},
+		})
+	}
+
+	title := DataField{Tag: "245", Ind1: '0', Ind2: '0'}
+	if len(authors) > 0 {
+		title.Ind1 = '1'
+	}
+	if main, sub, ok := strings.Cut(book.Title, ": "); ok {
+		title.Subfields = []Subfield{{Code: 'a', Value: main}, {Code: 'b', Value: sub}}
+	} else {
+		title.Subfields = []Subfield{{Code: 'a', Value: book.Title}}
+	}
+	rec.DataFields = append(rec.DataFields, title)
+
+	if book.Publisher != "" || book.PublishedYear != 0 {
+		pub := DataField{Tag: "264", Ind1: ' ', Ind2: '1'}
+		if book.Publisher != "" {
+			pub.Subfields = append(pub.Subfields, Subfield{Code: 'b', Value: book.Publisher})
+		}
+		if book.PublishedYear != 0 {
+			pub.Subfields = append(pub.Subfields, Subfield{Code: 'c', Value: strconv.Itoa(book.PublishedYear)})
+		}
+		rec.DataFields = append(rec.DataFields, pub)
+	}
+
+	for _, tag := range book.Tags {
+		rec.DataFields = append(rec.DataFields, DataField{
+			Tag: "653", Ind1: ' ', Ind2: ' ',
+			Subfields: []Subfield{{Code: 'a', Value: tag}},
+		})
+	}
+	// Second indicator 7 names the source of the genre term in subfield 2
+	for _, genre := range book.Genres {
+		rec.DataFields = append(rec.DataFields, DataField{
+			Tag: "655", Ind1: ' ', Ind2: '7',
+			Subfields: []Subfield{{Code: 'a', Value: genre}, {Code: '2', Value: "local"}},
+		})
+	}
+
+	return rec
+}
+
+// callNumber returns the first call number in the given field that is valid
+// in scheme, joining the class number in subfield a with the item number in
+// subfield b and dropping the slashes that mark Dewey segments
+func callNumber(rec *Record, tag string, scheme classification.Scheme) string {
+	for _, f := range rec.Fields(tag) {
+		s := classification.Normalize(strings.ReplaceAll(f.Subfield('a')+" "+f.Subfield('b'), "/", ""))
+		if _, err := classification.Parse(scheme, s); err == nil {
+			return s
+		}
+	}
+	return ""
+}
+
+// callNumberField builds a 050 or 082 field from a call number, putting its
+// first word in subfield a and any others in subfield b
+func callNumberField(tag string, ind1 byte, callNumber string) DataField {
+	class, item, _ := strings.Cut(callNumber, " ")
+	f := DataField{Tag: tag, Ind1: ind1, Ind2: '4', Subfields: []Subfield{{Code: 'a', Value: class}}}
+	if item != "" {
+		f.Subfields = append(f.Subfields, Subfield{Code: 'b', Value: item})
+	}
+	return f
+}
+
+// publicationField returns the RDA publication statement (264 with second
+// indicator 1), falling back to the older 260 field
+func publicationField(rec *Record) *DataField {
+	for _, f := range rec.Fields("264") {
+		if f.Ind2 == '1' {
+			return &f
+		}
+	}
+	return first(rec.Fields("260"))
+}
+
+// fixedField builds the 40-character 008 field, carrying the publication
+// year and language
+func fixedField(book models.Book) string {
+	b := []byte(strings.Repeat(" ", 40))
+	copy(b[0:6], time.Now().UTC().Format("060102"))
+	b[6] = 'n'
+	copy(b[7:11], "uuuu")
+	if book.PublishedYear > 0 && book.PublishedYear <= 9999 {
+		b[6] = 's'
+		copy(b[7:11], fmt.Sprintf("%04d", book.PublishedYear))
+	}
+	copy(b[11:15], "    ")
+	copy(b[35:38], "und")
+	if len(book.Language) == 3 {
+		copy(b[35:38], book.Language)
+	}
+	b[39] = 'd'
+	return string(b)
+}
+
+// personalName returns a name from a 100/700 field in direct order
+func personalName(f DataField) string {
+	name := strings.TrimRight(strings.TrimSpace(f.Subfield('a')), ",")
+	if f.Ind1 != '1' {
+		return name
+	}
+	surname, forename, ok := strings.Cut(name, ", ")
+	if !ok {
+		return name
+	}
+	return strings.TrimSpace(forename) + " " + strings.TrimSpace(surname)
+}
+
+// invertName converts a direct-order name into the "Surname, Forename" form
+// used in MARC headings and returns the matching first indicator
+func invertName(name string) (string, byte) {
+	if strings.Contains(name, ",") {
+		return name, '1'
+	}
+	i := strings.LastIndex(name, " ")
+	if i < 0 {
+		return name, '0'
+	}
+	return name[i+1:] + ", " + name[:i], '1'
+}
+
+// trimISBD removes the trailing ISBD punctuation (" /", " :", ".", etc.)
+// that catalogers append to subfields
+func trimISBD(s string) string {
+	s = strings.TrimSpace(s)
+	for {
+		trimmed := strings.TrimRight(s, " /:;,=")
+		if strings.HasSuffix(trimmed, ".") && !endsWithInitial(trimmed) {
+			trimmed = strings.TrimSuffix(trimmed, ".")
+		}
+		if trimmed == s {
+			return s
+		}
+		s = trimmed
+	}
+}
+
+// endsWithInitial reports whether s ends with an abbreviation such as "Inc."
+// or "C." whose full stop must be preserved
+func endsWithInitial(s string) bool {
+	word := s[strings.LastIndex(s, " ")+1:]
+	word = strings.TrimSuffix(word, ".")
+	return len(word) == 1 || word == "Inc" || word == "Co" || word == "Ltd"
+}
+
+// parseYear extracts the first four-digit year from a date statement such
+// as "[2015]" or "©2015."
+func parseYear(s string) int {
+	digits := 0
+	for i, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+			if digits == 4 {
+				year, _ := strconv.Atoi(s[i-3 : i+1])
+				return year
+			}
+			continue
+		}
+		digits = 0
+	}
+	return 0
+}
+
+// firstToken returns the first whitespace-separated token of s, dropping
+// qualifiers such as "(paperback)"
+func firstToken(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+func first(fields []DataField) *DataField {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &fields[0]
+}
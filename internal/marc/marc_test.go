@@ -0,0 +1,210 @@
+package marc
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func readSample(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("failed to read sample: %v", err)
+	}
+	return data
+}
+
+func TestReadAll_Binary(t *testing.T) {
+	records, err := ReadAll(bytes.NewReader(readSample(t, "sample.mrc")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	if got := records[0].ControlField("001"); got != "ocn915135233" {
+		t.Errorf("expected control number ocn915135233, got %s", got)
+	}
+
+	title := records[0].Fields("245")
+	if len(title) != 1 || title[0].Subfield('a') != "The Go programming language /" {
+		t.Errorf("unexpected 245 field: %+v", title)
+	}
+	if title[0].Ind1 != '1' || title[0].Ind2 != '4' {
+		t.Errorf("expected indicators 1 and 4, got %q and %q", title[0].Ind1, title[0].Ind2)
+	}
+}
+
+func TestBinary_RoundTrip(t *testing.T) {
+	sample := readSample(t, "sample.mrc")
+
+	records, err := ReadAll(bytes.NewReader(sample))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAll(&buf, records); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), sample) {
+		t.Errorf("round-tripped binary differs from sample:\n got %q\nwant %q", buf.Bytes(), sample)
+	}
+}
+
+func TestXML_MatchesBinary(t *testing.T) {
+	fromBinary, err := ReadAll(bytes.NewReader(readSample(t, "sample.mrc")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	fromXML, err := ReadXML(bytes.NewReader(readSample(t, "sample.xml")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(fromBinary, fromXML) {
+		t.Errorf("MARCXML and binary samples decode differently:\n%+v\n%+v", fromXML, fromBinary)
+	}
+}
+
+func TestXML_RoundTrip(t *testing.T) {
+	records, err := ReadXML(bytes.NewReader(readSample(t, "sample.xml")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteXML(&buf, records); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	again, err := ReadXML(&buf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(records, again) {
+		t.Errorf("round-tripped records differ:\n%+v\n%+v", again, records)
+	}
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "no terminator", data: []byte("00026nam a2200025 i 4500\x1e")},
+		{name: "bad base address", data: []byte("00026nam a22ABCDE i 4500\x1e\x1d")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Unmarshal(tt.data); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestToBook(t *testing.T) {
+	records, err := ReadAll(bytes.NewReader(readSample(t, "sample.mrc")))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []models.Book{
+		{
+			Title:         "The Go programming language",
+			Author:        "Alan A. A. Donovan; Brian W. Kernighan",
+			ISBN:          "9780134190440",
+			Publisher:     "Addison-Wesley",
+			PublishedYear: 2016,
+			Language:      "eng",
+		},
+		{
+			Title:         "Clean code: a handbook of agile software craftsmanship",
+			Author:        "Robert C. Martin",
+			ISBN:          "0132350882",
+			Publisher:     "Prentice Hall",
+			PublishedYear: 2009,
+			Language:      "eng",
+		},
+	}
+
+	for i, rec := range records {
+		if got := ToBook(rec); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("ToBook() = %+v, want %+v", got, want[i])
+		}
+	}
+}
+
+func TestFromBook_RoundTrip(t *testing.T) {
+	books := []models.Book{
+		{
+			ID:            42,
+			Title:         "Clean Code: A Handbook of Agile Software Craftsmanship",
+			Author:        "Robert C. Martin",
+			ISBN:          "978-0-13-235088-4",
+			Publisher:     "Prentice Hall",
+			PublishedYear: 2008,
+			Language:      "eng",
+			Dewey:         "005.1 M37",
+			LCC:           "QA76.76.D47 M37 2008",
+			Genres:        []string{"computing"},
+			Tags:          []string{"software engineering", "best practices"},
+		},
+		{
+			Title:  "Design Patterns",
+			Author: "Erich Gamma, Richard Helm, Ralph Johnson, John Vlissides",
+		},
+		{
+			Title:  "Collected Poems",
+			Author: "Homer",
+		},
+	}
+
+	for _, book := range books {
+		t.Run(book.Title, func(t *testing.T) {
+			data, err := Marshal(FromBook(book))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			rec, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			got := ToBook(rec)
+			if got.Title != book.Title {
+				t.Errorf("expected title %q, got %q", book.Title, got.Title)
+			}
+			if !reflect.DeepEqual(got.Authors(), book.Authors()) {
+				t.Errorf("expected authors %q, got %q", book.Authors(), got.Authors())
+			}
+			if got.ISBN != models.NormalizeISBN(book.ISBN) {
+				t.Errorf("expected ISBN %q, got %q", models.NormalizeISBN(book.ISBN), got.ISBN)
+			}
+			if got.Publisher != book.Publisher || got.PublishedYear != book.PublishedYear {
+				t.Errorf("expected publisher %q (%d), got %q (%d)",
+					book.Publisher, book.PublishedYear, got.Publisher, got.PublishedYear)
+			}
+			if got.Dewey != book.Dewey || got.LCC != book.LCC {
+				t.Errorf("expected call numbers %q and %q, got %q and %q", book.Dewey, book.LCC, got.Dewey, got.LCC)
+			}
+			if !reflect.DeepEqual(got.Genres, book.Genres) || !reflect.DeepEqual(got.Tags, book.Tags) {
+				t.Errorf("expected genres %q and tags %q, got %q and %q", book.Genres, book.Tags, got.Genres, got.Tags)
+			}
+			if book.ID != 0 && rec.ControlField("001") != "42" {
+				t.Errorf("expected control number 42, got %q", rec.ControlField("001"))
+			}
+		})
+	}
+}
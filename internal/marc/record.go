@@ -0,0 +1,69 @@
+// Package marc reads and writes MARC21 bibliographic records in the ISO 2709
+// binary transmission format and in MARCXML, and converts them to and from
+// models.Book.
+package marc
+
+import "strings"
+
+// Record is a single MARC21 bibliographic record
+type Record struct {
+	Leader        string
+	ControlFields []ControlField
+	DataFields    []DataField
+}
+
+// ControlField is a variable control field (tags 001-009)
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+// DataField is a variable data field with indicators and subfields
+type DataField struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+// Subfield is a coded subfield of a data field
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// ControlField returns the value of the first control field with the given tag
+func (r *Record) ControlField(tag string) string {
+	for _, f := range r.ControlFields {
+		if f.Tag == tag {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Fields returns all data fields with the given tag
+func (r *Record) Fields(tag string) []DataField {
+	var fields []DataField
+	for _, f := range r.DataFields {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Subfield returns the value of the first subfield with the given code
+func (f DataField) Subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+// isControlTag reports whether tag denotes a control field
+func isControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
@@ -0,0 +1,127 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Namespace is the MARCXML schema namespace
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlCollection struct {
+	XMLName xml.Name    `xml:"collection"`
+	Xmlns   string      `xml:"xmlns,attr,omitempty"`
+	Records []xmlRecord `xml:"record"`
+}
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ReadXML reads MARCXML records from r. Both a <collection> root and a single
+// <record> root are accepted.
+func ReadXML(r io.Reader) ([]*Record, error) {
+	dec := xml.NewDecoder(r)
+	var records []*Record
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+		var xr xmlRecord
+		if err := dec.DecodeElement(&xr, &start); err != nil {
+			return records, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+		records = append(records, xr.toRecord())
+	}
+}
+
+// WriteXML writes records to w as a MARCXML collection
+func WriteXML(w io.Writer, records []*Record) error {
+	coll := xmlCollection{Xmlns: Namespace}
+	for _, rec := range records {
+		coll.Records = append(coll.Records, fromRecord(rec))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(coll); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func (xr xmlRecord) toRecord() *Record {
+	rec := &Record{Leader: xr.Leader}
+	for _, cf := range xr.ControlFields {
+		rec.ControlFields = append(rec.ControlFields, ControlField{Tag: cf.Tag, Value: cf.Value})
+	}
+	for _, xdf := range xr.DataFields {
+		df := DataField{Tag: xdf.Tag, Ind1: firstByte(xdf.Ind1), Ind2: firstByte(xdf.Ind2)}
+		for _, sf := range xdf.Subfields {
+			df.Subfields = append(df.Subfields, Subfield{Code: firstByte(sf.Code), Value: sf.Value})
+		}
+		rec.DataFields = append(rec.DataFields, df)
+	}
+	return rec
+}
+
+func fromRecord(rec *Record) xmlRecord {
+	xr := xmlRecord{Leader: normalizeLeader(rec.Leader)}
+	for _, cf := range rec.ControlFields {
+		xr.ControlFields = append(xr.ControlFields, xmlControlField{Tag: cf.Tag, Value: cf.Value})
+	}
+	for _, df := range rec.DataFields {
+		xdf := xmlDataField{
+			Tag:  df.Tag,
+			Ind1: string(indicator(df.Ind1)),
+			Ind2: string(indicator(df.Ind2)),
+		}
+		for _, sf := range df.Subfields {
+			xdf.Subfields = append(xdf.Subfields, xmlSubfield{Code: string(sf.Code), Value: sf.Value})
+		}
+		xr.DataFields = append(xr.DataFields, xdf)
+	}
+	return xr
+}
+
+// firstByte returns the first byte of s, or a MARC blank when s is empty
+func firstByte(s string) byte {
+	if s == "" {
+		return ' '
+	}
+	return s[0]
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+)
+
+// ActorHeader names the request header identifying who is making a change
+const ActorHeader = "X-Actor"
+
+// Audit middleware attributes the storage writes made while handling a
+// request to the actor in the X-Actor header and the request ID. It must run
+// inside RequestID
+func Audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.NewContext(r.Context(), audit.Source{
+			Actor:     strings.TrimSpace(r.Header.Get(ActorHeader)),
+			RequestID: GetRequestID(r.Context()),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
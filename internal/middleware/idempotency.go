@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/idempotency"
+)
+
+// Idempotency header names
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key accepted
+const maxIdempotencyKeyLength = 255
+
+// DefaultMaxIdempotentBodyBytes is the largest body buffered for hashing in
+// an idempotent request when no larger limit is given
+const DefaultMaxIdempotentBodyBytes = 32 << 20
+
+// captureWriter passes a response through while keeping a copy of it
+type captureWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (cw *captureWriter) WriteHeader(code int) {
+	if cw.statusCode == 0 {
+		cw.statusCode = code
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *captureWriter) Write(b []byte) (int, error) {
+	if cw.statusCode == 0 {
+		cw.statusCode = http.StatusOK
+	}
+	cw.body.Write(b)
+	return cw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (cw *captureWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Idempotency middleware makes POST requests carrying an Idempotency-Key
+// header safe to retry. The first response for a key is stored per client,
+// identified by the X-Actor header or else the remote address, and replayed
+// for retries with the same method, target and body. Reusing a key for a
+// different request is rejected with 422, and a retry arriving while the
+// first request is still running with 409. Server errors are not stored so
+// the request can be retried. Bodies are buffered for hashing, up to
+// maxBodyBytes or DefaultMaxIdempotentBodyBytes if that is larger, and
+// larger ones are rejected with 413
+func Idempotency(store *idempotency.Store, maxBodyBytes int) func(http.Handler) http.Handler {
+	maxBodyBytes = max(maxBodyBytes, DefaultMaxIdempotentBodyBytes)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				writeJSONError(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, int64(maxBodyBytes)+1))
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, "Failed to read request body")
+				return
+			}
+			if len(body) > maxBodyBytes {
+				writeJSONError(w, http.StatusRequestEntityTooLarge, "Request body too large for an idempotent request")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			client := idempotencyClient(r)
+			resp, err := store.Begin(client, key, idempotency.Hash(r.Method, r.URL.RequestURI(), body))
+			switch err {
+			case idempotency.ErrMismatch:
+				writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+				return
+			case idempotency.ErrInProgress:
+				writeJSONError(w, http.StatusConflict, err.Error())
+				return
+			}
+			if resp != nil {
+				for name, values := range resp.Header {
+					w.Header()[name] = values
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(resp.StatusCode)
+				w.Write(resp.Body)
+				return
+			}
+
+			cw := &captureWriter{ResponseWriter: w}
+			defer func() {
+				if cw.statusCode == 0 || cw.statusCode >= http.StatusInternalServerError {
+					store.Release(client, key)
+					return
+				}
+				header := cw.Header().Clone()
+				header.Del("X-Request-ID")
+				store.Complete(client, key, idempotency.Response{
+					StatusCode: cw.statusCode,
+					Header:     header,
+					Body:       cw.body.Bytes(),
+				})
+			}()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// idempotencyClient identifies who sent a request, so that different clients
+// cannot collide on the same key
+func idempotencyClient(r *http.Request) string {
+	if actor := strings.TrimSpace(r.Header.Get(ActorHeader)); actor != "" {
+		return "actor:" + actor
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "addr:" + host
+}
+
+// writeJSONError writes an error response in the API's JSON error format
+func writeJSONError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/idempotency"
+)
+
+func TestIdempotency(t *testing.T) {
+	calls := 0
+	handler := Idempotency(idempotency.NewStore(time.Hour), 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":%d}`, calls)
+	}))
+
+	send := func(key, actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		if actor != "" {
+			req.Header.Set(ActorHeader, actor)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name           string
+		key            string
+		actor          string
+		body           string
+		expectedStatus int
+		expectedBody   string
+		replayed       bool
+	}{
+		{"first request", "key-1", "alice", `{"title":"Go"}`, http.StatusCreated, `{"id":1}`, false},
+		{"retry is replayed", "key-1", "alice", `{"title":"Go"}`, http.StatusCreated, `{"id":1}`, true},
+		{"different body", "key-1", "alice", `{"title":"Rust"}`, http.StatusUnprocessableEntity, "", false},
+		{"another client", "key-1", "bob", `{"title":"Go"}`, http.StatusCreated, `{"id":2}`, false},
+		{"no key", "", "alice", `{"title":"Go"}`, http.StatusCreated, `{"id":3}`, false},
+		{"key too long", strings.Repeat("k", 256), "alice", `{}`, http.StatusBadRequest, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(tt.key, tt.actor, tt.body)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedBody != "" && w.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %s, got %s", tt.expectedBody, w.Body.String())
+			}
+			if replayed := w.Header().Get(IdempotentReplayedHeader) == "true"; replayed != tt.replayed {
+				t.Errorf("expected replayed %v, got %v", tt.replayed, replayed)
+			}
+		})
+	}
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	fail := true
+	handler := Idempotency(idempotency.NewStore(time.Hour), 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for _, expected := range []int{http.StatusInternalServerError, http.StatusCreated} {
+		req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != expected {
+			t.Errorf("expected status %d, got %d", expected, w.Code)
+		}
+		fail = false
+	}
+}
+
+func TestIdempotency_BodyLimit(t *testing.T) {
+	const limit = DefaultMaxIdempotentBodyBytes + 1<<20
+	handler := Idempotency(idempotency.NewStore(time.Hour), limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	tests := []struct {
+		name     string
+		size     int
+		expected int
+	}{
+		{"over the default", DefaultMaxIdempotentBodyBytes + 1, http.StatusCreated},
+		{"at the limit", limit, http.StatusCreated},
+		{"over the limit", limit + 1, http.StatusRequestEntityTooLarge},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/books/import", strings.NewReader(strings.Repeat("a", tt.size)))
+			req.Header.Set(IdempotencyKeyHeader, fmt.Sprintf("key-%d", i))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, w.Code)
+			}
+		})
+	}
+}
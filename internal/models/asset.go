@@ -0,0 +1,33 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Asset is a file attached to a book for download, such as an EPUB or PDF
+// edition. URL is relative to the API's base URL
+type Asset struct {
+	ID          int       `json:"id"`
+	BookID      int       `json:"book_id"`
+	Filename    string    `json:"filename"`
+	Format      string    `json:"format"`
+	ContentType string    `json:"content_type"`
+	Bytes       int       `json:"bytes"`
+	SHA256      string    `json:"sha256"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ETag returns the entity tag of the asset's content
+func (a *Asset) ETag() string {
+	if len(a.SHA256) < 32 {
+		return `"` + a.SHA256 + `"`
+	}
+	return `"` + a.SHA256[:32] + `"`
+}
+
+// Path returns the path the asset is downloaded from
+func (a *Asset) Path() string {
+	return fmt.Sprintf("/books/%d/assets/%d", a.BookID, a.ID)
+}
@@ -1,13 +1,50 @@
 package models
 
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/codeforgood-org/golang-book-api/internal/classification"
+)
+
 // Book represents a book in the library
 type Book struct {
-	ID     int    `json:"id"`
-	Title  string `json:"title"`
-	Author string `json:"author"`
+	ID            int    `json:"id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	ISBN          string `json:"isbn,omitempty"`
+	Publisher     string `json:"publisher,omitempty"`
+	PublishedYear int    `json:"published_year,omitempty"`
+	Language      string `json:"language,omitempty"`
+	// Genres come from the controlled vocabulary in Genres; Tags are free
+	// form. Both are stored normalized by NormalizeLabels
+	Genres []string `json:"genres,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	// Dewey and LCC are the book's Dewey Decimal and Library of Congress
+	// call numbers, such as 005.133 D66 and QA76.73.G63 D66
+	Dewey string `json:"dewey,omitempty"`
+	LCC   string `json:"lcc,omitempty"`
+	// CreatedAt is when the book was added to the catalogue. It is set by
+	// the storage, and writes to the book leave it unchanged
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	// DeletedAt is set while the book is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// AverageRating and RatingCount summarize the approved reviews of the
+	// book. They are kept up to date by the reviews service, and writes to
+	// the book leave them unchanged
+	AverageRating float64 `json:"average_rating,omitempty"`
+	RatingCount   int     `json:"rating_count,omitempty"`
+	// WorkID links the book to the work it is an edition of. It is managed
+	// through the work, and writes to the book leave it unchanged
+	WorkID int `json:"work_id,omitempty"`
+	// Cover is set once a cover image has been uploaded. Like the rating, it
+	// is kept up to date by its own service and book writes leave it alone
+	Cover *Cover `json:"cover,omitempty"`
 }
 
-// Validate checks if the book data is valid
+// Validate checks if the book data is valid, normalizing its genres, tags
+// and call numbers
 func (b *Book) Validate() error {
 	if b.Title == "" {
 		return ErrInvalidTitle
@@ -15,5 +52,168 @@ func (b *Book) Validate() error {
 	if b.Author == "" {
 		return ErrInvalidAuthor
 	}
+	if b.ISBN != "" && !ValidISBN(b.ISBN) {
+		return ErrInvalidISBN
+	}
+	if b.PublishedYear < 0 {
+		return ErrInvalidYear
+	}
+
+	b.Dewey = classification.Normalize(b.Dewey)
+	if _, err := classification.Parse(classification.Dewey, b.Dewey); b.Dewey != "" && err != nil {
+		return ErrInvalidDewey
+	}
+	b.LCC = classification.Normalize(b.LCC)
+	if _, err := classification.Parse(classification.LCC, b.LCC); b.LCC != "" && err != nil {
+		return ErrInvalidLCC
+	}
+
+	b.Genres = NormalizeLabels(b.Genres)
+	for _, genre := range b.Genres {
+		if !ValidGenre(genre) {
+			return ErrInvalidGenre
+		}
+	}
+	b.Tags = NormalizeLabels(b.Tags)
+	if len(b.Tags) > MaxTags {
+		return ErrTooManyTags
+	}
+	for _, tag := range b.Tags {
+		if utf8.RuneCountInString(tag) > MaxTagLength || strings.Contains(tag, ",") {
+			return ErrInvalidTag
+		}
+	}
 	return nil
 }
+
+// CallNumber returns the book's call number in a classification scheme
+func (b Book) CallNumber(scheme classification.Scheme) string {
+	switch scheme {
+	case classification.Dewey:
+		return b.Dewey
+	case classification.LCC:
+		return b.LCC
+	default:
+		return ""
+	}
+}
+
+// Authors splits the author field into individual names. Names may be
+// separated by semicolons, by " and ", or by commas when every
+// comma-separated part is a full name (so "Martin, Robert C." stays whole).
+func (b Book) Authors() []string {
+	var names []string
+	for _, part := range strings.Split(b.Author, ";") {
+		for _, name := range strings.Split(part, " and ") {
+			names = append(names, splitCommaNames(name)...)
+		}
+	}
+
+	authors := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			authors = append(authors, name)
+		}
+	}
+	return authors
+}
+
+// splitCommaNames splits a comma-separated list of full names, leaving
+// inverted "Surname, Forename" names intact
+func splitCommaNames(s string) []string {
+	parts := strings.Split(s, ",")
+	if len(parts) == 1 {
+		return parts
+	}
+	for _, part := range parts {
+		if !strings.Contains(strings.TrimSpace(part), " ") {
+			return []string{s}
+		}
+	}
+	return parts
+}
+
+// JoinAuthors combines individual author names into a single author field
+func JoinAuthors(names []string) string {
+	return strings.Join(names, "; ")
+}
+
+// NormalizeISBN strips hyphens and spaces from an ISBN and upper-cases the
+// ISBN-10 check character
+func NormalizeISBN(isbn string) string {
+	var sb strings.Builder
+	for _, r := range isbn {
+		switch {
+		case r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		case r == 'x' || r == 'X':
+			sb.WriteRune('X')
+		case r == '-' || r == ' ':
+		default:
+			return isbn
+		}
+	}
+	return sb.String()
+}
+
+// ValidISBN reports whether isbn is a well-formed ISBN-10 or ISBN-13 with a
+// correct check digit
+func ValidISBN(isbn string) bool {
+	isbn = NormalizeISBN(isbn)
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i, r := range isbn {
+			var digit int
+			switch {
+			case r >= '0' && r <= '9':
+				digit = int(r - '0')
+			case r == 'X' && i == 9:
+				digit = 10
+			default:
+				return false
+			}
+			sum += (10 - i) * digit
+		}
+		return sum%11 == 0
+	case 13:
+		sum := 0
+		for i, r := range isbn {
+			if r < '0' || r > '9' {
+				return false
+			}
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += weight * int(r-'0')
+		}
+		return sum%10 == 0
+	default:
+		return false
+	}
+}
+
+// ISBN13 returns the normalized ISBN-13 form of a valid ISBN, converting
+// ISBN-10s, so that both forms of the same ISBN compare equal. It returns
+// "" for invalid ISBNs
+func ISBN13(isbn string) string {
+	if !ValidISBN(isbn) {
+		return ""
+	}
+	isbn = NormalizeISBN(isbn)
+	if len(isbn) == 13 {
+		return isbn
+	}
+
+	isbn = "978" + isbn[:9]
+	sum := 0
+	for i, r := range isbn {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return isbn + string(rune('0'+(10-sum%10)%10))
+}
@@ -1,6 +1,10 @@
 package models
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func TestBook_Validate(t *testing.T) {
 	tests := []struct {
@@ -32,6 +36,107 @@ func TestBook_Validate(t *testing.T) {
 			},
 			wantErr: ErrInvalidAuthor,
 		},
+		{
+			name: "valid ISBN-13",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				ISBN:   "978-0-13-235088-4",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid ISBN checksum",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				ISBN:   "9780132350885",
+			},
+			wantErr: ErrInvalidISBN,
+		},
+		{
+			name: "negative year",
+			book: Book{
+				Title:         "Test Book",
+				Author:        "Test Author",
+				PublishedYear: -1,
+			},
+			wantErr: ErrInvalidYear,
+		},
+		{
+			name: "call numbers",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				Dewey:  "005.133 D66",
+				LCC:    "QA76.73.G63 D66 2016",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid Dewey call number",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				Dewey:  "QA76.73",
+			},
+			wantErr: ErrInvalidDewey,
+		},
+		{
+			name: "invalid LCC call number",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				LCC:    "005.133",
+			},
+			wantErr: ErrInvalidLCC,
+		},
+		{
+			name: "genres and tags",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				Genres: []string{"Fiction", "mystery"},
+				Tags:   []string{"cozy", " Cozy ", "whodunit"},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "unknown genre",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				Genres: []string{"cozy-mystery"},
+			},
+			wantErr: ErrInvalidGenre,
+		},
+		{
+			name: "tag with a comma",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				Tags:   []string{"cozy, whodunit"},
+			},
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name: "tag too long",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				Tags:   []string{strings.Repeat("a", MaxTagLength+1)},
+			},
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name: "too many tags",
+			book: Book{
+				Title:  "Test Book",
+				Author: "Test Author",
+				Tags:   strings.Fields("a b c d e f g h i j k l m n o p q r s t u"),
+			},
+			wantErr: ErrTooManyTags,
+		},
 		{
 			name: "missing both",
 			book: Book{
@@ -51,3 +156,95 @@ func TestBook_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestBook_ValidateNormalizesLabels(t *testing.T) {
+	book := Book{Title: "Test Book", Author: "Test Author", Genres: []string{" Fiction", ""}, Tags: []string{"Cozy", "cozy "}}
+	if err := book.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(book.Genres, []string{"fiction"}) || !reflect.DeepEqual(book.Tags, []string{"cozy"}) {
+		t.Errorf("expected genres [fiction] and tags [cozy], got %q and %q", book.Genres, book.Tags)
+	}
+
+	book.Genres, book.Tags = []string{" "}, []string{}
+	book.Validate()
+	if book.Genres != nil || book.Tags != nil {
+		t.Errorf("expected no genres or tags, got %q and %q", book.Genres, book.Tags)
+	}
+}
+
+func TestValidISBN(t *testing.T) {
+	tests := []struct {
+		isbn string
+		want bool
+	}{
+		{isbn: "0132350882", want: true},
+		{isbn: "0-201-63361-2", want: true},
+		{isbn: "080442957X", want: true},
+		{isbn: "9780134190440", want: true},
+		{isbn: "978-0-13-419044-0", want: true},
+		{isbn: "0132350883", want: false},
+		{isbn: "9780134190441", want: false},
+		{isbn: "12345", want: false},
+		{isbn: "X132350882", want: false},
+		{isbn: "isbn", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.isbn, func(t *testing.T) {
+			if got := ValidISBN(tt.isbn); got != tt.want {
+				t.Errorf("ValidISBN(%q) = %v, want %v", tt.isbn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestISBN13(t *testing.T) {
+	tests := []struct {
+		isbn string
+		want string
+	}{
+		{isbn: "0132350882", want: "9780132350884"},
+		{isbn: "0-201-63361-2", want: "9780201633610"},
+		{isbn: "080442957X", want: "9780804429573"},
+		{isbn: "978-0-13-419044-0", want: "9780134190440"},
+		{isbn: "0132350883", want: ""},
+		{isbn: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.isbn, func(t *testing.T) {
+			if got := ISBN13(tt.isbn); got != tt.want {
+				t.Errorf("ISBN13(%q) = %q, want %q", tt.isbn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBook_Authors(t *testing.T) {
+	tests := []struct {
+		author string
+		want   []string
+	}{
+		{author: "Robert C. Martin", want: []string{"Robert C. Martin"}},
+		{author: "Martin, Robert C.", want: []string{"Martin, Robert C."}},
+		{author: "Andrew Hunt and David Thomas", want: []string{"Andrew Hunt", "David Thomas"}},
+		{author: "Erich Gamma, Richard Helm, Ralph Johnson", want: []string{"Erich Gamma", "Richard Helm", "Ralph Johnson"}},
+		{author: "Donovan, Alan; Kernighan, Brian", want: []string{"Donovan, Alan", "Kernighan, Brian"}},
+		{author: "", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.author, func(t *testing.T) {
+			got := Book{Author: tt.author}.Authors()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Authors() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Authors() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
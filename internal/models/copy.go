@@ -0,0 +1,91 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// CopyStatus is where a physical copy of a book is in circulation
+type CopyStatus string
+
+// Copy statuses
+const (
+	CopyAvailable CopyStatus = "available"
+	CopyOnLoan    CopyStatus = "on_loan"
+	CopyOnHold    CopyStatus = "on_hold"
+	CopyLost      CopyStatus = "lost"
+	CopyInRepair  CopyStatus = "in_repair"
+)
+
+// CopyStatuses lists the valid copy statuses
+var CopyStatuses = []CopyStatus{CopyAvailable, CopyOnLoan, CopyOnHold, CopyLost, CopyInRepair}
+
+// Condition describes the physical state of a copy
+type Condition string
+
+// Copy conditions
+const (
+	ConditionNew  Condition = "new"
+	ConditionGood Condition = "good"
+	ConditionFair Condition = "fair"
+	ConditionPoor Condition = "poor"
+)
+
+// Conditions lists the valid copy conditions
+var Conditions = []Condition{ConditionNew, ConditionGood, ConditionFair, ConditionPoor}
+
+// Copy is a physical item of a book that can be lent. The book holds the
+// bibliographic record; each copy has its own barcode, branch and status
+type Copy struct {
+	ID        int        `json:"id"`
+	BookID    int        `json:"book_id"`
+	Barcode   string     `json:"barcode"`
+	Branch    string     `json:"branch"`
+	Condition Condition  `json:"condition"`
+	Status    CopyStatus `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Validate checks if the copy data is valid, defaulting the condition to
+// good and the status to available
+func (c *Copy) Validate() error {
+	c.Barcode = strings.TrimSpace(c.Barcode)
+	if c.Barcode == "" {
+		return ErrInvalidBarcode
+	}
+	if strings.TrimSpace(c.Branch) == "" {
+		return ErrInvalidBranch
+	}
+	if c.Condition == "" {
+		c.Condition = ConditionGood
+	}
+	if !validCondition(c.Condition) {
+		return ErrInvalidCondition
+	}
+	if c.Status == "" {
+		c.Status = CopyAvailable
+	}
+	if !validCopyStatus(c.Status) {
+		return ErrInvalidCopyStatus
+	}
+	return nil
+}
+
+func validCondition(c Condition) bool {
+	for _, valid := range Conditions {
+		if c == valid {
+			return true
+		}
+	}
+	return false
+}
+
+func validCopyStatus(s CopyStatus) bool {
+	for _, valid := range CopyStatuses {
+		if s == valid {
+			return true
+		}
+	}
+	return false
+}
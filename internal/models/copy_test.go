@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestCopy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    Copy
+		wantErr error
+	}{
+		{"valid copy", Copy{Barcode: "CC-1", Branch: "Main", Condition: ConditionNew, Status: CopyLost}, nil},
+		{"defaults", Copy{Barcode: " CC-1 ", Branch: "Main"}, nil},
+		{"missing barcode", Copy{Branch: "Main"}, ErrInvalidBarcode},
+		{"missing branch", Copy{Barcode: "CC-1"}, ErrInvalidBranch},
+		{"unknown condition", Copy{Barcode: "CC-1", Branch: "Main", Condition: "mint"}, ErrInvalidCondition},
+		{"unknown status", Copy{Barcode: "CC-1", Branch: "Main", Status: "stolen"}, ErrInvalidCopyStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.item.Validate(); err != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	item := Copy{Barcode: " CC-1 ", Branch: "Main"}
+	item.Validate()
+	if item.Barcode != "CC-1" || item.Condition != ConditionGood || item.Status != CopyAvailable {
+		t.Errorf("expected trimmed barcode with good condition and available status, got %+v", item)
+	}
+}
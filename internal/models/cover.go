@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Cover describes the cover image of a book and the thumbnails made from
+// it. URLs are relative to the API's base URL
+type Cover struct {
+	URL         string      `json:"url"`
+	ContentType string      `json:"content_type"`
+	Width       int         `json:"width"`
+	Height      int         `json:"height"`
+	Bytes       int         `json:"bytes"`
+	ETag        string      `json:"etag"`
+	Thumbnails  []Thumbnail `json:"thumbnails"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// Thumbnail is a resized copy of a cover image
+type Thumbnail struct {
+	Size        string `json:"size"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Bytes       int    `json:"bytes"`
+	ETag        string `json:"etag"`
+}
+
+// Thumbnail returns the thumbnail of a size, or nil if there is none
+func (c *Cover) Thumbnail(size string) *Thumbnail {
+	for i := range c.Thumbnails {
+		if c.Thumbnails[i].Size == size {
+			return &c.Thumbnails[i]
+		}
+	}
+	return nil
+}
@@ -6,6 +6,10 @@ var (
 	// ErrBookNotFound is returned when a book is not found
 	ErrBookNotFound = errors.New("book not found")
 
+	// ErrBookExists is returned when restoring a book whose ID has been
+	// taken by another book
+	ErrBookExists = errors.New("a book with this ID already exists")
+
 	// ErrInvalidTitle is returned when book title is empty
 	ErrInvalidTitle = errors.New("book title cannot be empty")
 
@@ -14,4 +18,105 @@ var (
 
 	// ErrInvalidID is returned when book ID is invalid
 	ErrInvalidID = errors.New("invalid book ID")
+
+	// ErrInvalidISBN is returned when a book ISBN is malformed
+	ErrInvalidISBN = errors.New("book ISBN must be a valid ISBN-10 or ISBN-13")
+
+	// ErrInvalidYear is returned when a book publication year is negative
+	ErrInvalidYear = errors.New("book published year cannot be negative")
+
+	// ErrInvalidDewey is returned when a book's Dewey call number is malformed
+	ErrInvalidDewey = errors.New("book dewey must be a Dewey Decimal call number such as 005.133 D66")
+
+	// ErrInvalidLCC is returned when a book's Library of Congress call number is malformed
+	ErrInvalidLCC = errors.New("book lcc must be a Library of Congress call number such as QA76.73.G63 D66")
+
+	// ErrInvalidGenre is returned when a book genre is not in the controlled vocabulary
+	ErrInvalidGenre = errors.New("book genre must be one of the genres listed at /genres")
+
+	// ErrInvalidTag is returned when a book tag is too long or contains a comma
+	ErrInvalidTag = errors.New("book tags must be at most 50 characters and cannot contain commas")
+
+	// ErrTooManyTags is returned when a book has more than MaxTags tags
+	ErrTooManyTags = errors.New("book cannot have more than 20 tags")
+
+	// ErrInvalidSort is returned when books are sorted by an unknown field
+	ErrInvalidSort = errors.New("sort must be rating, dewey or lcc, with a leading - to reverse it")
+
+	// ErrInvalidCollapse is returned when a book list is collapsed by anything
+	// but work
+	ErrInvalidCollapse = errors.New("collapse must be work")
+
+	// ErrInvalidFuzziness is returned when a search allows fewer than none
+	// or more than two typos per word
+	ErrInvalidFuzziness = errors.New("fuzziness must be 0, 1 or 2")
+
+	// ErrPatronNotFound is returned when a patron is not found
+	ErrPatronNotFound = errors.New("patron not found")
+
+	// ErrInvalidPatronName is returned when a patron name is empty
+	ErrInvalidPatronName = errors.New("patron name cannot be empty")
+
+	// ErrInvalidEmail is returned when a patron email address is malformed
+	ErrInvalidEmail = errors.New("patron email must be a valid address")
+
+	// ErrInvalidPatronType is returned when a patron type is not recognized
+	ErrInvalidPatronType = errors.New("patron type must be adult, child or staff")
+
+	// ErrLoanNotFound is returned when a loan is not found
+	ErrLoanNotFound = errors.New("loan not found")
+
+	// ErrCopyNotFound is returned when a copy is not found
+	ErrCopyNotFound = errors.New("copy not found")
+
+	// ErrInvalidBarcode is returned when a copy barcode is empty
+	ErrInvalidBarcode = errors.New("copy barcode cannot be empty")
+
+	// ErrDuplicateBarcode is returned when a copy barcode is already in use
+	ErrDuplicateBarcode = errors.New("copy barcode is already in use")
+
+	// ErrInvalidBranch is returned when a copy branch is empty
+	ErrInvalidBranch = errors.New("copy branch cannot be empty")
+
+	// ErrInvalidCondition is returned when a copy condition is not recognized
+	ErrInvalidCondition = errors.New("copy condition must be new, good, fair or poor")
+
+	// ErrInvalidCopyStatus is returned when a copy status is not recognized
+	ErrInvalidCopyStatus = errors.New("copy status must be available, on_loan, on_hold, lost or in_repair")
+
+	// ErrHoldNotFound is returned when a hold is not found
+	ErrHoldNotFound = errors.New("hold not found")
+
+	// ErrFineNotFound is returned when a fine is not found
+	ErrFineNotFound = errors.New("fine not found")
+
+	// ErrReviewNotFound is returned when a review is not found
+	ErrReviewNotFound = errors.New("review not found")
+
+	// ErrDuplicateReview is returned when a patron reviews a book a second time
+	ErrDuplicateReview = errors.New("patron has already reviewed this book")
+
+	// ErrInvalidRating is returned when a rating is not a whole number of stars from 1 to 5
+	ErrInvalidRating = errors.New("rating must be from 1 to 5")
+
+	// ErrReviewTooLong is returned when the text of a review is too long
+	ErrReviewTooLong = errors.New("review text must be at most 10000 characters")
+
+	// ErrInvalidReviewStatus is returned when a review status is not recognized
+	ErrInvalidReviewStatus = errors.New("review status must be pending, approved or rejected")
+
+	// ErrSeriesNotFound is returned when a series is not found
+	ErrSeriesNotFound = errors.New("series not found")
+
+	// ErrInvalidSeriesTitle is returned when a series title is empty
+	ErrInvalidSeriesTitle = errors.New("series title cannot be empty")
+
+	// ErrWorkNotFound is returned when a work is not found
+	ErrWorkNotFound = errors.New("work not found")
+
+	// ErrInvalidWorkTitle is returned when a work title is empty
+	ErrInvalidWorkTitle = errors.New("work title cannot be empty")
+
+	// ErrAssetNotFound is returned when an asset is not found
+	ErrAssetNotFound = errors.New("asset not found")
 )
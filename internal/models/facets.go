@@ -0,0 +1,71 @@
+package models
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// FacetCount is the number of books carrying a facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets summarizes a list of books for building filters. Genres, tags and
+// languages are ordered by count, most common first; decades, named like
+// "1990s", run from the earliest
+type Facets struct {
+	Genres    []FacetCount `json:"genre"`
+	Tags      []FacetCount `json:"tag"`
+	Languages []FacetCount `json:"language"`
+	Decades   []FacetCount `json:"decade"`
+}
+
+// NewFacets counts the genres, tags, languages and publication decades of
+// books. Books without a language or year are left out of those facets
+func NewFacets(books []Book) Facets {
+	genres, tags, languages := map[string]int{}, map[string]int{}, map[string]int{}
+	decades := map[int]int{}
+	for _, book := range books {
+		for _, genre := range book.Genres {
+			genres[genre]++
+		}
+		for _, tag := range book.Tags {
+			tags[tag]++
+		}
+		if book.Language != "" {
+			languages[book.Language]++
+		}
+		if book.PublishedYear > 0 {
+			decades[book.PublishedYear/10*10]++
+		}
+	}
+
+	facets := Facets{
+		Genres:    byCount(genres),
+		Tags:      byCount(tags),
+		Languages: byCount(languages),
+		Decades:   make([]FacetCount, 0, len(decades)),
+	}
+	for _, decade := range slices.Sorted(maps.Keys(decades)) {
+		facets.Decades = append(facets.Decades, FacetCount{Value: strconv.Itoa(decade) + "s", Count: decades[decade]})
+	}
+	return facets
+}
+
+// byCount returns counts ordered by count, most common first, then by value
+func byCount(counts map[string]int) []FacetCount {
+	facets := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, FacetCount{Value: value, Count: count})
+	}
+	slices.SortFunc(facets, func(a, b FacetCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return facets
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewFacets(t *testing.T) {
+	books := []Book{
+		{Genres: []string{"fiction", "mystery"}, Tags: []string{"cozy"}, Language: "eng", PublishedYear: 1999},
+		{Genres: []string{"mystery"}, Tags: []string{"cozy", "cats"}, Language: "fre", PublishedYear: 2021},
+		{Genres: []string{"history"}, Language: "eng", PublishedYear: 1990},
+		{},
+	}
+
+	facets := NewFacets(books)
+
+	tests := []struct {
+		name     string
+		got      []FacetCount
+		expected []FacetCount
+	}{
+		{"genres, most common first", facets.Genres, []FacetCount{{"mystery", 2}, {"fiction", 1}, {"history", 1}}},
+		{"tags", facets.Tags, []FacetCount{{"cozy", 2}, {"cats", 1}}},
+		{"languages", facets.Languages, []FacetCount{{"eng", 2}, {"fre", 1}}},
+		{"decades, earliest first", facets.Decades, []FacetCount{{"1990s", 2}, {"2020s", 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, tt.got)
+			}
+		})
+	}
+
+	if empty := NewFacets(nil); empty.Genres == nil || empty.Decades == nil {
+		t.Errorf("expected empty facet lists rather than nil, got %+v", empty)
+	}
+}
@@ -2,7 +2,11 @@ package models
 
 import (
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/fuzzy"
 )
 
 // BookFilters holds filter parameters for book queries
@@ -10,47 +14,77 @@ type BookFilters struct {
 	Title  string
 	Author string
 	Search string
+	// Genres and Tags select books by label. A book must carry all of them,
+	// or with MatchAny at least one
+	Genres   []string
+	Tags     []string
+	MatchAny bool
+	// Fuzziness is the number of typos, up to fuzzy.MaxFuzziness, forgiven
+	// in each word of the title, author and search filters. With 0 they
+	// must appear exactly, ignoring case
+	Fuzziness int
 }
 
-// ParseBookFilters extracts filter parameters from request
+// ParseBookFilters extracts filter parameters from request. The genre and
+// tag parameters may be repeated or hold comma-separated lists, and
+// match=any selects books with any of the labels rather than all of them
 func ParseBookFilters(r *http.Request) BookFilters {
+	query := r.URL.Query()
 	return BookFilters{
-		Title:  strings.TrimSpace(r.URL.Query().Get("title")),
-		Author: strings.TrimSpace(r.URL.Query().Get("author")),
-		Search: strings.TrimSpace(r.URL.Query().Get("search")),
+		Title:    strings.TrimSpace(query.Get("title")),
+		Author:   strings.TrimSpace(query.Get("author")),
+		Search:   strings.TrimSpace(query.Get("search")),
+		Genres:   parseLabels(query["genre"]),
+		Tags:     parseLabels(query["tag"]),
+		MatchAny: strings.EqualFold(query.Get("match"), "any"),
+	}
+}
+
+// ParseFuzziness extracts the fuzziness parameter from request, or returns
+// defaultFuzziness if it is not given
+func ParseFuzziness(r *http.Request, defaultFuzziness int) (int, error) {
+	value := strings.TrimSpace(r.URL.Query().Get("fuzziness"))
+	if value == "" {
+		return defaultFuzziness, nil
+	}
+	fuzziness, err := strconv.Atoi(value)
+	if err != nil || fuzziness < 0 || fuzziness > fuzzy.MaxFuzziness {
+		return 0, ErrInvalidFuzziness
+	}
+	return fuzziness, nil
+}
+
+// parseLabels splits comma-separated query values into normalized labels
+func parseLabels(values []string) []string {
+	var labels []string
+	for _, value := range values {
+		labels = append(labels, strings.Split(value, ",")...)
 	}
+	return NormalizeLabels(labels)
 }
 
 // Match checks if a book matches the filters
 func (f BookFilters) Match(book Book) bool {
 	// If search is provided, match against title or author
-	if f.Search != "" {
-		searchLower := strings.ToLower(f.Search)
-		titleLower := strings.ToLower(book.Title)
-		authorLower := strings.ToLower(book.Author)
-
-		if !strings.Contains(titleLower, searchLower) &&
-			!strings.Contains(authorLower, searchLower) {
-			return false
-		}
+	if f.Search != "" && !matchText(f.Search, f.Fuzziness, book.Title, book.Author) {
+		return false
 	}
 
 	// Match specific title filter
-	if f.Title != "" {
-		titleLower := strings.ToLower(book.Title)
-		filterLower := strings.ToLower(f.Title)
-		if !strings.Contains(titleLower, filterLower) {
-			return false
-		}
+	if f.Title != "" && !matchText(f.Title, f.Fuzziness, book.Title) {
+		return false
 	}
 
 	// Match specific author filter
-	if f.Author != "" {
-		authorLower := strings.ToLower(book.Author)
-		filterLower := strings.ToLower(f.Author)
-		if !strings.Contains(authorLower, filterLower) {
-			return false
+	if f.Author != "" && !matchText(f.Author, f.Fuzziness, book.Author) {
+		return false
+	}
+
+	if len(f.Genres) > 0 || len(f.Tags) > 0 {
+		if f.MatchAny {
+			return containsAny(book.Genres, f.Genres) || containsAny(book.Tags, f.Tags)
 		}
+		return containsAll(book.Genres, f.Genres) && containsAll(book.Tags, f.Tags)
 	}
 
 	return true
@@ -58,5 +92,38 @@ func (f BookFilters) Match(book Book) bool {
 
 // HasFilters returns true if any filters are set
 func (f BookFilters) HasFilters() bool {
-	return f.Title != "" || f.Author != "" || f.Search != ""
+	return f.Title != "" || f.Author != "" || f.Search != "" || len(f.Genres) > 0 || len(f.Tags) > 0
+}
+
+// matchText reports whether query is part of one of fields, ignoring case,
+// or with fuzziness, whether each of its words is that many typos or fewer
+// from a word of the fields
+func matchText(query string, fuzziness int, fields ...string) bool {
+	queryLower := strings.ToLower(query)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), queryLower) {
+			return true
+		}
+	}
+	return fuzziness > 0 && fuzzy.Match(strings.Join(fields, " "), query, fuzziness)
+}
+
+// containsAll reports whether labels include every one of wanted
+func containsAll(labels, wanted []string) bool {
+	for _, label := range wanted {
+		if !slices.Contains(labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAny reports whether labels include at least one of wanted
+func containsAny(labels, wanted []string) bool {
+	for _, label := range wanted {
+		if slices.Contains(labels, label) {
+			return true
+		}
+	}
+	return false
 }
@@ -1,6 +1,10 @@
 package models
 
-import "testing"
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
 
 func TestBookFilters_Match(t *testing.T) {
 	tests := []struct {
@@ -81,6 +85,66 @@ func TestBookFilters_Match(t *testing.T) {
 			book:    Book{Title: "Go Programming", Author: "Alan Donovan"},
 			want:    false,
 		},
+		{
+			name:    "all genres and tags - match",
+			filters: BookFilters{Genres: []string{"fiction"}, Tags: []string{"cozy", "cats"}},
+			book:    Book{Title: "Book", Genres: []string{"fiction", "mystery"}, Tags: []string{"cats", "cozy"}},
+			want:    true,
+		},
+		{
+			name:    "all genres and tags - missing tag",
+			filters: BookFilters{Genres: []string{"fiction"}, Tags: []string{"cozy", "cats"}},
+			book:    Book{Title: "Book", Genres: []string{"fiction"}, Tags: []string{"cozy"}},
+			want:    false,
+		},
+		{
+			name:    "any genre or tag - match on tag",
+			filters: BookFilters{Genres: []string{"horror"}, Tags: []string{"cats"}, MatchAny: true},
+			book:    Book{Title: "Book", Genres: []string{"fiction"}, Tags: []string{"cats"}},
+			want:    true,
+		},
+		{
+			name:    "any genre or tag - no match",
+			filters: BookFilters{Genres: []string{"horror"}, Tags: []string{"dogs"}, MatchAny: true},
+			book:    Book{Title: "Book", Genres: []string{"fiction"}, Tags: []string{"cats"}},
+			want:    false,
+		},
+		{
+			name:    "genre filter with title filter",
+			filters: BookFilters{Title: "Python", Genres: []string{"computing"}, MatchAny: true},
+			book:    Book{Title: "Go Programming", Genres: []string{"computing"}},
+			want:    false,
+		},
+		{
+			name:    "search - typos forgiven",
+			filters: BookFilters{Search: "pragmatik programer", Fuzziness: 2},
+			book:    Book{Title: "The Pragmatic Programmer", Author: "Andrew Hunt"},
+			want:    true,
+		},
+		{
+			name:    "search - too many typos",
+			filters: BookFilters{Search: "pragmatic hnut", Fuzziness: 1},
+			book:    Book{Title: "The Pragmatic Programmer", Author: "Andrew Hunt"},
+			want:    false,
+		},
+		{
+			name:    "search - words across title and author",
+			filters: BookFilters{Search: "pragmatic hunt", Fuzziness: 1},
+			book:    Book{Title: "The Pragmatic Programmer", Author: "Andrew Hunt"},
+			want:    true,
+		},
+		{
+			name:    "search - typos not forgiven",
+			filters: BookFilters{Search: "pragmatik programer"},
+			book:    Book{Title: "The Pragmatic Programmer", Author: "Andrew Hunt"},
+			want:    false,
+		},
+		{
+			name:    "author filter - typo forgiven",
+			filters: BookFilters{Author: "Donavan", Fuzziness: 1},
+			book:    Book{Title: "Book", Author: "Alan Donovan"},
+			want:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -123,6 +187,16 @@ func TestBookFilters_HasFilters(t *testing.T) {
 			filters: BookFilters{Title: "Go", Author: "Donovan"},
 			want:    true,
 		},
+		{
+			name:    "tag filter only",
+			filters: BookFilters{Tags: []string{"cozy"}},
+			want:    true,
+		},
+		{
+			name:    "match any alone",
+			filters: BookFilters{MatchAny: true},
+			want:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -133,3 +207,40 @@ func TestBookFilters_HasFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBookFilters_Labels(t *testing.T) {
+	req := httptest.NewRequest("GET", "/books?genre=Fiction,mystery&genre=fiction&tag=cozy&match=ANY", nil)
+	filters := ParseBookFilters(req)
+
+	if !reflect.DeepEqual(filters.Genres, []string{"fiction", "mystery"}) {
+		t.Errorf("expected genres [fiction mystery], got %q", filters.Genres)
+	}
+	if !reflect.DeepEqual(filters.Tags, []string{"cozy"}) {
+		t.Errorf("expected tags [cozy], got %q", filters.Tags)
+	}
+	if !filters.MatchAny {
+		t.Error("expected match=any to be parsed")
+	}
+}
+
+func TestParseFuzziness(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    int
+		wantErr error
+	}{
+		{"", 2, nil},
+		{"?fuzziness=0", 0, nil},
+		{"?fuzziness=1", 1, nil},
+		{"?fuzziness=3", 0, ErrInvalidFuzziness},
+		{"?fuzziness=-1", 0, ErrInvalidFuzziness},
+		{"?fuzziness=auto", 0, ErrInvalidFuzziness},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFuzziness(httptest.NewRequest("GET", "/books"+tt.query, nil), 2)
+		if got != tt.want || err != tt.wantErr {
+			t.Errorf("%q: expected %d, %v, got %d, %v", tt.query, tt.want, tt.wantErr, got, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// FineStatus is the state of a fine's balance
+type FineStatus string
+
+// Fine statuses
+const (
+	// FineOpen fines have a balance left to pay
+	FineOpen FineStatus = "open"
+	// FinePaid fines were settled with at least one payment
+	FinePaid FineStatus = "paid"
+	// FineWaived fines were settled by waivers alone
+	FineWaived FineStatus = "waived"
+)
+
+// FineTransactionType is the kind of change made to a fine's balance
+type FineTransactionType string
+
+// Fine transaction types
+const (
+	FinePayment FineTransactionType = "payment"
+	FineWaiver  FineTransactionType = "waiver"
+)
+
+// FineTransaction records a payment towards, or waiver of, part of a fine
+type FineTransaction struct {
+	Type        FineTransactionType `json:"type"`
+	AmountCents int                 `json:"amount_cents"`
+	Note        string              `json:"note,omitempty"`
+	Time        time.Time           `json:"time"`
+}
+
+// Fine is the charge for an overdue loan. The amount grows while the loan
+// stays out, up to the cap of the patron's policy; amounts are in cents
+type Fine struct {
+	ID           int               `json:"id"`
+	PatronID     int               `json:"patron_id"`
+	LoanID       int               `json:"loan_id"`
+	BookID       int               `json:"book_id"`
+	AmountCents  int               `json:"amount_cents"`
+	PaidCents    int               `json:"paid_cents"`
+	WaivedCents  int               `json:"waived_cents"`
+	BalanceCents int               `json:"balance_cents"`
+	Status       FineStatus        `json:"status"`
+	Transactions []FineTransaction `json:"transactions"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// Reconcile recomputes the balance and status from the amount, payments
+// and waivers
+func (f *Fine) Reconcile() {
+	f.BalanceCents = f.AmountCents - f.PaidCents - f.WaivedCents
+	switch {
+	case f.BalanceCents > 0:
+		f.Status = FineOpen
+	case f.PaidCents > 0:
+		f.Status = FinePaid
+	default:
+		f.Status = FineWaived
+	}
+	if f.Transactions == nil {
+		f.Transactions = make([]FineTransaction, 0)
+	}
+}
+
+// FineFilter narrows a list of fines; zero fields match everything
+type FineFilter struct {
+	PatronID int
+	LoanID   int
+	// OpenOnly excludes settled fines
+	OpenOnly bool
+}
+
+// Match reports whether a fine satisfies the filter
+func (f FineFilter) Match(fine Fine) bool {
+	if f.PatronID != 0 && fine.PatronID != f.PatronID {
+		return false
+	}
+	if f.LoanID != 0 && fine.LoanID != f.LoanID {
+		return false
+	}
+	if f.OpenOnly && fine.Status != FineOpen {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package models
+
+import "testing"
+
+func TestFine_Reconcile(t *testing.T) {
+	tests := []struct {
+		name            string
+		fine            Fine
+		expectedBalance int
+		expectedStatus  FineStatus
+	}{
+		{"unpaid", Fine{AmountCents: 100}, 100, FineOpen},
+		{"part paid", Fine{AmountCents: 100, PaidCents: 40}, 60, FineOpen},
+		{"paid", Fine{AmountCents: 100, PaidCents: 60, WaivedCents: 40}, 0, FinePaid},
+		{"waived", Fine{AmountCents: 100, WaivedCents: 100}, 0, FineWaived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fine.Reconcile()
+			if tt.fine.BalanceCents != tt.expectedBalance || tt.fine.Status != tt.expectedStatus {
+				t.Errorf("expected balance %d and status %s, got %d and %s",
+					tt.expectedBalance, tt.expectedStatus, tt.fine.BalanceCents, tt.fine.Status)
+			}
+			if tt.fine.Transactions == nil {
+				t.Errorf("expected an empty transaction list, got nil")
+			}
+		})
+	}
+
+	open := Fine{PatronID: 1, LoanID: 2, Status: FineOpen}
+	if !(FineFilter{PatronID: 1, LoanID: 2, OpenOnly: true}).Match(open) {
+		t.Errorf("expected open fine to match")
+	}
+	if (FineFilter{OpenOnly: true}).Match(Fine{Status: FinePaid}) {
+		t.Errorf("expected paid fine not to match open filter")
+	}
+}
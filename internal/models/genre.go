@@ -0,0 +1,61 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// Genres is the controlled vocabulary of book genres
+var Genres = []string{
+	"art",
+	"biography",
+	"business",
+	"children",
+	"comics",
+	"computing",
+	"cooking",
+	"fantasy",
+	"fiction",
+	"health",
+	"history",
+	"horror",
+	"mystery",
+	"philosophy",
+	"poetry",
+	"reference",
+	"religion",
+	"romance",
+	"science",
+	"science-fiction",
+	"self-help",
+	"thriller",
+	"travel",
+	"young-adult",
+}
+
+const (
+	// MaxTags is the most tags a book can carry
+	MaxTags = 20
+
+	// MaxTagLength is the maximum length of a tag in characters
+	MaxTagLength = 50
+)
+
+// ValidGenre reports whether genre is in the controlled vocabulary
+func ValidGenre(genre string) bool {
+	return slices.Contains(Genres, genre)
+}
+
+// NormalizeLabels lower-cases and trims genre or tag labels, dropping empty
+// and repeated ones while keeping their order. It returns nil if no labels
+// are left.
+func NormalizeLabels(labels []string) []string {
+	var normalized []string
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label != "" && !slices.Contains(normalized, label) {
+			normalized = append(normalized, label)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// HoldStatus is the state of a hold in the queue for a book
+type HoldStatus string
+
+// Hold statuses
+const (
+	// HoldWaiting holds are queued until a copy comes back
+	HoldWaiting HoldStatus = "waiting"
+	// HoldReady holds have a copy set aside for pickup until ExpiresAt
+	HoldReady HoldStatus = "ready"
+	// HoldFulfilled holds ended with the patron checking out the copy
+	HoldFulfilled HoldStatus = "fulfilled"
+	// HoldCancelled holds were withdrawn before they were fulfilled
+	HoldCancelled HoldStatus = "cancelled"
+	// HoldExpired holds were not picked up in time
+	HoldExpired HoldStatus = "expired"
+)
+
+// Hold reserves the next available copy of a book for a patron. Holds on a
+// book are served first come, first served
+type Hold struct {
+	ID       int        `json:"id"`
+	BookID   int        `json:"book_id"`
+	PatronID int        `json:"patron_id"`
+	Status   HoldStatus `json:"status"`
+	// Position is the place of a waiting hold in the queue, starting at 1;
+	// it is computed when the hold is read
+	Position int `json:"position,omitempty"`
+	// CopyID is the copy set aside once the hold is ready
+	CopyID    int        `json:"copy_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadyAt   *time.Time `json:"ready_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Active reports whether the hold is still waiting or ready for pickup
+func (h Hold) Active() bool {
+	return h.Status == HoldWaiting || h.Status == HoldReady
+}
+
+// HoldFilter narrows a list of holds; zero fields match everything
+type HoldFilter struct {
+	BookID   int
+	PatronID int
+	// Statuses limits the holds to these statuses
+	Statuses []HoldStatus
+}
+
+// Match reports whether a hold satisfies the filter
+func (f HoldFilter) Match(h Hold) bool {
+	if f.BookID != 0 && h.BookID != f.BookID {
+		return false
+	}
+	if f.PatronID != 0 && h.PatronID != f.PatronID {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, status := range f.Statuses {
+		if h.Status == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestHoldFilter_Match(t *testing.T) {
+	hold := Hold{BookID: 1, PatronID: 2, Status: HoldReady}
+
+	tests := []struct {
+		name     string
+		filter   HoldFilter
+		expected bool
+	}{
+		{"empty filter", HoldFilter{}, true},
+		{"matching book and patron", HoldFilter{BookID: 1, PatronID: 2}, true},
+		{"other book", HoldFilter{BookID: 3}, false},
+		{"other patron", HoldFilter{PatronID: 3}, false},
+		{"matching status", HoldFilter{Statuses: []HoldStatus{HoldWaiting, HoldReady}}, true},
+		{"other status", HoldFilter{Statuses: []HoldStatus{HoldWaiting}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(hold); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if !hold.Active() || (Hold{Status: HoldExpired}).Active() {
+		t.Errorf("expected only waiting and ready holds to be active")
+	}
+}
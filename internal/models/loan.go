@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Loan records a copy of a book borrowed by a patron
+type Loan struct {
+	ID           int        `json:"id"`
+	BookID       int        `json:"book_id"`
+	CopyID       int        `json:"copy_id"`
+	PatronID     int        `json:"patron_id"`
+	CheckedOutAt time.Time  `json:"checked_out_at"`
+	DueAt        time.Time  `json:"due_at"`
+	ReturnedAt   *time.Time `json:"returned_at,omitempty"`
+	Renewals     int        `json:"renewals"`
+	// Overdue is set once the loan has been found out past its due date,
+	// and cleared if a renewal makes it due in the future again
+	Overdue bool `json:"overdue"`
+	// OverdueDays are the full days the loan was overdue before renewals
+	// moved its due date, which still count towards its fine
+	OverdueDays int `json:"overdue_days,omitempty"`
+}
+
+// Active reports whether the book is still out on the loan
+func (l Loan) Active() bool {
+	return l.ReturnedAt == nil
+}
+
+// LoanFilter narrows a list of loans; zero fields match everything
+type LoanFilter struct {
+	BookID   int
+	CopyID   int
+	PatronID int
+	// ActiveOnly excludes returned loans
+	ActiveOnly bool
+}
+
+// Match reports whether a loan satisfies the filter
+func (f LoanFilter) Match(l Loan) bool {
+	if f.BookID != 0 && l.BookID != f.BookID {
+		return false
+	}
+	if f.CopyID != 0 && l.CopyID != f.CopyID {
+		return false
+	}
+	if f.PatronID != 0 && l.PatronID != f.PatronID {
+		return false
+	}
+	if f.ActiveOnly && !l.Active() {
+		return false
+	}
+	return true
+}
@@ -19,32 +19,61 @@ type PaginatedResponse struct {
 	PageSize   int         `json:"page_size"`
 	Total      int         `json:"total"`
 	TotalPages int         `json:"total_pages"`
+	// Facets counts the labels of every item matched, not just this page
+	Facets *Facets `json:"facets,omitempty"`
+	// Suggestions proposes corrected searches when nothing matched
+	Suggestions []SearchSuggestion `json:"suggestions,omitempty"`
 }
 
 // ParsePaginationParams extracts pagination parameters from request
 func ParsePaginationParams(r *http.Request) PaginationParams {
-	page := 1
-	pageSize := 10
+	page := 0
+	pageSize := 0
 
 	if p := r.URL.Query().Get("page"); p != "" {
-		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+		if parsed, err := strconv.Atoi(p); err == nil {
 			page = parsed
 		}
 	}
 
 	if ps := r.URL.Query().Get("page_size"); ps != "" {
-		if parsed, err := strconv.Atoi(ps); err == nil && parsed > 0 && parsed <= 100 {
+		if parsed, err := strconv.Atoi(ps); err == nil {
 			pageSize = parsed
 		}
 	}
 
-	offset := (page - 1) * pageSize
+	return NewPaginationParams(page, pageSize)
+}
+
+// NewPaginationParams builds pagination parameters, using the defaults for
+// a page below 1 or a page size outside 1-100
+func NewPaginationParams(page, pageSize int) PaginationParams {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
 
 	return PaginationParams{
 		Page:     page,
 		PageSize: pageSize,
-		Offset:   offset,
+		Offset:   (page - 1) * pageSize,
+	}
+}
+
+// Bounds returns the start and end indexes of the page within total items
+func (p PaginationParams) Bounds(total int) (int, int) {
+	start := p.Offset
+	end := start + p.PageSize
+
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
 	}
+	return start, end
 }
 
 // NewPaginatedResponse creates a paginated response
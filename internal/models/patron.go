@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// PatronType groups patrons that share a loan policy
+type PatronType string
+
+// Patron types
+const (
+	PatronAdult PatronType = "adult"
+	PatronChild PatronType = "child"
+	PatronStaff PatronType = "staff"
+)
+
+// PatronTypes lists the valid patron types
+var PatronTypes = []PatronType{PatronAdult, PatronChild, PatronStaff}
+
+// Patron is a library member who can borrow books
+type Patron struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email,omitempty"`
+	Type      PatronType `json:"type"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Validate checks if the patron data is valid, defaulting the type to adult
+func (p *Patron) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return ErrInvalidPatronName
+	}
+	if p.Email != "" && !strings.Contains(p.Email, "@") {
+		return ErrInvalidEmail
+	}
+	if p.Type == "" {
+		p.Type = PatronAdult
+	}
+	for _, t := range PatronTypes {
+		if p.Type == t {
+			return nil
+		}
+	}
+	return ErrInvalidPatronType
+}
@@ -0,0 +1,30 @@
+package models
+
+import "testing"
+
+func TestPatron_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		patron   Patron
+		wantErr  error
+		wantType PatronType
+	}{
+		{"valid patron", Patron{Name: "Ada", Email: "ada@example.com", Type: PatronStaff}, nil, PatronStaff},
+		{"type defaults to adult", Patron{Name: "Ada"}, nil, PatronAdult},
+		{"missing name", Patron{Name: "  "}, ErrInvalidPatronName, ""},
+		{"invalid email", Patron{Name: "Ada", Email: "ada"}, ErrInvalidEmail, ""},
+		{"unknown type", Patron{Name: "Ada", Type: "robot"}, ErrInvalidPatronType, "robot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.patron.Validate()
+			if err != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.patron.Type != tt.wantType {
+				t.Errorf("expected type %q, got %q", tt.wantType, tt.patron.Type)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxReviewLength is the most characters the text of a review can have
+const MaxReviewLength = 10000
+
+// ReviewStatus is the moderation state of a review
+type ReviewStatus string
+
+// Review statuses
+const (
+	// ReviewPending reviews wait for a moderator and are not shown or counted
+	ReviewPending ReviewStatus = "pending"
+	// ReviewApproved reviews are shown and count towards the book's rating
+	ReviewApproved ReviewStatus = "approved"
+	// ReviewRejected reviews are hidden
+	ReviewRejected ReviewStatus = "rejected"
+)
+
+// ReviewStatuses lists the valid review statuses
+var ReviewStatuses = []ReviewStatus{ReviewPending, ReviewApproved, ReviewRejected}
+
+// Valid reports whether the status is recognized
+func (s ReviewStatus) Valid() bool {
+	for _, status := range ReviewStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Review is a patron's star rating of a book, with optional text
+type Review struct {
+	ID       int          `json:"id"`
+	BookID   int          `json:"book_id"`
+	PatronID int          `json:"patron_id"`
+	Rating   int          `json:"rating"`
+	Text     string       `json:"text,omitempty"`
+	Status   ReviewStatus `json:"status"`
+	// ModeratedBy, ModeratedAt and ModerationNote record the last moderation
+	ModeratedBy    string     `json:"moderated_by,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	ModerationNote string     `json:"moderation_note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Validate checks the rating and trims the text
+func (r *Review) Validate() error {
+	if r.Rating < 1 || r.Rating > 5 {
+		return ErrInvalidRating
+	}
+	r.Text = strings.TrimSpace(r.Text)
+	if utf8.RuneCountInString(r.Text) > MaxReviewLength {
+		return ErrReviewTooLong
+	}
+	return nil
+}
+
+// ReviewFilter narrows a list of reviews; zero fields match everything
+type ReviewFilter struct {
+	BookID   int
+	PatronID int
+	Status   ReviewStatus
+}
+
+// Match reports whether a review satisfies the filter
+func (f ReviewFilter) Match(r Review) bool {
+	if f.BookID != 0 && r.BookID != f.BookID {
+		return false
+	}
+	if f.PatronID != 0 && r.PatronID != f.PatronID {
+		return false
+	}
+	if f.Status != "" && r.Status != f.Status {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReview_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		review  Review
+		wantErr error
+	}{
+		{"valid review", Review{Rating: 5, Text: "Loved it"}, nil},
+		{"rating without text", Review{Rating: 1}, nil},
+		{"rating too low", Review{Rating: 0}, ErrInvalidRating},
+		{"rating too high", Review{Rating: 6}, ErrInvalidRating},
+		{"text too long", Review{Rating: 3, Text: strings.Repeat("a", MaxReviewLength+1)}, ErrReviewTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.review.Validate(); err != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	review := Review{Rating: 4, Text: "  Good  "}
+	review.Validate()
+	if review.Text != "Good" {
+		t.Errorf("expected trimmed text, got %q", review.Text)
+	}
+}
+
+func TestReviewFilter_Match(t *testing.T) {
+	review := Review{BookID: 1, PatronID: 2, Status: ReviewApproved}
+
+	tests := []struct {
+		name     string
+		filter   ReviewFilter
+		expected bool
+	}{
+		{"empty filter", ReviewFilter{}, true},
+		{"matching book, patron and status", ReviewFilter{BookID: 1, PatronID: 2, Status: ReviewApproved}, true},
+		{"other book", ReviewFilter{BookID: 3}, false},
+		{"other patron", ReviewFilter{PatronID: 3}, false},
+		{"other status", ReviewFilter{Status: ReviewPending}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(review); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Series is an ordered run of books, such as the volumes of a trilogy
+type Series struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// BookIDs lists the books in the series in reading order
+	BookIDs   []int     `json:"book_ids"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks if the series data is valid, trimming its title
+func (s *Series) Validate() error {
+	s.Title = strings.TrimSpace(s.Title)
+	if s.Title == "" {
+		return ErrInvalidSeriesTitle
+	}
+	return nil
+}
+
+// Position returns the 1-based position of a book in the series, or 0 if the
+// book is not in it
+func (s *Series) Position(bookID int) int {
+	for i, id := range s.BookIDs {
+		if id == bookID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// SeriesEntry is a book listed with its position in a series
+type SeriesEntry struct {
+	Position int `json:"position"`
+	Book
+}
@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestSeries_Validate(t *testing.T) {
+	series := Series{Title: " Earthsea "}
+	if err := series.Validate(); err != nil || series.Title != "Earthsea" {
+		t.Errorf("expected a trimmed title and no error, got %q and %v", series.Title, err)
+	}
+
+	if err := (&Series{}).Validate(); err != ErrInvalidSeriesTitle {
+		t.Errorf("expected ErrInvalidSeriesTitle, got %v", err)
+	}
+}
+
+func TestSeries_Position(t *testing.T) {
+	series := Series{BookIDs: []int{4, 2, 9}}
+
+	tests := []struct {
+		bookID   int
+		expected int
+	}{
+		{4, 1},
+		{9, 3},
+		{5, 0},
+	}
+
+	for _, tt := range tests {
+		if got := series.Position(tt.bookID); got != tt.expected {
+			t.Errorf("expected book %d at position %d, got %d", tt.bookID, tt.expected, got)
+		}
+	}
+}
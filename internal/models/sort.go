@@ -0,0 +1,99 @@
+package models
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/classification"
+)
+
+// Sortable book fields
+const (
+	// SortByRating orders books by average rating, then by rating count
+	SortByRating = "rating"
+	// SortByDewey orders books by Dewey call number in shelf order
+	SortByDewey = "dewey"
+	// SortByLCC orders books by Library of Congress call number in shelf order
+	SortByLCC = "lcc"
+)
+
+// BookSort orders a list of books; a zero BookSort keeps the stored order
+type BookSort struct {
+	Field      string
+	Descending bool
+}
+
+// ParseBookSort reads the sort query parameter, such as sort=rating or
+// sort=-rating for the highest rated first
+func ParseBookSort(r *http.Request) (BookSort, error) {
+	value := strings.TrimSpace(r.URL.Query().Get("sort"))
+	if value == "" {
+		return BookSort{}, nil
+	}
+	field, descending := strings.CutPrefix(value, "-")
+	switch field {
+	case SortByRating, SortByDewey, SortByLCC:
+		return BookSort{Field: field, Descending: descending}, nil
+	default:
+		return BookSort{}, ErrInvalidSort
+	}
+}
+
+// Apply sorts books in place, keeping the stored order of equal books.
+// Books without a call number are shelved after the rest in either direction
+func (s BookSort) Apply(books []Book) {
+	switch s.Field {
+	case SortByRating:
+	case SortByDewey, SortByLCC:
+		ShelfOrder(books, classification.Scheme(s.Field), s.Descending)
+		return
+	default:
+		return
+	}
+	sort.SliceStable(books, func(i, j int) bool {
+		a, b := books[i], books[j]
+		if s.Descending {
+			a, b = b, a
+		}
+		if a.AverageRating != b.AverageRating {
+			return a.AverageRating < b.AverageRating
+		}
+		return a.RatingCount < b.RatingCount
+	})
+}
+
+// ShelfOrder sorts books in place by their call numbers in a classification
+// scheme, keeping the stored order of equal books. Books without a valid
+// call number in the scheme go last
+func ShelfOrder(books []Book, scheme classification.Scheme, descending bool) {
+	type shelved struct {
+		book Book
+		key  classification.CallNumber
+		ok   bool
+	}
+	list := make([]shelved, len(books))
+	for i, book := range books {
+		key, err := classification.Parse(scheme, book.CallNumber(scheme))
+		list[i] = shelved{book: book, key: key, ok: err == nil}
+	}
+	slices.SortStableFunc(list, func(a, b shelved) int {
+		switch {
+		case a.ok != b.ok:
+			if a.ok {
+				return -1
+			}
+			return 1
+		case !a.ok:
+			return 0
+		case descending:
+			return b.key.Compare(a.key)
+		default:
+			return a.key.Compare(b.key)
+		}
+	})
+	for i, item := range list {
+		books[i] = item.book
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseBookSort(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected BookSort
+		wantErr  error
+	}{
+		{"", BookSort{}, nil},
+		{"?sort=rating", BookSort{Field: SortByRating}, nil},
+		{"?sort=-rating", BookSort{Field: SortByRating, Descending: true}, nil},
+		{"?sort=dewey", BookSort{Field: SortByDewey}, nil},
+		{"?sort=-lcc", BookSort{Field: SortByLCC, Descending: true}, nil},
+		{"?sort=title", BookSort{}, ErrInvalidSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := ParseBookSort(httptest.NewRequest("GET", "/books"+tt.query, nil))
+			if err != tt.wantErr || got != tt.expected {
+				t.Errorf("expected %+v (%v), got %+v (%v)", tt.expected, tt.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func TestBookSort_Apply(t *testing.T) {
+	books := func() []Book {
+		return []Book{
+			{ID: 1, AverageRating: 4.5, RatingCount: 2},
+			{ID: 2},
+			{ID: 3, AverageRating: 4.5, RatingCount: 10},
+			{ID: 4, AverageRating: 2},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		sort     BookSort
+		expected []int
+	}{
+		{"unsorted", BookSort{}, []int{1, 2, 3, 4}},
+		{"lowest rated first", BookSort{Field: SortByRating}, []int{2, 4, 1, 3}},
+		{"highest rated first", BookSort{Field: SortByRating, Descending: true}, []int{3, 1, 4, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := books()
+			tt.sort.Apply(list)
+			for i, id := range tt.expected {
+				if list[i].ID != id {
+					t.Errorf("expected book %d at position %d, got %d", id, i, list[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestBookSort_ApplyShelfOrder(t *testing.T) {
+	books := func() []Book {
+		return []Book{
+			{ID: 1, Dewey: "005.2"},
+			{ID: 2},
+			{ID: 3, Dewey: "005.13 K5"},
+			{ID: 4, Dewey: "005.133"},
+			{ID: 5, Dewey: "005.13"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		sort     BookSort
+		expected []int
+	}{
+		{"shelf order", BookSort{Field: SortByDewey}, []int{5, 3, 4, 1, 2}},
+		{"reverse shelf order", BookSort{Field: SortByDewey, Descending: true}, []int{1, 4, 3, 5, 2}},
+		{"no call numbers in the scheme", BookSort{Field: SortByLCC}, []int{1, 2, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := books()
+			tt.sort.Apply(list)
+			for i, id := range tt.expected {
+				if list[i].ID != id {
+					t.Errorf("expected book %d at position %d, got %d", id, i, list[i].ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/codeforgood-org/golang-book-api/internal/fuzzy"
+)
+
+// MaxSuggestions is the most corrected searches suggested when a search
+// finds nothing
+const MaxSuggestions = 3
+
+// Words of the catalogue at least this similar to a word of a search, or
+// within fuzzy.MaxFuzziness edits of it, are its possible corrections
+const minSimilarity = 0.3
+
+// maxCorrections is the most corrections considered for each word
+const maxCorrections = 3
+
+// SearchSuggestion is a corrected form of the title, author and search
+// filters of a search that found nothing. Filters that were not given are
+// left empty
+type SearchSuggestion struct {
+	Search string `json:"search,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Author string `json:"author,omitempty"`
+}
+
+// SuggestQueries proposes corrections of the text filters of a search that
+// matched none of books, spelling each word like the closest word of their
+// titles and authors, and the most common where several are as close. Only
+// corrections that match books under the other filters are suggested, best
+// first
+func SuggestQueries(books []Book, f BookFilters) []SearchSuggestion {
+	if f.Search == "" && f.Title == "" && f.Author == "" {
+		return nil
+	}
+	titles, authors := make(map[string]int), make(map[string]int)
+	for _, book := range books {
+		for _, word := range fuzzy.Words(book.Title) {
+			titles[word]++
+		}
+		for _, word := range fuzzy.Words(book.Author) {
+			authors[word]++
+		}
+	}
+	both := make(map[string]int, len(titles)+len(authors))
+	for _, counts := range []map[string]int{titles, authors} {
+		for word, n := range counts {
+			both[word] += n
+		}
+	}
+
+	searches := corrections(f.Search, both)
+	titleOptions := corrections(f.Title, titles)
+	authorOptions := corrections(f.Author, authors)
+	option := func(options []string, i int) string {
+		return options[min(i, len(options)-1)]
+	}
+
+	suggestions := make([]SearchSuggestion, 0, MaxSuggestions)
+	for i := 0; i < max(len(searches), len(titleOptions), len(authorOptions)); i++ {
+		suggestion := SearchSuggestion{
+			Search: option(searches, i),
+			Title:  option(titleOptions, i),
+			Author: option(authorOptions, i),
+		}
+		if slices.Contains(suggestions, suggestion) || suggestion == (SearchSuggestion{
+			Search: strings.Join(fuzzy.Words(f.Search), " "),
+			Title:  strings.Join(fuzzy.Words(f.Title), " "),
+			Author: strings.Join(fuzzy.Words(f.Author), " "),
+		}) {
+			continue
+		}
+		corrected := f
+		corrected.Search, corrected.Title, corrected.Author = suggestion.Search, suggestion.Title, suggestion.Author
+		if slices.ContainsFunc(books, corrected.Match) {
+			suggestions = append(suggestions, suggestion)
+			if len(suggestions) == MaxSuggestions {
+				break
+			}
+		}
+	}
+	return suggestions
+}
+
+// corrections returns text corrected word by word against the counts of
+// the words of the catalogue: first with the best correction of every word,
+// then with the next best of one word at a time
+func corrections(text string, counts map[string]int) []string {
+	words := fuzzy.Words(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	candidates := make([][]string, len(words))
+	best := make([]string, len(words))
+	for i, word := range words {
+		candidates[i] = correct(word, counts)
+		best[i] = candidates[i][0]
+	}
+
+	options := []string{strings.Join(best, " ")}
+	for rank := 1; rank < maxCorrections; rank++ {
+		for i := range words {
+			if rank < len(candidates[i]) {
+				alternative := slices.Clone(best)
+				alternative[i] = candidates[i][rank]
+				options = append(options, strings.Join(alternative, " "))
+			}
+		}
+	}
+	return options
+}
+
+// correct returns the words of the catalogue a word may be a misspelling
+// of, fewest edits away first and then the most common, or the word itself
+// if it is spelled like one of them or there are none
+func correct(word string, counts map[string]int) []string {
+	if counts[word] > 0 {
+		return []string{word}
+	}
+	var candidates []string
+	for candidate := range counts {
+		if fuzzy.Similarity(word, candidate) >= minSimilarity || fuzzy.Within(word, candidate, fuzzy.MaxFuzziness) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		return []string{word}
+	}
+	distances := make(map[string]int, len(candidates))
+	for _, candidate := range candidates {
+		distances[candidate] = fuzzy.Distance(word, candidate)
+	}
+	slices.SortFunc(candidates, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(distances[a], distances[b]),
+			cmp.Compare(counts[b], counts[a]),
+			strings.Compare(a, b),
+		)
+	})
+	return candidates[:min(len(candidates), maxCorrections)]
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestQueries(t *testing.T) {
+	books := []Book{
+		{Title: "The Pragmatic Programmer", Author: "Andrew Hunt", Genres: []string{"computing"}},
+		{Title: "The Passionate Programmer", Author: "Chad Fowler", Genres: []string{"computing"}},
+		{Title: "Programming Pearls", Author: "Jon Bentley", Genres: []string{"computing"}},
+		{Title: "Pride and Prejudice", Author: "Jane Austen", Genres: []string{"fiction"}},
+	}
+
+	tests := []struct {
+		name    string
+		filters BookFilters
+		want    []SearchSuggestion
+	}{
+		{
+			name:    "misspelled search",
+			filters: BookFilters{Search: "pragmatik programer"},
+			want:    []SearchSuggestion{{Search: "pragmatic programmer"}},
+		},
+		{
+			name:    "letters swapped",
+			filters: BookFilters{Search: "pargmatic"},
+			want:    []SearchSuggestion{{Search: "pragmatic"}},
+		},
+		{
+			name:    "title and author",
+			filters: BookFilters{Title: "pride", Author: "austin"},
+			want:    []SearchSuggestion{{Title: "pride", Author: "austen"}},
+		},
+		{
+			name:    "correction must match the other filters",
+			filters: BookFilters{Search: "pragmatik", Genres: []string{"fiction"}},
+			want:    []SearchSuggestion{},
+		},
+		{
+			name:    "nothing close",
+			filters: BookFilters{Search: "xylophone"},
+			want:    []SearchSuggestion{},
+		},
+		{
+			name:    "no text filters",
+			filters: BookFilters{Genres: []string{"horror"}},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestQueries(books, tt.filters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
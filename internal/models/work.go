@@ -0,0 +1,54 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Work is the book that its editions and translations are all editions of.
+// Books are linked to a work through their WorkID
+type Work struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks if the work data is valid, trimming its title and author
+func (w *Work) Validate() error {
+	w.Title = strings.TrimSpace(w.Title)
+	w.Author = strings.TrimSpace(w.Author)
+	if w.Title == "" {
+		return ErrInvalidWorkTitle
+	}
+	return nil
+}
+
+// WorkEntry stands for every edition of a work in a collapsed book list. It
+// is the first of the editions listed, with the IDs of all of them in list
+// order; books that are not linked to a work stand for themselves
+type WorkEntry struct {
+	Book
+	EditionIDs []int `json:"edition_ids,omitempty"`
+}
+
+// CollapseEditions replaces the editions of each work in books with a single
+// entry where the first of them appears, keeping the order of the list
+func CollapseEditions(books []Book) []WorkEntry {
+	entries := make([]WorkEntry, 0, len(books))
+	index := make(map[int]int)
+	for _, book := range books {
+		if book.WorkID == 0 {
+			entries = append(entries, WorkEntry{Book: book})
+			continue
+		}
+		if i, ok := index[book.WorkID]; ok {
+			entries[i].EditionIDs = append(entries[i].EditionIDs, book.ID)
+			continue
+		}
+		index[book.WorkID] = len(entries)
+		entries = append(entries, WorkEntry{Book: book, EditionIDs: []int{book.ID}})
+	}
+	return entries
+}
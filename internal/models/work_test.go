@@ -0,0 +1,53 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWork_Validate(t *testing.T) {
+	work := Work{Title: "  Don Quixote ", Author: " Cervantes"}
+	if err := work.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if work.Title != "Don Quixote" || work.Author != "Cervantes" {
+		t.Errorf("expected a trimmed title and author, got %q by %q", work.Title, work.Author)
+	}
+
+	if err := (&Work{Title: " "}).Validate(); err != ErrInvalidWorkTitle {
+		t.Errorf("expected ErrInvalidWorkTitle, got %v", err)
+	}
+}
+
+func TestCollapseEditions(t *testing.T) {
+	books := []Book{
+		{ID: 1, Title: "Don Quixote", WorkID: 7},
+		{ID: 2, Title: "Dune"},
+		{ID: 3, Title: "Don Quijote", WorkID: 7},
+		{ID: 4, Title: "War and Peace", WorkID: 8},
+		{ID: 5, Title: "Don Quichotte", WorkID: 7},
+	}
+
+	entries := CollapseEditions(books)
+
+	expected := []struct {
+		id       int
+		editions []int
+	}{
+		{1, []int{1, 3, 5}},
+		{2, nil},
+		{4, []int{4}},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, want := range expected {
+		if entries[i].ID != want.id || !reflect.DeepEqual(entries[i].EditionIDs, want.editions) {
+			t.Errorf("entry %d: expected book %d with editions %v, got book %d with %v", i, want.id, want.editions, entries[i].ID, entries[i].EditionIDs)
+		}
+	}
+
+	if entries := CollapseEditions(nil); len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
@@ -0,0 +1,266 @@
+package opds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Media types of OPDS 1.2 documents
+const (
+	AtomNavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	AtomAcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	OpenSearchType      = "application/opensearchdescription+xml"
+)
+
+// Link relations specific to OPDS
+const (
+	relAcquisition = "http://opds-spec.org/acquisition"
+	relImage       = "http://opds-spec.org/image"
+	relThumbnail   = "http://opds-spec.org/image/thumbnail"
+)
+
+// AtomType returns the OPDS 1.2 media type of feeds of the kind
+func (k Kind) AtomType() string {
+	if k == Acquisition {
+		return AtomAcquisitionType
+	}
+	return AtomNavigationType
+}
+
+type atomFeed struct {
+	XMLName      xml.Name    `xml:"feed"`
+	Xmlns        string      `xml:"xmlns,attr"`
+	XmlnsDC      string      `xml:"xmlns:dc,attr"`
+	XmlnsOPDS    string      `xml:"xmlns:opds,attr"`
+	XmlnsSearch  string      `xml:"xmlns:opensearch,attr"`
+	XmlnsThread  string      `xml:"xmlns:thr,attr"`
+	ID           string      `xml:"id"`
+	Title        string      `xml:"title"`
+	Updated      string      `xml:"updated"`
+	Author       atomAuthor  `xml:"author"`
+	Links        []atomLink  `xml:"link"`
+	TotalResults int         `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage int         `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex   int         `xml:"opensearch:startIndex,omitempty"`
+	Entries      []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr,omitempty"`
+	Title  string `xml:"title,attr,omitempty"`
+	Length int    `xml:"length,attr,omitempty"`
+	Count  *int   `xml:"thr:count,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Term   string `xml:"term,attr"`
+	Label  string `xml:"label,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomAuthor   `xml:"author"`
+	Identifier string         `xml:"dc:identifier,omitempty"`
+	Language   string         `xml:"dc:language,omitempty"`
+	Publisher  string         `xml:"dc:publisher,omitempty"`
+	Issued     string         `xml:"dc:issued,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Links      []atomLink     `xml:"link"`
+}
+
+// WriteAtom writes a feed as an OPDS 1.2 Atom document
+func WriteAtom(w io.Writer, title string, feed *Feed) error {
+	doc := atomFeed{
+		Xmlns:       "http://www.w3.org/2005/Atom",
+		XmlnsDC:     "http://purl.org/dc/terms/",
+		XmlnsOPDS:   "http://opds-spec.org/2010/catalog",
+		XmlnsSearch: "http://a9.com/-/spec/opensearch/1.1/",
+		XmlnsThread: "http://purl.org/syndication/thread/1.0",
+		ID:          urn(feed.ID),
+		Title:       feed.Title,
+		Updated:     atomTime(feed.Updated),
+		Author:      atomAuthor{Name: title},
+		Links: []atomLink{
+			{Rel: "self", Href: feed.Self, Type: feed.Kind.AtomType()},
+			{Rel: "start", Href: Root, Type: AtomNavigationType, Title: title},
+			{Rel: "search", Href: Root + "/opensearch.xml", Type: OpenSearchType},
+			{Rel: "alternate", Href: withFormat(feed.Self, "json"), Type: JSONType},
+		},
+	}
+	for _, link := range feed.Links {
+		doc.Links = append(doc.Links, atomLink{Rel: link.Rel, Href: link.Href, Type: link.Kind.AtomType(), Title: link.Title})
+	}
+	if feed.Kind == Acquisition {
+		doc.TotalResults = feed.Total
+		doc.ItemsPerPage = feed.PageSize
+		doc.StartIndex = (feed.Page-1)*feed.PageSize + 1
+	}
+
+	for _, entry := range feed.Entries {
+		count := entry.Count
+		link := atomLink{Rel: entry.Rel, Href: entry.Href, Type: entry.Kind.AtomType()}
+		if entry.Kind == Acquisition {
+			link.Count = &count
+		}
+		atom := atomEntry{
+			Title:   entry.Title,
+			ID:      urn(entry.ID),
+			Updated: atomTime(feed.Updated),
+			Links:   []atomLink{link},
+		}
+		if summary := entrySummary(entry); summary != "" {
+			atom.Content = &atomText{Type: "text", Text: summary}
+		}
+		doc.Entries = append(doc.Entries, atom)
+	}
+
+	for _, p := range feed.Publications {
+		doc.Entries = append(doc.Entries, atomPublication(p, feed.Updated))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// atomPublication returns the entry of a book
+func atomPublication(p Publication, updated time.Time) atomEntry {
+	book := p.Book
+	if !book.CreatedAt.IsZero() {
+		updated = book.CreatedAt
+	}
+	entry := atomEntry{
+		Title:     book.Title,
+		ID:        urn(fmt.Sprintf("book:%d", book.ID)),
+		Updated:   atomTime(updated),
+		Language:  book.Language,
+		Publisher: book.Publisher,
+		Links: []atomLink{
+			{Rel: "alternate", Href: fmt.Sprintf("/books/%d", book.ID), Type: "application/json", Title: "Catalog record"},
+		},
+	}
+	for _, name := range book.Authors() {
+		entry.Authors = append(entry.Authors, atomAuthor{Name: name})
+	}
+	if book.ISBN != "" {
+		entry.Identifier = "urn:isbn:" + book.ISBN
+	}
+	if book.PublishedYear > 0 {
+		entry.Issued = strconv.Itoa(book.PublishedYear)
+	}
+	for _, genre := range book.Genres {
+		entry.Categories = append(entry.Categories, atomCategory{Scheme: Root + "/genres", Term: genre, Label: genreTitle(genre)})
+	}
+	for _, tag := range book.Tags {
+		entry.Categories = append(entry.Categories, atomCategory{Term: tag, Label: tag})
+	}
+	if book.Cover != nil {
+		entry.Links = append(entry.Links, atomLink{Rel: relImage, Href: book.Cover.URL, Type: book.Cover.ContentType})
+		if thumb := book.Cover.Thumbnail("small"); thumb != nil {
+			entry.Links = append(entry.Links, atomLink{Rel: relThumbnail, Href: thumb.URL, Type: thumb.ContentType})
+		}
+	}
+	for _, asset := range p.Assets {
+		entry.Links = append(entry.Links, atomLink{
+			Rel:    relAcquisition,
+			Href:   asset.URL,
+			Type:   asset.ContentType,
+			Title:  asset.Filename,
+			Length: asset.Bytes,
+		})
+	}
+	return entry
+}
+
+type openSearchDescription struct {
+	XMLName        xml.Name        `xml:"OpenSearchDescription"`
+	Xmlns          string          `xml:"xmlns,attr"`
+	ShortName      string          `xml:"ShortName"`
+	Description    string          `xml:"Description"`
+	InputEncoding  string          `xml:"InputEncoding"`
+	OutputEncoding string          `xml:"OutputEncoding"`
+	URLs           []openSearchURL `xml:"Url"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// WriteOpenSearch writes the OpenSearch description that tells apps how to
+// search the catalog
+func WriteOpenSearch(w io.Writer, title string) error {
+	shortName := title
+	// OpenSearch limits the short name to 16 characters
+	for utf8.RuneCountInString(shortName) > 16 {
+		_, size := utf8.DecodeLastRuneInString(shortName)
+		shortName = shortName[:len(shortName)-size]
+	}
+	doc := openSearchDescription{
+		Xmlns:          "http://a9.com/-/spec/opensearch/1.1/",
+		ShortName:      shortName,
+		Description:    "Search " + title + " by title or author",
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URLs: []openSearchURL{
+			{Type: AtomAcquisitionType, Template: Root + "/search?q={searchTerms}"},
+			{Type: JSONType, Template: Root + "/search?q={searchTerms}&format=json"},
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// entrySummary describes a navigation entry for apps that show it
+func entrySummary(entry Entry) string {
+	switch {
+	case entry.Summary != "":
+		return entry.Summary
+	case entry.Count == 1:
+		return "1 book"
+	case entry.Count > 1:
+		return fmt.Sprintf("%d books", entry.Count)
+	default:
+		return ""
+	}
+}
+
+// atomTime formats a time as an Atom date
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,162 @@
+package opds
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// JSONType is the media type of OPDS 2.0 documents
+const JSONType = "application/opds+json"
+
+type jsonFeed struct {
+	Metadata     jsonMetadata      `json:"metadata"`
+	Links        []jsonLink        `json:"links"`
+	Navigation   []jsonLink        `json:"navigation,omitempty"`
+	Publications []jsonPublication `json:"publications,omitempty"`
+}
+
+type jsonMetadata struct {
+	Title         string `json:"title"`
+	Modified      string `json:"modified,omitempty"`
+	NumberOfItems *int   `json:"numberOfItems,omitempty"`
+	ItemsPerPage  int    `json:"itemsPerPage,omitempty"`
+	CurrentPage   int    `json:"currentPage,omitempty"`
+}
+
+type jsonLink struct {
+	Rel        string          `json:"rel,omitempty"`
+	Href       string          `json:"href"`
+	Type       string          `json:"type,omitempty"`
+	Title      string          `json:"title,omitempty"`
+	Templated  bool            `json:"templated,omitempty"`
+	Length     int             `json:"length,omitempty"`
+	Properties *jsonProperties `json:"properties,omitempty"`
+}
+
+type jsonProperties struct {
+	NumberOfItems int `json:"numberOfItems"`
+}
+
+type jsonPublication struct {
+	Metadata jsonBook   `json:"metadata"`
+	Links    []jsonLink `json:"links"`
+	Images   []jsonLink `json:"images,omitempty"`
+}
+
+type jsonBook struct {
+	Type       string            `json:"@type"`
+	Identifier string            `json:"identifier,omitempty"`
+	Title      string            `json:"title"`
+	Author     []jsonContributor `json:"author,omitempty"`
+	Publisher  string            `json:"publisher,omitempty"`
+	Language   string            `json:"language,omitempty"`
+	Published  string            `json:"published,omitempty"`
+	Modified   string            `json:"modified,omitempty"`
+	Subject    []jsonSubject     `json:"subject,omitempty"`
+}
+
+type jsonContributor struct {
+	Name string `json:"name"`
+}
+
+type jsonSubject struct {
+	Name   string `json:"name"`
+	Code   string `json:"code,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// WriteJSON writes a feed as an OPDS 2.0 JSON document
+func WriteJSON(w io.Writer, title string, feed *Feed) error {
+	doc := jsonFeed{
+		Metadata: jsonMetadata{
+			Title:    feed.Title,
+			Modified: feed.Updated.UTC().Format(time.RFC3339),
+		},
+		Links: []jsonLink{
+			{Rel: "self", Href: withFormat(feed.Self, "json"), Type: JSONType},
+			{Rel: "start", Href: withFormat(Root, "json"), Type: JSONType, Title: title},
+			{Rel: "search", Href: Root + "/search?format=json{&query}", Type: JSONType, Templated: true},
+			{Rel: "alternate", Href: withFormat(feed.Self, "atom"), Type: feed.Kind.AtomType()},
+		},
+	}
+	for _, link := range feed.Links {
+		doc.Links = append(doc.Links, jsonLink{Rel: link.Rel, Href: withFormat(link.Href, "json"), Type: JSONType, Title: link.Title})
+	}
+	if feed.Kind == Acquisition {
+		total := feed.Total
+		doc.Metadata.NumberOfItems = &total
+		doc.Metadata.ItemsPerPage = feed.PageSize
+		doc.Metadata.CurrentPage = feed.Page
+		// OPDS 2.0 requires the collection even when the page is empty
+		doc.Publications = make([]jsonPublication, 0, len(feed.Publications))
+	}
+
+	for _, entry := range feed.Entries {
+		link := jsonLink{Rel: entry.Rel, Href: withFormat(entry.Href, "json"), Type: JSONType, Title: entry.Title}
+		if entry.Kind == Acquisition {
+			link.Properties = &jsonProperties{NumberOfItems: entry.Count}
+		}
+		doc.Navigation = append(doc.Navigation, link)
+	}
+	for _, p := range feed.Publications {
+		doc.Publications = append(doc.Publications, jsonPublicationOf(p))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
+
+// jsonPublicationOf returns the publication of a book
+func jsonPublicationOf(p Publication) jsonPublication {
+	book := p.Book
+	pub := jsonPublication{
+		Metadata: jsonBook{
+			Type:      "http://schema.org/Book",
+			Title:     book.Title,
+			Publisher: book.Publisher,
+			Language:  book.Language,
+		},
+		Links: []jsonLink{
+			{Rel: "self", Href: fmt.Sprintf("/books/%d", book.ID), Type: "application/json"},
+		},
+	}
+	if book.ISBN != "" {
+		pub.Metadata.Identifier = "urn:isbn:" + book.ISBN
+	}
+	for _, name := range book.Authors() {
+		pub.Metadata.Author = append(pub.Metadata.Author, jsonContributor{Name: name})
+	}
+	if book.PublishedYear > 0 {
+		pub.Metadata.Published = strconv.Itoa(book.PublishedYear)
+	}
+	if !book.CreatedAt.IsZero() {
+		pub.Metadata.Modified = book.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	for _, genre := range book.Genres {
+		pub.Metadata.Subject = append(pub.Metadata.Subject, jsonSubject{Name: genreTitle(genre), Code: genre, Scheme: Root + "/genres"})
+	}
+	for _, tag := range book.Tags {
+		pub.Metadata.Subject = append(pub.Metadata.Subject, jsonSubject{Name: tag})
+	}
+	for _, asset := range p.Assets {
+		pub.Links = append(pub.Links, jsonLink{
+			Rel:    relAcquisition,
+			Href:   asset.URL,
+			Type:   asset.ContentType,
+			Title:  asset.Filename,
+			Length: asset.Bytes,
+		})
+	}
+	if book.Cover != nil {
+		pub.Images = append(pub.Images, jsonLink{Href: book.Cover.URL, Type: book.Cover.ContentType})
+		for _, thumb := range book.Cover.Thumbnails {
+			pub.Images = append(pub.Images, jsonLink{Href: thumb.URL, Type: thumb.ContentType})
+		}
+	}
+	return pub
+}
@@ -0,0 +1,420 @@
+// Package opds publishes the catalogue as OPDS feeds for e-reader apps.
+// Feeds are built once from storage and written either as OPDS 1.2 Atom
+// documents or as OPDS 2.0 JSON documents.
+package opds
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// Root is the path of the start feed; every other feed lives under it
+const Root = "/opds"
+
+// DefaultTitle is the title of the start feed unless SetTitle changes it
+const DefaultTitle = "Library Catalog"
+
+// Kind tells navigation feeds, which list other feeds, from acquisition
+// feeds, which list books
+type Kind string
+
+// Feed kinds
+const (
+	Navigation  Kind = "navigation"
+	Acquisition Kind = "acquisition"
+)
+
+// Relations of navigation entries to their feeds
+const (
+	RelSubsection = "subsection"
+	RelNew        = "http://opds-spec.org/sort/new"
+)
+
+var (
+	// ErrNotFound is returned for an author or genre with no books
+	ErrNotFound = errors.New("no books found")
+
+	// ErrEmptyQuery is returned for a search without search terms
+	ErrEmptyQuery = errors.New("search terms are required")
+)
+
+// Feed is an OPDS feed independent of the version it is written as
+type Feed struct {
+	// ID is unique to the feed and stable across requests
+	ID    string
+	Title string
+	// Self is the path and query of the feed, without a format parameter
+	Self    string
+	Kind    Kind
+	Updated time.Time
+	// Links are the feed's up and paging links
+	Links []Link
+	// Entries lists the feeds of a navigation feed
+	Entries []Entry
+	// Publications lists the books of an acquisition feed, one page at a
+	// time
+	Publications []Publication
+	Page         int
+	PageSize     int
+	Total        int
+}
+
+// Link is a link from a feed to a related one
+type Link struct {
+	Rel   string
+	Href  string
+	Kind  Kind
+	Title string
+}
+
+// Entry is an entry of a navigation feed
+type Entry struct {
+	ID      string
+	Title   string
+	Summary string
+	Rel     string
+	Href    string
+	Kind    Kind
+	// Count is the number of books in the linked feed
+	Count int
+}
+
+// Publication is a book with the files it can be downloaded as
+type Publication struct {
+	Book   models.Book
+	Assets []models.Asset
+}
+
+// Catalog builds feeds from the book storage and, if given, the asset
+// storage the downloadable files are recorded in
+type Catalog struct {
+	books  storage.Storage
+	assets storage.AssetStorage
+	title  string
+	now    func() time.Time
+}
+
+// NewCatalog creates a catalog. assets may be nil, in which case no book
+// has acquisition links
+func NewCatalog(books storage.Storage, assets storage.AssetStorage) *Catalog {
+	return &Catalog{
+		books:  books,
+		assets: assets,
+		title:  DefaultTitle,
+		now:    time.Now,
+	}
+}
+
+// SetTitle sets the title of the start feed, which apps show as the name of
+// the catalog
+func (c *Catalog) SetTitle(title string) {
+	c.title = title
+}
+
+// Title returns the title of the start feed
+func (c *Catalog) Title() string {
+	return c.title
+}
+
+// Start returns the navigation feed apps start browsing from
+func (c *Catalog) Start() (*Feed, error) {
+	books, err := c.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return &Feed{
+		ID:      "root",
+		Title:   c.title,
+		Self:    Root,
+		Kind:    Navigation,
+		Updated: c.now().UTC(),
+		Entries: []Entry{
+			{ID: "new", Title: "New Arrivals", Summary: "The most recently added books", Rel: RelNew, Href: Root + "/new", Kind: Acquisition, Count: len(books)},
+			{ID: "books", Title: "All Books", Summary: "Every book, by title", Rel: RelSubsection, Href: Root + "/books", Kind: Acquisition, Count: len(books)},
+			{ID: "authors", Title: "By Author", Summary: "Books grouped by author", Rel: RelSubsection, Href: Root + "/authors", Kind: Navigation},
+			{ID: "genres", Title: "By Genre", Summary: "Books grouped by genre", Rel: RelSubsection, Href: Root + "/genres", Kind: Navigation},
+		},
+	}, nil
+}
+
+// New returns a page of books, most recently added first
+func (c *Catalog) New(params models.PaginationParams) (*Feed, error) {
+	books, err := c.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(books, func(i, j int) bool {
+		if !books[i].CreatedAt.Equal(books[j].CreatedAt) {
+			return books[i].CreatedAt.After(books[j].CreatedAt)
+		}
+		return books[i].ID > books[j].ID
+	})
+	return c.acquisition("new", "New Arrivals", Root+"/new", nil, books, params)
+}
+
+// All returns a page of every book, by title
+func (c *Catalog) All(params models.PaginationParams) (*Feed, error) {
+	books, err := c.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	sortByTitle(books)
+	return c.acquisition("books", "All Books", Root+"/books", nil, books, params)
+}
+
+// Authors returns a navigation feed with an entry for each author, by name
+func (c *Catalog) Authors() (*Feed, error) {
+	books, err := c.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	names := make(map[string]string)
+	for _, book := range books {
+		seen := make(map[string]bool)
+		for _, name := range book.Authors() {
+			key := strings.ToLower(name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if _, ok := names[key]; !ok {
+				names[key] = name
+			}
+			counts[key]++
+		}
+	}
+
+	keys := make([]string, 0, len(names))
+	for key := range names {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	feed := c.navigation("authors", "By Author", Root+"/authors")
+	for _, key := range keys {
+		feed.Entries = append(feed.Entries, Entry{
+			ID:    "author:" + key,
+			Title: names[key],
+			Rel:   RelSubsection,
+			Href:  Root + "/authors/" + url.PathEscape(names[key]),
+			Kind:  Acquisition,
+			Count: counts[key],
+		})
+	}
+	return feed, nil
+}
+
+// Author returns a page of the books by an author, by title
+func (c *Catalog) Author(name string, params models.PaginationParams) (*Feed, error) {
+	books, err := c.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]models.Book, 0)
+	for _, book := range books {
+		for _, author := range book.Authors() {
+			if strings.EqualFold(author, name) {
+				matched = append(matched, book)
+				break
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return nil, ErrNotFound
+	}
+	sortByTitle(matched)
+	up := &Link{Rel: "up", Href: Root + "/authors", Kind: Navigation, Title: "By Author"}
+	return c.acquisition("author:"+strings.ToLower(name), name, Root+"/authors/"+url.PathEscape(name), up, matched, params)
+}
+
+// Genres returns a navigation feed with an entry for each genre that has
+// books, in vocabulary order
+func (c *Catalog) Genres() (*Feed, error) {
+	books, err := c.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, book := range books {
+		for _, genre := range book.Genres {
+			counts[genre]++
+		}
+	}
+
+	feed := c.navigation("genres", "By Genre", Root+"/genres")
+	for _, genre := range models.Genres {
+		if counts[genre] == 0 {
+			continue
+		}
+		feed.Entries = append(feed.Entries, Entry{
+			ID:    "genre:" + genre,
+			Title: genreTitle(genre),
+			Rel:   RelSubsection,
+			Href:  Root + "/genres/" + genre,
+			Kind:  Acquisition,
+			Count: counts[genre],
+		})
+	}
+	return feed, nil
+}
+
+// Genre returns a page of the books in a genre, by title
+func (c *Catalog) Genre(genre string, params models.PaginationParams) (*Feed, error) {
+	genre = strings.ToLower(genre)
+	if !models.ValidGenre(genre) {
+		return nil, ErrNotFound
+	}
+	books, err := c.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	filters := models.BookFilters{Genres: []string{genre}}
+	matched := make([]models.Book, 0)
+	for _, book := range books {
+		if filters.Match(book) {
+			matched = append(matched, book)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, ErrNotFound
+	}
+	sortByTitle(matched)
+	up := &Link{Rel: "up", Href: Root + "/genres", Kind: Navigation, Title: "By Genre"}
+	return c.acquisition("genre:"+genre, genreTitle(genre), Root+"/genres/"+genre, up, matched, params)
+}
+
+// Search returns a page of the books whose title or author contains query,
+// by title
+func (c *Catalog) Search(query string, params models.PaginationParams) (*Feed, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptyQuery
+	}
+	books, err := c.books.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	filters := models.BookFilters{Search: query}
+	matched := make([]models.Book, 0)
+	for _, book := range books {
+		if filters.Match(book) {
+			matched = append(matched, book)
+		}
+	}
+	sortByTitle(matched)
+	self := Root + "/search?q=" + url.QueryEscape(query)
+	return c.acquisition("search:"+query, fmt.Sprintf("Search results for %q", query), self, nil, matched, params)
+}
+
+// navigation returns an empty navigation feed linking up to the start feed
+func (c *Catalog) navigation(id, title, self string) *Feed {
+	return &Feed{
+		ID:      id,
+		Title:   title,
+		Self:    self,
+		Kind:    Navigation,
+		Updated: c.now().UTC(),
+		Links:   []Link{{Rel: "up", Href: Root, Kind: Navigation, Title: c.title}},
+	}
+}
+
+// acquisition returns the page of books params asks for, with links to the
+// other pages and up to the parent feed, or the start feed if up is nil
+func (c *Catalog) acquisition(id, title, self string, up *Link, books []models.Book, params models.PaginationParams) (*Feed, error) {
+	if up == nil {
+		up = &Link{Rel: "up", Href: Root, Kind: Navigation, Title: c.title}
+	}
+	feed := &Feed{
+		ID:       id,
+		Title:    title,
+		Kind:     Acquisition,
+		Updated:  c.now().UTC(),
+		Links:    []Link{*up},
+		Page:     params.Page,
+		PageSize: params.PageSize,
+		Total:    len(books),
+	}
+
+	page := func(n int) string {
+		sep := "?"
+		if strings.Contains(self, "?") {
+			sep = "&"
+		}
+		return fmt.Sprintf("%s%spage=%d&page_size=%d", self, sep, n, params.PageSize)
+	}
+	feed.Self = page(params.Page)
+	last := max(1, (len(books)+params.PageSize-1)/params.PageSize)
+	feed.Links = append(feed.Links,
+		Link{Rel: "first", Href: page(1), Kind: Acquisition},
+		Link{Rel: "last", Href: page(last), Kind: Acquisition},
+	)
+	if params.Page > 1 {
+		feed.Links = append(feed.Links, Link{Rel: "previous", Href: page(min(params.Page-1, last)), Kind: Acquisition})
+	}
+	if params.Page < last {
+		feed.Links = append(feed.Links, Link{Rel: "next", Href: page(params.Page + 1), Kind: Acquisition})
+	}
+
+	start, end := params.Bounds(len(books))
+	for _, book := range books[start:end] {
+		publication := Publication{Book: book}
+		if c.assets != nil {
+			assets, err := c.assets.GetAssets(book.ID)
+			if err != nil {
+				return nil, err
+			}
+			for i := range assets {
+				assets[i].URL = assets[i].Path()
+			}
+			publication.Assets = assets
+		}
+		feed.Publications = append(feed.Publications, publication)
+	}
+	return feed, nil
+}
+
+// sortByTitle sorts books by title, ignoring case, then by ID
+func sortByTitle(books []models.Book) {
+	sort.SliceStable(books, func(i, j int) bool {
+		a, b := strings.ToLower(books[i].Title), strings.ToLower(books[j].Title)
+		if a != b {
+			return a < b
+		}
+		return books[i].ID < books[j].ID
+	})
+}
+
+// genreTitle returns the display name of a genre, such as Science Fiction
+// for science-fiction
+func genreTitle(genre string) string {
+	words := strings.Split(genre, "-")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// urn returns the Atom ID of a feed or entry
+func urn(id string) string {
+	return "urn:book-api:opds:" + url.PathEscape(id)
+}
+
+// withFormat adds a format parameter to a path, so that apps that cannot
+// send an Accept header can follow links to the other OPDS version
+func withFormat(href, format string) string {
+	if strings.Contains(href, "?") {
+		return href + "&format=" + format
+	}
+	return href + "?format=" + format
+}
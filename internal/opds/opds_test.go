@@ -0,0 +1,334 @@
+package opds
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newTestCatalog returns a catalog of four books, one of them with a file
+// and a cover
+func newTestCatalog(t *testing.T) (*Catalog, map[string]*models.Book) {
+	t.Helper()
+
+	books := storage.NewMemoryStorage()
+	assets := storage.NewMemoryAssetStorage()
+	c := NewCatalog(books, assets)
+	c.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	created := make(map[string]*models.Book)
+	for _, book := range []models.Book{
+		{Title: "Dune", Author: "Frank Herbert", Genres: []string{"science-fiction"}, ISBN: "9780441013593", PublishedYear: 1965},
+		{Title: "A Wizard of Earthsea", Author: "Ursula K. Le Guin", Genres: []string{"fantasy"}},
+		{Title: "The Left Hand of Darkness", Author: "Ursula K. Le Guin", Genres: []string{"science-fiction"}, Tags: []string{"hugo"}},
+		{Title: "Good Omens", Author: "Terry Pratchett; Neil Gaiman", Genres: []string{"fantasy", "fiction"}},
+	} {
+		b, _ := books.Create(book)
+		created[book.Title] = b
+	}
+
+	dune := created["Dune"]
+	books.SetCover(dune.ID, &models.Cover{URL: "/books/1/cover", ContentType: "image/png", Thumbnails: []models.Thumbnail{
+		{Size: "small", URL: "/books/1/cover?size=small", ContentType: "image/jpeg"},
+	}})
+	assets.CreateAsset(models.Asset{BookID: dune.ID, Filename: "dune.epub", Format: "epub", ContentType: "application/epub+zip", Bytes: 1234})
+	return c, created
+}
+
+// titles returns the titles of the books in a feed
+func titles(feed *Feed) []string {
+	var list []string
+	for _, p := range feed.Publications {
+		list = append(list, p.Book.Title)
+	}
+	return list
+}
+
+func TestCatalog_Start(t *testing.T) {
+	c, _ := newTestCatalog(t)
+	c.SetTitle("City Library")
+
+	feed, err := c.Start()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if feed.Kind != Navigation || feed.Title != "City Library" || len(feed.Entries) != 4 {
+		t.Fatalf("unexpected start feed %+v", feed)
+	}
+	if feed.Entries[0].Rel != RelNew || feed.Entries[0].Count != 4 {
+		t.Errorf("expected new arrivals first, with every book, got %+v", feed.Entries[0])
+	}
+}
+
+func TestCatalog_New(t *testing.T) {
+	c, _ := newTestCatalog(t)
+
+	feed, err := c.New(models.NewPaginationParams(1, 10))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(feed.Publications) != 4 {
+		t.Fatalf("expected four books, got %d", len(feed.Publications))
+	}
+	for i := 1; i < len(feed.Publications); i++ {
+		if feed.Publications[i].Book.CreatedAt.After(feed.Publications[i-1].Book.CreatedAt) {
+			t.Errorf("expected the most recently added first, got %v", titles(feed))
+		}
+	}
+}
+
+func TestCatalog_All_Pages(t *testing.T) {
+	c, _ := newTestCatalog(t)
+
+	feed, err := c.All(models.NewPaginationParams(1, 3))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{"A Wizard of Earthsea", "Dune", "Good Omens"}
+	if strings.Join(titles(feed), "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %v, got %v", expected, titles(feed))
+	}
+	if feed.Total != 4 || feed.Self != "/opds/books?page=1&page_size=3" {
+		t.Errorf("unexpected total %d or self %q", feed.Total, feed.Self)
+	}
+
+	links := make(map[string]string)
+	for _, link := range feed.Links {
+		links[link.Rel] = link.Href
+	}
+	if links["next"] != "/opds/books?page=2&page_size=3" || links["last"] != "/opds/books?page=2&page_size=3" || links["up"] != "/opds" {
+		t.Errorf("unexpected links %v", links)
+	}
+	if _, ok := links["previous"]; ok {
+		t.Error("expected no previous link on the first page")
+	}
+
+	var dune Publication
+	for _, p := range feed.Publications {
+		if p.Book.Title == "Dune" {
+			dune = p
+		}
+	}
+	if len(dune.Assets) != 1 || dune.Assets[0].URL != dune.Assets[0].Path() {
+		t.Errorf("expected Dune's file with its URL, got %+v", dune.Assets)
+	}
+}
+
+func TestCatalog_Authors(t *testing.T) {
+	c, _ := newTestCatalog(t)
+
+	feed, err := c.Authors()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var names []string
+	for _, entry := range feed.Entries {
+		names = append(names, entry.Title)
+		if entry.Title == "Ursula K. Le Guin" && (entry.Count != 2 || entry.Href != "/opds/authors/Ursula%20K.%20Le%20Guin") {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+	}
+	expected := "Frank Herbert|Neil Gaiman|Terry Pratchett|Ursula K. Le Guin"
+	if strings.Join(names, "|") != expected {
+		t.Errorf("expected %s, got %v", expected, names)
+	}
+
+	books, err := c.Author("ursula k. le guin", models.NewPaginationParams(1, 10))
+	if err != nil || len(books.Publications) != 2 {
+		t.Errorf("expected Le Guin's two books, got %v, %v", titles(books), err)
+	}
+	if _, err := c.Author("Nobody", models.NewPaginationParams(1, 10)); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCatalog_Genres(t *testing.T) {
+	c, _ := newTestCatalog(t)
+
+	feed, err := c.Genres()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var names []string
+	for _, entry := range feed.Entries {
+		names = append(names, entry.Title)
+	}
+	if strings.Join(names, "|") != "Fantasy|Fiction|Science Fiction" {
+		t.Errorf("expected only genres with books, got %v", names)
+	}
+
+	books, err := c.Genre("Science-Fiction", models.NewPaginationParams(1, 10))
+	if err != nil || strings.Join(titles(books), "|") != "Dune|The Left Hand of Darkness" {
+		t.Errorf("expected the science fiction, got %v, %v", titles(books), err)
+	}
+	for _, genre := range []string{"horror", "not-a-genre"} {
+		if _, err := c.Genre(genre, models.NewPaginationParams(1, 10)); err != ErrNotFound {
+			t.Errorf("expected ErrNotFound for %s, got %v", genre, err)
+		}
+	}
+}
+
+func TestCatalog_Search(t *testing.T) {
+	c, _ := newTestCatalog(t)
+
+	feed, err := c.Search("le guin", models.NewPaginationParams(1, 10))
+	if err != nil || len(feed.Publications) != 2 {
+		t.Errorf("expected two books, got %v, %v", titles(feed), err)
+	}
+	if feed.Self != "/opds/search?q=le+guin&page=1&page_size=10" {
+		t.Errorf("unexpected self %q", feed.Self)
+	}
+	if _, err := c.Search("  ", models.NewPaginationParams(1, 10)); err != ErrEmptyQuery {
+		t.Errorf("expected ErrEmptyQuery, got %v", err)
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	c, _ := newTestCatalog(t)
+	feed, _ := c.Genre("science-fiction", models.NewPaginationParams(1, 10))
+
+	var buf bytes.Buffer
+	if err := WriteAtom(&buf, c.Title(), feed); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var doc struct {
+		ID           string `xml:"id"`
+		TotalResults int    `xml:"totalResults"`
+		Links        []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			Title      string `xml:"title"`
+			Identifier string `xml:"identifier"`
+			Links      []struct {
+				Rel    string `xml:"rel,attr"`
+				Href   string `xml:"href,attr"`
+				Type   string `xml:"type,attr"`
+				Length int    `xml:"length,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("expected valid XML, got %v:\n%s", err, buf.String())
+	}
+	if doc.ID != "urn:book-api:opds:genre:science-fiction" || doc.TotalResults != 2 || len(doc.Entries) != 2 {
+		t.Errorf("unexpected feed %+v", doc)
+	}
+
+	rels := make(map[string]string)
+	for _, link := range doc.Links {
+		rels[link.Rel] = link.Href
+	}
+	if rels["self"] == "" || rels["search"] != "/opds/opensearch.xml" || rels["up"] != "/opds/genres" ||
+		!strings.HasSuffix(rels["alternate"], "format=json") {
+		t.Errorf("unexpected feed links %v", rels)
+	}
+
+	dune := doc.Entries[0]
+	if dune.Title != "Dune" || dune.Identifier != "urn:isbn:9780441013593" {
+		t.Errorf("unexpected entry %+v", dune)
+	}
+	found := make(map[string]bool)
+	for _, link := range dune.Links {
+		found[link.Rel] = true
+		if link.Rel == relAcquisition && (link.Type != "application/epub+zip" || link.Length != 1234) {
+			t.Errorf("unexpected acquisition link %+v", link)
+		}
+	}
+	if !found[relAcquisition] || !found[relImage] || !found[relThumbnail] {
+		t.Errorf("expected acquisition, image and thumbnail links, got %+v", dune.Links)
+	}
+}
+
+func TestWriteAtom_Navigation(t *testing.T) {
+	c, _ := newTestCatalog(t)
+	feed, _ := c.Genres()
+
+	var buf bytes.Buffer
+	if err := WriteAtom(&buf, c.Title(), feed); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`xmlns:thr="http://purl.org/syndication/thread/1.0"`,
+		`href="/opds/genres/fantasy" type="application/atom+xml;profile=opds-catalog;kind=acquisition" thr:count="2"`,
+		`<content type="text">2 books</content>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected feed to contain %s, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	c, _ := newTestCatalog(t)
+	feed, _ := c.All(models.NewPaginationParams(1, 10))
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, c.Title(), feed); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var doc jsonFeed
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+	if doc.Metadata.NumberOfItems == nil || *doc.Metadata.NumberOfItems != 4 || len(doc.Publications) != 4 {
+		t.Fatalf("unexpected feed %+v", doc)
+	}
+	if doc.Links[0].Rel != "self" || doc.Links[0].Href != "/opds/books?page=1&page_size=10&format=json" {
+		t.Errorf("unexpected self link %+v", doc.Links[0])
+	}
+
+	dune := doc.Publications[1]
+	if dune.Metadata.Title != "Dune" || dune.Metadata.Published != "1965" || len(dune.Images) != 2 {
+		t.Errorf("unexpected publication %+v", dune)
+	}
+	if last := dune.Links[len(dune.Links)-1]; last.Rel != relAcquisition || last.Type != "application/epub+zip" {
+		t.Errorf("expected an acquisition link, got %+v", dune.Links)
+	}
+	omens := doc.Publications[2]
+	if len(omens.Metadata.Author) != 2 || omens.Metadata.Subject[0].Code != "fantasy" {
+		t.Errorf("unexpected publication %+v", omens)
+	}
+}
+
+func TestWriteJSON_Navigation(t *testing.T) {
+	c, _ := newTestCatalog(t)
+	feed, _ := c.Start()
+
+	var buf bytes.Buffer
+	WriteJSON(&buf, c.Title(), feed)
+	var doc jsonFeed
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+	if len(doc.Navigation) != 4 || doc.Publications != nil || doc.Navigation[0].Href != "/opds/new?format=json" {
+		t.Errorf("unexpected navigation feed %+v", doc)
+	}
+}
+
+func TestWriteOpenSearch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteOpenSearch(&buf, "Springfield Public Library"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<ShortName>Springfield Publ</ShortName>",
+		`template="/opds/search?q={searchTerms}"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected description to contain %s, got:\n%s", want, out)
+		}
+	}
+}
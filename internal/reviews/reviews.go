@@ -0,0 +1,237 @@
+// Package reviews lets patrons rate and review books, holds reviews for
+// moderation and keeps each book's average rating up to date.
+package reviews
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// Service manages reviews against the book, patron and review storages
+type Service struct {
+	books   storage.Storage
+	patrons storage.PatronStorage
+	reviews storage.ReviewStorage
+	// mu serializes writes so that a book's rating matches its reviews
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewService creates a reviews service. The book storage must implement
+// storage.Ratings for ratings to appear on books
+func NewService(books storage.Storage, patrons storage.PatronStorage, reviews storage.ReviewStorage) *Service {
+	return &Service{
+		books:   books,
+		patrons: patrons,
+		reviews: reviews,
+		now:     time.Now,
+	}
+}
+
+// BookReviews returns the approved reviews of a book, newest first
+func (s *Service) BookReviews(bookID int) ([]models.Review, error) {
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	reviews, err := s.reviews.GetReviews(models.ReviewFilter{BookID: bookID, Status: models.ReviewApproved})
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(reviews)-1; i < j; i, j = i+1, j-1 {
+		reviews[i], reviews[j] = reviews[j], reviews[i]
+	}
+	return reviews, nil
+}
+
+// Reviews returns the reviews with a given status, or all reviews if status
+// is empty, oldest first
+func (s *Service) Reviews(status models.ReviewStatus) ([]models.Review, error) {
+	if status != "" && !status.Valid() {
+		return nil, models.ErrInvalidReviewStatus
+	}
+	return s.reviews.GetReviews(models.ReviewFilter{Status: status})
+}
+
+// Review returns a review of a book by its ID
+func (s *Service) Review(bookID, reviewID int) (*models.Review, error) {
+	review, err := s.reviews.GetReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.BookID != bookID {
+		return nil, models.ErrReviewNotFound
+	}
+	return review, nil
+}
+
+// Submit adds a patron's review of a book, pending moderation. A patron can
+// review each book only once
+func (s *Service) Submit(bookID int, review models.Review) (*models.Review, error) {
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	if _, err := s.patrons.GetPatron(review.PatronID); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	return s.reviews.CreateReview(models.Review{
+		BookID:    bookID,
+		PatronID:  review.PatronID,
+		Rating:    review.Rating,
+		Text:      review.Text,
+		Status:    models.ReviewPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+// Update replaces the rating and text of a review, which then waits for
+// moderation again
+func (s *Service) Update(bookID, reviewID int, review models.Review) (*models.Review, error) {
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.Review(bookID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	wasApproved := existing.Status == models.ReviewApproved
+	existing.Rating = review.Rating
+	existing.Text = review.Text
+	existing.Status = models.ReviewPending
+	existing.UpdatedAt = s.now().UTC()
+	updated, err := s.reviews.UpdateReview(*existing)
+	if err != nil {
+		return nil, err
+	}
+	if wasApproved {
+		if err := s.updateRating(bookID); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+// Delete removes a review of a book
+func (s *Service) Delete(bookID, reviewID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.Review(bookID, reviewID)
+	if err != nil {
+		return err
+	}
+	if err := s.reviews.DeleteReview(reviewID); err != nil {
+		return err
+	}
+	if existing.Status == models.ReviewApproved {
+		return s.updateRating(bookID)
+	}
+	return nil
+}
+
+// Moderate approves or rejects a review on behalf of moderator, updating the
+// book's rating if the review starts or stops counting towards it
+func (s *Service) Moderate(reviewID int, status models.ReviewStatus, moderator, note string) (*models.Review, error) {
+	if status != models.ReviewApproved && status != models.ReviewRejected {
+		return nil, models.ErrInvalidReviewStatus
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	review, err := s.reviews.GetReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	changed := review.Status != status
+	now := s.now().UTC()
+	review.Status = status
+	review.ModeratedBy = moderator
+	review.ModeratedAt = &now
+	review.ModerationNote = note
+	review.UpdatedAt = now
+	moderated, err := s.reviews.UpdateReview(*review)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := s.updateRating(review.BookID); err != nil {
+			return nil, err
+		}
+	}
+	return moderated, nil
+}
+
+// MoveBook moves the reviews of one book to another when the first is merged
+// into it. A patron who reviewed both books keeps their review of the book
+// merged into
+func (s *Service) MoveBook(from, to int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reviews, err := s.reviews.GetReviews(models.ReviewFilter{BookID: from})
+	if err != nil {
+		return err
+	}
+	for _, review := range reviews {
+		existing, err := s.reviews.GetReviews(models.ReviewFilter{BookID: to, PatronID: review.PatronID})
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			err = s.reviews.DeleteReview(review.ID)
+		} else {
+			review.BookID = to
+			_, err = s.reviews.UpdateReview(review)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return s.updateRating(to)
+}
+
+// updateRating recomputes a book's rating from its approved reviews, rounded
+// to two decimal places; s.mu must be held
+func (s *Service) updateRating(bookID int) error {
+	ratings, ok := s.books.(storage.Ratings)
+	if !ok {
+		return nil
+	}
+	approved, err := s.reviews.GetReviews(models.ReviewFilter{BookID: bookID, Status: models.ReviewApproved})
+	if err != nil {
+		return err
+	}
+
+	average := 0.0
+	if len(approved) > 0 {
+		sum := 0
+		for _, review := range approved {
+			sum += review.Rating
+		}
+		average = math.Round(float64(sum)/float64(len(approved))*100) / 100
+	}
+	err = ratings.SetRating(bookID, average, len(approved))
+	// A book purged from the trash has no rating left to update
+	if err == models.ErrBookNotFound {
+		return nil
+	}
+	return err
+}
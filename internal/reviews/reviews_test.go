@@ -0,0 +1,173 @@
+package reviews
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+// newTestService returns a service with one book and two patrons
+func newTestService(t *testing.T) (*Service, *models.Book, *models.Patron, *models.Patron) {
+	t.Helper()
+
+	books := storage.NewMemoryStorage()
+	patrons := storage.NewMemoryPatronStorage()
+	s := NewService(books, patrons, storage.NewMemoryReviewStorage())
+	s.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	book, _ := books.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	ada, _ := patrons.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	bob, _ := patrons.CreatePatron(models.Patron{Name: "Bob", Type: models.PatronAdult})
+	return s, book, ada, bob
+}
+
+// rating returns the rating summary stored on a book
+func rating(t *testing.T, s *Service, bookID int) (float64, int) {
+	t.Helper()
+
+	book, err := s.books.GetByID(bookID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return book.AverageRating, book.RatingCount
+}
+
+func TestService_Submit(t *testing.T) {
+	s, book, ada, _ := newTestService(t)
+
+	review, err := s.Submit(book.ID, models.Review{PatronID: ada.ID, Rating: 4, Text: " Solid "})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if review.Status != models.ReviewPending || review.Text != "Solid" || review.BookID != book.ID {
+		t.Errorf("expected a pending review of book %d, got %+v", book.ID, review)
+	}
+
+	tests := []struct {
+		name    string
+		bookID  int
+		review  models.Review
+		wantErr error
+	}{
+		{"second review by the same patron", book.ID, models.Review{PatronID: ada.ID, Rating: 5}, models.ErrDuplicateReview},
+		{"unknown book", 999, models.Review{PatronID: ada.ID, Rating: 5}, models.ErrBookNotFound},
+		{"unknown patron", book.ID, models.Review{PatronID: 999, Rating: 5}, models.ErrPatronNotFound},
+		{"invalid rating", book.ID, models.Review{PatronID: ada.ID, Rating: 0}, models.ErrInvalidRating},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Submit(tt.bookID, tt.review); err != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Pending reviews do not count towards the rating or appear on the book
+	if average, count := rating(t, s, book.ID); average != 0 || count != 0 {
+		t.Errorf("expected no rating, got %v from %d", average, count)
+	}
+	if list, _ := s.BookReviews(book.ID); len(list) != 0 {
+		t.Errorf("expected no approved reviews, got %d", len(list))
+	}
+}
+
+func TestService_Moderate(t *testing.T) {
+	s, book, ada, bob := newTestService(t)
+
+	first, _ := s.Submit(book.ID, models.Review{PatronID: ada.ID, Rating: 5})
+	second, _ := s.Submit(book.ID, models.Review{PatronID: bob.ID, Rating: 2})
+
+	moderated, err := s.Moderate(first.ID, models.ReviewApproved, "admin", "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if moderated.ModeratedBy != "admin" || moderated.ModeratedAt == nil {
+		t.Errorf("expected the moderator to be recorded, got %+v", moderated)
+	}
+	s.Moderate(second.ID, models.ReviewApproved, "admin", "")
+	if average, count := rating(t, s, book.ID); average != 3.5 || count != 2 {
+		t.Errorf("expected rating 3.5 from 2 reviews, got %v from %d", average, count)
+	}
+	if list, _ := s.BookReviews(book.ID); len(list) != 2 || list[0].ID != second.ID {
+		t.Errorf("expected both reviews, newest first, got %+v", list)
+	}
+
+	s.Moderate(second.ID, models.ReviewRejected, "admin", "spoilers")
+	if average, count := rating(t, s, book.ID); average != 5 || count != 1 {
+		t.Errorf("expected rating 5 from 1 review, got %v from %d", average, count)
+	}
+
+	if _, err := s.Moderate(first.ID, models.ReviewPending, "admin", ""); err != models.ErrInvalidReviewStatus {
+		t.Errorf("expected ErrInvalidReviewStatus, got %v", err)
+	}
+	if _, err := s.Moderate(999, models.ReviewApproved, "admin", ""); err != models.ErrReviewNotFound {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+}
+
+func TestService_UpdateAndDelete(t *testing.T) {
+	s, book, ada, bob := newTestService(t)
+
+	first, _ := s.Submit(book.ID, models.Review{PatronID: ada.ID, Rating: 5})
+	second, _ := s.Submit(book.ID, models.Review{PatronID: bob.ID, Rating: 3})
+	s.Moderate(first.ID, models.ReviewApproved, "admin", "")
+	s.Moderate(second.ID, models.ReviewApproved, "admin", "")
+
+	// An edited review goes back to the moderation queue
+	updated, err := s.Update(book.ID, first.ID, models.Review{Rating: 1, Text: "Changed my mind"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Status != models.ReviewPending || updated.Rating != 1 || updated.PatronID != ada.ID {
+		t.Errorf("expected a pending one star review by patron %d, got %+v", ada.ID, updated)
+	}
+	if average, count := rating(t, s, book.ID); average != 3 || count != 1 {
+		t.Errorf("expected rating 3 from 1 review, got %v from %d", average, count)
+	}
+	if queue, _ := s.Reviews(models.ReviewPending); len(queue) != 1 || queue[0].ID != first.ID {
+		t.Errorf("expected the edited review in the queue, got %+v", queue)
+	}
+
+	if err := s.Delete(book.ID, second.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if average, count := rating(t, s, book.ID); average != 0 || count != 0 {
+		t.Errorf("expected no rating, got %v from %d", average, count)
+	}
+
+	// Reviews are only found through the book they belong to
+	if _, err := s.Review(999, first.ID); err != models.ErrReviewNotFound {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+	if err := s.Delete(book.ID, second.ID); err != models.ErrReviewNotFound {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+}
+
+func TestService_MoveBook(t *testing.T) {
+	s, book, ada, bob := newTestService(t)
+	duplicate, _ := s.books.Create(models.Book{Title: "Clean Code", Author: "Robert Martin"})
+
+	kept, _ := s.Submit(book.ID, models.Review{PatronID: ada.ID, Rating: 5})
+	dropped, _ := s.Submit(duplicate.ID, models.Review{PatronID: ada.ID, Rating: 1})
+	moved, _ := s.Submit(duplicate.ID, models.Review{PatronID: bob.ID, Rating: 3})
+	for _, review := range []*models.Review{kept, dropped, moved} {
+		s.Moderate(review.ID, models.ReviewApproved, "admin", "")
+	}
+
+	if err := s.MoveBook(duplicate.ID, book.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if list, _ := s.BookReviews(book.ID); len(list) != 2 || list[0].ID != moved.ID || list[1].ID != kept.ID {
+		t.Errorf("expected the kept and moved reviews, got %+v", list)
+	}
+	if _, err := s.Review(duplicate.ID, dropped.ID); err != models.ErrReviewNotFound {
+		t.Errorf("expected the second review by the same patron dropped, got %v", err)
+	}
+	if average, count := rating(t, s, book.ID); average != 4 || count != 2 {
+		t.Errorf("expected rating 4 from 2 reviews, got %v from %d", average, count)
+	}
+}
@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// unaryLogger logs unary calls in the same shape as the HTTP request logger
+func unaryLogger(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logger.Info.Printf("GRPC %s %s %s", info.FullMethod, status.Code(err), time.Since(start))
+	return resp, err
+}
+
+// streamLogger logs streaming calls once the stream completes
+func streamLogger(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logger.Info.Printf("GRPC %s %s %s", info.FullMethod, status.Code(err), time.Since(start))
+	return err
+}
+
+// unaryAudit attributes storage writes to the caller named in the x-actor
+// metadata and the x-request-id metadata, generating a request ID if none
+// was sent, mirroring the HTTP RequestID and Audit middleware
+func unaryAudit(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	src := audit.Source{
+		Actor:     firstMetadata(md, "x-actor"),
+		RequestID: firstMetadata(md, "x-request-id"),
+	}
+	if src.RequestID == "" {
+		src.RequestID = uuid.New().String()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("x-request-id", src.RequestID))
+	return handler(audit.NewContext(ctx, src), req)
+}
+
+// firstMetadata returns the first value of a metadata key, or ""
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
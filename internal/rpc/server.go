@@ -0,0 +1,172 @@
+// Package rpc implements the gRPC BookService on top of book storage.
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+	bookv1 "github.com/codeforgood-org/golang-book-api/pkg/pb/book/v1"
+)
+
+// BookServer implements bookv1.BookServiceServer
+type BookServer struct {
+	bookv1.UnimplementedBookServiceServer
+	storage storage.Storage
+}
+
+// NewBookServer creates a new gRPC book service
+func NewBookServer(storage storage.Storage) *BookServer {
+	return &BookServer{
+		storage: storage,
+	}
+}
+
+// NewServer creates a gRPC server exposing the book service together with
+// the standard health and reflection services
+func NewServer(store storage.Storage) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLogger, unaryAudit),
+		grpc.ChainStreamInterceptor(streamLogger),
+	)
+
+	bookv1.RegisterBookServiceServer(server, NewBookServer(store))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(bookv1.BookService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server
+}
+
+// GetBook returns a book by its ID
+func (s *BookServer) GetBook(ctx context.Context, req *bookv1.GetBookRequest) (*bookv1.Book, error) {
+	book, err := s.storage.GetByID(int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err, "Failed to retrieve book")
+	}
+	return toProto(*book), nil
+}
+
+// ListBooks streams the books matching the request filters
+func (s *BookServer) ListBooks(req *bookv1.ListBooksRequest, stream bookv1.BookService_ListBooksServer) error {
+	books, err := s.storage.GetAll()
+	if err != nil {
+		return toStatus(err, "Failed to retrieve books")
+	}
+
+	filters := models.BookFilters{
+		Title:  req.GetTitle(),
+		Author: req.GetAuthor(),
+		Search: req.GetSearch(),
+	}
+
+	matched := make([]models.Book, 0, len(books))
+	for _, book := range books {
+		if filters.Match(book) {
+			matched = append(matched, book)
+		}
+	}
+
+	if req.GetPageSize() > 0 {
+		params := models.NewPaginationParams(int(req.GetPage()), int(req.GetPageSize()))
+		start, end := params.Bounds(len(matched))
+		matched = matched[start:end]
+	}
+
+	for _, book := range matched {
+		if err := stream.Send(toProto(book)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateBook validates and stores a new book
+func (s *BookServer) CreateBook(ctx context.Context, req *bookv1.CreateBookRequest) (*bookv1.Book, error) {
+	book := fromProto(req.GetBook())
+	if err := book.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	created, err := storage.WithContext(s.storage, ctx).Create(book)
+	if err != nil {
+		return nil, toStatus(err, "Failed to create book")
+	}
+	return toProto(*created), nil
+}
+
+// UpdateBook validates and replaces an existing book
+func (s *BookServer) UpdateBook(ctx context.Context, req *bookv1.UpdateBookRequest) (*bookv1.Book, error) {
+	book := fromProto(req.GetBook())
+	if err := book.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	updated, err := storage.WithContext(s.storage, ctx).Update(int(req.GetId()), book)
+	if err != nil {
+		return nil, toStatus(err, "Failed to update book")
+	}
+	return toProto(*updated), nil
+}
+
+// DeleteBook removes a book by its ID
+func (s *BookServer) DeleteBook(ctx context.Context, req *bookv1.DeleteBookRequest) (*emptypb.Empty, error) {
+	if err := storage.WithContext(s.storage, ctx).Delete(int(req.GetId())); err != nil {
+		return nil, toStatus(err, "Failed to delete book")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// toStatus maps a storage error onto a gRPC status, hiding internal errors
+// behind a generic message as the REST handlers do
+func toStatus(err error, message string) error {
+	if errors.Is(err, models.ErrBookNotFound) {
+		return status.Error(codes.NotFound, "Book not found")
+	}
+	logger.Error.Printf("%s: %v", message, err)
+	return status.Error(codes.Internal, message)
+}
+
+func toProto(book models.Book) *bookv1.Book {
+	return &bookv1.Book{
+		Id:            int64(book.ID),
+		Title:         book.Title,
+		Author:        book.Author,
+		Isbn:          book.ISBN,
+		Publisher:     book.Publisher,
+		PublishedYear: int32(book.PublishedYear),
+		Language:      book.Language,
+		Genres:        book.Genres,
+		Tags:          book.Tags,
+		Dewey:         book.Dewey,
+		Lcc:           book.LCC,
+	}
+}
+
+func fromProto(book *bookv1.Book) models.Book {
+	return models.Book{
+		Title:         book.GetTitle(),
+		Author:        book.GetAuthor(),
+		ISBN:          book.GetIsbn(),
+		Publisher:     book.GetPublisher(),
+		PublishedYear: int(book.GetPublishedYear()),
+		Language:      book.GetLanguage(),
+		Genres:        book.GetGenres(),
+		Tags:          book.GetTags(),
+		Dewey:         book.GetDewey(),
+		LCC:           book.GetLcc(),
+	}
+}
@@ -0,0 +1,235 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	bookv1 "github.com/codeforgood-org/golang-book-api/pkg/pb/book/v1"
+)
+
+func newTestClient(t *testing.T, store storage.Storage) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(store)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestBookServer_CRUD(t *testing.T) {
+	ctx := context.Background()
+	client := bookv1.NewBookServiceClient(newTestClient(t, storage.NewMemoryStorage()))
+
+	created, err := client.CreateBook(ctx, &bookv1.CreateBookRequest{
+		Book: &bookv1.Book{Title: "Clean Code", Author: "Robert C. Martin", PublishedYear: 2008},
+	})
+	if err != nil {
+		t.Fatalf("CreateBook() error = %v", err)
+	}
+	if created.GetId() == 0 {
+		t.Error("expected book to have an ID assigned")
+	}
+
+	got, err := client.GetBook(ctx, &bookv1.GetBookRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("GetBook() error = %v", err)
+	}
+	if got.GetTitle() != "Clean Code" || got.GetPublishedYear() != 2008 {
+		t.Errorf("unexpected book: %v", got)
+	}
+
+	updated, err := client.UpdateBook(ctx, &bookv1.UpdateBookRequest{
+		Id:   created.GetId(),
+		Book: &bookv1.Book{Title: "The Clean Coder", Author: "Robert C. Martin"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateBook() error = %v", err)
+	}
+	if updated.GetTitle() != "The Clean Coder" || updated.GetId() != created.GetId() {
+		t.Errorf("unexpected updated book: %v", updated)
+	}
+
+	if _, err := client.DeleteBook(ctx, &bookv1.DeleteBookRequest{Id: created.GetId()}); err != nil {
+		t.Fatalf("DeleteBook() error = %v", err)
+	}
+
+	_, err = client.GetBook(ctx, &bookv1.GetBookRequest{Id: created.GetId()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound after delete, got %v", err)
+	}
+}
+
+func TestBookServer_Validation(t *testing.T) {
+	client := bookv1.NewBookServiceClient(newTestClient(t, storage.NewMemoryStorage()))
+
+	_, err := client.CreateBook(context.Background(), &bookv1.CreateBookRequest{
+		Book: &bookv1.Book{Author: "Robert C. Martin"},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	if status.Convert(err).Message() != models.ErrInvalidTitle.Error() {
+		t.Errorf("expected message %q, got %q", models.ErrInvalidTitle, status.Convert(err).Message())
+	}
+}
+
+func TestBookServer_ListBooks(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	store.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin"})
+	store.Create(models.Book{Title: "Clean Architecture", Author: "Robert C. Martin"})
+	store.Create(models.Book{Title: "Refactoring", Author: "Martin Fowler"})
+
+	client := bookv1.NewBookServiceClient(newTestClient(t, store))
+
+	tests := []struct {
+		name string
+		req  *bookv1.ListBooksRequest
+		want int
+	}{
+		{name: "all", req: &bookv1.ListBooksRequest{}, want: 3},
+		{name: "filtered", req: &bookv1.ListBooksRequest{Title: "clean"}, want: 2},
+		{name: "paged", req: &bookv1.ListBooksRequest{Page: 2, PageSize: 2}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.ListBooks(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("ListBooks() error = %v", err)
+			}
+
+			count := 0
+			for {
+				_, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Recv() error = %v", err)
+				}
+				count++
+			}
+			if count != tt.want {
+				t.Errorf("expected %d books, got %d", tt.want, count)
+			}
+		})
+	}
+}
+
+func TestNewServer_Health(t *testing.T) {
+	client := healthpb.NewHealthClient(newTestClient(t, storage.NewMemoryStorage()))
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: bookv1.BookService_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING, got %v", resp.GetStatus())
+	}
+}
+
+func TestBookServer_AuditsActor(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	log := audit.NewLog(0)
+	store.SetAuditLog(log)
+	client := bookv1.NewBookServiceClient(newTestClient(t, store))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-actor", "alice", "x-request-id", "req-9")
+	created, err := client.CreateBook(ctx, &bookv1.CreateBookRequest{
+		Book: &bookv1.Book{Title: "Clean Code", Author: "Robert C. Martin"},
+	})
+	if err != nil {
+		t.Fatalf("CreateBook() error = %v", err)
+	}
+
+	history := log.History(int(created.GetId()))
+	if len(history) != 1 || history[0].Actor != "alice" || history[0].RequestID != "req-9" {
+		t.Errorf("expected create attributed to alice/req-9, got %+v", history)
+	}
+}
+
+func TestBookServer_UpdateKeepsLabels(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	book, _ := store.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin", Genres: []string{"computing"}, Tags: []string{"craft"}})
+	client := bookv1.NewBookServiceClient(newTestClient(t, store))
+
+	got, err := client.GetBook(ctx, &bookv1.GetBookRequest{Id: int64(book.ID)})
+	if err != nil {
+		t.Fatalf("GetBook() error = %v", err)
+	}
+	if len(got.GetGenres()) != 1 || len(got.GetTags()) != 1 {
+		t.Fatalf("expected the book's genres and tags, got %v", got)
+	}
+
+	got.Title = "Clean Code, 2nd Edition"
+	got.Tags = append(got.Tags, "Classic")
+	if _, err := client.UpdateBook(ctx, &bookv1.UpdateBookRequest{Id: got.GetId(), Book: got}); err != nil {
+		t.Fatalf("UpdateBook() error = %v", err)
+	}
+	stored, _ := store.GetByID(book.ID)
+	if len(stored.Genres) != 1 || stored.Genres[0] != "computing" || len(stored.Tags) != 2 || stored.Tags[1] != "classic" {
+		t.Errorf("expected the genres kept and the tags updated, got %v and %v", stored.Genres, stored.Tags)
+	}
+
+	got.Genres = []string{"not-a-genre"}
+	if _, err := client.UpdateBook(ctx, &bookv1.UpdateBookRequest{Id: got.GetId(), Book: got}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for an unknown genre, got %v", err)
+	}
+}
+
+func TestBookServer_UpdateKeepsCallNumbers(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	book, _ := store.Create(models.Book{Title: "Clean Code", Author: "Robert C. Martin", Dewey: "005.1", LCC: "QA76.76.D47"})
+	client := bookv1.NewBookServiceClient(newTestClient(t, store))
+
+	got, err := client.GetBook(ctx, &bookv1.GetBookRequest{Id: int64(book.ID)})
+	if err != nil {
+		t.Fatalf("GetBook() error = %v", err)
+	}
+	if got.GetDewey() != "005.1" || got.GetLcc() != "QA76.76.D47" {
+		t.Fatalf("expected the book's call numbers, got %v", got)
+	}
+
+	got.Title = "Clean Code, 2nd Edition"
+	if _, err := client.UpdateBook(ctx, &bookv1.UpdateBookRequest{Id: got.GetId(), Book: got}); err != nil {
+		t.Fatalf("UpdateBook() error = %v", err)
+	}
+	stored, _ := store.GetByID(book.ID)
+	if stored.Dewey != "005.1" || stored.LCC != "QA76.76.D47" {
+		t.Errorf("expected the call numbers kept, got %q and %q", stored.Dewey, stored.LCC)
+	}
+
+	got.Dewey = "not a call number"
+	if _, err := client.UpdateBook(ctx, &bookv1.UpdateBookRequest{Id: got.GetId(), Book: got}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for an invalid Dewey number, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// MemoryAssetStorage implements in-memory storage for asset records
+type MemoryAssetStorage struct {
+	assets []models.Asset
+	nextID int
+	mu     sync.RWMutex
+}
+
+// NewMemoryAssetStorage creates a new in-memory asset storage instance
+func NewMemoryAssetStorage() *MemoryAssetStorage {
+	return &MemoryAssetStorage{
+		assets: make([]models.Asset, 0),
+		nextID: 1,
+	}
+}
+
+// GetAssets returns the assets of a book, oldest first
+func (s *MemoryAssetStorage) GetAssets(bookID int) ([]models.Asset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	assets := make([]models.Asset, 0)
+	for _, asset := range s.assets {
+		if asset.BookID == bookID {
+			assets = append(assets, asset)
+		}
+	}
+	return assets, nil
+}
+
+// GetAsset returns an asset by its ID
+func (s *MemoryAssetStorage) GetAsset(id int) (*models.Asset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, asset := range s.assets {
+		if asset.ID == id {
+			assetCopy := asset
+			return &assetCopy, nil
+		}
+	}
+	return nil, models.ErrAssetNotFound
+}
+
+// CreateAsset adds a new asset and returns it with an assigned ID
+func (s *MemoryAssetStorage) CreateAsset(asset models.Asset) (*models.Asset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	asset.ID = s.nextID
+	s.nextID++
+	s.assets = append(s.assets, asset)
+	return &asset, nil
+}
+
+// UpdateAsset updates an existing asset
+func (s *MemoryAssetStorage) UpdateAsset(asset models.Asset) (*models.Asset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.assets {
+		if existing.ID == asset.ID {
+			s.assets[i] = asset
+			return &asset, nil
+		}
+	}
+	return nil, models.ErrAssetNotFound
+}
+
+// DeleteAsset removes an asset
+func (s *MemoryAssetStorage) DeleteAsset(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, asset := range s.assets {
+		if asset.ID == id {
+			s.assets = append(s.assets[:i], s.assets[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrAssetNotFound
+}
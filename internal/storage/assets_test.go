@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestMemoryAssetStorage(t *testing.T) {
+	storage := NewMemoryAssetStorage()
+
+	first, _ := storage.CreateAsset(models.Asset{BookID: 1, Filename: "book.epub"})
+	storage.CreateAsset(models.Asset{BookID: 2, Filename: "other.pdf"})
+	storage.CreateAsset(models.Asset{BookID: 1, Filename: "book.pdf"})
+	if first.ID != 1 {
+		t.Errorf("expected ID 1, got %d", first.ID)
+	}
+
+	assets, _ := storage.GetAssets(1)
+	if len(assets) != 2 || assets[0].Filename != "book.epub" || assets[1].Filename != "book.pdf" {
+		t.Errorf("expected the two assets of book 1, oldest first, got %+v", assets)
+	}
+	if got, _ := storage.GetAsset(first.ID); got.Filename != "book.epub" {
+		t.Errorf("expected book.epub, got %q", got.Filename)
+	}
+
+	first.BookID = 3
+	if _, err := storage.UpdateAsset(*first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if moved, _ := storage.GetAssets(3); len(moved) != 1 || moved[0].ID != first.ID {
+		t.Errorf("expected the asset to move to book 3, got %+v", moved)
+	}
+	if _, err := storage.UpdateAsset(models.Asset{ID: 99}); err != models.ErrAssetNotFound {
+		t.Errorf("expected ErrAssetNotFound, got %v", err)
+	}
+
+	if err := storage.DeleteAsset(first.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := storage.GetAsset(first.ID); err != models.ErrAssetNotFound {
+		t.Errorf("expected ErrAssetNotFound, got %v", err)
+	}
+	if err := storage.DeleteAsset(first.ID); err != models.ErrAssetNotFound {
+		t.Errorf("expected ErrAssetNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// MemoryCopyStorage implements in-memory storage for copies
+type MemoryCopyStorage struct {
+	copies []models.Copy
+	nextID int
+	mu     sync.RWMutex
+}
+
+// NewMemoryCopyStorage creates a new in-memory copy storage instance
+func NewMemoryCopyStorage() *MemoryCopyStorage {
+	return &MemoryCopyStorage{
+		copies: make([]models.Copy, 0),
+		nextID: 1,
+	}
+}
+
+// GetCopies returns the copies of a book, oldest first
+func (s *MemoryCopyStorage) GetCopies(bookID int) ([]models.Copy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	copies := make([]models.Copy, 0)
+	for _, c := range s.copies {
+		if c.BookID == bookID {
+			copies = append(copies, c)
+		}
+	}
+	return copies, nil
+}
+
+// GetCopy returns a copy by its ID
+func (s *MemoryCopyStorage) GetCopy(id int) (*models.Copy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, c := range s.copies {
+		if c.ID == id {
+			copyCopy := c
+			return &copyCopy, nil
+		}
+	}
+	return nil, models.ErrCopyNotFound
+}
+
+// GetCopyByBarcode returns a copy by its barcode
+func (s *MemoryCopyStorage) GetCopyByBarcode(barcode string) (*models.Copy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, c := range s.copies {
+		if c.Barcode == barcode {
+			copyCopy := c
+			return &copyCopy, nil
+		}
+	}
+	return nil, models.ErrCopyNotFound
+}
+
+// CreateCopy adds a new copy and returns it with an assigned ID
+func (s *MemoryCopyStorage) CreateCopy(c models.Copy) (*models.Copy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.barcodeInUse(c.Barcode, 0) {
+		return nil, models.ErrDuplicateBarcode
+	}
+	c.ID = s.nextID
+	s.nextID++
+	s.copies = append(s.copies, c)
+	return &c, nil
+}
+
+// UpdateCopy updates an existing copy
+func (s *MemoryCopyStorage) UpdateCopy(c models.Copy) (*models.Copy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.barcodeInUse(c.Barcode, c.ID) {
+		return nil, models.ErrDuplicateBarcode
+	}
+	for i, existing := range s.copies {
+		if existing.ID == c.ID {
+			s.copies[i] = c
+			return &c, nil
+		}
+	}
+	return nil, models.ErrCopyNotFound
+}
+
+// DeleteCopy removes a copy by its ID
+func (s *MemoryCopyStorage) DeleteCopy(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, c := range s.copies {
+		if c.ID == id {
+			s.copies = append(s.copies[:i], s.copies[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrCopyNotFound
+}
+
+// barcodeInUse reports whether a copy other than the one with ID except has
+// the barcode; s.mu must be held
+func (s *MemoryCopyStorage) barcodeInUse(barcode string, except int) bool {
+	for _, c := range s.copies {
+		if c.Barcode == barcode && c.ID != except {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestMemoryCopyStorage(t *testing.T) {
+	storage := NewMemoryCopyStorage()
+
+	first, _ := storage.CreateCopy(models.Copy{BookID: 1, Barcode: "A-1"})
+	second, _ := storage.CreateCopy(models.Copy{BookID: 1, Barcode: "A-2"})
+	storage.CreateCopy(models.Copy{BookID: 2, Barcode: "B-1"})
+
+	if _, err := storage.CreateCopy(models.Copy{BookID: 2, Barcode: "A-1"}); err != models.ErrDuplicateBarcode {
+		t.Errorf("expected ErrDuplicateBarcode, got %v", err)
+	}
+
+	copies, _ := storage.GetCopies(1)
+	if len(copies) != 2 || copies[0].ID != first.ID {
+		t.Errorf("expected the 2 copies of book 1 oldest first, got %+v", copies)
+	}
+
+	second.Barcode = "A-1"
+	if _, err := storage.UpdateCopy(*second); err != models.ErrDuplicateBarcode {
+		t.Errorf("expected ErrDuplicateBarcode, got %v", err)
+	}
+	second.Barcode = "A-3"
+	if _, err := storage.UpdateCopy(*second); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if found, err := storage.GetCopyByBarcode("A-3"); err != nil || found.ID != second.ID {
+		t.Errorf("expected copy %d by barcode, got %+v (%v)", second.ID, found, err)
+	}
+
+	if err := storage.DeleteCopy(first.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := storage.GetCopy(first.ID); err != models.ErrCopyNotFound {
+		t.Errorf("expected ErrCopyNotFound after deletion, got %v", err)
+	}
+}
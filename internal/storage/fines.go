@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// MemoryFineStorage implements in-memory storage for fines
+type MemoryFineStorage struct {
+	fines  []models.Fine
+	nextID int
+	mu     sync.RWMutex
+}
+
+// NewMemoryFineStorage creates a new in-memory fine storage instance
+func NewMemoryFineStorage() *MemoryFineStorage {
+	return &MemoryFineStorage{
+		fines:  make([]models.Fine, 0),
+		nextID: 1,
+	}
+}
+
+// GetFines returns the fines matching the filter, oldest first
+func (s *MemoryFineStorage) GetFines(filter models.FineFilter) ([]models.Fine, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fines := make([]models.Fine, 0)
+	for _, fine := range s.fines {
+		if filter.Match(fine) {
+			fines = append(fines, copyFine(fine))
+		}
+	}
+	return fines, nil
+}
+
+// GetFine returns a fine by its ID
+func (s *MemoryFineStorage) GetFine(id int) (*models.Fine, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, fine := range s.fines {
+		if fine.ID == id {
+			fineCopy := copyFine(fine)
+			return &fineCopy, nil
+		}
+	}
+	return nil, models.ErrFineNotFound
+}
+
+// CreateFine adds a new fine and returns it with an assigned ID
+func (s *MemoryFineStorage) CreateFine(fine models.Fine) (*models.Fine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fine.ID = s.nextID
+	s.nextID++
+	s.fines = append(s.fines, copyFine(fine))
+	return &fine, nil
+}
+
+// UpdateFine updates an existing fine
+func (s *MemoryFineStorage) UpdateFine(fine models.Fine) (*models.Fine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.fines {
+		if f.ID == fine.ID {
+			s.fines[i] = copyFine(fine)
+			return &fine, nil
+		}
+	}
+	return nil, models.ErrFineNotFound
+}
+
+// copyFine returns a copy of a fine that shares no transactions with it
+func copyFine(fine models.Fine) models.Fine {
+	fine.Transactions = slices.Clone(fine.Transactions)
+	return fine
+}
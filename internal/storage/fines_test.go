@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestMemoryFineStorage(t *testing.T) {
+	storage := NewMemoryFineStorage()
+
+	first, _ := storage.CreateFine(models.Fine{PatronID: 1, LoanID: 1, Status: models.FineOpen})
+	storage.CreateFine(models.Fine{PatronID: 1, LoanID: 2, Status: models.FineOpen})
+	storage.CreateFine(models.Fine{PatronID: 2, LoanID: 3, Status: models.FineOpen})
+
+	first.Status = models.FinePaid
+	first.Transactions = []models.FineTransaction{{Type: models.FinePayment, AmountCents: 50}}
+	if _, err := storage.UpdateFine(*first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   models.FineFilter
+		expected []int
+	}{
+		{"all fines, oldest first", models.FineFilter{}, []int{1, 2, 3}},
+		{"by patron", models.FineFilter{PatronID: 1}, []int{1, 2}},
+		{"open by patron", models.FineFilter{PatronID: 1, OpenOnly: true}, []int{2}},
+		{"by loan", models.FineFilter{LoanID: 3}, []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fines, _ := storage.GetFines(tt.filter)
+			if len(fines) != len(tt.expected) {
+				t.Fatalf("expected %d fines, got %d", len(tt.expected), len(fines))
+			}
+			for i, id := range tt.expected {
+				if fines[i].ID != id {
+					t.Errorf("expected fine %d at position %d, got %d", id, i, fines[i].ID)
+				}
+			}
+		})
+	}
+
+	// Changing a returned fine must not change the stored one
+	stored, _ := storage.GetFine(first.ID)
+	stored.Transactions[0].AmountCents = 999
+	if again, _ := storage.GetFine(first.ID); again.Transactions[0].AmountCents != 50 {
+		t.Errorf("expected stored transaction unchanged, got %+v", again.Transactions[0])
+	}
+
+	if _, err := storage.GetFine(999); err != models.ErrFineNotFound {
+		t.Errorf("expected ErrFineNotFound, got %v", err)
+	}
+	if _, err := storage.UpdateFine(models.Fine{ID: 999}); err != models.ErrFineNotFound {
+		t.Errorf("expected ErrFineNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// MemoryHoldStorage implements in-memory storage for holds
+type MemoryHoldStorage struct {
+	holds  []models.Hold
+	nextID int
+	mu     sync.RWMutex
+}
+
+// NewMemoryHoldStorage creates a new in-memory hold storage instance
+func NewMemoryHoldStorage() *MemoryHoldStorage {
+	return &MemoryHoldStorage{
+		holds:  make([]models.Hold, 0),
+		nextID: 1,
+	}
+}
+
+// GetHolds returns the holds matching the filter, oldest first
+func (s *MemoryHoldStorage) GetHolds(filter models.HoldFilter) ([]models.Hold, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	holds := make([]models.Hold, 0)
+	for _, hold := range s.holds {
+		if filter.Match(hold) {
+			holds = append(holds, hold)
+		}
+	}
+	return holds, nil
+}
+
+// GetHold returns a hold by its ID
+func (s *MemoryHoldStorage) GetHold(id int) (*models.Hold, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, hold := range s.holds {
+		if hold.ID == id {
+			holdCopy := hold
+			return &holdCopy, nil
+		}
+	}
+	return nil, models.ErrHoldNotFound
+}
+
+// CreateHold adds a new hold and returns it with an assigned ID
+func (s *MemoryHoldStorage) CreateHold(hold models.Hold) (*models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hold.ID = s.nextID
+	s.nextID++
+	s.holds = append(s.holds, hold)
+	return &hold, nil
+}
+
+// UpdateHold updates an existing hold
+func (s *MemoryHoldStorage) UpdateHold(hold models.Hold) (*models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, h := range s.holds {
+		if h.ID == hold.ID {
+			s.holds[i] = hold
+			return &hold, nil
+		}
+	}
+	return nil, models.ErrHoldNotFound
+}
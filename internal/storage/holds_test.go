@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestMemoryHoldStorage(t *testing.T) {
+	storage := NewMemoryHoldStorage()
+
+	first, _ := storage.CreateHold(models.Hold{BookID: 1, PatronID: 1, Status: models.HoldWaiting})
+	storage.CreateHold(models.Hold{BookID: 1, PatronID: 2, Status: models.HoldWaiting})
+	storage.CreateHold(models.Hold{BookID: 2, PatronID: 1, Status: models.HoldWaiting})
+
+	first.Status = models.HoldCancelled
+	if _, err := storage.UpdateHold(*first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   models.HoldFilter
+		expected []int
+	}{
+		{"all holds, oldest first", models.HoldFilter{}, []int{1, 2, 3}},
+		{"by book", models.HoldFilter{BookID: 1}, []int{1, 2}},
+		{"by patron", models.HoldFilter{PatronID: 1}, []int{1, 3}},
+		{"waiting on book", models.HoldFilter{BookID: 1, Statuses: []models.HoldStatus{models.HoldWaiting}}, []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holds, _ := storage.GetHolds(tt.filter)
+			if len(holds) != len(tt.expected) {
+				t.Fatalf("expected %d holds, got %d", len(tt.expected), len(holds))
+			}
+			for i, id := range tt.expected {
+				if holds[i].ID != id {
+					t.Errorf("expected hold %d at position %d, got %d", id, i, holds[i].ID)
+				}
+			}
+		})
+	}
+
+	if _, err := storage.GetHold(999); err != models.ErrHoldNotFound {
+		t.Errorf("expected ErrHoldNotFound, got %v", err)
+	}
+	if _, err := storage.UpdateHold(models.Hold{ID: 999}); err != models.ErrHoldNotFound {
+		t.Errorf("expected ErrHoldNotFound, got %v", err)
+	}
+}
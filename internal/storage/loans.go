@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// MemoryLoanStorage implements in-memory storage for loans
+type MemoryLoanStorage struct {
+	loans  []models.Loan
+	nextID int
+	mu     sync.RWMutex
+}
+
+// NewMemoryLoanStorage creates a new in-memory loan storage instance
+func NewMemoryLoanStorage() *MemoryLoanStorage {
+	return &MemoryLoanStorage{
+		loans:  make([]models.Loan, 0),
+		nextID: 1,
+	}
+}
+
+// GetLoans returns the loans matching the filter, most recent first
+func (s *MemoryLoanStorage) GetLoans(filter models.LoanFilter) ([]models.Loan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	loans := make([]models.Loan, 0)
+	for i := len(s.loans) - 1; i >= 0; i-- {
+		if filter.Match(s.loans[i]) {
+			loans = append(loans, s.loans[i])
+		}
+	}
+	return loans, nil
+}
+
+// GetLoan returns a loan by its ID
+func (s *MemoryLoanStorage) GetLoan(id int) (*models.Loan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, loan := range s.loans {
+		if loan.ID == id {
+			loanCopy := loan
+			return &loanCopy, nil
+		}
+	}
+	return nil, models.ErrLoanNotFound
+}
+
+// CreateLoan adds a new loan and returns it with an assigned ID
+func (s *MemoryLoanStorage) CreateLoan(loan models.Loan) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loan.ID = s.nextID
+	s.nextID++
+	s.loans = append(s.loans, loan)
+	return &loan, nil
+}
+
+// UpdateLoan updates an existing loan
+func (s *MemoryLoanStorage) UpdateLoan(loan models.Loan) (*models.Loan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, l := range s.loans {
+		if l.ID == loan.ID {
+			s.loans[i] = loan
+			return &loan, nil
+		}
+	}
+	return nil, models.ErrLoanNotFound
+}
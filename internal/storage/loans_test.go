@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestMemoryLoanStorage(t *testing.T) {
+	storage := NewMemoryLoanStorage()
+
+	first, _ := storage.CreateLoan(models.Loan{BookID: 1, PatronID: 1})
+	storage.CreateLoan(models.Loan{BookID: 2, PatronID: 1})
+	storage.CreateLoan(models.Loan{BookID: 3, PatronID: 2})
+
+	returned := time.Now()
+	first.ReturnedAt = &returned
+	if _, err := storage.UpdateLoan(*first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   models.LoanFilter
+		expected []int
+	}{
+		{"all loans, newest first", models.LoanFilter{}, []int{3, 2, 1}},
+		{"by patron", models.LoanFilter{PatronID: 1}, []int{2, 1}},
+		{"active by patron", models.LoanFilter{PatronID: 1, ActiveOnly: true}, []int{2}},
+		{"by book", models.LoanFilter{BookID: 3}, []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loans, _ := storage.GetLoans(tt.filter)
+			if len(loans) != len(tt.expected) {
+				t.Fatalf("expected %d loans, got %d", len(tt.expected), len(loans))
+			}
+			for i, id := range tt.expected {
+				if loans[i].ID != id {
+					t.Errorf("expected loan %d at position %d, got %d", id, i, loans[i].ID)
+				}
+			}
+		})
+	}
+
+	if _, err := storage.GetLoan(999); err != models.ErrLoanNotFound {
+		t.Errorf("expected ErrLoanNotFound, got %v", err)
+	}
+}
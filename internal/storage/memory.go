@@ -1,28 +1,79 @@
 package storage
 
 import (
+	"context"
 	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/events"
 	"github.com/codeforgood-org/golang-book-api/internal/models"
 )
 
 // MemoryStorage implements in-memory storage for books
 type MemoryStorage struct {
 	books []models.Book
+	trash []models.Book
 	mu    sync.RWMutex
 	rng   *rand.Rand
+	bus   *events.Bus
+	audit *audit.Log
+	// redirects maps the IDs of merged books to the books they were merged
+	// into
+	redirects map[int]int
+	// retired holds the IDs of books merged away or purged, which are never
+	// given to a new book since audit history and everything else kept by
+	// book ID would attach to it
+	retired map[int]bool
 }
 
 // NewMemoryStorage creates a new in-memory storage instance
 func NewMemoryStorage() *MemoryStorage {
 	return &MemoryStorage{
-		books: make([]models.Book, 0),
-		rng:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		books:     make([]models.Book, 0),
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		redirects: make(map[int]int),
+		retired:   make(map[int]bool),
 	}
 }
 
+// SetEventBus makes the storage publish an event to bus for every successful
+// create, update and delete
+func (s *MemoryStorage) SetEventBus(bus *events.Bus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bus = bus
+}
+
+// publish sends a change event if an event bus is attached; s.mu must be held
+// so that events are published in the order the changes were applied
+func (s *MemoryStorage) publish(typ events.Type, id int, book *models.Book) {
+	if s.bus != nil {
+		s.bus.Publish(typ, id, book)
+	}
+}
+
+// SetAuditLog makes the storage record every successful write in log
+func (s *MemoryStorage) SetAuditLog(log *audit.Log) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.audit = log
+}
+
+// record adds a write to the audit log if one is attached; s.mu must be held
+func (s *MemoryStorage) record(ctx context.Context, action audit.Action, id int, before, after *models.Book) {
+	if s.audit != nil {
+		s.audit.Record(ctx, action, id, before, after)
+	}
+}
+
+// WithContext returns a view of the storage whose writes are attributed to
+// the actor and request carried by ctx
+func (s *MemoryStorage) WithContext(ctx context.Context) Storage {
+	return &memoryContextStorage{MemoryStorage: s, ctx: ctx}
+}
+
 // GetAll returns all books
 func (s *MemoryStorage) GetAll() ([]models.Book, error) {
 	s.mu.RLock()
@@ -51,42 +102,389 @@ func (s *MemoryStorage) GetByID(id int) (*models.Book, error) {
 
 // Create adds a new book and returns it with an assigned ID
 func (s *MemoryStorage) Create(book models.Book) (*models.Book, error) {
+	return s.create(context.Background(), book)
+}
+
+// Update updates an existing book
+func (s *MemoryStorage) Update(id int, book models.Book) (*models.Book, error) {
+	return s.update(context.Background(), id, book)
+}
+
+// Delete removes a book by its ID
+func (s *MemoryStorage) Delete(id int) error {
+	return s.delete(context.Background(), id)
+}
+
+// Revert puts a book back to an earlier state, recreating it under the same
+// ID if it has been deleted
+func (s *MemoryStorage) Revert(id int, book models.Book, revision int) (*models.Book, error) {
+	return s.revert(context.Background(), id, book, revision)
+}
+
+// Merge removes a book for good, recording it as merged into another book,
+// and redirects its ID there
+func (s *MemoryStorage) Merge(id, into int) error {
+	return s.merge(context.Background(), id, into)
+}
+
+// Redirect returns the book a merged book's ID now stands for
+func (s *MemoryStorage) Redirect(id int) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	into, ok := s.redirects[id]
+	return into, ok
+}
+
+// Trashed returns the deleted books still in the trash, most recently
+// deleted first
+func (s *MemoryStorage) Trashed() ([]models.Book, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	trashed := make([]models.Book, 0, len(s.trash))
+	for i := len(s.trash) - 1; i >= 0; i-- {
+		trashed = append(trashed, s.trash[i])
+	}
+	return trashed, nil
+}
+
+// Restore moves a book out of the trash
+func (s *MemoryStorage) Restore(id int) (*models.Book, error) {
+	return s.restore(context.Background(), id)
+}
+
+// Purge permanently removes the books deleted before the cutoff and returns
+// them
+func (s *MemoryStorage) Purge(before time.Time) ([]models.Book, error) {
+	return s.purge(context.Background(), before)
+}
+
+func (s *MemoryStorage) create(ctx context.Context, book models.Book) (*models.Book, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Generate a unique ID
-	book.ID = s.rng.Intn(1000000)
+	book.ID = s.newID()
+	book.CreatedAt = time.Now().UTC()
+	book.DeletedAt = nil
+	book.AverageRating, book.RatingCount = 0, 0
+	book.WorkID = 0
+	book.Cover = nil
 	s.books = append(s.books, book)
+	s.record(ctx, audit.ActionCreate, book.ID, nil, &book)
+	s.publish(events.BookCreated, book.ID, &book)
 
 	return &book, nil
 }
 
-// Update updates an existing book
-func (s *MemoryStorage) Update(id int, book models.Book) (*models.Book, error) {
+func (s *MemoryStorage) update(ctx context.Context, id int, book models.Book) (*models.Book, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for i, b := range s.books {
 		if b.ID == id {
-			// Preserve the original ID
+			// Preserve the original ID, creation time, rating, work and cover
 			book.ID = id
+			book.CreatedAt = b.CreatedAt
+			book.DeletedAt = nil
+			book.AverageRating, book.RatingCount = b.AverageRating, b.RatingCount
+			book.WorkID = b.WorkID
+			book.Cover = b.Cover
 			s.books[i] = book
+			s.record(ctx, audit.ActionUpdate, id, &b, &book)
+			s.publish(events.BookUpdated, id, &book)
 			return &book, nil
 		}
 	}
 	return nil, models.ErrBookNotFound
 }
 
-// Delete removes a book by its ID
-func (s *MemoryStorage) Delete(id int) error {
+func (s *MemoryStorage) delete(ctx context.Context, id int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for i, book := range s.books {
 		if book.ID == id {
 			s.books = append(s.books[:i], s.books[i+1:]...)
+			s.record(ctx, audit.ActionDelete, id, &book, nil)
+			s.publish(events.BookDeleted, id, &book)
+
+			// Keep the book in the trash until it is restored or purged
+			deletedAt := time.Now().UTC()
+			book.DeletedAt = &deletedAt
+			s.trash = append(s.trash, book)
+			return nil
+		}
+	}
+	return models.ErrBookNotFound
+}
+
+func (s *MemoryStorage) merge(ctx context.Context, id, into int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id == into {
+		return models.ErrInvalidID
+	}
+	var target *models.Book
+	for i := range s.books {
+		if s.books[i].ID == into {
+			target = &s.books[i]
+		}
+	}
+	if target == nil {
+		return models.ErrBookNotFound
+	}
+	merged := *target
+
+	for i, book := range s.books {
+		if book.ID == id {
+			s.books = append(s.books[:i], s.books[i+1:]...)
+			// Books merged into this one before now stand for the target
+			for from, to := range s.redirects {
+				if to == id {
+					s.redirects[from] = into
+				}
+			}
+			s.redirects[id] = into
+			s.retired[id] = true
+			s.recordMerge(ctx, id, &book, into)
+			s.publish(events.BookMerged, id, &merged)
+			return nil
+		}
+	}
+	return models.ErrBookNotFound
+}
+
+func (s *MemoryStorage) restore(ctx context.Context, id int) (*models.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.trashIndex(id)
+	if i < 0 {
+		return nil, models.ErrBookNotFound
+	}
+	if s.liveIndex(id) >= 0 {
+		return nil, models.ErrBookExists
+	}
+	book := s.trash[i]
+	book.DeletedAt = nil
+	s.trash = append(s.trash[:i], s.trash[i+1:]...)
+	s.books = append(s.books, book)
+
+	s.record(ctx, audit.ActionRestore, id, nil, &book)
+	s.publish(events.BookRestored, id, &book)
+	return &book, nil
+}
+
+func (s *MemoryStorage) purge(ctx context.Context, before time.Time) ([]models.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.trash[:0]
+	purged := make([]models.Book, 0)
+	for _, book := range s.trash {
+		if book.DeletedAt.Before(before) {
+			s.record(ctx, audit.ActionPurge, book.ID, &book, nil)
+			purged = append(purged, book)
+			s.retired[book.ID] = true
+			continue
+		}
+		kept = append(kept, book)
+	}
+	s.trash = kept
+	return purged, nil
+}
+
+// SetRating replaces the rating summary of a book, whether or not it is in
+// the trash. It is not a write to the book, so it is neither audited nor
+// published
+func (s *MemoryStorage) SetRating(id int, average float64, count int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.books {
+		if s.books[i].ID == id {
+			s.books[i].AverageRating, s.books[i].RatingCount = average, count
+			return nil
+		}
+	}
+	if i := s.trashIndex(id); i >= 0 {
+		s.trash[i].AverageRating, s.trash[i].RatingCount = average, count
+		return nil
+	}
+	return models.ErrBookNotFound
+}
+
+// SetWork links a book to the work it is an edition of, or unlinks it if
+// workID is 0, whether or not the book is in the trash. Like SetRating, it
+// is neither audited nor published
+func (s *MemoryStorage) SetWork(id, workID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.books {
+		if s.books[i].ID == id {
+			s.books[i].WorkID = workID
 			return nil
 		}
 	}
+	if i := s.trashIndex(id); i >= 0 {
+		s.trash[i].WorkID = workID
+		return nil
+	}
 	return models.ErrBookNotFound
 }
+
+// SetCover replaces the cover of a book, or removes it if cover is nil,
+// whether or not the book is in the trash. Like SetRating, it is neither
+// audited nor published
+func (s *MemoryStorage) SetCover(id int, cover *models.Cover) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.books {
+		if s.books[i].ID == id {
+			s.books[i].Cover = cover
+			return nil
+		}
+	}
+	if i := s.trashIndex(id); i >= 0 {
+		s.trash[i].Cover = cover
+		return nil
+	}
+	return models.ErrBookNotFound
+}
+
+// newID returns a random ID not used by any book, live or in the trash,
+// nor ever used by a book since merged away or purged; s.mu must be held
+func (s *MemoryStorage) newID() int {
+	for {
+		id := s.rng.Intn(1000000)
+		if !s.retired[id] && s.liveIndex(id) < 0 && s.trashIndex(id) < 0 {
+			return id
+		}
+	}
+}
+
+// liveIndex returns the position of a book not in the trash, or -1; s.mu
+// must be held
+func (s *MemoryStorage) liveIndex(id int) int {
+	for i, book := range s.books {
+		if book.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// trashIndex returns the position of a book in the trash, or -1; s.mu must
+// be held
+func (s *MemoryStorage) trashIndex(id int) int {
+	for i, book := range s.trash {
+		if book.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *MemoryStorage) revert(ctx context.Context, id int, book models.Book, revision int) (*models.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	book.ID = id
+	book.DeletedAt = nil
+	// A merged book brought back stands for itself again
+	delete(s.redirects, id)
+	// The rating summarizes the book's reviews, and the work and cover are
+	// managed on their own, so none of them come from the revision
+	book.AverageRating, book.RatingCount = 0, 0
+	book.WorkID = 0
+	book.Cover = nil
+	if book.CreatedAt.IsZero() {
+		book.CreatedAt = time.Now().UTC()
+	}
+	if i := s.trashIndex(id); i >= 0 {
+		book.CreatedAt = s.trash[i].CreatedAt
+		book.AverageRating, book.RatingCount = s.trash[i].AverageRating, s.trash[i].RatingCount
+		book.WorkID = s.trash[i].WorkID
+		book.Cover = s.trash[i].Cover
+		s.trash = append(s.trash[:i], s.trash[i+1:]...)
+	}
+	for i, b := range s.books {
+		if b.ID == id {
+			book.CreatedAt = b.CreatedAt
+			book.AverageRating, book.RatingCount = b.AverageRating, b.RatingCount
+			book.WorkID = b.WorkID
+			book.Cover = b.Cover
+			s.books[i] = book
+			s.recordRevert(ctx, id, &b, &book, revision)
+			s.publish(events.BookUpdated, id, &book)
+			return &book, nil
+		}
+	}
+
+	s.books = append(s.books, book)
+	s.recordRevert(ctx, id, nil, &book, revision)
+	s.publish(events.BookCreated, id, &book)
+	return &book, nil
+}
+
+// recordRevert adds a revert to the audit log, noting the revision it
+// restored; s.mu must be held
+func (s *MemoryStorage) recordRevert(ctx context.Context, id int, before, after *models.Book, revision int) {
+	if s.audit != nil {
+		s.audit.RecordRevert(ctx, id, before, after, revision)
+	}
+}
+
+// recordMerge adds a merge to the audit log, noting the book merged into;
+// s.mu must be held
+func (s *MemoryStorage) recordMerge(ctx context.Context, id int, before *models.Book, into int) {
+	if s.audit != nil {
+		s.audit.RecordMerge(ctx, id, before, into)
+	}
+}
+
+// memoryContextStorage attributes the writes of a MemoryStorage to a context
+type memoryContextStorage struct {
+	*MemoryStorage
+	ctx context.Context
+}
+
+// Create adds a new book and returns it with an assigned ID
+func (s *memoryContextStorage) Create(book models.Book) (*models.Book, error) {
+	return s.create(s.ctx, book)
+}
+
+// Update updates an existing book
+func (s *memoryContextStorage) Update(id int, book models.Book) (*models.Book, error) {
+	return s.update(s.ctx, id, book)
+}
+
+// Delete removes a book by its ID
+func (s *memoryContextStorage) Delete(id int) error {
+	return s.delete(s.ctx, id)
+}
+
+// Revert puts a book back to an earlier state
+func (s *memoryContextStorage) Revert(id int, book models.Book, revision int) (*models.Book, error) {
+	return s.revert(s.ctx, id, book, revision)
+}
+
+// Merge removes a book, recording it as merged into another
+func (s *memoryContextStorage) Merge(id, into int) error {
+	return s.merge(s.ctx, id, into)
+}
+
+// Restore moves a book out of the trash
+func (s *memoryContextStorage) Restore(id int) (*models.Book, error) {
+	return s.restore(s.ctx, id)
+}
+
+// Purge permanently removes the books deleted before the cutoff and returns
+// them
+func (s *memoryContextStorage) Purge(before time.Time) ([]models.Book, error) {
+	return s.purge(s.ctx, before)
+}
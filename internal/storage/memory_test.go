@@ -1,8 +1,13 @@
 package storage
 
 import (
+	"context"
+	"math/rand"
 	"testing"
+	"time"
 
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/events"
 	"github.com/codeforgood-org/golang-book-api/internal/models"
 )
 
@@ -141,3 +146,299 @@ func TestMemoryStorage_Concurrent(t *testing.T) {
 		t.Errorf("expected 10 books after concurrent writes, got %d", len(books))
 	}
 }
+
+func TestMemoryStorage_Trash(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	created, _ := storage.Create(models.Book{Title: "Test Book", Author: "Test Author"})
+	if err := storage.Delete(created.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	trashed, _ := storage.Trashed()
+	if len(trashed) != 1 {
+		t.Fatalf("expected 1 book in the trash, got %d", len(trashed))
+	}
+	if trashed[0].ID != created.ID || trashed[0].DeletedAt == nil {
+		t.Errorf("expected book %d with deleted_at set, got %+v", created.ID, trashed[0])
+	}
+
+	restored, err := storage.Restore(created.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("expected deleted_at to be cleared, got %v", restored.DeletedAt)
+	}
+	if _, err := storage.GetByID(created.ID); err != nil {
+		t.Errorf("expected restored book to be found, got %v", err)
+	}
+	if trashed, _ := storage.Trashed(); len(trashed) != 0 {
+		t.Errorf("expected empty trash after restore, got %d books", len(trashed))
+	}
+
+	// Restoring a book that is not in the trash
+	if _, err := storage.Restore(created.ID); err != models.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStorage_TrashedIDsNotReused(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.rng = rand.New(rand.NewSource(1))
+	trashed, _ := storage.Create(models.Book{Title: "Trashed", Author: "Author"})
+	storage.Delete(trashed.ID)
+
+	// The same random source draws the trashed book's ID first
+	storage.rng = rand.New(rand.NewSource(1))
+	created, _ := storage.Create(models.Book{Title: "New", Author: "Author"})
+	if created.ID == trashed.ID {
+		t.Fatalf("expected a new ID, got the trashed book's ID %d", created.ID)
+	}
+
+	// A live book holding the ID blocks the restore
+	storage.mu.Lock()
+	storage.books = append(storage.books, models.Book{ID: trashed.ID, Title: "Other", Author: "Author"})
+	storage.mu.Unlock()
+	if _, err := storage.Restore(trashed.ID); err != models.ErrBookExists {
+		t.Errorf("expected ErrBookExists, got %v", err)
+	}
+}
+
+func TestMemoryStorage_MergedIDsNotReused(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.rng = rand.New(rand.NewSource(1))
+	merged, _ := storage.Create(models.Book{Title: "Merged", Author: "Author"})
+	kept, _ := storage.Create(models.Book{Title: "Kept", Author: "Author"})
+	if err := storage.Merge(merged.ID, kept.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	storage.rng = rand.New(rand.NewSource(1))
+	created, _ := storage.Create(models.Book{Title: "New", Author: "Author"})
+	if created.ID == merged.ID {
+		t.Errorf("expected a new ID, got the merged book's ID %d", created.ID)
+	}
+}
+
+func TestMemoryStorage_PurgedIDsNotReused(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.rng = rand.New(rand.NewSource(1))
+	purged, _ := storage.Create(models.Book{Title: "Purged", Author: "Author"})
+	storage.Delete(purged.ID)
+	if books, _ := storage.Purge(time.Now().Add(time.Second)); len(books) != 1 {
+		t.Fatalf("expected 1 book purged, got %d", len(books))
+	}
+
+	storage.rng = rand.New(rand.NewSource(1))
+	created, _ := storage.Create(models.Book{Title: "New", Author: "Author"})
+	if created.ID == purged.ID {
+		t.Errorf("expected a new ID, got the purged book's ID %d", created.ID)
+	}
+}
+
+func TestMemoryStorage_Purge(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	created, _ := storage.Create(models.Book{Title: "Test Book", Author: "Test Author"})
+	storage.Delete(created.ID)
+
+	// Nothing was deleted before an hour ago
+	purged, err := storage.Purge(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(purged) != 0 {
+		t.Errorf("expected 0 books purged, got %d", len(purged))
+	}
+
+	purged, _ = storage.Purge(time.Now().Add(time.Second))
+	if len(purged) != 1 || purged[0].ID != created.ID {
+		t.Errorf("expected book %d purged, got %+v", created.ID, purged)
+	}
+	if _, err := storage.Restore(created.ID); err != models.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound after purge, got %v", err)
+	}
+}
+
+func TestMemoryStorage_SetRating(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	created, _ := storage.Create(models.Book{Title: "Test Book", Author: "Test Author", AverageRating: 5, RatingCount: 9})
+	if created.AverageRating != 0 || created.RatingCount != 0 {
+		t.Errorf("expected a new book to have no rating, got %v from %d", created.AverageRating, created.RatingCount)
+	}
+
+	if err := storage.SetRating(created.ID, 4.5, 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Book writes leave the rating alone
+	updated, _ := storage.Update(created.ID, models.Book{Title: "Renamed", Author: "Test Author"})
+	if updated.AverageRating != 4.5 || updated.RatingCount != 2 {
+		t.Errorf("expected rating 4.5 from 2 reviews after update, got %v from %d", updated.AverageRating, updated.RatingCount)
+	}
+
+	storage.Delete(created.ID)
+	if err := storage.SetRating(created.ID, 3, 1); err != nil {
+		t.Errorf("expected the rating of a trashed book to be set, got %v", err)
+	}
+	restored, _ := storage.Restore(created.ID)
+	if restored.AverageRating != 3 || restored.RatingCount != 1 {
+		t.Errorf("expected rating 3 from 1 review after restore, got %v from %d", restored.AverageRating, restored.RatingCount)
+	}
+
+	if err := storage.SetRating(999, 1, 1); err != models.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStorage_SetWork(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	created, _ := storage.Create(models.Book{Title: "Test Book", Author: "Test Author", WorkID: 7})
+	if created.WorkID != 0 {
+		t.Errorf("expected a new book to have no work, got %d", created.WorkID)
+	}
+
+	if err := storage.SetWork(created.ID, 3); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Book writes leave the work alone
+	updated, _ := storage.Update(created.ID, models.Book{Title: "Renamed", Author: "Test Author"})
+	if updated.WorkID != 3 {
+		t.Errorf("expected work 3 after update, got %d", updated.WorkID)
+	}
+	reverted, _ := storage.Revert(created.ID, models.Book{Title: "Test Book", Author: "Test Author", WorkID: 9}, 1)
+	if reverted.WorkID != 3 {
+		t.Errorf("expected work 3 after revert, got %d", reverted.WorkID)
+	}
+
+	storage.Delete(created.ID)
+	if err := storage.SetWork(created.ID, 0); err != nil {
+		t.Errorf("expected the work of a trashed book to be set, got %v", err)
+	}
+	restored, _ := storage.Restore(created.ID)
+	if restored.WorkID != 0 {
+		t.Errorf("expected no work after restore, got %d", restored.WorkID)
+	}
+
+	if err := storage.SetWork(999, 1); err != models.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStorage_SetCover(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	created, _ := storage.Create(models.Book{Title: "Test Book", Author: "Test Author", Cover: &models.Cover{ETag: `"forged"`}})
+	if created.Cover != nil {
+		t.Errorf("expected a new book to have no cover, got %+v", created.Cover)
+	}
+
+	cover := &models.Cover{URL: "/books/1/cover", ETag: `"abc"`}
+	if err := storage.SetCover(created.ID, cover); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Book writes leave the cover alone
+	updated, _ := storage.Update(created.ID, models.Book{Title: "Renamed", Author: "Test Author"})
+	if updated.Cover == nil || updated.Cover.ETag != `"abc"` {
+		t.Errorf("expected the cover to be kept after update, got %+v", updated.Cover)
+	}
+
+	storage.Delete(created.ID)
+	if err := storage.SetCover(created.ID, nil); err != nil {
+		t.Errorf("expected the cover of a trashed book to be set, got %v", err)
+	}
+	restored, _ := storage.Restore(created.ID)
+	if restored.Cover != nil {
+		t.Errorf("expected no cover after restore, got %+v", restored.Cover)
+	}
+
+	if err := storage.SetCover(999, cover); err != models.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStorage_CreatedAt(t *testing.T) {
+	storage := NewMemoryStorage()
+	forged := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	created, _ := storage.Create(models.Book{Title: "Test Book", Author: "Test Author", CreatedAt: forged})
+	if created.CreatedAt.IsZero() || created.CreatedAt.Equal(forged) {
+		t.Fatalf("expected the storage to set the creation time, got %v", created.CreatedAt)
+	}
+
+	updated, _ := storage.Update(created.ID, models.Book{Title: "Renamed", Author: "Test Author", CreatedAt: forged})
+	if !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("expected the creation time to be kept after update, got %v", updated.CreatedAt)
+	}
+	reverted, _ := storage.Revert(created.ID, models.Book{Title: "Test Book", Author: "Test Author"}, 1)
+	if !reverted.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("expected the creation time to be kept after revert, got %v", reverted.CreatedAt)
+	}
+
+	storage.Delete(created.ID)
+	restored, _ := storage.Restore(created.ID)
+	if !restored.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("expected the creation time to be kept after restore, got %v", restored.CreatedAt)
+	}
+}
+
+func TestMemoryStorage_Merge(t *testing.T) {
+	storage := NewMemoryStorage()
+	log := audit.NewLog(0)
+	storage.SetAuditLog(log)
+	bus := events.NewBus(10)
+	storage.SetEventBus(bus)
+
+	a, _ := storage.Create(models.Book{Title: "The Hobit", Author: "Tolkien"})
+	b, _ := storage.Create(models.Book{Title: "Hobbit", Author: "J. R. R. Tolkien"})
+	c, _ := storage.Create(models.Book{Title: "The Hobbit", Author: "J.R.R. Tolkien"})
+
+	sub, _, _ := bus.Subscribe(bus.LastID())
+	defer sub.Close()
+
+	ctx := audit.NewContext(context.Background(), audit.Source{Actor: "librarian"})
+	if err := WithContext(storage, ctx).(Redirects).Merge(a.ID, b.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := storage.GetByID(a.ID); err != models.ErrBookNotFound {
+		t.Errorf("expected the merged book to be gone, got %v", err)
+	}
+	if trashed, _ := storage.Trashed(); len(trashed) != 0 {
+		t.Errorf("expected nothing in the trash, got %+v", trashed)
+	}
+	if into, ok := storage.Redirect(a.ID); !ok || into != b.ID {
+		t.Errorf("expected %d to redirect to %d, got %d, %v", a.ID, b.ID, into, ok)
+	}
+
+	entry := log.History(a.ID)[0]
+	if entry.Action != audit.ActionMerge || entry.MergedInto != b.ID || entry.Actor != "librarian" {
+		t.Errorf("unexpected audit entry %+v", entry)
+	}
+	if last := <-sub.Events(); last.Type != events.BookMerged || last.BookID != a.ID || last.Book.ID != b.ID {
+		t.Errorf("unexpected event %+v", last)
+	}
+
+	// Merging the target again carries the earlier redirect along
+	storage.Merge(b.ID, c.ID)
+	if into, _ := storage.Redirect(a.ID); into != c.ID {
+		t.Errorf("expected %d to redirect to %d, got %d", a.ID, c.ID, into)
+	}
+
+	if err := storage.Merge(c.ID, c.ID); err != models.ErrInvalidID {
+		t.Errorf("expected ErrInvalidID, got %v", err)
+	}
+	if err := storage.Merge(a.ID, c.ID); err != models.ErrBookNotFound {
+		t.Errorf("expected ErrBookNotFound, got %v", err)
+	}
+
+	// Reverting a merged book brings it back under its own ID
+	storage.Revert(a.ID, models.Book{Title: "The Hobit", Author: "Tolkien"}, 1)
+	if _, ok := storage.Redirect(a.ID); ok {
+		t.Error("expected the redirect to be dropped")
+	}
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// MemoryPatronStorage implements in-memory storage for patrons
+type MemoryPatronStorage struct {
+	patrons []models.Patron
+	nextID  int
+	mu      sync.RWMutex
+}
+
+// NewMemoryPatronStorage creates a new in-memory patron storage instance
+func NewMemoryPatronStorage() *MemoryPatronStorage {
+	return &MemoryPatronStorage{
+		patrons: make([]models.Patron, 0),
+		nextID:  1,
+	}
+}
+
+// GetAllPatrons returns all patrons
+func (s *MemoryPatronStorage) GetAllPatrons() ([]models.Patron, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	patronsCopy := make([]models.Patron, len(s.patrons))
+	copy(patronsCopy, s.patrons)
+	return patronsCopy, nil
+}
+
+// GetPatron returns a patron by its ID
+func (s *MemoryPatronStorage) GetPatron(id int) (*models.Patron, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, patron := range s.patrons {
+		if patron.ID == id {
+			patronCopy := patron
+			return &patronCopy, nil
+		}
+	}
+	return nil, models.ErrPatronNotFound
+}
+
+// CreatePatron adds a new patron and returns it with an assigned ID
+func (s *MemoryPatronStorage) CreatePatron(patron models.Patron) (*models.Patron, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	patron.ID = s.nextID
+	s.nextID++
+	s.patrons = append(s.patrons, patron)
+	return &patron, nil
+}
+
+// UpdatePatron updates an existing patron, keeping its ID and creation time
+func (s *MemoryPatronStorage) UpdatePatron(id int, patron models.Patron) (*models.Patron, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, p := range s.patrons {
+		if p.ID == id {
+			patron.ID = id
+			patron.CreatedAt = p.CreatedAt
+			s.patrons[i] = patron
+			return &patron, nil
+		}
+	}
+	return nil, models.ErrPatronNotFound
+}
+
+// DeletePatron removes a patron by its ID
+func (s *MemoryPatronStorage) DeletePatron(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, patron := range s.patrons {
+		if patron.ID == id {
+			s.patrons = append(s.patrons[:i], s.patrons[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrPatronNotFound
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestMemoryPatronStorage(t *testing.T) {
+	storage := NewMemoryPatronStorage()
+
+	first, _ := storage.CreatePatron(models.Patron{Name: "Ada", Type: models.PatronAdult})
+	second, _ := storage.CreatePatron(models.Patron{Name: "Alan", Type: models.PatronStaff})
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("expected sequential IDs 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+
+	updated, err := storage.UpdatePatron(first.ID, models.Patron{Name: "Ada Lovelace", Type: models.PatronAdult})
+	if err != nil || updated.ID != first.ID || updated.Name != "Ada Lovelace" {
+		t.Errorf("expected updated patron %d, got %+v (%v)", first.ID, updated, err)
+	}
+
+	if err := storage.DeletePatron(second.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := storage.GetPatron(second.ID); err != models.ErrPatronNotFound {
+		t.Errorf("expected ErrPatronNotFound after deletion, got %v", err)
+	}
+
+	patrons, _ := storage.GetAllPatrons()
+	if len(patrons) != 1 {
+		t.Errorf("expected 1 patron, got %d", len(patrons))
+	}
+
+	if _, err := storage.UpdatePatron(999, models.Patron{Name: "Nobody"}); err != models.ErrPatronNotFound {
+		t.Errorf("expected ErrPatronNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+// Forgetter drops what is kept outside the book storage for a book, such as
+// its cover images, once the book is purged
+type Forgetter interface {
+	ForgetBook(book models.Book) error
+}
+
+// Purger periodically hard-deletes books that have been in the trash for
+// longer than the retention period
+type Purger struct {
+	storage    Storage
+	forgetters []Forgetter
+	retention  time.Duration
+	interval   time.Duration
+	now        func() time.Time
+}
+
+// NewPurger creates a purger for s, which must implement Trash for the
+// purger to do anything
+func NewPurger(s Storage, retention, interval time.Duration) *Purger {
+	return &Purger{
+		storage:   s,
+		retention: retention,
+		interval:  interval,
+		now:       time.Now,
+	}
+}
+
+// AddForgetter makes the purger tell f about every book it purges
+func (p *Purger) AddForgetter(f Forgetter) {
+	p.forgetters = append(p.forgetters, f)
+}
+
+// Run purges once per interval until ctx is cancelled. An interval of zero
+// or less turns purging off
+func (p *Purger) Run(ctx context.Context) {
+	if p.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := p.PurgeOnce(ctx); err != nil {
+			logger.Error.Printf("Failed to purge trash: %v", err)
+		} else if n > 0 {
+			logger.Info.Printf("Purged %d books from the trash", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce hard-deletes the books deleted more than the retention period
+// ago, attributing the purge to the system actor, and has the forgetters
+// drop what they keep for them. A forgetter failing is logged but does not
+// fail the purge, as the books are already gone
+func (p *Purger) PurgeOnce(ctx context.Context) (int, error) {
+	ctx = audit.NewContext(ctx, audit.Source{Actor: audit.System})
+	trash, ok := WithContext(p.storage, ctx).(Trash)
+	if !ok {
+		return 0, nil
+	}
+	purged, err := trash.Purge(p.now().Add(-p.retention))
+	if err != nil {
+		return 0, err
+	}
+	for _, book := range purged {
+		for _, f := range p.forgetters {
+			if err := f.ForgetBook(book); err != nil {
+				logger.Error.Printf("Failed to clean up after purged book %d: %v", book.ID, err)
+			}
+		}
+	}
+	return len(purged), nil
+}
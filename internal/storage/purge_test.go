@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/audit"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestPurger_PurgeOnce(t *testing.T) {
+	store := NewMemoryStorage()
+	log := audit.NewLog(0)
+	store.SetAuditLog(log)
+
+	old, _ := store.Create(models.Book{Title: "Old", Author: "Author"})
+	recent, _ := store.Create(models.Book{Title: "Recent", Author: "Author"})
+	store.Delete(old.ID)
+	store.Delete(recent.ID)
+
+	purger := NewPurger(store, 24*time.Hour, time.Hour)
+	// Backdate the first deletion past the retention period
+	store.mu.Lock()
+	for i := range store.trash {
+		if store.trash[i].ID == old.ID {
+			deletedAt := time.Now().Add(-48 * time.Hour)
+			store.trash[i].DeletedAt = &deletedAt
+		}
+	}
+	store.mu.Unlock()
+
+	purged, err := purger.PurgeOnce(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("expected 1 book purged, got %d", purged)
+	}
+
+	trashed, _ := store.Trashed()
+	if len(trashed) != 1 || trashed[0].ID != recent.ID {
+		t.Errorf("expected only the recent book left in the trash, got %+v", trashed)
+	}
+
+	entries := log.Query(audit.Filter{Action: audit.ActionPurge})
+	if len(entries) != 1 || entries[0].BookID != old.ID || entries[0].Actor != audit.System {
+		t.Errorf("expected a system purge entry for book %d, got %+v", old.ID, entries)
+	}
+}
+
+func TestPurger_RunWithoutInterval(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		NewPurger(NewMemoryStorage(), time.Hour, 0).Run(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return when purging is off")
+	}
+}
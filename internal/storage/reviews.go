@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// MemoryReviewStorage implements in-memory storage for reviews
+type MemoryReviewStorage struct {
+	reviews []models.Review
+	nextID  int
+	mu      sync.RWMutex
+}
+
+// NewMemoryReviewStorage creates a new in-memory review storage instance
+func NewMemoryReviewStorage() *MemoryReviewStorage {
+	return &MemoryReviewStorage{
+		reviews: make([]models.Review, 0),
+		nextID:  1,
+	}
+}
+
+// GetReviews returns the reviews matching the filter, oldest first
+func (s *MemoryReviewStorage) GetReviews(filter models.ReviewFilter) ([]models.Review, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reviews := make([]models.Review, 0)
+	for _, review := range s.reviews {
+		if filter.Match(review) {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+// GetReview returns a review by its ID
+func (s *MemoryReviewStorage) GetReview(id int) (*models.Review, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, review := range s.reviews {
+		if review.ID == id {
+			reviewCopy := review
+			return &reviewCopy, nil
+		}
+	}
+	return nil, models.ErrReviewNotFound
+}
+
+// CreateReview adds a new review and returns it with an assigned ID. A
+// patron can review each book only once
+func (s *MemoryReviewStorage) CreateReview(review models.Review) (*models.Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.reviews {
+		if r.BookID == review.BookID && r.PatronID == review.PatronID {
+			return nil, models.ErrDuplicateReview
+		}
+	}
+	review.ID = s.nextID
+	s.nextID++
+	s.reviews = append(s.reviews, review)
+	return &review, nil
+}
+
+// UpdateReview updates an existing review
+func (s *MemoryReviewStorage) UpdateReview(review models.Review) (*models.Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, r := range s.reviews {
+		if r.ID == review.ID {
+			s.reviews[i] = review
+			return &review, nil
+		}
+	}
+	return nil, models.ErrReviewNotFound
+}
+
+// DeleteReview removes a review
+func (s *MemoryReviewStorage) DeleteReview(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, r := range s.reviews {
+		if r.ID == id {
+			s.reviews = append(s.reviews[:i], s.reviews[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrReviewNotFound
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestMemoryReviewStorage(t *testing.T) {
+	storage := NewMemoryReviewStorage()
+
+	first, _ := storage.CreateReview(models.Review{BookID: 1, PatronID: 1, Rating: 5, Status: models.ReviewPending})
+	storage.CreateReview(models.Review{BookID: 1, PatronID: 2, Rating: 3, Status: models.ReviewPending})
+	storage.CreateReview(models.Review{BookID: 2, PatronID: 1, Rating: 4, Status: models.ReviewPending})
+
+	if _, err := storage.CreateReview(models.Review{BookID: 1, PatronID: 1, Rating: 1}); err != models.ErrDuplicateReview {
+		t.Errorf("expected ErrDuplicateReview, got %v", err)
+	}
+
+	first.Status = models.ReviewApproved
+	if _, err := storage.UpdateReview(*first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   models.ReviewFilter
+		expected []int
+	}{
+		{"all reviews, oldest first", models.ReviewFilter{}, []int{1, 2, 3}},
+		{"by book", models.ReviewFilter{BookID: 1}, []int{1, 2}},
+		{"by patron", models.ReviewFilter{PatronID: 1}, []int{1, 3}},
+		{"approved", models.ReviewFilter{Status: models.ReviewApproved}, []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviews, _ := storage.GetReviews(tt.filter)
+			if len(reviews) != len(tt.expected) {
+				t.Fatalf("expected %d reviews, got %d", len(tt.expected), len(reviews))
+			}
+			for i, id := range tt.expected {
+				if reviews[i].ID != id {
+					t.Errorf("expected review %d at position %d, got %d", id, i, reviews[i].ID)
+				}
+			}
+		})
+	}
+
+	if err := storage.DeleteReview(first.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := storage.GetReview(first.ID); err != models.ErrReviewNotFound {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+	if err := storage.DeleteReview(first.ID); err != models.ErrReviewNotFound {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+	if _, err := storage.UpdateReview(models.Review{ID: 999}); err != models.ErrReviewNotFound {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// MemorySeriesStorage implements in-memory storage for series
+type MemorySeriesStorage struct {
+	series []models.Series
+	nextID int
+	mu     sync.RWMutex
+}
+
+// NewMemorySeriesStorage creates a new in-memory series storage instance
+func NewMemorySeriesStorage() *MemorySeriesStorage {
+	return &MemorySeriesStorage{
+		series: make([]models.Series, 0),
+		nextID: 1,
+	}
+}
+
+// GetAllSeries returns all series, oldest first
+func (s *MemorySeriesStorage) GetAllSeries() ([]models.Series, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series := make([]models.Series, len(s.series))
+	for i, sr := range s.series {
+		series[i] = copySeries(sr)
+	}
+	return series, nil
+}
+
+// GetSeries returns a series by its ID
+func (s *MemorySeriesStorage) GetSeries(id int) (*models.Series, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, sr := range s.series {
+		if sr.ID == id {
+			seriesCopy := copySeries(sr)
+			return &seriesCopy, nil
+		}
+	}
+	return nil, models.ErrSeriesNotFound
+}
+
+// CreateSeries adds a new series and returns it with an assigned ID
+func (s *MemorySeriesStorage) CreateSeries(series models.Series) (*models.Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series = copySeries(series)
+	series.ID = s.nextID
+	s.nextID++
+	s.series = append(s.series, series)
+	created := copySeries(series)
+	return &created, nil
+}
+
+// UpdateSeries updates an existing series
+func (s *MemorySeriesStorage) UpdateSeries(series models.Series) (*models.Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sr := range s.series {
+		if sr.ID == series.ID {
+			s.series[i] = copySeries(series)
+			updated := copySeries(series)
+			return &updated, nil
+		}
+	}
+	return nil, models.ErrSeriesNotFound
+}
+
+// DeleteSeries removes a series
+func (s *MemorySeriesStorage) DeleteSeries(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sr := range s.series {
+		if sr.ID == id {
+			s.series = append(s.series[:i], s.series[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrSeriesNotFound
+}
+
+// copySeries returns a series that shares no memory with s, so that callers
+// cannot reorder a stored series behind the storage's back
+func copySeries(s models.Series) models.Series {
+	s.BookIDs = slices.Clone(s.BookIDs)
+	if s.BookIDs == nil {
+		s.BookIDs = []int{}
+	}
+	return s
+}
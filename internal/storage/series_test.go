@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestMemorySeriesStorage(t *testing.T) {
+	storage := NewMemorySeriesStorage()
+
+	first, _ := storage.CreateSeries(models.Series{Title: "Earthsea", BookIDs: []int{4, 2}})
+	storage.CreateSeries(models.Series{Title: "Discworld"})
+	if first.ID != 1 {
+		t.Errorf("expected ID 1, got %d", first.ID)
+	}
+
+	// Changing a returned series does not change the stored one
+	first.BookIDs[0] = 99
+	if got, _ := storage.GetSeries(first.ID); !slices.Equal(got.BookIDs, []int{4, 2}) {
+		t.Errorf("expected books [4 2], got %v", got.BookIDs)
+	}
+
+	first.BookIDs = []int{2, 4, 6}
+	if _, err := storage.UpdateSeries(*first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	all, _ := storage.GetAllSeries()
+	if len(all) != 2 || !slices.Equal(all[0].BookIDs, []int{2, 4, 6}) || all[1].BookIDs == nil {
+		t.Errorf("expected two series, the first with books [2 4 6], got %+v", all)
+	}
+
+	if err := storage.DeleteSeries(first.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := storage.GetSeries(first.ID); err != models.ErrSeriesNotFound {
+		t.Errorf("expected ErrSeriesNotFound, got %v", err)
+	}
+	if _, err := storage.UpdateSeries(*first); err != models.ErrSeriesNotFound {
+		t.Errorf("expected ErrSeriesNotFound, got %v", err)
+	}
+	if err := storage.DeleteSeries(first.ID); err != models.ErrSeriesNotFound {
+		t.Errorf("expected ErrSeriesNotFound, got %v", err)
+	}
+}
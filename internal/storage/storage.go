@@ -1,6 +1,11 @@
 package storage
 
-import "github.com/codeforgood-org/golang-book-api/internal/models"
+import (
+	"context"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
 
 // Storage defines the interface for book storage operations
 type Storage interface {
@@ -19,3 +24,233 @@ type Storage interface {
 	// Delete removes a book by its ID
 	Delete(id int) error
 }
+
+// ContextStorage is implemented by storages that can attribute writes to the
+// actor and request carried by a context
+type ContextStorage interface {
+	WithContext(ctx context.Context) Storage
+}
+
+// WithContext scopes s to ctx if it supports it, and returns s unchanged
+// otherwise
+func WithContext(s Storage, ctx context.Context) Storage {
+	if cs, ok := s.(ContextStorage); ok {
+		return cs.WithContext(ctx)
+	}
+	return s
+}
+
+// Reverter is implemented by storages that can put a book back to the state
+// recorded in an earlier revision, recreating it if it was deleted
+type Reverter interface {
+	Revert(id int, book models.Book, revision int) (*models.Book, error)
+}
+
+// Trash is implemented by storages that keep deleted books, with DeletedAt
+// set, so that they can be restored until they are purged
+type Trash interface {
+	// Trashed returns the deleted books, most recently deleted first
+	Trashed() ([]models.Book, error)
+
+	// Restore moves a book out of the trash
+	Restore(id int) (*models.Book, error)
+
+	// Purge permanently removes the books deleted before the cutoff and
+	// returns them
+	Purge(before time.Time) ([]models.Book, error)
+}
+
+// Redirects is implemented by storages that can retire a book merged into
+// another, remembering which book its ID now stands for
+type Redirects interface {
+	// Merge removes a book for good, recording it as merged into another
+	// book, and redirects its ID, and any IDs redirected to it, there
+	Merge(id, into int) error
+
+	// Redirect returns the book a merged book's ID now stands for
+	Redirect(id int) (int, bool)
+}
+
+// PatronStorage defines the interface for patron storage operations
+type PatronStorage interface {
+	// GetAllPatrons returns all patrons
+	GetAllPatrons() ([]models.Patron, error)
+
+	// GetPatron returns a patron by its ID
+	GetPatron(id int) (*models.Patron, error)
+
+	// CreatePatron adds a new patron and returns it with an assigned ID
+	CreatePatron(patron models.Patron) (*models.Patron, error)
+
+	// UpdatePatron updates an existing patron
+	UpdatePatron(id int, patron models.Patron) (*models.Patron, error)
+
+	// DeletePatron removes a patron by its ID
+	DeletePatron(id int) error
+}
+
+// LoanStorage defines the interface for loan storage operations
+type LoanStorage interface {
+	// GetLoans returns the loans matching the filter, most recent first
+	GetLoans(filter models.LoanFilter) ([]models.Loan, error)
+
+	// GetLoan returns a loan by its ID
+	GetLoan(id int) (*models.Loan, error)
+
+	// CreateLoan adds a new loan and returns it with an assigned ID
+	CreateLoan(loan models.Loan) (*models.Loan, error)
+
+	// UpdateLoan updates an existing loan
+	UpdateLoan(loan models.Loan) (*models.Loan, error)
+}
+
+// CopyStorage defines the interface for storage of the physical copies of books
+type CopyStorage interface {
+	// GetCopies returns the copies of a book, oldest first
+	GetCopies(bookID int) ([]models.Copy, error)
+
+	// GetCopy returns a copy by its ID
+	GetCopy(id int) (*models.Copy, error)
+
+	// GetCopyByBarcode returns a copy by its barcode
+	GetCopyByBarcode(barcode string) (*models.Copy, error)
+
+	// CreateCopy adds a new copy and returns it with an assigned ID; the
+	// barcode must not already be in use
+	CreateCopy(c models.Copy) (*models.Copy, error)
+
+	// UpdateCopy updates an existing copy
+	UpdateCopy(c models.Copy) (*models.Copy, error)
+
+	// DeleteCopy removes a copy by its ID
+	DeleteCopy(id int) error
+}
+
+// HoldStorage defines the interface for hold storage operations
+type HoldStorage interface {
+	// GetHolds returns the holds matching the filter, oldest first
+	GetHolds(filter models.HoldFilter) ([]models.Hold, error)
+
+	// GetHold returns a hold by its ID
+	GetHold(id int) (*models.Hold, error)
+
+	// CreateHold adds a new hold and returns it with an assigned ID
+	CreateHold(hold models.Hold) (*models.Hold, error)
+
+	// UpdateHold updates an existing hold
+	UpdateHold(hold models.Hold) (*models.Hold, error)
+}
+
+// FineStorage defines the interface for fine storage operations
+type FineStorage interface {
+	// GetFines returns the fines matching the filter, oldest first
+	GetFines(filter models.FineFilter) ([]models.Fine, error)
+
+	// GetFine returns a fine by its ID
+	GetFine(id int) (*models.Fine, error)
+
+	// CreateFine adds a new fine and returns it with an assigned ID
+	CreateFine(fine models.Fine) (*models.Fine, error)
+
+	// UpdateFine updates an existing fine
+	UpdateFine(fine models.Fine) (*models.Fine, error)
+}
+
+// Ratings is implemented by storages that keep a summary of each book's
+// approved reviews on the book itself
+type Ratings interface {
+	// SetRating replaces the rating summary of a book, whether or not it is
+	// in the trash, without recording it as a write to the book
+	SetRating(id int, average float64, count int) error
+}
+
+// ReviewStorage defines the interface for review storage operations
+type ReviewStorage interface {
+	// GetReviews returns the reviews matching the filter, oldest first
+	GetReviews(filter models.ReviewFilter) ([]models.Review, error)
+
+	// GetReview returns a review by its ID
+	GetReview(id int) (*models.Review, error)
+
+	// CreateReview adds a new review and returns it with an assigned ID
+	CreateReview(review models.Review) (*models.Review, error)
+
+	// UpdateReview updates an existing review
+	UpdateReview(review models.Review) (*models.Review, error)
+
+	// DeleteReview removes a review
+	DeleteReview(id int) error
+}
+
+// Editions is implemented by storages that record on each book the work it
+// is an edition of
+type Editions interface {
+	// SetWork links a book to a work, or unlinks it if workID is 0, whether
+	// or not the book is in the trash, without recording it as a write to
+	// the book
+	SetWork(id, workID int) error
+}
+
+// Covers is implemented by storages that keep a description of each book's
+// cover image on the book itself
+type Covers interface {
+	// SetCover replaces the cover of a book, or removes it if cover is nil,
+	// whether or not the book is in the trash, without recording it as a
+	// write to the book
+	SetCover(id int, cover *models.Cover) error
+}
+
+// SeriesStorage defines the interface for series storage operations
+type SeriesStorage interface {
+	// GetAllSeries returns all series, oldest first
+	GetAllSeries() ([]models.Series, error)
+
+	// GetSeries returns a series by its ID
+	GetSeries(id int) (*models.Series, error)
+
+	// CreateSeries adds a new series and returns it with an assigned ID
+	CreateSeries(series models.Series) (*models.Series, error)
+
+	// UpdateSeries updates an existing series
+	UpdateSeries(series models.Series) (*models.Series, error)
+
+	// DeleteSeries removes a series
+	DeleteSeries(id int) error
+}
+
+// WorkStorage defines the interface for work storage operations
+type WorkStorage interface {
+	// GetWorks returns all works, oldest first
+	GetWorks() ([]models.Work, error)
+
+	// GetWork returns a work by its ID
+	GetWork(id int) (*models.Work, error)
+
+	// CreateWork adds a new work and returns it with an assigned ID
+	CreateWork(work models.Work) (*models.Work, error)
+
+	// UpdateWork updates an existing work
+	UpdateWork(work models.Work) (*models.Work, error)
+
+	// DeleteWork removes a work
+	DeleteWork(id int) error
+}
+
+// AssetStorage defines the interface for storing the records of files
+// attached to books. The files themselves live in a blob store
+type AssetStorage interface {
+	// GetAssets returns the assets of a book, oldest first
+	GetAssets(bookID int) ([]models.Asset, error)
+
+	// GetAsset returns an asset by its ID
+	GetAsset(id int) (*models.Asset, error)
+
+	// CreateAsset adds a new asset and returns it with an assigned ID
+	CreateAsset(asset models.Asset) (*models.Asset, error)
+
+	// UpdateAsset updates an existing asset
+	UpdateAsset(asset models.Asset) (*models.Asset, error)
+
+	// DeleteAsset removes an asset
+	DeleteAsset(id int) error
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// MemoryWorkStorage implements in-memory storage for works
+type MemoryWorkStorage struct {
+	works  []models.Work
+	nextID int
+	mu     sync.RWMutex
+}
+
+// NewMemoryWorkStorage creates a new in-memory work storage instance
+func NewMemoryWorkStorage() *MemoryWorkStorage {
+	return &MemoryWorkStorage{
+		works:  make([]models.Work, 0),
+		nextID: 1,
+	}
+}
+
+// GetWorks returns all works, oldest first
+func (s *MemoryWorkStorage) GetWorks() ([]models.Work, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	works := make([]models.Work, len(s.works))
+	copy(works, s.works)
+	return works, nil
+}
+
+// GetWork returns a work by its ID
+func (s *MemoryWorkStorage) GetWork(id int) (*models.Work, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, work := range s.works {
+		if work.ID == id {
+			workCopy := work
+			return &workCopy, nil
+		}
+	}
+	return nil, models.ErrWorkNotFound
+}
+
+// CreateWork adds a new work and returns it with an assigned ID
+func (s *MemoryWorkStorage) CreateWork(work models.Work) (*models.Work, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	work.ID = s.nextID
+	s.nextID++
+	s.works = append(s.works, work)
+	return &work, nil
+}
+
+// UpdateWork updates an existing work
+func (s *MemoryWorkStorage) UpdateWork(work models.Work) (*models.Work, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, w := range s.works {
+		if w.ID == work.ID {
+			s.works[i] = work
+			return &work, nil
+		}
+	}
+	return nil, models.ErrWorkNotFound
+}
+
+// DeleteWork removes a work
+func (s *MemoryWorkStorage) DeleteWork(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, work := range s.works {
+		if work.ID == id {
+			s.works = append(s.works[:i], s.works[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrWorkNotFound
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestMemoryWorkStorage(t *testing.T) {
+	storage := NewMemoryWorkStorage()
+
+	first, _ := storage.CreateWork(models.Work{Title: "Don Quixote"})
+	storage.CreateWork(models.Work{Title: "War and Peace"})
+	if first.ID != 1 {
+		t.Errorf("expected ID 1, got %d", first.ID)
+	}
+
+	first.Author = "Miguel de Cervantes"
+	if _, err := storage.UpdateWork(*first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got, _ := storage.GetWork(first.ID); got.Author != "Miguel de Cervantes" {
+		t.Errorf("expected the updated author, got %q", got.Author)
+	}
+	if works, _ := storage.GetWorks(); len(works) != 2 || works[0].ID != 1 {
+		t.Errorf("expected two works, oldest first, got %+v", works)
+	}
+
+	if err := storage.DeleteWork(first.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := storage.GetWork(first.ID); err != models.ErrWorkNotFound {
+		t.Errorf("expected ErrWorkNotFound, got %v", err)
+	}
+	if _, err := storage.UpdateWork(*first); err != models.ErrWorkNotFound {
+		t.Errorf("expected ErrWorkNotFound, got %v", err)
+	}
+	if err := storage.DeleteWork(first.ID); err != models.ErrWorkNotFound {
+		t.Errorf("expected ErrWorkNotFound, got %v", err)
+	}
+}
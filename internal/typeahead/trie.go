@@ -0,0 +1,160 @@
+package typeahead
+
+import "container/heap"
+
+// node is a node of a trie keyed by the runes of normalized text. Each node
+// knows the weight of the heaviest completion at or below it, so that the
+// best completions of a prefix are found without visiting every one
+type node struct {
+	children map[rune]*node
+	// ends are the completions with a key ending at this node
+	ends map[*completion]struct{}
+	// best is the highest weight of a completion ending at or below the node
+	best float64
+}
+
+func newNode() *node {
+	return &node{children: make(map[rune]*node)}
+}
+
+// insert adds a key of c to the trie below n
+func (n *node) insert(key string, c *completion) {
+	current := n
+	for _, r := range key {
+		child, ok := current.children[r]
+		if !ok {
+			child = newNode()
+			current.children[r] = child
+		}
+		current = child
+	}
+	if current.ends == nil {
+		current.ends = make(map[*completion]struct{})
+	}
+	current.ends[c] = struct{}{}
+	n.reweigh(key)
+}
+
+// remove takes a key of c out of the trie below n
+func (n *node) remove(key string, c *completion) {
+	if end := n.find(key); end != nil {
+		delete(end.ends, c)
+		n.reweigh(key)
+	}
+}
+
+// reweigh recomputes the best weights along the path of key after the
+// weight of a completion ending there changed, pruning nodes left empty
+func (n *node) reweigh(key string) {
+	path := []*node{n}
+	runes := []rune(key)
+	for _, r := range runes {
+		child, ok := path[len(path)-1].children[r]
+		if !ok {
+			return
+		}
+		path = append(path, child)
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		current := path[i]
+		current.best = 0
+		for c := range current.ends {
+			current.best = max(current.best, c.weight)
+		}
+		for _, child := range current.children {
+			current.best = max(current.best, child.best)
+		}
+		if i > 0 && len(current.ends) == 0 && len(current.children) == 0 {
+			delete(path[i-1].children, runes[i-1])
+		}
+	}
+}
+
+// find returns the node at the end of prefix, or nil if no key starts with it
+func (n *node) find(prefix string) *node {
+	current := n
+	for _, r := range prefix {
+		child, ok := current.children[r]
+		if !ok {
+			return nil
+		}
+		current = child
+	}
+	return current
+}
+
+// top returns up to limit distinct completions with a key starting with
+// prefix, heaviest first. It searches best first, so it visits little more
+// than the paths to the completions it returns
+func (n *node) top(prefix string, limit int) []*completion {
+	start := n.find(prefix)
+	if start == nil || limit < 1 {
+		return nil
+	}
+
+	queue := &candidates{{weight: start.best, key: prefix, node: start}}
+	seen := make(map[*completion]bool)
+	var found []*completion
+	for queue.Len() > 0 && len(found) < limit {
+		next := heap.Pop(queue).(candidate)
+		if next.completion != nil {
+			if !seen[next.completion] {
+				seen[next.completion] = true
+				found = append(found, next.completion)
+			}
+			continue
+		}
+		for c := range next.node.ends {
+			heap.Push(queue, candidate{weight: c.weight, key: next.key, completion: c})
+		}
+		for r, child := range next.node.children {
+			heap.Push(queue, candidate{weight: child.best, key: next.key + string(r), node: child})
+		}
+	}
+	return found
+}
+
+// candidate is a node still to be searched or a completion found by the
+// search, with the weight it is ranked by
+type candidate struct {
+	weight     float64
+	key        string
+	node       *node
+	completion *completion
+}
+
+// candidates is a priority queue of the heaviest candidate first. Among
+// equal weights completions come before nodes, so that a search of equally
+// popular books stops early, then shorter keys, then keys in order
+type candidates []candidate
+
+func (q candidates) Len() int { return len(q) }
+
+func (q candidates) Less(i, j int) bool {
+	a, b := q[i], q[j]
+	if a.weight != b.weight {
+		return a.weight > b.weight
+	}
+	if (a.completion != nil) != (b.completion != nil) {
+		return a.completion != nil
+	}
+	if len(a.key) != len(b.key) {
+		return len(a.key) < len(b.key)
+	}
+	if a.key != b.key {
+		return a.key < b.key
+	}
+	return a.completion != nil && a.completion.less(b.completion)
+}
+
+func (q candidates) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *candidates) Push(x any) { *q = append(*q, x.(candidate)) }
+
+func (q *candidates) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,356 @@
+// Package typeahead completes what a user is typing into the titles and
+// authors of the catalogue, from a prefix index kept up to date as books
+// change and ranked by how popular their books are.
+package typeahead
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/fuzzy"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+)
+
+const (
+	// DefaultLimit is the number of suggestions returned when no limit is given
+	DefaultLimit = 10
+
+	// MaxLimit is the most suggestions returned for a prefix
+	MaxLimit = 50
+)
+
+var (
+	// ErrInvalidLimit is returned for a limit outside 1 to MaxLimit
+	ErrInvalidLimit = errors.New("limit must be from 1 to 50")
+
+	// ErrInvalidKind is returned for a kind other than title or author
+	ErrInvalidKind = errors.New("kind must be title or author")
+)
+
+// Kind is what a suggestion completes
+type Kind string
+
+// Kinds of suggestion
+const (
+	KindTitle  Kind = "title"
+	KindAuthor Kind = "author"
+)
+
+// Valid reports whether k is a known kind
+func (k Kind) Valid() bool {
+	return k == KindTitle || k == KindAuthor
+}
+
+// Suggestion is a title or author completing a prefix
+type Suggestion struct {
+	Text string `json:"text"`
+	Kind Kind   `json:"kind"`
+	// Books is the number of books with this title or author
+	Books int `json:"books"`
+	// BookID is the book with this title or author when there is only one
+	BookID int `json:"book_id,omitempty"`
+}
+
+// Popularity weighs a book for ranking its title and author: one, plus one
+// for every rating it has been given
+func Popularity(book models.Book) float64 {
+	return 1 + float64(book.RatingCount)
+}
+
+// completion is a title or author in the index, with the books that have it
+type completion struct {
+	kind Kind
+	// text is the title or author normalized into lower-case words
+	text  string
+	books map[int]spelling
+	// weight is the sum of the popularity of its books
+	weight float64
+}
+
+// spelling is a title or author as written on one book, and that book's
+// popularity
+type spelling struct {
+	text   string
+	weight float64
+}
+
+// less orders completions ending at the same key
+func (c *completion) less(other *completion) bool {
+	if c.kind != other.kind {
+		return c.kind > other.kind
+	}
+	return c.text < other.text
+}
+
+// suggestion returns c as written on its most popular book
+func (c *completion) suggestion() Suggestion {
+	s := Suggestion{Kind: c.kind, Books: len(c.books)}
+	best := spelling{weight: -1}
+	bestID := 0
+	for id, book := range c.books {
+		if book.weight > best.weight || (book.weight == best.weight && id < bestID) {
+			best, bestID = book, id
+		}
+	}
+	s.Text = best.text
+	if len(c.books) == 1 {
+		s.BookID = bestID
+	}
+	return s
+}
+
+// keys returns the keys c is found under: its text from the start of each
+// of its words, so that any word of a title or author can be completed
+func (c *completion) keys() []string {
+	words := strings.Split(c.text, " ")
+	keys := make([]string, len(words))
+	for i := range words {
+		keys[i] = strings.Join(words[i:], " ")
+	}
+	return keys
+}
+
+// completionKey identifies a completion in the index
+type completionKey struct {
+	kind Kind
+	text string
+}
+
+// indexed is what a book was indexed under, to take it out again
+type indexed struct {
+	title, author *completion
+}
+
+// Index is a prefix index of the titles and authors of books. It is safe
+// for concurrent use
+type Index struct {
+	mu          sync.RWMutex
+	root        *node
+	completions map[completionKey]*completion
+	books       map[int]indexed
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		root:        newNode(),
+		completions: make(map[completionKey]*completion),
+		books:       make(map[int]indexed),
+	}
+}
+
+// Put adds a book to the index, replacing what it was indexed under before
+func (x *Index) Put(book models.Book) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(book.ID)
+	weight := Popularity(book)
+	x.books[book.ID] = indexed{
+		title:  x.add(KindTitle, book.Title, book.ID, weight),
+		author: x.add(KindAuthor, book.Author, book.ID, weight),
+	}
+}
+
+// Remove takes a book out of the index
+func (x *Index) Remove(bookID int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(bookID)
+}
+
+// Rebuild replaces the contents of the index with books. The new contents
+// are built aside, so suggestions are never made from a partial index
+func (x *Index) Rebuild(books []models.Book) {
+	fresh := NewIndex()
+	for _, book := range books {
+		fresh.Put(book)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.root, x.completions, x.books = fresh.root, fresh.completions, fresh.books
+}
+
+// Len returns the number of books in the index
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.books)
+}
+
+// Suggest returns up to limit titles and authors with a word starting with
+// the words of prefix, those of the most popular books first. Only
+// suggestions of kind are returned unless kind is empty. A prefix ending in
+// a space only completes its last word as a whole word
+func (x *Index) Suggest(prefix string, kind Kind, limit int) ([]Suggestion, error) {
+	if limit < 1 || limit > MaxLimit {
+		return nil, ErrInvalidLimit
+	}
+	if kind != "" && !kind.Valid() {
+		return nil, ErrInvalidKind
+	}
+	key := strings.Join(fuzzy.Words(prefix), " ")
+	if key == "" {
+		return []Suggestion{}, nil
+	}
+	if last := []rune(prefix); !unicode.IsLetter(last[len(last)-1]) && !unicode.IsDigit(last[len(last)-1]) {
+		key += " "
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	// Both kinds are searched together, so fetch enough of both for the
+	// kind asked for
+	want := limit
+	if kind != "" {
+		want = limit * 2
+	}
+	suggestions := make([]Suggestion, 0, limit)
+	for {
+		found := x.root.top(key, want)
+		suggestions = suggestions[:0]
+		for _, c := range found {
+			if kind == "" || c.kind == kind {
+				suggestions = append(suggestions, c.suggestion())
+				if len(suggestions) == limit {
+					return suggestions, nil
+				}
+			}
+		}
+		if len(found) < want {
+			return suggestions, nil
+		}
+		want *= 2
+	}
+}
+
+// Run keeps the index up to date with the books in s from the events
+// published on bus until ctx is cancelled. It rebuilds the index from s at
+// the start, whenever it falls behind the events, and once per interval to
+// pick up ratings, which change a book's popularity without an event. An
+// interval of zero turns the periodic rebuild off
+func (x *Index) Run(ctx context.Context, bus *events.Bus, s storage.Storage, interval time.Duration) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		sub, _, _ := bus.Subscribe(0)
+		x.refresh(s)
+		if !x.follow(ctx, sub, s, tick) {
+			sub.Close()
+			return
+		}
+		logger.Warning.Printf("Typeahead index fell behind the event stream, rebuilding")
+	}
+}
+
+// follow applies events from sub to the index until ctx is cancelled,
+// reporting false, or the subscription is closed, reporting true
+func (x *Index) follow(ctx context.Context, sub *events.Subscription, s storage.Storage, tick <-chan time.Time) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-tick:
+			x.refresh(s)
+		case event, ok := <-sub.Events():
+			if !ok {
+				return true
+			}
+			x.Apply(event)
+		}
+	}
+}
+
+// Apply updates the index for an event about a book
+func (x *Index) Apply(event events.Event) {
+	switch event.Type {
+	case events.BookCreated, events.BookUpdated, events.BookRestored:
+		if event.Book != nil {
+			x.Put(*event.Book)
+		}
+	case events.BookDeleted, events.BookMerged:
+		x.Remove(event.BookID)
+	}
+}
+
+// refresh rebuilds the index from the books in s
+func (x *Index) refresh(s storage.Storage) {
+	books, err := s.GetAll()
+	if err != nil {
+		logger.Error.Printf("Failed to rebuild typeahead index: %v", err)
+		return
+	}
+	x.Rebuild(books)
+}
+
+// add indexes the title or author text of a book, returning its completion,
+// or nil if text has no words; x.mu must be held
+func (x *Index) add(kind Kind, text string, bookID int, weight float64) *completion {
+	normalized := strings.Join(fuzzy.Words(text), " ")
+	if normalized == "" {
+		return nil
+	}
+	id := completionKey{kind: kind, text: normalized}
+	c, ok := x.completions[id]
+	if !ok {
+		c = &completion{kind: kind, text: normalized, books: make(map[int]spelling)}
+		x.completions[id] = c
+	}
+	c.books[bookID] = spelling{text: strings.TrimSpace(text), weight: weight}
+	x.reweigh(c, ok)
+	return c
+}
+
+// remove takes a book out of the completions it was indexed under; x.mu
+// must be held
+func (x *Index) remove(bookID int) {
+	entry, ok := x.books[bookID]
+	if !ok {
+		return
+	}
+	delete(x.books, bookID)
+	for _, c := range []*completion{entry.title, entry.author} {
+		if c == nil {
+			continue
+		}
+		delete(c.books, bookID)
+		if len(c.books) == 0 {
+			delete(x.completions, completionKey{kind: c.kind, text: c.text})
+			for _, key := range c.keys() {
+				x.root.remove(key, c)
+			}
+			continue
+		}
+		x.reweigh(c, true)
+	}
+}
+
+// reweigh sums the popularity of the books of c and updates the trie, into
+// which c is inserted unless it is already there; x.mu must be held
+func (x *Index) reweigh(c *completion, inserted bool) {
+	c.weight = 0
+	for _, book := range c.books {
+		c.weight += book.weight
+	}
+	for _, key := range c.keys() {
+		if inserted {
+			x.root.reweigh(key)
+		} else {
+			x.root.insert(key, c)
+		}
+	}
+}
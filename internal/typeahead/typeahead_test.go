@@ -0,0 +1,203 @@
+package typeahead
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/internal/storage"
+)
+
+func texts(suggestions []Suggestion) []string {
+	out := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		out[i] = s.Text
+	}
+	return out
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func newTestIndex() *Index {
+	index := NewIndex()
+	index.Rebuild([]models.Book{
+		{ID: 1, Title: "The Hobbit", Author: "J. R. R. Tolkien", RatingCount: 10},
+		{ID: 2, Title: "The Lord of the Rings", Author: "J. R. R. Tolkien", RatingCount: 3},
+		{ID: 3, Title: "The Pragmatic Programmer", Author: "Andrew Hunt"},
+		{ID: 4, Title: "Programming Pearls", Author: "Jon Bentley", RatingCount: 1},
+		{ID: 5, Title: "Hobbit Houses", Author: "Tom Holt"},
+	})
+	return index
+}
+
+func TestIndex_Suggest(t *testing.T) {
+	index := newTestIndex()
+
+	tests := []struct {
+		name   string
+		prefix string
+		kind   Kind
+		limit  int
+		want   []string
+	}{
+		{"title prefix", "the h", "", 10, []string{"The Hobbit"}},
+		{"word within title", "hob", "", 10, []string{"The Hobbit", "Hobbit Houses"}},
+		{"most popular first", "prog", "", 10, []string{"Programming Pearls", "The Pragmatic Programmer"}},
+		{"author", "tolk", "", 10, []string{"J. R. R. Tolkien"}},
+		{"titles and authors", "t", "", 10, []string{"J. R. R. Tolkien", "The Hobbit", "The Lord of the Rings", "Tom Holt", "The Pragmatic Programmer"}},
+		{"kind", "t", KindTitle, 10, []string{"The Hobbit", "The Lord of the Rings", "The Pragmatic Programmer"}},
+		{"limit", "t", "", 2, []string{"J. R. R. Tolkien", "The Hobbit"}},
+		{"case and punctuation ignored", "THE LORD-OF", "", 10, []string{"The Lord of the Rings"}},
+		{"trailing space completes whole words", "hobbit ", "", 10, []string{"Hobbit Houses"}},
+		{"no match", "xyz", "", 10, []string{}},
+		{"empty prefix", "  ", "", 10, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := index.Suggest(tt.prefix, tt.kind, tt.limit)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !equal(texts(got), tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, texts(got))
+			}
+		})
+	}
+}
+
+func TestIndex_Suggest_Details(t *testing.T) {
+	index := newTestIndex()
+
+	got, _ := index.Suggest("tolkien", "", 10)
+	if len(got) != 1 || got[0].Kind != KindAuthor || got[0].Books != 2 || got[0].BookID != 0 {
+		t.Errorf("expected one author of 2 books without a book ID, got %+v", got)
+	}
+	got, _ = index.Suggest("pearls", "", 10)
+	if len(got) != 1 || got[0].Kind != KindTitle || got[0].Books != 1 || got[0].BookID != 4 {
+		t.Errorf("expected title of book 4, got %+v", got)
+	}
+}
+
+func TestIndex_Suggest_Invalid(t *testing.T) {
+	index := NewIndex()
+
+	if _, err := index.Suggest("a", "", 0); err != ErrInvalidLimit {
+		t.Errorf("expected ErrInvalidLimit, got %v", err)
+	}
+	if _, err := index.Suggest("a", "", MaxLimit+1); err != ErrInvalidLimit {
+		t.Errorf("expected ErrInvalidLimit, got %v", err)
+	}
+	if _, err := index.Suggest("a", "isbn", 10); err != ErrInvalidKind {
+		t.Errorf("expected ErrInvalidKind, got %v", err)
+	}
+}
+
+func TestIndex_PutAndRemove(t *testing.T) {
+	index := newTestIndex()
+
+	// Renaming a book moves it to its new title
+	index.Put(models.Book{ID: 5, Title: "Hobbit Holes", Author: "Tom Holt"})
+	got, _ := index.Suggest("hobbit h", "", 10)
+	if want := []string{"Hobbit Holes"}; !equal(texts(got), want) {
+		t.Errorf("expected %q, got %q", want, texts(got))
+	}
+
+	// Ratings raise a book above more popular ones
+	index.Put(models.Book{ID: 3, Title: "The Pragmatic Programmer", Author: "Andrew Hunt", RatingCount: 5})
+	got, _ = index.Suggest("prog", "", 10)
+	if want := []string{"The Pragmatic Programmer", "Programming Pearls"}; !equal(texts(got), want) {
+		t.Errorf("expected %q, got %q", want, texts(got))
+	}
+
+	// An author stays while any of their books do
+	index.Remove(1)
+	got, _ = index.Suggest("tolk", "", 10)
+	if len(got) != 1 || got[0].Books != 1 || got[0].BookID != 2 {
+		t.Errorf("expected author of book 2 only, got %+v", got)
+	}
+	index.Remove(2)
+	if got, _ = index.Suggest("tolk", "", 10); len(got) != 0 {
+		t.Errorf("expected no suggestions, got %q", texts(got))
+	}
+	if got, _ = index.Suggest("the h", "", 10); len(got) != 0 {
+		t.Errorf("expected no suggestions, got %q", texts(got))
+	}
+
+	if index.Len() != 3 {
+		t.Errorf("expected 3 books, got %d", index.Len())
+	}
+	// Removing everything leaves an empty trie
+	for _, id := range []int{3, 4, 5} {
+		index.Remove(id)
+	}
+	if len(index.root.children) != 0 || len(index.completions) != 0 {
+		t.Errorf("expected an empty index, got %d nodes and %d completions", len(index.root.children), len(index.completions))
+	}
+}
+
+func TestIndex_Run(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	bus := events.NewBus(0)
+	store.SetEventBus(bus)
+	if _, err := store.Create(models.Book{Title: "Dune", Author: "Frank Herbert"}); err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+
+	index := NewIndex()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		index.Run(ctx, bus, store, 0)
+		close(done)
+	}()
+
+	waitFor := func(prefix string, want []string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			got, _ := index.Suggest(prefix, "", 10)
+			if equal(texts(got), want) {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %q for %q, got %q", want, prefix, texts(got))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	waitFor("du", []string{"Dune"})
+	created, err := store.Create(models.Book{Title: "Dune Messiah", Author: "Frank Herbert"})
+	if err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+	waitFor("dune m", []string{"Dune Messiah"})
+	if _, err := store.Update(created.ID, models.Book{Title: "Children of Dune", Author: "Frank Herbert"}); err != nil {
+		t.Fatalf("failed to update book: %v", err)
+	}
+	waitFor("chil", []string{"Children of Dune"})
+	if err := store.Delete(created.ID); err != nil {
+		t.Fatalf("failed to delete book: %v", err)
+	}
+	waitFor("chil", []string{})
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Run to return after cancel")
+	}
+}
@@ -0,0 +1,289 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+	"github.com/codeforgood-org/golang-book-api/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// Options configures a Dispatcher; zero values select the defaults
+type Options struct {
+	// MaxAttempts is the number of attempts before a delivery is dead-lettered
+	MaxAttempts int
+	// Timeout bounds each delivery request
+	Timeout time.Duration
+	// BaseDelay is the wait before the first retry; it doubles per attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between retries
+	MaxDelay time.Duration
+	// Workers is the most deliveries sent at once
+	Workers int
+	// Client sends deliveries; defaults to an http.Client using Timeout
+	Client *http.Client
+}
+
+// Default dispatcher options
+const (
+	DefaultMaxAttempts = 8
+	DefaultTimeout     = 10 * time.Second
+	DefaultBaseDelay   = 30 * time.Second
+	DefaultMaxDelay    = time.Hour
+	DefaultWorkers     = 8
+)
+
+// Dispatcher queues events for matching subscriptions and delivers them in
+// the background
+type Dispatcher struct {
+	store  *Store
+	opts   Options
+	client *http.Client
+	now    func() time.Time
+	wake   chan struct{}
+}
+
+// NewDispatcher creates a dispatcher delivering from store
+func NewDispatcher(store *Store, opts Options) *Dispatcher {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = DefaultBaseDelay
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = DefaultMaxDelay
+	}
+	if opts.Workers < 1 {
+		opts.Workers = DefaultWorkers
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: opts.Timeout}
+	}
+	return &Dispatcher{
+		store:  store,
+		opts:   opts,
+		client: client,
+		now:    time.Now,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Notify queues a delivery of the change to every active subscription that
+// wants events of its type
+func (d *Dispatcher) Notify(typ events.Type, bookID int, book *models.Book) {
+	d.notify(Payload{Type: typ, BookID: bookID, Book: book})
+}
+
+// NotifyHold queues a delivery of a hold event, such as a copy being set
+// aside for pickup, in the same way as Notify
+func (d *Dispatcher) NotifyHold(typ events.Type, hold models.Hold) {
+	d.notify(Payload{Type: typ, BookID: hold.BookID, Hold: &hold})
+}
+
+// notify queues a delivery of payload, stamped with a delivery ID and the
+// current time, to every subscription that wants events of its type
+func (d *Dispatcher) notify(payload Payload) {
+	subs := d.store.matching(payload.Type)
+	if len(subs) == 0 {
+		return
+	}
+
+	now := d.now().UTC()
+	deliveries := make([]Delivery, 0, len(subs))
+	for _, sub := range subs {
+		payload.ID = uuid.New().String()
+		payload.Time = now
+		body, err := json.Marshal(payload)
+		if err != nil {
+			logger.Error.Printf("Failed to encode webhook payload: %v", err)
+			return
+		}
+		deliveries = append(deliveries, Delivery{
+			ID:             payload.ID,
+			SubscriptionID: sub.ID,
+			Event:          payload.Type,
+			Payload:        body,
+			Status:         StatusPending,
+			Attempts:       []Attempt{},
+			NextAttemptAt:  &now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+	}
+
+	if err := d.store.enqueue(deliveries); err != nil {
+		logger.Error.Printf("Failed to queue webhook deliveries: %v", err)
+		return
+	}
+	d.signal()
+}
+
+// Redeliver moves a dead-lettered delivery of a subscription back onto the
+// queue with a fresh set of attempts
+func (d *Dispatcher) Redeliver(subscriptionID, id string) (*Delivery, error) {
+	delivery, err := d.store.requeue(subscriptionID, id, d.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	d.signal()
+	return delivery, nil
+}
+
+// Run delivers queued deliveries as they become due until ctx is cancelled.
+// Deliveries left pending by a previous run are picked up immediately
+func (d *Dispatcher) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-d.wake:
+		}
+
+		next := d.deliverDue(ctx)
+
+		timer.Stop()
+		wait := time.Minute
+		if !next.IsZero() {
+			wait = next.Sub(d.now())
+		}
+		timer.Reset(max(wait, 0))
+	}
+}
+
+// deliverDue sends every due delivery, up to Workers at once, and returns
+// when the earliest remaining delivery becomes due, or the zero time if none
+// remain
+func (d *Dispatcher) deliverDue(ctx context.Context) time.Time {
+	due, _ := d.store.due(d.now())
+
+	queue := make(chan Delivery)
+	var wg sync.WaitGroup
+	for range min(d.opts.Workers, len(due)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range queue {
+				d.attempt(ctx, delivery)
+			}
+		}()
+	}
+	for _, delivery := range due {
+		queue <- delivery
+	}
+	close(queue)
+	wg.Wait()
+
+	_, next := d.store.due(d.now())
+	return next
+}
+
+// attempt sends one delivery and records the outcome, scheduling a retry or
+// dead-lettering it on failure
+func (d *Dispatcher) attempt(ctx context.Context, delivery Delivery) {
+	sub, ok := d.store.subscription(delivery.SubscriptionID)
+	if !ok {
+		return
+	}
+
+	start := d.now()
+	statusCode, err := d.send(ctx, sub, delivery)
+	if ctx.Err() != nil {
+		// Shutting down; leave the delivery pending for the next run
+		return
+	}
+
+	attempt := Attempt{
+		Time:       start.UTC(),
+		StatusCode: statusCode,
+		DurationMS: d.now().Sub(start).Milliseconds(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.UpdatedAt = d.now().UTC()
+
+	switch {
+	case err == nil:
+		delivery.Status = StatusSucceeded
+		delivery.NextAttemptAt = nil
+	case len(delivery.Attempts) >= d.opts.MaxAttempts:
+		delivery.Status = StatusDead
+		delivery.NextAttemptAt = nil
+		logger.Error.Printf("Webhook delivery %s to %s dead-lettered after %d attempts: %v",
+			delivery.ID, sub.URL, len(delivery.Attempts), err)
+	default:
+		next := delivery.UpdatedAt.Add(d.backoff(len(delivery.Attempts)))
+		delivery.NextAttemptAt = &next
+	}
+
+	if err := d.store.record(delivery); err != nil {
+		logger.Error.Printf("Failed to record webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// send POSTs the signed payload, returning the response status code and an
+// error unless the endpoint answered with a 2xx status
+func (d *Dispatcher) send(ctx context.Context, sub Subscription, delivery Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "golang-book-api-webhooks")
+	req.Header.Set(HeaderID, delivery.ID)
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.BaseDelay
+	for i := 1; i < attempts && delay < d.opts.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, d.opts.MaxDelay)
+}
+
+// signal wakes Run without blocking
+func (d *Dispatcher) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
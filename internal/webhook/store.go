@@ -0,0 +1,366 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/google/uuid"
+)
+
+// deliveryLogSize is the number of succeeded deliveries kept per subscription
+const deliveryLogSize = 100
+
+// state is the on-disk representation of the store
+type state struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	Deliveries    []Delivery     `json:"deliveries"`
+}
+
+// Store holds webhook subscriptions and deliveries. When opened with a file
+// path every change is written through to that file, so pending retries and
+// dead letters survive a restart
+type Store struct {
+	mu            sync.Mutex
+	path          string
+	subscriptions map[string]*Subscription
+	deliveries    map[string]*Delivery
+}
+
+// OpenStore loads the store from path, creating it on first write. An empty
+// path keeps the store in memory only
+func OpenStore(path string) (*Store, error) {
+	s := &Store{
+		path:          path,
+		subscriptions: make(map[string]*Subscription),
+		deliveries:    make(map[string]*Delivery),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	for i := range st.Subscriptions {
+		sub := st.Subscriptions[i]
+		s.subscriptions[sub.ID] = &sub
+	}
+	for i := range st.Deliveries {
+		d := st.Deliveries[i]
+		s.deliveries[d.ID] = &d
+	}
+	return s, nil
+}
+
+// ListSubscriptions returns all subscriptions, oldest first
+func (s *Store) ListSubscriptions() []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := make([]Subscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		subs = append(subs, *sub)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].CreatedAt.Before(subs[j].CreatedAt)
+	})
+	return subs
+}
+
+// GetSubscription returns a subscription by its ID
+func (s *Store) GetSubscription(id string) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return nil, ErrSubscriptionNotFound
+	}
+	subCopy := *sub
+	return &subCopy, nil
+}
+
+// CreateSubscription adds a subscription, assigning its ID and generating a
+// secret if none was given
+func (s *Store) CreateSubscription(sub Subscription) (*Subscription, error) {
+	if sub.Secret == "" {
+		secret, err := NewSecret()
+		if err != nil {
+			return nil, err
+		}
+		sub.Secret = secret
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	sub.ID = uuid.New().String()
+	sub.CreatedAt = now
+	sub.UpdatedAt = now
+	s.subscriptions[sub.ID] = &sub
+
+	if err := s.save(); err != nil {
+		delete(s.subscriptions, sub.ID)
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// UpdateSubscription replaces a subscription's URL, events and active flag,
+// and its secret if a new one is given
+func (s *Store) UpdateSubscription(id string, sub Subscription) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.subscriptions[id]
+	if !ok {
+		return nil, ErrSubscriptionNotFound
+	}
+	previous := *existing
+
+	existing.URL = sub.URL
+	existing.Events = sub.Events
+	existing.Active = sub.Active
+	if sub.Secret != "" {
+		existing.Secret = sub.Secret
+	}
+	existing.UpdatedAt = time.Now().UTC()
+
+	if err := s.save(); err != nil {
+		*existing = previous
+		return nil, err
+	}
+	updated := *existing
+	return &updated, nil
+}
+
+// DeleteSubscription removes a subscription along with its deliveries
+func (s *Store) DeleteSubscription(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[id]; !ok {
+		return ErrSubscriptionNotFound
+	}
+	delete(s.subscriptions, id)
+	for deliveryID, d := range s.deliveries {
+		if d.SubscriptionID == id {
+			delete(s.deliveries, deliveryID)
+		}
+	}
+	return s.save()
+}
+
+// Deliveries returns the delivery log of a subscription, newest first
+func (s *Store) Deliveries(subscriptionID string) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[subscriptionID]; !ok {
+		return nil, ErrSubscriptionNotFound
+	}
+	return s.filter(func(d *Delivery) bool {
+		return d.SubscriptionID == subscriptionID
+	}), nil
+}
+
+// DeadLetters returns the deliveries that exhausted their retries, newest first
+func (s *Store) DeadLetters() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filter(func(d *Delivery) bool {
+		return d.Status == StatusDead
+	})
+}
+
+// DeleteDeadLetter discards a dead delivery of a subscription
+func (s *Store) DeleteDeadLetter(subscriptionID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveries[id]
+	if !ok || d.SubscriptionID != subscriptionID || d.Status != StatusDead {
+		return ErrDeliveryNotFound
+	}
+	delete(s.deliveries, id)
+	return s.save()
+}
+
+// requeue moves a dead delivery of a subscription back to pending, due at
+// the given time
+func (s *Store) requeue(subscriptionID, id string, at time.Time) (*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveries[id]
+	if !ok || d.SubscriptionID != subscriptionID || d.Status != StatusDead {
+		return nil, ErrDeliveryNotFound
+	}
+	d.Status = StatusPending
+	d.Attempts = []Attempt{}
+	d.NextAttemptAt = &at
+	d.UpdatedAt = at
+
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+	dCopy := *d
+	return &dCopy, nil
+}
+
+// matching returns copies of the subscriptions that want an event of typ
+func (s *Store) matching(typ events.Type) []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var subs []Subscription
+	for _, sub := range s.subscriptions {
+		if sub.Matches(typ) {
+			subs = append(subs, *sub)
+		}
+	}
+	return subs
+}
+
+// enqueue stores new pending deliveries
+func (s *Store) enqueue(deliveries []Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range deliveries {
+		d := deliveries[i]
+		s.deliveries[d.ID] = &d
+	}
+	return s.save()
+}
+
+// due returns the pending deliveries whose next attempt is at or before now,
+// along with the time the earliest remaining one becomes due
+func (s *Store) due(now time.Time) (due []Delivery, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range s.deliveries {
+		if d.Status != StatusPending || d.NextAttemptAt == nil {
+			continue
+		}
+		if !d.NextAttemptAt.After(now) {
+			due = append(due, *d)
+		} else if next.IsZero() || d.NextAttemptAt.Before(next) {
+			next = *d.NextAttemptAt
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+	return due, next
+}
+
+// record saves the outcome of an attempt, pruning the subscription's log of
+// old succeeded deliveries
+func (s *Store) record(d Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The subscription or delivery may have been removed mid-attempt
+	if _, ok := s.deliveries[d.ID]; !ok {
+		return nil
+	}
+	s.deliveries[d.ID] = &d
+
+	if d.Status == StatusSucceeded {
+		succeeded := s.filter(func(other *Delivery) bool {
+			return other.SubscriptionID == d.SubscriptionID && other.Status == StatusSucceeded
+		})
+		for _, old := range succeeded[min(len(succeeded), deliveryLogSize):] {
+			delete(s.deliveries, old.ID)
+		}
+	}
+	return s.save()
+}
+
+// subscription returns a copy of a subscription; ok is false if it is gone
+func (s *Store) subscription(id string) (Subscription, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return Subscription{}, false
+	}
+	return *sub, true
+}
+
+// filter returns copies of the matching deliveries, newest first; s.mu must
+// be held
+func (s *Store) filter(match func(*Delivery) bool) []Delivery {
+	deliveries := make([]Delivery, 0)
+	for _, d := range s.deliveries {
+		if match(d) {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	return deliveries
+}
+
+// save writes the store to its file, if any, replacing it atomically; s.mu
+// must be held
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	st := state{
+		Subscriptions: make([]Subscription, 0, len(s.subscriptions)),
+		Deliveries:    make([]Delivery, 0, len(s.deliveries)),
+	}
+	for _, sub := range s.subscriptions {
+		st.Subscriptions = append(st.Subscriptions, *sub)
+	}
+	for _, d := range s.deliveries {
+		st.Deliveries = append(st.Deliveries, *d)
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
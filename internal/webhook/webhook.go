@@ -0,0 +1,154 @@
+// Package webhook delivers signed notifications of book changes to
+// subscribed partner endpoints, retrying failed deliveries with exponential
+// backoff and keeping a dead-letter list of deliveries that never succeeded.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+// Webhook errors
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrInvalidURL           = errors.New("webhook url must be an absolute http or https URL")
+	ErrInvalidEvent         = errors.New("unknown webhook event type")
+)
+
+// Headers set on every delivery
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// EventTypes lists the event types a subscription can filter on
+var EventTypes = []events.Type{events.BookCreated, events.BookUpdated, events.BookDeleted, events.BookRestored, events.BookMerged, events.HoldReady}
+
+// Subscription registers an endpoint to receive events
+type Subscription struct {
+	ID string `json:"id"`
+	// URL is the endpoint deliveries are POSTed to
+	URL string `json:"url"`
+	// Events limits deliveries to these types; empty means all events
+	Events []events.Type `json:"events,omitempty"`
+	// Secret is the HMAC-SHA256 signing key
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the subscription URL and event filter
+func (s *Subscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	for _, typ := range s.Events {
+		if !validEventType(typ) {
+			return ErrInvalidEvent
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the subscription wants events of the given type
+func (s *Subscription) Matches(typ events.Type) bool {
+	if !s.Active {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, t := range s.Events {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery status values
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusDead      = "dead"
+)
+
+// Delivery is a single event queued for, or sent to, one subscription
+type Delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	Event          events.Type     `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       []Attempt       `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// Attempt records the outcome of one delivery attempt
+type Attempt struct {
+	Time       time.Time `json:"time"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+}
+
+// Payload is the JSON body POSTed to subscribers
+type Payload struct {
+	ID     string       `json:"id"`
+	Type   events.Type  `json:"type"`
+	BookID int          `json:"book_id"`
+	Book   *models.Book `json:"book,omitempty"`
+	Hold   *models.Hold `json:"hold,omitempty"`
+	Time   time.Time    `json:"time"`
+}
+
+// Sign returns the signature header value for a delivery body sent at the
+// given Unix timestamp: "sha256=" followed by the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the subscription secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for the body and timestamp
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// NewSecret generates a random signing secret
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func validEventType(typ events.Type) bool {
+	for _, t := range EventTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
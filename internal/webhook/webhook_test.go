@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codeforgood-org/golang-book-api/internal/events"
+	"github.com/codeforgood-org/golang-book-api/internal/models"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	sig := Sign("secret", 1700000000, body)
+
+	if len(sig) != len("sha256=")+64 || sig[:7] != "sha256=" {
+		t.Errorf("expected sha256= prefixed hex signature, got %s", sig)
+	}
+	if !Verify("secret", 1700000000, body, sig) {
+		t.Error("expected signature to verify")
+	}
+	if Verify("other", 1700000000, body, sig) {
+		t.Error("expected signature with wrong secret to fail")
+	}
+	if Verify("secret", 1700000001, body, sig) {
+		t.Error("expected signature with wrong timestamp to fail")
+	}
+}
+
+func TestSubscription_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sub     Subscription
+		wantErr error
+	}{
+		{"valid", Subscription{URL: "https://example.com/hook"}, nil},
+		{"valid with events", Subscription{URL: "http://example.com", Events: []events.Type{events.BookDeleted}}, nil},
+		{"relative url", Subscription{URL: "/hook"}, ErrInvalidURL},
+		{"unsupported scheme", Subscription{URL: "ftp://example.com"}, ErrInvalidURL},
+		{"unknown event", Subscription{URL: "https://example.com", Events: []events.Type{"book.read"}}, ErrInvalidEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sub.Validate(); err != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	store, _ := OpenStore("")
+	var received atomic.Int32
+	var sub *Subscription
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if !Verify(sub.Secret, ts, body, r.Header.Get(HeaderSignature)) {
+			t.Errorf("expected valid signature, got %s", r.Header.Get(HeaderSignature))
+		}
+		if got := r.Header.Get(HeaderEvent); got != string(events.BookCreated) {
+			t.Errorf("expected event header book.created, got %s", got)
+		}
+		received.Add(1)
+	}))
+	defer receiver.Close()
+
+	sub, _ = store.CreateSubscription(Subscription{URL: receiver.URL, Active: true})
+	store.CreateSubscription(Subscription{URL: receiver.URL, Active: true, Events: []events.Type{events.BookDeleted}})
+	store.CreateSubscription(Subscription{URL: receiver.URL, Active: false})
+
+	d := NewDispatcher(store, Options{})
+	d.Notify(events.BookCreated, 1, &models.Book{ID: 1, Title: "Title", Author: "Author"})
+	d.deliverDue(context.Background())
+
+	if received.Load() != 1 {
+		t.Fatalf("expected 1 delivery, got %d", received.Load())
+	}
+	deliveries, _ := store.Deliveries(sub.ID)
+	if len(deliveries) != 1 || deliveries[0].Status != StatusSucceeded {
+		t.Fatalf("expected one succeeded delivery, got %+v", deliveries)
+	}
+	if len(deliveries[0].Attempts) != 1 || deliveries[0].Attempts[0].StatusCode != http.StatusOK {
+		t.Errorf("expected one 200 attempt, got %+v", deliveries[0].Attempts)
+	}
+}
+
+func TestDispatcher_RetriesThenDeadLetters(t *testing.T) {
+	store, _ := OpenStore("")
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	sub, _ := store.CreateSubscription(Subscription{URL: receiver.URL, Active: true})
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := NewDispatcher(store, Options{MaxAttempts: 3, BaseDelay: time.Minute})
+	d.now = func() time.Time { return now }
+
+	d.Notify(events.BookUpdated, 1, nil)
+
+	for i, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		next := d.deliverDue(context.Background())
+		if want := now.Add(wantDelay); !next.Equal(want) {
+			t.Fatalf("attempt %d: expected retry at %v, got %v", i+1, want, next)
+		}
+		now = next
+	}
+	if next := d.deliverDue(context.Background()); !next.IsZero() {
+		t.Errorf("expected nothing left to retry, got %v", next)
+	}
+
+	dead := store.DeadLetters()
+	if len(dead) != 1 || len(dead[0].Attempts) != 3 {
+		t.Fatalf("expected one dead letter with 3 attempts, got %+v", dead)
+	}
+	if dead[0].Attempts[2].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 recorded, got %d", dead[0].Attempts[2].StatusCode)
+	}
+
+	redelivered, err := d.Redeliver(sub.ID, dead[0].ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if redelivered.Status != StatusPending || len(redelivered.Attempts) != 0 {
+		t.Errorf("expected pending delivery with no attempts, got %+v", redelivered)
+	}
+	if _, err := d.Redeliver(sub.ID, dead[0].ID); err != ErrDeliveryNotFound {
+		t.Errorf("expected ErrDeliveryNotFound for non-dead delivery, got %v", err)
+	}
+}
+
+func TestDispatcher_BoundsConcurrentDeliveries(t *testing.T) {
+	store, _ := OpenStore("")
+	var inFlight, most, received atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := most.Load()
+			if n <= m || most.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		received.Add(1)
+	}))
+	defer receiver.Close()
+
+	for range 10 {
+		store.CreateSubscription(Subscription{URL: receiver.URL, Active: true})
+	}
+
+	d := NewDispatcher(store, Options{Workers: 3})
+	d.Notify(events.BookCreated, 1, nil)
+	d.deliverDue(context.Background())
+
+	if received.Load() != 10 {
+		t.Fatalf("expected 10 deliveries, got %d", received.Load())
+	}
+	if most.Load() > 3 {
+		t.Errorf("expected at most 3 deliveries at once, got %d", most.Load())
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := NewDispatcher(nil, Options{BaseDelay: 30 * time.Second, MaxDelay: 5 * time.Minute})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{20, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d): expected %v, got %v", tt.attempts, tt.want, got)
+		}
+	}
+}
+
+func TestStore_PersistsAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	sub, _ := store.CreateSubscription(Subscription{URL: "http://127.0.0.1:1/hook", Active: true})
+	NewDispatcher(store, Options{}).Notify(events.BookDeleted, 9, nil)
+
+	reopened, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got, err := reopened.GetSubscription(sub.ID)
+	if err != nil || got.Secret != sub.Secret {
+		t.Fatalf("expected subscription with its secret to persist, got %+v (%v)", got, err)
+	}
+	due, _ := reopened.due(time.Now())
+	if len(due) != 1 || due[0].Event != events.BookDeleted {
+		t.Errorf("expected pending delivery to persist, got %+v", due)
+	}
+
+	if err := reopened.DeleteSubscription(sub.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if deliveries := reopened.filter(func(*Delivery) bool { return true }); len(deliveries) != 0 {
+		t.Errorf("expected deliveries to be removed with subscription, got %d", len(deliveries))
+	}
+}